@@ -102,6 +102,9 @@ func getPipelineCommand() *cobra.Command {
 	cmd.Flags().StringSliceP(cobraext.DataStreamsFlagName, "d", nil, cobraext.DataStreamsFlagDescription)
 	cmd.Flags().BoolP(cobraext.BenchWithTestSamplesFlagName, "", true, cobraext.BenchWithTestSamplesFlagDescription)
 	cmd.Flags().IntP(cobraext.BenchNumTopProcsFlagName, "", 10, cobraext.BenchNumTopProcsFlagDescription)
+	cmd.Flags().String(cobraext.BenchBaselineFlagName, "", cobraext.BenchBaselineFlagDescription)
+	cmd.Flags().Bool(cobraext.BenchRecordBaselineFlagName, false, cobraext.BenchRecordBaselineFlagDescription)
+	cmd.Flags().String(cobraext.BenchFailOnRegressionFlagName, "", cobraext.BenchFailOnRegressionFlagDescription)
 
 	return cmd
 }
@@ -134,6 +137,32 @@ func pipelineCommandAction(cmd *cobra.Command, args []string) error {
 		return cobraext.FlagParsingError(err, cobraext.BenchNumTopProcsFlagName)
 	}
 
+	baselinePath, err := cmd.Flags().GetString(cobraext.BenchBaselineFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.BenchBaselineFlagName)
+	}
+
+	recordBaseline, err := cmd.Flags().GetBool(cobraext.BenchRecordBaselineFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.BenchRecordBaselineFlagName)
+	}
+
+	failOnRegression, err := cmd.Flags().GetString(cobraext.BenchFailOnRegressionFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.BenchFailOnRegressionFlagName)
+	}
+
+	var tolerance float64
+	if failOnRegression != "" {
+		tolerance, err = pipeline.ParseTolerance(failOnRegression)
+		if err != nil {
+			return cobraext.FlagParsingError(err, cobraext.BenchFailOnRegressionFlagName)
+		}
+	}
+	if baselinePath == "" && (recordBaseline || failOnRegression != "") {
+		return fmt.Errorf("--%s is required to record or compare with a baseline", cobraext.BenchBaselineFlagName)
+	}
+
 	packageRootPath, found, err := packages.FindPackageRoot()
 	if !found {
 		return errors.New("package root not found")
@@ -192,6 +221,11 @@ func pipelineCommandAction(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	var currentBaseline *pipeline.Baseline
+	if baselinePath != "" {
+		currentBaseline = pipeline.NewBaseline()
+	}
+
 	var results []reporters.Reportable
 	for idx, folder := range benchFolders {
 		opts := pipeline.NewOptions(
@@ -201,6 +235,7 @@ func pipelineCommandAction(cmd *cobra.Command, args []string) error {
 			pipeline.WithESAPI(esClient.API),
 			pipeline.WithNumTopProcs(numTopProcs),
 			pipeline.WithFormat(reportFormat),
+			pipeline.WithBaseline(currentBaseline),
 		)
 		runner := pipeline.NewPipelineBenchmark(opts)
 
@@ -219,6 +254,40 @@ func pipelineCommandAction(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if currentBaseline == nil {
+		return nil
+	}
+	if recordBaseline {
+		if err := currentBaseline.Write(baselinePath); err != nil {
+			return err
+		}
+		cmd.Printf("Benchmark baseline recorded in %s\n", baselinePath)
+		return nil
+	}
+	return compareWithBaseline(cmd, baselinePath, currentBaseline, tolerance, failOnRegression != "")
+}
+
+// compareWithBaseline compares the metrics of the current benchmarks with the ones in the baseline file.
+// It fails if there are regressions or there is no baseline, only if failing on regressions was requested.
+func compareWithBaseline(cmd *cobra.Command, baselinePath string, current *pipeline.Baseline, tolerance float64, failOnRegression bool) error {
+	baseline, err := pipeline.ReadBaseline(baselinePath)
+	if errors.Is(err, os.ErrNotExist) {
+		err = fmt.Errorf("baseline not found in %s, it can be recorded with --%s", baselinePath, cobraext.BenchRecordBaselineFlagName)
+		if failOnRegression {
+			return err
+		}
+		cmd.Println(err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	comparison := baseline.Compare(current, tolerance)
+	cmd.Print(comparison.String())
+	if failOnRegression {
+		return comparison.Err()
+	}
 	return nil
 }
 
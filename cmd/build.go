@@ -27,6 +27,10 @@ Built packages are served up by the Elastic Package Registry running locally (se
 
 Built packages can also be published to the global package registry service.
 
+Zipped packages are reproducible, building the same package contents produces identical archives. All files in the archive have the same modification time, that can be set with the SOURCE_DATE_EPOCH environment variable.
+
+Throwaway builds of prerelease versions ("-SNAPSHOT" or "-next") can be created before adding their changelog entry with the --skip-changelog-validation-for-prerelease flag. The rest of the package is still validated.
+
 For details on how to enable dependency management, see the [HOWTO guide](https://github.com/elastic/elastic-package/blob/main/docs/howto/dependency_management.md).`
 
 func setupBuildCommand() *cobraext.Command {
@@ -40,6 +44,7 @@ func setupBuildCommand() *cobraext.Command {
 	cmd.Flags().Bool(cobraext.BuildZipFlagName, true, cobraext.BuildZipFlagDescription)
 	cmd.Flags().Bool(cobraext.SignPackageFlagName, false, cobraext.SignPackageFlagDescription)
 	cmd.Flags().Bool(cobraext.BuildSkipValidationFlagName, false, cobraext.BuildSkipValidationFlagDescription)
+	cmd.Flags().Bool(cobraext.BuildSkipPrereleaseChangelogValidationFlagName, false, cobraext.BuildSkipPrereleaseChangelogValidationFlagDescription)
 	return cobraext.NewCommand(cmd, cobraext.ContextPackage)
 }
 
@@ -49,6 +54,7 @@ func buildCommandAction(cmd *cobra.Command, args []string) error {
 	createZip, _ := cmd.Flags().GetBool(cobraext.BuildZipFlagName)
 	signPackage, _ := cmd.Flags().GetBool(cobraext.SignPackageFlagName)
 	skipValidation, _ := cmd.Flags().GetBool(cobraext.BuildSkipValidationFlagName)
+	skipPrereleaseChangelogValidation, _ := cmd.Flags().GetBool(cobraext.BuildSkipPrereleaseChangelogValidationFlagName)
 
 	if signPackage && !createZip {
 		return errors.New("can't sign the unzipped package, please use also the --zip switch")
@@ -87,6 +93,8 @@ func buildCommandAction(cmd *cobra.Command, args []string) error {
 		CreateZip:      createZip,
 		SignPackage:    signPackage,
 		SkipValidation: skipValidation,
+
+		SkipPrereleaseChangelogValidation: skipPrereleaseChangelogValidation,
 	})
 	if err != nil {
 		return fmt.Errorf("building package failed: %w", err)
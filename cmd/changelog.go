@@ -30,7 +30,11 @@ The entry added will include the given description, type and link. It is added o
 last entry in the current version
 
 Alternatively, you can start a new version indicating the specific version, or if it should
-be the next major, minor or patch version.`
+be the next major, minor or patch version. If the given version is not in the changelog, a new
+version is added in its place, keeping newer versions on top.
+
+The changelog is validated after adding the entry, and nothing is written if it is not valid.
+The version in the package manifest is updated only if the entry is added to the latest version.`
 
 func setupChangelogCommand() *cobraext.Command {
 	addChangelogCmd := &cobra.Command{
@@ -95,11 +99,16 @@ func changelogAddCmd(cmd *cobra.Command, args []string) error {
 		},
 	}
 
-	err = patchChangelogFile(packageRoot, entry)
+	revisions, err := patchChangelogFile(packageRoot, entry)
 	if err != nil {
 		return err
 	}
 
+	// Don't set the version of the manifest to the one of an older entry.
+	if revisions[0].Version != version {
+		return nil
+	}
+
 	err = setManifestVersion(packageRoot, version)
 	if err != nil {
 		return err
@@ -122,6 +131,16 @@ func changelogCmdVersion(nextMode, packageRoot string) (*semver.Version, error)
 		return nil, fmt.Errorf("invalid version in changelog %q: %w", revisions[0].Version, err)
 	}
 
+	version, err = nextVersion(version, nextMode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %q: %w", cobraext.ChangelogAddNextFlagName, err)
+	}
+	return version, nil
+}
+
+// nextVersion returns the next `major`, `minor` or `patch` version. The same version is returned
+// if no mode is given.
+func nextVersion(version *semver.Version, nextMode string) (*semver.Version, error) {
 	switch nextMode {
 	case "":
 		break
@@ -135,28 +154,41 @@ func changelogCmdVersion(nextMode, packageRoot string) (*semver.Version, error)
 		v := version.IncPatch()
 		version = &v
 	default:
-		return nil, fmt.Errorf("invalid value for %q: %s",
-			cobraext.ChangelogAddNextFlagName, nextMode)
+		return nil, fmt.Errorf("unknown mode %s", nextMode)
 	}
 
 	return version, nil
 }
 
 // patchChangelogFile looks for the proper place to add the new revision in the changelog,
-// trying to conserve original format and comments.
-func patchChangelogFile(packageRoot string, patch changelog.Revision) error {
+// trying to conserve original format and comments. The patched changelog is validated before
+// writing it, and its revisions are returned.
+func patchChangelogFile(packageRoot string, patch changelog.Revision) ([]changelog.Revision, error) {
 	changelogPath := filepath.Join(packageRoot, changelog.PackageChangelogFile)
 	d, err := os.ReadFile(changelogPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	d, err = changelog.PatchYAML(d, patch)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	revisions, err := changelog.ParseChangelog(d)
+	if err != nil {
+		return nil, err
+	}
+	err = changelog.ValidateRevisions(revisions, changelog.DefaultLinkHost)
+	if err != nil {
+		return nil, fmt.Errorf("invalid changelog entries:\n%w", err)
 	}
 
-	return os.WriteFile(changelogPath, d, 0644)
+	err = os.WriteFile(changelogPath, d, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
 }
 
 func setManifestVersion(packageRoot string, version string) error {
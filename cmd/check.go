@@ -5,26 +5,96 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/elastic/elastic-package/internal/cobraext"
+	"github.com/elastic/elastic-package/internal/elasticsearch"
+	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/files"
+	"github.com/elastic/elastic-package/internal/install"
+	"github.com/elastic/elastic-package/internal/logger"
+	"github.com/elastic/elastic-package/internal/multierror"
+	"github.com/elastic/elastic-package/internal/packages"
+	"github.com/elastic/elastic-package/internal/packages/buildmanifest"
+	"github.com/elastic/elastic-package/internal/packages/changelog"
+	"github.com/elastic/elastic-package/internal/servicedeployer"
+	"github.com/elastic/elastic-package/internal/stack"
+	"github.com/elastic/elastic-package/internal/testrunner/runners"
+	"github.com/elastic/elastic-package/internal/validation"
 )
 
 const checkLongDescription = `Use this command to verify if the package is correct in terms of formatting, validation and building.
 
-It will execute the lint and build commands all at once, in that order.`
+It will execute the lint, test-config, deploy-config, changelog, transforms and build commands all at once, in that order.
+
+With --fix, the issues that can be safely fixed, such as unsorted changelog versions or trailing whitespace in YAML files, are fixed before checking the package. The files changed are reported, and the rest of issues are still reported as errors.`
+
+const checkTestConfigLongDescription = `Use this command to verify the test configuration files of the package.
+
+All the test configuration files are loaded as the test runners do, and unknown settings are reported with the file where they are found, as they are usually misspelled settings that would be silently ignored otherwise.`
+
+const checkDeployConfigLongDescription = `Use this command to verify the service deployer configurations of the package.
+
+The configurations found in the _dev/deploy directories of the package and its data streams are parsed, reporting files referenced by them that don't exist, and variables without default value that are not defined by elastic-package, the service variants or the environment. This allows to find these problems before deploying any service.`
+
+const checkChangelogLongDescription = `Use this command to verify the entries of the changelog of the package.
+
+Each entry must have a valid type (bugfix, enhancement or breaking-change), a non-empty description, and a link to the pull request or issue in GitHub. Invalid entries are reported with their version and their index in the list of changes of the version.
+
+A warning is logged if the versions are not sorted from newest to oldest. They can be sorted with --fix.`
+
+const checkTransformsLongDescription = `Use this command to list and verify the transforms of the package.
+
+The definition of each transform in elasticsearch/transform is parsed, checking that it has the settings required to create it: the source indexes, the destination index, and either a pivot or a latest configuration.
+
+With --preview, the transforms are also previewed in the running stack with the data available in their source indexes, and the generated documents are validated with the fields defined for the transform. Source data streams need to have some data for this, for example after running system tests with --defer-cleanup.`
+
+const checkRuntimeFieldsLongDescription = `Use this command to verify the runtime fields defined in the Kibana data views of the package.
+
+Runtime fields are read from the Kibana saved objects of the package, including data views embedded in other objects. Each runtime field that shadows a field defined in the package must have a compatible type, for example a runtime keyword field defined over a long field is reported as a conflict. The values of these fields in the sample events of the package are also checked to be compatible with the type of the runtime field.`
+
+const checkECSLongDescription = `Use this command to list the ECS references used by the packages in the repository.
+
+With the --consistent flag, the command fails if some packages use an ECS reference different to the one used by most of the packages, or to the one given with the --reference flag.`
 
 func setupCheckCommand() *cobraext.Command {
+	checkTestConfigCmd := setupCheckTestConfigCommand()
+	checkDeployConfigCmd := setupCheckDeployConfigCommand()
+	checkChangelogCmd := setupCheckChangelogCommand()
+	checkTransformsCmd := setupCheckTransformsCommand()
+	checkRuntimeFieldsCmd := setupCheckRuntimeFieldsCommand()
+
 	cmd := &cobra.Command{
 		Use:   "check",
 		Short: "Check the package",
 		Long:  checkLongDescription,
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			err := cobraext.ComposeCommands(args,
-				setupLintCommand(),
+			fix, err := cmd.Flags().GetBool(cobraext.FixFlagName)
+			if err != nil {
+				return cobraext.FlagParsingError(err, cobraext.FixFlagName)
+			}
+			lintCmd := setupLintCommand()
+			if fix {
+				for _, c := range []*cobraext.Command{lintCmd, checkChangelogCmd} {
+					err := c.Flags().Set(cobraext.FixFlagName, "true")
+					if err != nil {
+						return cobraext.FlagParsingError(err, cobraext.FixFlagName)
+					}
+				}
+			}
+
+			err = cobraext.ComposeCommands(args,
+				lintCmd,
+				checkTestConfigCmd,
+				checkDeployConfigCmd,
+				checkChangelogCmd,
+				checkTransformsCmd,
 				setupBuildCommand(),
 			)
 			if err != nil {
@@ -34,6 +104,335 @@ func setupCheckCommand() *cobraext.Command {
 		},
 	}
 	cmd.PersistentFlags().BoolP(cobraext.FailFastFlagName, "f", true, cobraext.FailFastFlagDescription)
+	cmd.Flags().Bool(cobraext.FixFlagName, false, cobraext.FixFlagDescription)
+
+	checkECSCmd := &cobra.Command{
+		Use:   "ecs",
+		Short: "Check the ECS references of the packages in the repository",
+		Long:  checkECSLongDescription,
+		Args:  cobra.NoArgs,
+		RunE:  checkECSCommandAction,
+	}
+	checkECSCmd.Flags().Bool(cobraext.CheckECSConsistentFlagName, false, cobraext.CheckECSConsistentFlagDescription)
+	checkECSCmd.Flags().String(cobraext.CheckECSReferenceFlagName, "", cobraext.CheckECSReferenceFlagDescription)
+	cmd.AddCommand(checkECSCmd)
+	cmd.AddCommand(checkTestConfigCmd.Command)
+	cmd.AddCommand(checkDeployConfigCmd.Command)
+	cmd.AddCommand(checkChangelogCmd.Command)
+	cmd.AddCommand(checkTransformsCmd.Command)
+	cmd.AddCommand(checkRuntimeFieldsCmd.Command)
+
+	return cobraext.NewCommand(cmd, cobraext.ContextPackage)
+}
+
+func setupCheckTestConfigCommand() *cobraext.Command {
+	cmd := &cobra.Command{
+		Use:   "test-config",
+		Short: "Check the test configuration files of the package",
+		Long:  checkTestConfigLongDescription,
+		Args:  cobra.NoArgs,
+		RunE:  checkTestConfigCommandAction,
+	}
+	return cobraext.NewCommand(cmd, cobraext.ContextPackage)
+}
+
+func checkTestConfigCommandAction(cmd *cobra.Command, args []string) error {
+	cmd.Println("Check test configuration files")
+
+	packageRootPath, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	err = runners.ValidateTestConfigs(packageRootPath)
+	if err != nil {
+		return fmt.Errorf("invalid test configuration files:\n%w", err)
+	}
+	return nil
+}
+
+func setupCheckDeployConfigCommand() *cobraext.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy-config",
+		Short: "Check the service deployer configurations of the package",
+		Long:  checkDeployConfigLongDescription,
+		Args:  cobra.NoArgs,
+		RunE:  checkDeployConfigCommandAction,
+	}
+	return cobraext.NewCommand(cmd, cobraext.ContextPackage)
+}
+
+func checkDeployConfigCommandAction(cmd *cobra.Command, args []string) error {
+	cmd.Println("Check service deployer configurations")
+
+	packageRootPath, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	err = servicedeployer.ValidateDevDeployConfigs(packageRootPath)
+	if err != nil {
+		return fmt.Errorf("invalid service deployer configurations:\n%w", err)
+	}
+	return nil
+}
+
+func setupCheckChangelogCommand() *cobraext.Command {
+	cmd := &cobra.Command{
+		Use:   "changelog",
+		Short: "Check the changelog of the package",
+		Long:  checkChangelogLongDescription,
+		Args:  cobra.NoArgs,
+		RunE:  checkChangelogCommandAction,
+	}
+	cmd.Flags().Bool(cobraext.FixFlagName, false, cobraext.FixFlagDescription)
+	return cobraext.NewCommand(cmd, cobraext.ContextPackage)
+}
+
+func checkChangelogCommandAction(cmd *cobra.Command, args []string) error {
+	cmd.Println("Check changelog")
+
+	fix, err := cmd.Flags().GetBool(cobraext.FixFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.FixFlagName)
+	}
+
+	packageRootPath, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	if fix {
+		sorted, err := changelog.SortChangelogFromPackageRoot(packageRootPath)
+		if err != nil {
+			return err
+		}
+		if sorted {
+			cmd.Printf("Fixed: sorted versions in %s\n", changelog.PackageChangelogFile)
+		}
+	}
+
+	revisions, err := changelog.ReadChangelogFromPackageRoot(packageRootPath)
+	if err != nil {
+		return err
+	}
+	err = changelog.ValidateRevisions(revisions, changelog.DefaultLinkHost)
+	if err != nil {
+		return fmt.Errorf("invalid changelog entries:\n%w", err)
+	}
 
+	unsorted, err := changelog.UnsortedRevisions(revisions)
+	if err != nil {
+		return fmt.Errorf("checking order of changelog versions failed: %w", err)
+	}
+	if len(unsorted) > 0 {
+		logger.Warnf("changelog versions are not sorted from newest to oldest (%s), sort them with --fix", strings.Join(unsorted, ", "))
+	}
+	return nil
+}
+
+func setupCheckTransformsCommand() *cobraext.Command {
+	cmd := &cobra.Command{
+		Use:   "transforms",
+		Short: "Check the transforms of the package",
+		Long:  checkTransformsLongDescription,
+		Args:  cobra.NoArgs,
+		RunE:  checkTransformsCommandAction,
+	}
+	cmd.Flags().Bool(cobraext.CheckTransformsPreviewFlagName, false, cobraext.CheckTransformsPreviewFlagDescription)
+	cmd.Flags().Bool(cobraext.TLSSkipVerifyFlagName, false, cobraext.TLSSkipVerifyFlagDescription)
+	cmd.Flags().StringP(cobraext.ProfileFlagName, "p", "", fmt.Sprintf(cobraext.ProfileFlagDescription, install.ProfileNameEnvVar))
+	return cobraext.NewCommand(cmd, cobraext.ContextPackage)
+}
+
+func checkTransformsCommandAction(cmd *cobra.Command, args []string) error {
+	cmd.Println("Check transforms")
+
+	preview, err := cmd.Flags().GetBool(cobraext.CheckTransformsPreviewFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.CheckTransformsPreviewFlagName)
+	}
+
+	packageRootPath, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+	transforms, err := packages.ReadTransformsFromPackageRoot(packageRootPath)
+	if err != nil {
+		return fmt.Errorf("loading transforms failed: %w", err)
+	}
+	if len(transforms) == 0 {
+		cmd.Println("No transforms found")
+		return nil
+	}
+
+	var errs multierror.Error
+	var valid []packages.Transform
+	for _, transform := range transforms {
+		cmd.Printf("  - %s (source: %s)\n", transform.Name, strings.Join(transform.Definition.Source.Index, ", "))
+		err := packages.ValidateTransform(transform)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("transform %q is not valid:\n%w", transform.Name, err))
+			continue
+		}
+		valid = append(valid, transform)
+	}
+
+	if preview && len(valid) > 0 {
+		previewErrs, err := previewTransforms(cmd, packageRootPath, valid)
+		if err != nil {
+			return err
+		}
+		errs = append(errs, previewErrs...)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid transforms:\n%w", errs)
+	}
+	return nil
+}
+
+// previewTransforms previews the given transforms in the running stack, and validates the
+// documents they generate with the fields defined for each transform.
+func previewTransforms(cmd *cobra.Command, packageRootPath string, transforms []packages.Transform) (multierror.Error, error) {
+	tlsSkipVerify, err := cmd.Flags().GetBool(cobraext.TLSSkipVerifyFlagName)
+	if err != nil {
+		return nil, cobraext.FlagParsingError(err, cobraext.TLSSkipVerifyFlagName)
+	}
+	profile, err := cobraext.GetProfileFlag(cmd)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := packages.ReadPackageManifestFromPackageRoot(packageRootPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading package manifest failed (path: %s): %w", packageRootPath, err)
+	}
+
+	var clientOptions []elasticsearch.ClientOption
+	if tlsSkipVerify {
+		clientOptions = append(clientOptions, elasticsearch.OptionWithSkipTLSVerify())
+	}
+	client, err := stack.NewElasticsearchClientFromProfile(profile, clientOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Elasticsearch client: %w", err)
+	}
+
+	previews, err := validation.PreviewTransforms(cmd.Context(), client, transforms,
+		fields.WithSpecVersion(manifest.SpecVersion),
+		fields.WithEnabledImportAllECSSChema(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs multierror.Error
+	for _, preview := range previews {
+		switch {
+		case preview.Err != nil:
+			errs = append(errs, fmt.Errorf("transform %q: %w", preview.Transform, preview.Err))
+		case preview.Documents == 0:
+			logger.Warnf("transform %q generated no documents in preview, ingest some data in its source indexes to validate them", preview.Transform)
+		default:
+			cmd.Printf("Transform %q generated %d valid documents in preview\n", preview.Transform, preview.Documents)
+		}
+	}
+	return errs, nil
+}
+
+func setupCheckRuntimeFieldsCommand() *cobraext.Command {
+	cmd := &cobra.Command{
+		Use:   "runtime-fields",
+		Short: "Check the runtime fields of the Kibana data views of the package",
+		Long:  checkRuntimeFieldsLongDescription,
+		Args:  cobra.NoArgs,
+		RunE:  checkRuntimeFieldsCommandAction,
+	}
 	return cobraext.NewCommand(cmd, cobraext.ContextPackage)
 }
+
+func checkRuntimeFieldsCommandAction(cmd *cobra.Command, args []string) error {
+	cmd.Println("Check runtime fields")
+
+	packageRootPath, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+	runtimeFields, err := fields.ReadRuntimeFields(packageRootPath)
+	if err != nil {
+		return fmt.Errorf("loading runtime fields failed: %w", err)
+	}
+	if len(runtimeFields) == 0 {
+		cmd.Println("No runtime fields found")
+		return nil
+	}
+	for _, runtimeField := range runtimeFields {
+		cmd.Printf("  - %s (type: %s)\n", runtimeField.Name, runtimeField.Type)
+	}
+
+	manifest, err := packages.ReadPackageManifestFromPackageRoot(packageRootPath)
+	if err != nil {
+		return fmt.Errorf("reading package manifest failed (path: %s): %w", packageRootPath, err)
+	}
+	errs, err := validation.CheckRuntimeFields(packageRootPath, runtimeFields,
+		fields.WithSpecVersion(manifest.SpecVersion),
+		fields.WithEnabledImportAllECSSChema(true),
+	)
+	if err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("conflicts found in runtime fields:\n%w", errs)
+	}
+	return nil
+}
+
+func checkECSCommandAction(cmd *cobra.Command, args []string) error {
+	consistent, err := cmd.Flags().GetBool(cobraext.CheckECSConsistentFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.CheckECSConsistentFlagName)
+	}
+	expected, err := cmd.Flags().GetString(cobraext.CheckECSReferenceFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.CheckECSReferenceFlagName)
+	}
+
+	repositoryRoot, err := files.FindRepositoryRootDirectory()
+	if err != nil {
+		return fmt.Errorf("locating repository root failed: %w", err)
+	}
+	references, err := buildmanifest.FindECSReferences(repositoryRoot)
+	if err != nil {
+		return err
+	}
+	if len(references) == 0 {
+		cmd.Println("No packages with ECS dependencies found")
+		return nil
+	}
+
+	relative := func(path string) string {
+		rel, err := filepath.Rel(repositoryRoot, path)
+		if err != nil {
+			return path
+		}
+		return rel
+	}
+
+	if !consistent {
+		for _, reference := range references {
+			cmd.Printf("%s: %s\n", relative(reference.PackageRoot), reference.Reference)
+		}
+		return nil
+	}
+
+	expected, inconsistent := buildmanifest.InconsistentECSReferences(references, expected)
+	if len(inconsistent) == 0 {
+		cmd.Printf("All packages use ECS reference %s\n", expected)
+		return nil
+	}
+	cmd.Printf("Packages using an ECS reference different to %s:\n", expected)
+	for _, reference := range inconsistent {
+		cmd.Printf("  - %s: %s\n", relative(reference.PackageRoot), reference.Reference)
+	}
+	return errors.New("ECS references are not consistent")
+}
@@ -0,0 +1,124 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/elastic-package/internal/cobraext"
+	"github.com/elastic/elastic-package/internal/export"
+	"github.com/elastic/elastic-package/internal/install"
+	"github.com/elastic/elastic-package/internal/kibana"
+	"github.com/elastic/elastic-package/internal/packages"
+	"github.com/elastic/elastic-package/internal/stack"
+)
+
+const diffLongDescription = `Use this command to compare the package with the resources installed in the Elastic Stack.`
+
+const diffAssetsLongDescription = `Use this command to compare the Kibana assets installed in Kibana with their source in the package.
+
+The dashboards, visualizations, searches, maps, lens and tags of the package are exported from Kibana and compared with the files in the kibana directory of the package, after applying the same adjustments done when exporting dashboards. For each asset with differences, the fields that changed are listed, marked with "~" if they have different values, "-" if they are only in the package, and "+" if they are only in Kibana. Assets not installed in Kibana are also reported.
+
+By default all the assets of the package are compared. With --data-stream, only the assets that reference the dataset of the given data stream are compared.
+
+This helps to bring back to the package the changes done manually in Kibana, and to find changes done by mistake.`
+
+// diffValueMaxLength is the maximum length of the values printed in the differences.
+const diffValueMaxLength = 80
+
+func setupDiffCommand() *cobraext.Command {
+	diffAssetsCmd := &cobra.Command{
+		Use:   "assets",
+		Short: "Compare the Kibana assets installed in Kibana with the package",
+		Long:  diffAssetsLongDescription,
+		Args:  cobra.NoArgs,
+		RunE:  diffAssetsCommandAction,
+	}
+	diffAssetsCmd.Flags().StringP(cobraext.DataStreamFlagName, "d", "", cobraext.DiffAssetsDataStreamFlagDescription)
+	diffAssetsCmd.Flags().Bool(cobraext.TLSSkipVerifyFlagName, false, cobraext.TLSSkipVerifyFlagDescription)
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare the package with the installed resources",
+		Long:  diffLongDescription,
+	}
+	cmd.AddCommand(diffAssetsCmd)
+	cmd.PersistentFlags().StringP(cobraext.ProfileFlagName, "p", "", fmt.Sprintf(cobraext.ProfileFlagDescription, install.ProfileNameEnvVar))
+
+	return cobraext.NewCommand(cmd, cobraext.ContextPackage)
+}
+
+func diffAssetsCommandAction(cmd *cobra.Command, args []string) error {
+	cmd.Println("Compare Kibana assets")
+
+	dataStream, err := cmd.Flags().GetString(cobraext.DataStreamFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.DataStreamFlagName)
+	}
+
+	packageRoot, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	var opts []kibana.ClientOption
+	tlsSkipVerify, _ := cmd.Flags().GetBool(cobraext.TLSSkipVerifyFlagName)
+	if tlsSkipVerify {
+		opts = append(opts, kibana.TLSSkipVerify())
+	}
+
+	profile, err := cobraext.GetProfileFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	kibanaClient, err := stack.NewKibanaClientFromProfile(profile, opts...)
+	if err != nil {
+		return fmt.Errorf("can't create Kibana client: %w", err)
+	}
+
+	diffs, err := export.DiffAssets(cmd.Context(), kibanaClient, packageRoot, dataStream)
+	if err != nil {
+		return fmt.Errorf("comparing assets failed: %w", err)
+	}
+	if len(diffs) == 0 {
+		cmd.Println("No differences found")
+		return nil
+	}
+
+	for _, diff := range diffs {
+		if diff.NotInstalled {
+			cmd.Printf("%s: not installed in Kibana\n", diff.Path)
+			continue
+		}
+		cmd.Printf("%s: %d fields changed\n", diff.Path, len(diff.Changes))
+		for _, change := range diff.Changes {
+			switch {
+			case !change.InInstalled:
+				cmd.Printf("  - %s: %s\n", change.Path, formatDiffValue(change.Source))
+			case !change.InSource:
+				cmd.Printf("  + %s: %s\n", change.Path, formatDiffValue(change.Installed))
+			default:
+				cmd.Printf("  ~ %s: %s -> %s\n", change.Path, formatDiffValue(change.Source), formatDiffValue(change.Installed))
+			}
+		}
+	}
+	return nil
+}
+
+// formatDiffValue encodes a value as JSON, truncating it if it is too long.
+func formatDiffValue(value any) string {
+	d, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	if len(d) > diffValueMaxLength {
+		return string(d[:diffValueMaxLength]) + "..."
+	}
+	return string(d)
+}
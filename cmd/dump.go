@@ -6,14 +6,18 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 
 	"github.com/elastic/elastic-package/internal/cobraext"
 	"github.com/elastic/elastic-package/internal/dump"
 	"github.com/elastic/elastic-package/internal/elasticsearch"
+	"github.com/elastic/elastic-package/internal/fields"
 	"github.com/elastic/elastic-package/internal/install"
 	"github.com/elastic/elastic-package/internal/kibana"
+	"github.com/elastic/elastic-package/internal/packages"
 	"github.com/elastic/elastic-package/internal/stack"
 )
 
@@ -31,6 +35,14 @@ If no flag is provided, by default this command dumps all agent policies created
 
 If --package flag is provided, this command dumps all agent policies that the given package has been assigned to it.`
 
+const dumpPipelineLongDescription = `Use this command to dump an ingest pipeline installed in Elasticsearch as package files.
+
+Use this command as a starting point to include an existing ingest pipeline in a package. The pipeline with the given name is stored as the default pipeline in the elasticsearch/ingest_pipeline directory of the output path, following the layout used in packages and data streams. Pipelines referenced by pipeline processors are dumped too, recursively, and the references are replaced by the IngestPipeline template used in packages. Metadata added by Fleet is removed.`
+
+const dumpFieldsLongDescription = `Use this command to dump the field definitions used to validate the documents of a data stream.
+
+Use this command to debug the field definitions as the validator sees them, after resolving the fields imported from external sources, like ECS. The definitions are written in YAML to the standard output.`
+
 func setupDumpCommand() *cobraext.Command {
 	dumpInstalledObjectsCmd := &cobra.Command{
 		Use:   "installed-objects",
@@ -53,6 +65,25 @@ func setupDumpCommand() *cobraext.Command {
 	dumpAgentPoliciesCmd.Flags().StringP(cobraext.AgentPolicyFlagName, "", "", cobraext.AgentPolicyDescription)
 	dumpAgentPoliciesCmd.Flags().StringP(cobraext.PackageFlagName, cobraext.PackageFlagShorthand, "", cobraext.PackageFlagDescription)
 
+	dumpPipelineCmd := &cobra.Command{
+		Use:   "pipeline <name>",
+		Short: "Dump an ingest pipeline as package files",
+		Long:  dumpPipelineLongDescription,
+		Args:  cobra.ExactArgs(1),
+		RunE:  dumpPipelineCmdAction,
+	}
+	dumpPipelineCmd.Flags().Bool(cobraext.TLSSkipVerifyFlagName, false, cobraext.TLSSkipVerifyFlagDescription)
+
+	dumpFieldsCmd := &cobra.Command{
+		Use:    "fields",
+		Short:  "Dump the effective field definitions of a data stream",
+		Long:   dumpFieldsLongDescription,
+		Args:   cobra.NoArgs,
+		RunE:   dumpFieldsCmdAction,
+		Hidden: true,
+	}
+	dumpFieldsCmd.Flags().String(cobraext.DataStreamFlagName, "", cobraext.DumpFieldsDataStreamFlagDescription)
+
 	cmd := &cobra.Command{
 		Use:   "dump",
 		Short: "Dump package assets",
@@ -63,6 +94,8 @@ func setupDumpCommand() *cobraext.Command {
 
 	cmd.AddCommand(dumpInstalledObjectsCmd)
 	cmd.AddCommand(dumpAgentPoliciesCmd)
+	cmd.AddCommand(dumpPipelineCmd)
+	cmd.AddCommand(dumpFieldsCmd)
 
 	return cobraext.NewCommand(cmd, cobraext.ContextGlobal)
 }
@@ -198,3 +231,78 @@ func dumpAgentPoliciesCmdAction(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+func dumpPipelineCmdAction(cmd *cobra.Command, args []string) error {
+	pipelineName := args[0]
+
+	outputPath, err := cmd.Flags().GetString(cobraext.DumpOutputFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.DumpOutputFlagName)
+	}
+
+	tlsSkipVerify, err := cmd.Flags().GetBool(cobraext.TLSSkipVerifyFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.TLSSkipVerifyFlagName)
+	}
+
+	profile, err := cobraext.GetProfileFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	var clientOptions []elasticsearch.ClientOption
+	if tlsSkipVerify {
+		clientOptions = append(clientOptions, elasticsearch.OptionWithSkipTLSVerify())
+	}
+	client, err := stack.NewElasticsearchClientFromProfile(profile, clientOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to initialize Elasticsearch client: %w", err)
+	}
+
+	dumper := dump.NewIngestPipelineDumper(client.API)
+	count, err := dumper.DumpByName(cmd.Context(), outputPath, pipelineName)
+	if err != nil {
+		return fmt.Errorf("dump failed: %w", err)
+	}
+	cmd.Printf("Dumped %d ingest pipelines for pipeline %s to %s\n", count, pipelineName, outputPath)
+	return nil
+}
+
+func dumpFieldsCmdAction(cmd *cobra.Command, args []string) error {
+	dataStream, err := cmd.Flags().GetString(cobraext.DataStreamFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.DataStreamFlagName)
+	}
+
+	packageRoot, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+	manifest, err := packages.ReadPackageManifestFromPackageRoot(packageRoot)
+	if err != nil {
+		return fmt.Errorf("reading package manifest failed: %w", err)
+	}
+
+	fieldsParentDir := packageRoot
+	if dataStream != "" {
+		fieldsParentDir = filepath.Join(packageRoot, "data_stream", dataStream)
+		if _, err := os.Stat(fieldsParentDir); err != nil {
+			return fmt.Errorf("data stream %q not found: %w", dataStream, err)
+		}
+	}
+
+	validator, err := fields.CreateValidatorForDirectory(fieldsParentDir,
+		fields.WithSpecVersion(manifest.SpecVersion),
+		fields.WithEnabledImportAllECSSChema(true),
+	)
+	if err != nil {
+		return fmt.Errorf("creating fields validator failed (path: %s): %w", fieldsParentDir, err)
+	}
+
+	d, err := validator.DumpSchema()
+	if err != nil {
+		return err
+	}
+	_, err = cmd.OutOrStdout().Write(d)
+	return err
+}
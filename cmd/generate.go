@@ -0,0 +1,90 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/elastic-package/internal/cobraext"
+	"github.com/elastic/elastic-package/internal/elasticsearch"
+	"github.com/elastic/elastic-package/internal/install"
+	"github.com/elastic/elastic-package/internal/packages"
+	"github.com/elastic/elastic-package/internal/sampleevent"
+	"github.com/elastic/elastic-package/internal/stack"
+)
+
+const generateLongDescription = `Use this command to generate package files from data available in the Elastic Stack.`
+
+const generateSampleEventLongDescription = `Use this command to generate the sample event of a data stream from data ingested in the Elastic Stack.
+
+The stack must be running and data must have been ingested in the data stream, for example with a system test run with --defer-cleanup. Recent documents of the data stream are retrieved, fields that change on every execution or are managed by the agent (like ids or the ingestion timestamp) are removed, and the most recent document that is valid according to the field definitions of the data stream is written to its sample_event.json file. The command fails if none of the documents is valid.`
+
+func setupGenerateCommand() *cobraext.Command {
+	generateSampleEventCmd := &cobra.Command{
+		Use:   "sample-event",
+		Short: "Generate the sample event of a data stream",
+		Long:  generateSampleEventLongDescription,
+		Args:  cobra.NoArgs,
+		RunE:  generateSampleEventCmdAction,
+	}
+	generateSampleEventCmd.Flags().String(cobraext.DataStreamFlagName, "", cobraext.GenerateSampleEventDataStreamFlagDescription)
+	generateSampleEventCmd.MarkFlagRequired(cobraext.DataStreamFlagName)
+	generateSampleEventCmd.Flags().Bool(cobraext.TLSSkipVerifyFlagName, false, cobraext.TLSSkipVerifyFlagDescription)
+
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate package files",
+		Long:  generateLongDescription,
+	}
+	cmd.AddCommand(generateSampleEventCmd)
+	cmd.PersistentFlags().StringP(cobraext.ProfileFlagName, "p", "", fmt.Sprintf(cobraext.ProfileFlagDescription, install.ProfileNameEnvVar))
+
+	return cobraext.NewCommand(cmd, cobraext.ContextPackage)
+}
+
+func generateSampleEventCmdAction(cmd *cobra.Command, args []string) error {
+	dataStream, err := cmd.Flags().GetString(cobraext.DataStreamFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.DataStreamFlagName)
+	}
+
+	tlsSkipVerify, err := cmd.Flags().GetBool(cobraext.TLSSkipVerifyFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.TLSSkipVerifyFlagName)
+	}
+
+	packageRoot, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	profile, err := cobraext.GetProfileFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	var clientOptions []elasticsearch.ClientOption
+	if tlsSkipVerify {
+		clientOptions = append(clientOptions, elasticsearch.OptionWithSkipTLSVerify())
+	}
+	client, err := stack.NewElasticsearchClientFromProfile(profile, clientOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to initialize Elasticsearch client: %w", err)
+	}
+
+	path, err := sampleevent.Generate(cmd.Context(), sampleevent.Options{
+		API:         client.API,
+		PackageRoot: packageRoot,
+		DataStream:  dataStream,
+	})
+	if err != nil {
+		return fmt.Errorf("generating sample event failed: %w", err)
+	}
+
+	cmd.Printf("Sample event written to %s\n", path)
+	return nil
+}
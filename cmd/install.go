@@ -20,7 +20,9 @@ import (
 
 const installLongDescription = `Use this command to install the package in Kibana.
 
-The command uses Kibana API to install the package in Kibana. The package must be exposed via the Package Registry or built locally in zip format so they can be installed using --zip parameter. Zip packages can be installed directly in Kibana >= 8.7.0. More details in this [HOWTO guide](https://github.com/elastic/elastic-package/blob/main/docs/howto/install_package.md).`
+The command uses Kibana API to install the package in Kibana. The package must be exposed via the Package Registry or built locally in zip format so they can be installed using --zip parameter. Zip packages can be installed directly in Kibana >= 8.7.0.
+
+Before installing the package, the command verifies that the version of the stack satisfies the Kibana version conditions of the package manifest, and fails if it doesn't. Use the --force flag to install the package anyway. More details in this [HOWTO guide](https://github.com/elastic/elastic-package/blob/main/docs/howto/install_package.md).`
 
 func setupInstallCommand() *cobraext.Command {
 	cmd := &cobra.Command{
@@ -36,6 +38,7 @@ func setupInstallCommand() *cobraext.Command {
 	cmd.Flags().Bool(cobraext.BuildSkipValidationFlagName, false, cobraext.BuildSkipValidationFlagDescription)
 	cmd.Flags().StringP(cobraext.ProfileFlagName, "p", "", fmt.Sprintf(cobraext.ProfileFlagDescription, install.ProfileNameEnvVar))
 	cmd.Flags().Bool(cobraext.TLSSkipVerifyFlagName, false, cobraext.TLSSkipVerifyFlagDescription)
+	cmd.Flags().Bool(cobraext.InstallForceFlagName, false, cobraext.InstallForceFlagDescription)
 
 	return cobraext.NewCommand(cmd, cobraext.ContextPackage)
 }
@@ -53,6 +56,10 @@ func installCommandAction(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		return cobraext.FlagParsingError(err, cobraext.BuildSkipValidationFlagName)
 	}
+	force, err := cmd.Flags().GetBool(cobraext.InstallForceFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.InstallForceFlagName)
+	}
 
 	profile, err := cobraext.GetProfileFlag(cmd)
 	if err != nil {
@@ -113,6 +120,36 @@ func installCommandAction(cmd *cobra.Command, _ []string) error {
 		return nil
 	}
 
+	if force {
+		cmd.Println("Skipping compatibility check with the stack version")
+	} else {
+		err = checkStackCompatibility(cmd, installer, kibanaClient)
+		if err != nil {
+			return err
+		}
+	}
+
 	_, err = installer.Install(cmd.Context())
 	return err
 }
+
+// checkStackCompatibility checks that the version of the stack satisfies the Kibana version
+// conditions of the package, so installation fails early with a meaningful error.
+func checkStackCompatibility(cmd *cobra.Command, packageInstaller installer.Installer, kibanaClient *kibana.Client) error {
+	manifest, err := packageInstaller.Manifest(cmd.Context())
+	if err != nil {
+		return err
+	}
+	versionInfo, err := kibanaClient.Version()
+	if err != nil {
+		return fmt.Errorf("failed to get kibana version: %w", err)
+	}
+
+	condition := fmt.Sprintf("kibana.version=%s", versionInfo.Version())
+	err = packages.CheckConditions(*manifest, []string{condition})
+	if err != nil {
+		return fmt.Errorf("package %s %s is not compatible with the stack version %s (conditions: kibana.version %s), use --%s to install it anyway: %w",
+			manifest.Name, manifest.Version, versionInfo.Version(), manifest.Conditions.Kibana.Version, cobraext.InstallForceFlagName, err)
+	}
+	return nil
+}
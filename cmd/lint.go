@@ -7,6 +7,8 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -19,7 +21,15 @@ import (
 
 const lintLongDescription = `Use this command to validate the contents of a package using the package specification (see: https://github.com/elastic/package-spec).
 
-The command ensures that the package is aligned with the package spec and the README file is up-to-date with its template (if present).`
+The command ensures that the package is aligned with the package spec and the README file is up-to-date with its template (if present).
+
+Additional checks are performed on the coherence of the package contents, such as data streams using the time_series index mode declaring dimension fields.
+
+Some checks are optional and can be enabled with --enable-checks, such as "on_failure_handling", that checks that the ingest pipelines of the data streams handle failures.
+
+When a git reference is given with --base-ref, the package is also compared with its version in this reference to find changes that break upgrades, such as changes in the type of dimension fields.
+
+With --fix, trailing whitespace is removed from the YAML files of the package, unless it is part of the value of some block scalar.`
 
 func setupLintCommand() *cobraext.Command {
 	cmd := &cobra.Command{
@@ -29,8 +39,11 @@ func setupLintCommand() *cobraext.Command {
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := cobraext.ComposeCommandActions(cmd, args,
+				fixLintIssuesCommandAction,
 				lintCommandAction,
 				validateSourceCommandAction,
+				validateSemanticsCommandAction,
+				validateChangesCommandAction,
 			)
 			if err != nil {
 				return err
@@ -40,9 +53,36 @@ func setupLintCommand() *cobraext.Command {
 		},
 	}
 
+	cmd.Flags().String(cobraext.BaseRefFlagName, "", cobraext.BaseRefFlagDescription)
+	cmd.Flags().Bool(cobraext.FixFlagName, false, cobraext.FixFlagDescription)
+	cmd.Flags().StringSlice(cobraext.EnableChecksFlagName, nil, fmt.Sprintf(cobraext.EnableChecksFlagDescription, strings.Join(validation.OptionalSemanticChecks(), ", ")))
+
 	return cobraext.NewCommand(cmd, cobraext.ContextPackage)
 }
 
+func fixLintIssuesCommandAction(cmd *cobra.Command, args []string) error {
+	fix, err := cmd.Flags().GetBool(cobraext.FixFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.FixFlagName)
+	}
+	if !fix {
+		return nil
+	}
+
+	packageRootPath, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return err
+	}
+	fixed, err := validation.FixTrailingWhitespace(packageRootPath)
+	if err != nil {
+		return fmt.Errorf("fixing trailing whitespace failed: %w", err)
+	}
+	for _, path := range fixed {
+		cmd.Printf("Fixed: removed trailing whitespace in %s\n", path)
+	}
+	return nil
+}
+
 func lintCommandAction(cmd *cobra.Command, args []string) error {
 	cmd.Println("Lint the package")
 
@@ -74,6 +114,76 @@ func validateSourceCommandAction(cmd *cobra.Command, args []string) error {
 		logger.Infof("Skipped errors: %v", skipped)
 	}
 	if errs != nil {
+		// Keys removed in newer versions of the spec fail with errors that are not
+		// always clear, look for them to give some guidance.
+		removedKeys, err := validation.CheckRemovedManifestKeys(packageRootPath)
+		if err != nil {
+			logger.Debugf("Failed to look for removed manifest keys: %v", err)
+		} else if len(removedKeys) > 0 {
+			errs = errors.Join(errs, removedKeys)
+		}
+		return fmt.Errorf("linting package failed: %w", errs)
+	}
+	return nil
+}
+
+func validateSemanticsCommandAction(cmd *cobra.Command, args []string) error {
+	enabledChecks, err := cmd.Flags().GetStringSlice(cobraext.EnableChecksFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.EnableChecksFlagName)
+	}
+
+	packageRootPath, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return err
+	}
+	issues, err := validation.ValidateSemanticsFromPath(packageRootPath, enabledChecks...)
+	if err != nil {
+		return fmt.Errorf("checking package semantics failed: %w", err)
+	}
+	for _, warning := range issues.Warnings {
+		logger.Warn(warning.Error())
+	}
+	if len(issues.Errors) > 0 {
+		return fmt.Errorf("linting package failed: %w", issues.Errors)
+	}
+	return nil
+}
+
+func validateChangesCommandAction(cmd *cobra.Command, args []string) error {
+	baseRef, err := cmd.Flags().GetString(cobraext.BaseRefFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.BaseRefFlagName)
+	}
+	if baseRef == "" {
+		return nil
+	}
+
+	packageRootPath, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return err
+	}
+
+	basePackageRoot, err := os.MkdirTemp("", "elastic-package-base-")
+	if err != nil {
+		return fmt.Errorf("failed to create directory for base package: %w", err)
+	}
+	defer os.RemoveAll(basePackageRoot)
+
+	err = validation.ExtractPackageFromRef(packageRootPath, baseRef, basePackageRoot)
+	if errors.Is(err, validation.ErrPackageNotFoundInRef) {
+		logger.Infof("Package not found in %q, skipping checks for breaking changes", baseRef)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read package from %q: %w", baseRef, err)
+	}
+
+	errs, err := validation.CheckDimensionTypeChanges(basePackageRoot, packageRootPath)
+	if err != nil {
+		return fmt.Errorf("checking changes in package failed: %w", err)
+	}
+	if len(errs) > 0 {
 		return fmt.Errorf("linting package failed: %w", errs)
 	}
 	return nil
@@ -19,6 +19,7 @@ import (
 	"github.com/elastic/elastic-package/internal/configuration/locations"
 	"github.com/elastic/elastic-package/internal/install"
 	"github.com/elastic/elastic-package/internal/profile"
+	"github.com/elastic/elastic-package/internal/stack"
 )
 
 // jsonFormat is the format for JSON output
@@ -181,9 +182,43 @@ User profiles can be configured with a "config.yml" file in the profile director
 		},
 	}
 
+	profileDiffCommand := &cobra.Command{
+		Use:   "diff [profile] [profile]",
+		Short: "Show the differences between the settings of two profiles",
+		Long: `Use this command to compare the effective settings of two profiles.
+
+It shows the settings of the profile configuration files, with their defaults, and the connection settings of the stack (Elasticsearch and Kibana hosts, CA certificate...) that have different values in each profile.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var settings []map[string]string
+			for _, profileName := range args {
+				p, err := profile.LoadProfile(profileName)
+				if err != nil {
+					return fmt.Errorf("cannot load profile %q: %w", profileName, err)
+				}
+				if p == nil {
+					return fmt.Errorf("cannot load profile %q", profileName)
+				}
+				s, err := stack.EffectiveSettings(p)
+				if err != nil {
+					return fmt.Errorf("cannot read settings of profile %q: %w", profileName, err)
+				}
+				settings = append(settings, s)
+			}
+
+			diff := profile.DiffSettings(settings[0], settings[1])
+			if len(diff) == 0 {
+				cmd.Printf("Profiles %q and %q have the same settings.\n", args[0], args[1])
+				return nil
+			}
+			return formatDiffTable(args[0], args[1], diff)
+		},
+	}
+
 	profileCommand.AddCommand(
 		profileNewCommand,
 		profileDeleteCommand,
+		profileDiffCommand,
 		profileListCommand,
 		profileUseCommand,
 	)
@@ -228,6 +263,37 @@ func formatTable(profilesDir string, profileList []profile.Metadata, currentProf
 	return nil
 }
 
+func formatDiffTable(nameA, nameB string, diff []profile.SettingDifference) error {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Setting", nameA, nameB})
+	table.SetHeaderColor(
+		twColor(tablewriter.Colors{tablewriter.Bold}),
+		twColor(tablewriter.Colors{tablewriter.Bold}),
+		twColor(tablewriter.Colors{tablewriter.Bold}),
+	)
+	table.SetColumnColor(
+		twColor(tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}),
+		tablewriter.Colors{},
+		tablewriter.Colors{},
+	)
+	table.SetAutoMergeCells(false)
+	table.SetAutoWrapText(false)
+	table.SetRowLine(true)
+	for _, d := range diff {
+		table.Append([]string{d.Name, settingValue(d.A, d.FoundA), settingValue(d.B, d.FoundB)})
+	}
+	table.Render()
+
+	return nil
+}
+
+func settingValue(value string, found bool) string {
+	if !found {
+		return "(not set)"
+	}
+	return value
+}
+
 func profileToList(profilesDir string, profiles []profile.Metadata, currentProfile string) [][]string {
 	var profileList [][]string
 	for _, profile := range profiles {
@@ -23,10 +23,12 @@ var commands = []*cobraext.Command{
 	setupCheckCommand(),
 	setupCleanCommand(),
 	setupCreateCommand(),
+	setupDiffCommand(),
 	setupDumpCommand(),
 	setupEditCommand(),
 	setupExportCommand(),
 	setupFormatCommand(),
+	setupGenerateCommand(),
 	setupInstallCommand(),
 	setupLintCommand(),
 	setupPromoteCommand(),
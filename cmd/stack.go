@@ -7,6 +7,7 @@ package cmd
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jedib0t/go-pretty/table"
 
@@ -43,11 +44,21 @@ You can run your own custom images for Elasticsearch, Kibana or Elastic Agent, s
 
 Be aware that a common issue while trying to boot up the stack is that your Docker environments settings are too low in terms of memory threshold.
 
+Use the --services flag to boot up only some of the services of the stack, for example "--services elasticsearch,kibana" when Elastic Agents are not needed. The services these ones depend on are also started.
+
+The version of the stack booted up is kept in the profile, and used by other commands as "stack down" or "stack dump". To test a package with multiple versions of the stack, boot up and tear down the stack for each version, for example: 'for v in 8.11.4 8.12.2 8.13.4; do elastic-package stack up -d --version $v && elastic-package test; elastic-package stack down; done'.
+
 To expose local packages in the Package Registry, build them first and boot up the stack from inside of the Git repository containing the package (e.g. elastic/integrations). They will be copied to the development stack (~/.elastic-package/stack/development) and used to build a custom Docker image of the Package Registry. Starting with Elastic stack version >= 8.7.0, it is not mandatory to be available local packages in the Package Registry to run the tests.
 
 For details on how to connect the service with the Elastic stack, see the [service command](https://github.com/elastic/elastic-package/blob/main/README.md#elastic-package-service).
 
-You can customize your stack using profile settings, see [Elastic Package profiles](https://github.com/elastic/elastic-package/blob/main/README.md#elastic-package-profiles-1) section. These settings can be also overriden with the --parameter flag. Settings configured this way are not persisted.`
+You can customize your stack using profile settings, see [Elastic Package profiles](https://github.com/elastic/elastic-package/blob/main/README.md#elastic-package-profiles-1) section. These settings can be also overriden with the --parameter flag. Settings configured this way are not persisted.
+
+Progress of the steps that can take long is reported in the error output, with a spinner when it is a terminal. Use the --quiet flag to disable it.`
+
+const stackStatusLongDescription = `Show status of the stack services.
+
+Use the --wait flag to wait till Elasticsearch, Kibana and Fleet are ready to be used, what can be useful in scripts that run tests after booting up the stack. The command fails if the services are not ready before the time set with --timeout, showing the health reported by each service.`
 
 const stackShellinitLongDescription = `Use this command to export to the current shell the configuration of the stack managed by elastic-package.
 
@@ -114,6 +125,11 @@ func setupStackCommand() *cobraext.Command {
 			}
 			profile.RuntimeOverrides(userParameters)
 
+			progressReporter, err := cobraext.GetProgressReporter(cmd)
+			if err != nil {
+				return err
+			}
+
 			cmd.Printf("Using profile %s.\n", profile.ProfilePath)
 			err = provider.BootUp(cmd.Context(), stack.Options{
 				DaemonMode:   daemonMode,
@@ -121,6 +137,7 @@ func setupStackCommand() *cobraext.Command {
 				Services:     services,
 				Profile:      profile,
 				Printer:      cmd,
+				Progress:     progressReporter,
 			})
 			if err != nil {
 				return fmt.Errorf("booting up the stack failed: %w", err)
@@ -136,6 +153,7 @@ func setupStackCommand() *cobraext.Command {
 	upCommand.Flags().StringP(cobraext.StackVersionFlagName, "", install.DefaultStackVersion, cobraext.StackVersionFlagDescription)
 	upCommand.Flags().String(cobraext.StackProviderFlagName, "", fmt.Sprintf(cobraext.StackProviderFlagDescription, strings.Join(stack.SupportedProviders, ", ")))
 	upCommand.Flags().StringSliceP(cobraext.StackUserParameterFlagName, cobraext.StackUserParameterFlagShorthand, nil, cobraext.StackUserParameterDescription)
+	upCommand.Flags().Bool(cobraext.QuietFlagName, false, cobraext.QuietFlagDescription)
 
 	downCommand := &cobra.Command{
 		Use:   "down",
@@ -274,6 +292,7 @@ func setupStackCommand() *cobraext.Command {
 	statusCommand := &cobra.Command{
 		Use:   "status",
 		Short: "Show status of the stack services",
+		Long:  stackStatusLongDescription,
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			profile, err := cobraext.GetProfileFlag(cmd)
@@ -296,9 +315,33 @@ func setupStackCommand() *cobraext.Command {
 
 			cmd.Println("Status of Elastic stack services:")
 			printStatus(cmd, servicesStatus)
+
+			wait, err := cmd.Flags().GetBool(cobraext.StackStatusWaitFlagName)
+			if err != nil {
+				return cobraext.FlagParsingError(err, cobraext.StackStatusWaitFlagName)
+			}
+			if !wait {
+				return nil
+			}
+			timeout, err := cmd.Flags().GetDuration(cobraext.StackStatusWaitTimeoutFlagName)
+			if err != nil {
+				return cobraext.FlagParsingError(err, cobraext.StackStatusWaitTimeoutFlagName)
+			}
+
+			cmd.Println("Waiting for Elastic stack services to be ready...")
+			health, err := stack.WaitReady(cmd.Context(), stack.Options{
+				Profile: profile,
+				Printer: cmd,
+			}, timeout)
+			printHealth(cmd, health)
+			if err != nil {
+				return fmt.Errorf("waiting for the stack failed: %w", err)
+			}
 			return nil
 		},
 	}
+	statusCommand.Flags().Bool(cobraext.StackStatusWaitFlagName, false, cobraext.StackStatusWaitFlagDescription)
+	statusCommand.Flags().Duration(cobraext.StackStatusWaitTimeoutFlagName, 10*time.Minute, cobraext.StackStatusWaitTimeoutFlagDescription)
 
 	cmd := &cobra.Command{
 		Use:   "stack",
@@ -358,3 +401,17 @@ func printStatus(cmd *cobra.Command, servicesStatus []stack.ServiceStatus) {
 	t.SetStyle(table.StyleRounded)
 	cmd.Println(t.Render())
 }
+
+func printHealth(cmd *cobra.Command, health []stack.ServiceHealth) {
+	if len(health) == 0 {
+		return
+	}
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"Service", "Ready", "Details"})
+
+	for _, service := range health {
+		t.AppendRow(table.Row{service.Name, service.Ready, service.Message})
+	}
+	t.SetStyle(table.StyleRounded)
+	cmd.Println(t.Render())
+}
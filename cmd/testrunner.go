@@ -5,18 +5,23 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/dustin/go-humanize"
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 
 	"github.com/elastic/elastic-package/internal/cobraext"
 	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/fields"
 	"github.com/elastic/elastic-package/internal/install"
 	"github.com/elastic/elastic-package/internal/logger"
 	"github.com/elastic/elastic-package/internal/packages"
@@ -25,6 +30,7 @@ import (
 	"github.com/elastic/elastic-package/internal/testrunner"
 	"github.com/elastic/elastic-package/internal/testrunner/reporters/formats"
 	"github.com/elastic/elastic-package/internal/testrunner/reporters/outputs"
+	"github.com/elastic/elastic-package/internal/testrunner/runners"
 	"github.com/elastic/elastic-package/internal/testrunner/runners/asset"
 	"github.com/elastic/elastic-package/internal/testrunner/runners/pipeline"
 	"github.com/elastic/elastic-package/internal/testrunner/runners/policy"
@@ -54,12 +60,24 @@ These tests allow you to test a package's ability to ingest data end-to-end.
 
 For details on how to configure and run system tests, review the [HOWTO guide](https://github.com/elastic/elastic-package/blob/main/docs/howto/system_testing.md).
 
+While system tests run, the steps that can take long, as installing the package or waiting for agents and documents, are reported in the error output, with a spinner when it is a terminal. Use the ` + "`--quiet`" + ` flag to disable it.
+
 #### Policy Tests
 These tests allow you to test different configuration options and the policies they generate, without needing to run a full scenario.
 
-For details on how to configure and run policy tests, review the [HOWTO guide](https://github.com/elastic/elastic-package/blob/main/docs/howto/policy_testing.md).`
+For details on how to configure and run policy tests, review the [HOWTO guide](https://github.com/elastic/elastic-package/blob/main/docs/howto/policy_testing.md).
+
+#### Test Reports
+Test results are reported in a human-readable format by default. Use the ` + "`--report-format`" + ` flag to select a different format: ` + "`xUnit`" + `, or ` + "`json`" + ` to get a stable schema with the name, data stream, duration, status and errors of each test, including the field and definition location of field validation errors. Use ` + "`--report-output file`" + ` to write the reports to the ` + "`build/test-results`" + ` directory.
+
+#### Test Coverage
+Use the ` + "`coverage`" + ` subcommand to get a report of the test coverage of the package, without running the tests. It lists the data streams with pipeline and system tests, and the fields exercised by the expected results of pipeline tests and by sample events. Documents ingested by system tests are not considered. External fields, such as the ECS ones, are not resolved, so they are not counted in the fields coverage. Use ` + "`--report-format json`" + ` to get the report in JSON format, for example to check it in CI pipelines.
+
+#### Testing Changes
+Use the ` + "`--changed-only`" + ` flag to run tests only if the package changed since the git reference given with ` + "`--base-ref`" + ` (` + "`main`" + ` by default), including uncommitted changes. If only files in some data streams changed, tests are executed only for these data streams. Changes in other files of the package, or in files of the repository that don't belong to any package, cause all the tests of the package to be executed.`
 
 func setupTestCommand() *cobraext.Command {
+	var testCommands []*cobra.Command
 	cmd := &cobra.Command{
 		Use:   "test",
 		Short: "Run test suite for the package",
@@ -68,7 +86,7 @@ func setupTestCommand() *cobraext.Command {
 			if len(args) > 0 {
 				return fmt.Errorf("unsupported test type: %s", args[0])
 			}
-			return cobraext.ComposeCommandsParentContext(parent, args, parent.Commands()...)
+			return cobraext.ComposeCommandsParentContext(parent, args, testCommands...)
 		},
 	}
 
@@ -77,10 +95,13 @@ func setupTestCommand() *cobraext.Command {
 	cmd.PersistentFlags().BoolP(cobraext.TestCoverageFlagName, "", false, cobraext.TestCoverageFlagDescription)
 	cmd.PersistentFlags().StringP(cobraext.TestCoverageFormatFlagName, "", "cobertura", fmt.Sprintf(cobraext.TestCoverageFormatFlagDescription, strings.Join(testrunner.CoverageFormatsList(), ",")))
 	cmd.PersistentFlags().StringP(cobraext.ProfileFlagName, "p", "", fmt.Sprintf(cobraext.ProfileFlagDescription, install.ProfileNameEnvVar))
+	cmd.PersistentFlags().Bool(cobraext.TestChangedOnlyFlagName, false, cobraext.TestChangedOnlyFlagDescription)
+	cmd.PersistentFlags().String(cobraext.BaseRefFlagName, "main", cobraext.TestChangedOnlyBaseRefFlagDescription)
 
 	// Just used in pipeline and system tests
 	// Keep it here for backwards compatibility
 	cmd.PersistentFlags().DurationP(cobraext.DeferCleanupFlagName, "", 0, cobraext.DeferCleanupFlagDescription)
+	cmd.PersistentFlags().Bool(cobraext.QuietFlagName, false, cobraext.QuietFlagDescription)
 
 	assetCmd := getTestRunnerAssetCommand()
 	cmd.AddCommand(assetCmd)
@@ -97,6 +118,12 @@ func setupTestCommand() *cobraext.Command {
 	policyCmd := getTestRunnerPolicyCommand()
 	cmd.AddCommand(policyCmd)
 
+	// Coverage is a report about the tests, it is not run with the rest of the tests.
+	testCommands = []*cobra.Command{assetCmd, pipelineCmd, policyCmd, staticCmd, systemCmd}
+
+	coverageCmd := getTestCoverageCommand()
+	cmd.AddCommand(coverageCmd)
+
 	return cobraext.NewCommand(cmd, cobraext.ContextPackage)
 }
 
@@ -158,6 +185,14 @@ func testRunnerAssetCommandAction(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("reading package manifest failed (path: %s): %w", packageRootPath, err)
 	}
 
+	_, changed, err := changedOnlyDataStreams(cmd, testType, packageRootPath, nil)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+
 	ctx, stop := signal.Enable(cmd.Context(), logger.Info)
 	defer stop()
 
@@ -253,6 +288,14 @@ func testRunnerStaticCommandAction(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	dataStreams, changed, err := changedOnlyDataStreams(cmd, testType, packageRootPath, dataStreams)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+
 	ctx, stop := signal.Enable(cmd.Context(), logger.Info)
 	defer stop()
 
@@ -290,6 +333,12 @@ func getTestRunnerPipelineCommand() *cobra.Command {
 	cmd.Flags().BoolP(cobraext.FailOnMissingFlagName, "m", false, cobraext.FailOnMissingFlagDescription)
 	cmd.Flags().BoolP(cobraext.GenerateTestResultFlagName, "g", false, cobraext.GenerateTestResultFlagDescription)
 	cmd.Flags().StringSliceP(cobraext.DataStreamsFlagName, "d", nil, cobraext.DataStreamsFlagDescription)
+	cmd.Flags().Bool(cobraext.TestCoverageFieldsFlagName, false, cobraext.TestCoverageFieldsFlagDescription)
+	cmd.Flags().String(cobraext.TestCoverageHTMLFlagName, "", cobraext.TestCoverageHTMLFlagDescription)
+	cmd.Flags().Int(cobraext.TestSlowestFieldsFlagName, 0, cobraext.TestSlowestFieldsFlagDescription)
+	cmd.Flags().Duration(cobraext.TestValidationTimeBudgetFlagName, 0, cobraext.TestValidationTimeBudgetFlagDescription)
+	cmd.Flags().String(cobraext.TestValidationMemoryBudgetFlagName, "", cobraext.TestValidationMemoryBudgetFlagDescription)
+	cmd.Flags().Int(cobraext.TestWorkersFlagName, 1, cobraext.TestWorkersFlagDescription)
 
 	return cmd
 }
@@ -342,6 +391,45 @@ func testRunnerPipelineCommandAction(cmd *cobra.Command, args []string) error {
 		return cobraext.FlagParsingError(err, cobraext.DeferCleanupFlagName)
 	}
 
+	coverageFields, err := cmd.Flags().GetBool(cobraext.TestCoverageFieldsFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.TestCoverageFieldsFlagName)
+	}
+
+	coverageHTML, err := cmd.Flags().GetString(cobraext.TestCoverageHTMLFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.TestCoverageHTMLFlagName)
+	}
+
+	slowestFields, err := cmd.Flags().GetInt(cobraext.TestSlowestFieldsFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.TestSlowestFieldsFlagName)
+	}
+
+	var validationBudget fields.ValidationBudget
+	validationBudget.Time, err = cmd.Flags().GetDuration(cobraext.TestValidationTimeBudgetFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.TestValidationTimeBudgetFlagName)
+	}
+	memoryBudget, err := cmd.Flags().GetString(cobraext.TestValidationMemoryBudgetFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.TestValidationMemoryBudgetFlagName)
+	}
+	if memoryBudget != "" {
+		validationBudget.Memory, err = humanize.ParseBytes(memoryBudget)
+		if err != nil {
+			return cobraext.FlagParsingError(err, cobraext.TestValidationMemoryBudgetFlagName)
+		}
+	}
+
+	workers, err := cmd.Flags().GetInt(cobraext.TestWorkersFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.TestWorkersFlagName)
+	}
+	if workers < 1 {
+		return cobraext.FlagParsingError(fmt.Errorf("number of workers must be at least 1, found %d", workers), cobraext.TestWorkersFlagName)
+	}
+
 	packageRootPath, found, err := packages.FindPackageRoot()
 	if !found {
 		return errors.New("package root not found")
@@ -355,6 +443,14 @@ func testRunnerPipelineCommandAction(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	dataStreams, changed, err := changedOnlyDataStreams(cmd, testType, packageRootPath, dataStreams)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+
 	ctx, stop := signal.Enable(cmd.Context(), logger.Info)
 	defer stop()
 
@@ -388,6 +484,10 @@ func testRunnerPipelineCommandAction(cmd *cobra.Command, args []string) error {
 		CoverageType:       testCoverageFormat,
 		DeferCleanup:       deferCleanup,
 		GlobalTestConfig:   globalTestConfig.Pipeline,
+		WithFieldsCoverage: coverageFields || coverageHTML != "",
+		SlowestFields:      slowestFields,
+		ValidationBudget:   validationBudget,
+		Workers:            workers,
 	})
 
 	results, err := testrunner.RunSuite(ctx, runner)
@@ -395,6 +495,20 @@ func testRunnerPipelineCommandAction(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if coverageFields {
+		cmd.Print(runner.FieldsCoverageSummary())
+	}
+	if coverageHTML != "" {
+		err := runner.WriteFieldsCoverageHTML(coverageHTML)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Fields coverage report written in %s\n", coverageHTML)
+	}
+	if slowestFields > 0 {
+		cmd.Print(runner.SlowestFieldsSummary())
+	}
+
 	return processResults(results, testType, reportFormat, reportOutput, packageRootPath, manifest.Name, manifest.Type, testCoverageFormat, testCoverage)
 }
 
@@ -411,6 +525,8 @@ func getTestRunnerSystemCommand() *cobra.Command {
 	cmd.Flags().BoolP(cobraext.GenerateTestResultFlagName, "g", false, cobraext.GenerateTestResultFlagDescription)
 	cmd.Flags().StringSliceP(cobraext.DataStreamsFlagName, "d", nil, cobraext.DataStreamsFlagDescription)
 	cmd.Flags().String(cobraext.VariantFlagName, "", cobraext.VariantFlagDescription)
+	cmd.Flags().Int(cobraext.ContainerLogsTailFlagName, system.DefaultContainerLogsTail, cobraext.ContainerLogsTailFlagDescription)
+	cmd.Flags().Bool(cobraext.ReuseAgentPolicyFlagName, false, cobraext.ReuseAgentPolicyFlagDescription)
 
 	cmd.Flags().String(cobraext.ConfigFileFlagName, "", cobraext.ConfigFileFlagDescription)
 	cmd.Flags().Bool(cobraext.SetupFlagName, false, cobraext.SetupFlagDescription)
@@ -486,6 +602,19 @@ func testRunnerSystemCommandAction(cmd *cobra.Command, args []string) error {
 		return cobraext.FlagParsingError(err, cobraext.VariantFlagName)
 	}
 
+	containerLogsTail, err := cmd.Flags().GetInt(cobraext.ContainerLogsTailFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.ContainerLogsTailFlagName)
+	}
+	if containerLogsTail < 0 {
+		return cobraext.FlagParsingError(errors.New("it cannot be negative"), cobraext.ContainerLogsTailFlagName)
+	}
+
+	reuseAgentPolicy, err := cmd.Flags().GetBool(cobraext.ReuseAgentPolicyFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.ReuseAgentPolicyFlagName)
+	}
+
 	packageRootPath, found, err := packages.FindPackageRoot()
 	if !found {
 		return errors.New("package root not found")
@@ -527,6 +656,18 @@ func testRunnerSystemCommandAction(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Stages after the setup need to be executed to complete the tests.
+	if !runTearDown && !runTestsOnly {
+		var changed bool
+		dataStreams, changed, err = changedOnlyDataStreams(cmd, system.TestType, packageRootPath, dataStreams)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			return nil
+		}
+	}
+
 	ctx, stop := signal.Enable(cmd.Context(), logger.Info)
 	defer stop()
 
@@ -564,6 +705,11 @@ func testRunnerSystemCommandAction(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to read global config: %w", err)
 	}
 
+	progressReporter, err := cobraext.GetProgressReporter(cmd)
+	if err != nil {
+		return err
+	}
+
 	runner := system.NewSystemTestRunner(system.SystemTestRunnerOptions{
 		Profile:            profile,
 		PackageRootPath:    packageRootPath,
@@ -583,6 +729,9 @@ func testRunnerSystemCommandAction(cmd *cobra.Command, args []string) error {
 		WithCoverage:       testCoverage,
 		CoverageType:       testCoverageFormat,
 		CheckFailureStore:  checkFailureStore,
+		ContainerLogsTail:  containerLogsTail,
+		ReuseAgentPolicy:   reuseAgentPolicy,
+		Progress:           progressReporter,
 	})
 
 	logger.Debugf("Running suite...")
@@ -669,6 +818,14 @@ func testRunnerPolicyCommandAction(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	dataStreams, changed, err := changedOnlyDataStreams(cmd, testType, packageRootPath, dataStreams)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+
 	ctx, stop := signal.Enable(cmd.Context(), logger.Info)
 	defer stop()
 
@@ -706,6 +863,89 @@ func testRunnerPolicyCommandAction(cmd *cobra.Command, args []string) error {
 	return processResults(results, testType, reportFormat, reportOutput, packageRootPath, manifest.Name, manifest.Type, testCoverageFormat, testCoverage)
 }
 
+func getTestCoverageCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "coverage",
+		Short: "Report test coverage of the package",
+		Long:  "Report which data streams of the package have pipeline and system tests, and the fields exercised by the expected results of pipeline tests and by sample events. Tests are not executed. Documents ingested by system tests are not considered. External fields, such as the ECS ones, are not resolved, so they are not counted in the fields coverage. Use `--report-format json` to get the report in JSON format.",
+		Args:  cobra.NoArgs,
+		RunE:  testCoverageCommandAction,
+	}
+
+	return cmd
+}
+
+func testCoverageCommandAction(cmd *cobra.Command, args []string) error {
+	reportFormat, err := cmd.Flags().GetString(cobraext.ReportFormatFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.ReportFormatFlagName)
+	}
+	switch testrunner.TestReportFormat(reportFormat) {
+	case formats.ReportFormatHuman, formats.ReportFormatJSON:
+	default:
+		return cobraext.FlagParsingError(fmt.Errorf("report format not available for coverage: %s", reportFormat), cobraext.ReportFormatFlagName)
+	}
+
+	packageRootPath, found, err := packages.FindPackageRoot()
+	if !found {
+		return errors.New("package root not found")
+	}
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	coverage, err := runners.CalculatePackageCoverage(packageRootPath)
+	if err != nil {
+		return fmt.Errorf("calculating test coverage failed: %w", err)
+	}
+
+	if testrunner.TestReportFormat(reportFormat) == formats.ReportFormatJSON {
+		data, err := json.MarshalIndent(coverage, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error formatting test coverage in JSON format: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	}
+
+	table := tablewriter.NewWriter(cmd.OutOrStdout())
+	table.SetHeader([]string{"Data stream", "Pipeline tests", "System tests", "Fields coverage"})
+	table.SetHeaderColor(
+		twColor(tablewriter.Colors{tablewriter.Bold}),
+		twColor(tablewriter.Colors{tablewriter.Bold}),
+		twColor(tablewriter.Colors{tablewriter.Bold}),
+		twColor(tablewriter.Colors{tablewriter.Bold}),
+	)
+	for _, dataStream := range coverage.DataStreams {
+		name := dataStream.DataStream
+		if name == "" {
+			name = "-"
+		}
+		table.Append([]string{
+			name,
+			strconv.Itoa(dataStream.PipelineTests),
+			strconv.Itoa(dataStream.SystemTests),
+			formatFieldsCoverage(dataStream.ExercisedFields, dataStream.Fields),
+		})
+	}
+	table.SetAutoMergeCells(false)
+	table.Render()
+
+	summary := coverage.Summary
+	fmt.Fprintf(cmd.OutOrStdout(), "Package: %s\n", coverage.Package)
+	fmt.Fprintf(cmd.OutOrStdout(), "Data streams with pipeline tests: %d/%d\n", summary.DataStreamsWithPipelineTests, summary.DataStreams)
+	fmt.Fprintf(cmd.OutOrStdout(), "Data streams with system tests: %d/%d\n", summary.DataStreamsWithSystemTests, summary.DataStreams)
+	fmt.Fprintf(cmd.OutOrStdout(), "Fields coverage: %s\n", formatFieldsCoverage(summary.ExercisedFields, summary.Fields))
+	return nil
+}
+
+func formatFieldsCoverage(exercised, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d (%.1f%%)", exercised, total, 100*float64(exercised)/float64(total))
+}
+
 func processResults(results []testrunner.TestResult, testType testrunner.TestType, reportFormat, reportOutput, packageRootPath, packageName, packageType, testCoverageFormat string, testCoverage bool) error {
 	sort.Slice(results, func(i, j int) bool {
 		if results[i].Package != results[j].Package {
@@ -760,6 +1000,52 @@ func validateDataStreamsFlag(packageRootPath string, dataStreams []string) error
 	return nil
 }
 
+// changedOnlyDataStreams selects the data streams to test when tests are requested only for
+// the changes since a git reference. It returns false if there are no changes to test.
+func changedOnlyDataStreams(cmd *cobra.Command, testType testrunner.TestType, packageRootPath string, dataStreams []string) ([]string, bool, error) {
+	changedOnly, err := cmd.Flags().GetBool(cobraext.TestChangedOnlyFlagName)
+	if err != nil {
+		return nil, false, cobraext.FlagParsingError(err, cobraext.TestChangedOnlyFlagName)
+	}
+	if !changedOnly {
+		return dataStreams, true, nil
+	}
+
+	baseRef, err := cmd.Flags().GetString(cobraext.BaseRefFlagName)
+	if err != nil {
+		return nil, false, cobraext.FlagParsingError(err, cobraext.BaseRefFlagName)
+	}
+
+	changes, err := testrunner.FindPackageChanges(packageRootPath, baseRef)
+	if err != nil {
+		return nil, false, fmt.Errorf("looking for changes since %q failed: %w", baseRef, err)
+	}
+	if !changes.Changed {
+		cmd.Printf("No changes found in the package since %q, skipping %s tests\n", baseRef, testType)
+		return nil, false, nil
+	}
+	if len(changes.DataStreams) == 0 {
+		// Changes affect all data streams.
+		return dataStreams, true, nil
+	}
+	if len(dataStreams) == 0 {
+		cmd.Printf("Running %s tests for data streams changed since %q: %s\n", testType, baseRef, strings.Join(changes.DataStreams, ", "))
+		return changes.DataStreams, true, nil
+	}
+
+	var selected []string
+	for _, dataStream := range dataStreams {
+		if slices.Contains(changes.DataStreams, dataStream) {
+			selected = append(selected, dataStream)
+		}
+	}
+	if len(selected) == 0 {
+		cmd.Printf("No changes found in the selected data streams since %q, skipping %s tests\n", baseRef, testType)
+		return nil, false, nil
+	}
+	return selected, true, nil
+}
+
 func getDataStreamsFlag(cmd *cobra.Command, packageRootPath string) ([]string, error) {
 	dataStreams, err := cmd.Flags().GetStringSlice(cobraext.DataStreamsFlagName)
 	common.TrimStringSlice(dataStreams)
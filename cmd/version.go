@@ -5,17 +5,30 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/Masterminds/semver/v3"
+
 	"github.com/spf13/cobra"
 
 	"github.com/elastic/elastic-package/internal/cobraext"
+	"github.com/elastic/elastic-package/internal/packages"
+	"github.com/elastic/elastic-package/internal/packages/changelog"
 	"github.com/elastic/elastic-package/internal/version"
 )
 
 const versionLongDescription = `Use this command to print the version of elastic-package that you have installed. This is especially useful when reporting bugs.`
 
+const versionBumpLongDescription = `Use this command to bump the version of the package.
+
+The next version is calculated from the version in the package manifest, bumping the patch version by default, or the major or minor versions with the --major or --minor flags. The manifest is updated with the new version, and a changelog entry for the new version is added on top of the changelog, with a description and type based on the bump, that can be overridden with the --description and --type flags. The link of the entry is required.
+
+The changelog is validated before writing any file. Use the --dry-run flag to show the changes without writing them.`
+
 func setupVersionCommand() *cobraext.Command {
 	cmd := &cobra.Command{
 		Use:   "version",
@@ -25,6 +38,24 @@ func setupVersionCommand() *cobraext.Command {
 		RunE:  versionCommandAction,
 	}
 
+	bumpCmd := &cobra.Command{
+		Use:   "bump",
+		Short: "Bump the version of the package",
+		Long:  versionBumpLongDescription,
+		Args:  cobra.NoArgs,
+		RunE:  versionBumpCommandAction,
+	}
+	bumpCmd.Flags().Bool(cobraext.VersionBumpMajorFlagName, false, cobraext.VersionBumpMajorFlagDescription)
+	bumpCmd.Flags().Bool(cobraext.VersionBumpMinorFlagName, false, cobraext.VersionBumpMinorFlagDescription)
+	bumpCmd.Flags().Bool(cobraext.VersionBumpPatchFlagName, false, cobraext.VersionBumpPatchFlagDescription)
+	bumpCmd.MarkFlagsMutuallyExclusive(cobraext.VersionBumpMajorFlagName, cobraext.VersionBumpMinorFlagName, cobraext.VersionBumpPatchFlagName)
+	bumpCmd.Flags().String(cobraext.ChangelogAddDescriptionFlagName, "", cobraext.ChangelogAddDescriptionFlagDescription)
+	bumpCmd.Flags().String(cobraext.ChangelogAddTypeFlagName, "", cobraext.ChangelogAddTypeFlagDescription)
+	bumpCmd.Flags().String(cobraext.ChangelogAddLinkFlagName, "", cobraext.ChangelogAddLinkFlagDescription)
+	bumpCmd.MarkFlagRequired(cobraext.ChangelogAddLinkFlagName)
+	bumpCmd.Flags().Bool(cobraext.VersionBumpDryRunFlagName, false, cobraext.VersionBumpDryRunFlagDescription)
+	cmd.AddCommand(bumpCmd)
+
 	return cobraext.NewCommand(cmd, cobraext.ContextGlobal)
 }
 
@@ -39,3 +70,128 @@ func versionCommandAction(cmd *cobra.Command, args []string) error {
 	fmt.Println(sb.String())
 	return nil
 }
+
+func versionBumpCommandAction(cmd *cobra.Command, args []string) error {
+	packageRoot, err := packages.MustFindPackageRoot()
+	if err != nil {
+		return fmt.Errorf("locating package root failed: %w", err)
+	}
+
+	major, err := cmd.Flags().GetBool(cobraext.VersionBumpMajorFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.VersionBumpMajorFlagName)
+	}
+	minor, err := cmd.Flags().GetBool(cobraext.VersionBumpMinorFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.VersionBumpMinorFlagName)
+	}
+	changeType, err := cmd.Flags().GetString(cobraext.ChangelogAddTypeFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.ChangelogAddTypeFlagName)
+	}
+	description, err := cmd.Flags().GetString(cobraext.ChangelogAddDescriptionFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.ChangelogAddDescriptionFlagName)
+	}
+	link, err := cmd.Flags().GetString(cobraext.ChangelogAddLinkFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.ChangelogAddLinkFlagName)
+	}
+	dryRun, err := cmd.Flags().GetBool(cobraext.VersionBumpDryRunFlagName)
+	if err != nil {
+		return cobraext.FlagParsingError(err, cobraext.VersionBumpDryRunFlagName)
+	}
+
+	nextMode := "patch"
+	defaultChangeType := "bugfix"
+	if major {
+		nextMode = "major"
+		defaultChangeType = "breaking-change"
+	} else if minor {
+		nextMode = "minor"
+		defaultChangeType = "enhancement"
+	}
+	if changeType == "" {
+		changeType = defaultChangeType
+	}
+
+	manifest, err := packages.ReadPackageManifestFromPackageRoot(packageRoot)
+	if err != nil {
+		return fmt.Errorf("reading package manifest failed (path: %s): %w", packageRoot, err)
+	}
+	current, err := semver.NewVersion(manifest.Version)
+	if err != nil {
+		return fmt.Errorf("invalid version in manifest %q: %w", manifest.Version, err)
+	}
+	next, err := nextVersion(current, nextMode)
+	if err != nil {
+		return err
+	}
+
+	if description == "" {
+		description = fmt.Sprintf("Bump version to %s.", next)
+	}
+	revision := changelog.Revision{
+		Version: next.String(),
+		Changes: []changelog.Entry{
+			{
+				Description: description,
+				Type:        changeType,
+				Link:        link,
+			},
+		},
+	}
+
+	changelogPath := filepath.Join(packageRoot, changelog.PackageChangelogFile)
+	changelogContent, err := os.ReadFile(changelogPath)
+	if err != nil {
+		return err
+	}
+	patchedChangelog, err := changelog.PatchYAML(changelogContent, revision)
+	if err != nil {
+		return fmt.Errorf("adding changelog entry failed: %w", err)
+	}
+	revisions, err := changelog.ParseChangelog(patchedChangelog)
+	if err != nil {
+		return err
+	}
+	err = changelog.ValidateRevisions(revisions, changelog.DefaultLinkHost)
+	if err != nil {
+		return fmt.Errorf("invalid changelog entries:\n%w", err)
+	}
+
+	manifestPath := filepath.Join(packageRoot, packages.PackageManifestFile)
+	manifestContent, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return err
+	}
+	patchedManifest, err := changelog.SetManifestVersion(manifestContent, next.String())
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		cmd.Printf("Version would be bumped from %s to %s.\n", current, next)
+		cmd.Printf("Version in %s would be changed:\n", packages.PackageManifestFile)
+		cmd.Printf("- version: %s\n+ version: %s\n", manifest.Version, next)
+		cmd.Printf("This entry would be added to %s:\n", changelog.PackageChangelogFile)
+		cmd.Printf("- version: %q\n  changes:\n    - description: %q\n      type: %s\n      link: %s\n", next, description, changeType, link)
+		return nil
+	}
+
+	err = os.WriteFile(changelogPath, patchedChangelog, 0644)
+	if err != nil {
+		return fmt.Errorf("writing changelog failed: %w", err)
+	}
+	err = os.WriteFile(manifestPath, patchedManifest, 0644)
+	if err != nil {
+		// Restore the changelog so both files are kept consistent.
+		if restoreErr := os.WriteFile(changelogPath, changelogContent, 0644); restoreErr != nil {
+			err = errors.Join(err, fmt.Errorf("restoring changelog failed: %w", restoreErr))
+		}
+		return fmt.Errorf("writing manifest failed: %w", err)
+	}
+
+	cmd.Printf("Version bumped from %s to %s\n", current, next)
+	return nil
+}
@@ -0,0 +1,277 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/jedib0t/go-pretty/text"
+)
+
+const (
+	baselineEPSMetric             = "eps"
+	baselineAvgTimePerDocMetric   = "avg_time_per_doc"
+	baselineProcessorMetricPrefix = "procs_by_avg_time_per_doc/"
+)
+
+// Baseline contains the metrics of pipeline benchmark runs that can be compared with the metrics
+// of later runs to detect regressions. Metrics are stored by benchmark, identified by the path of
+// the benchmark folder relative to the package root.
+type Baseline struct {
+	Benchmarks map[string]map[string]float64 `json:"benchmarks"`
+}
+
+// NewBaseline creates an empty Baseline.
+func NewBaseline() *Baseline {
+	return &Baseline{
+		Benchmarks: make(map[string]map[string]float64),
+	}
+}
+
+// ReadBaseline reads a baseline from the given file.
+func ReadBaseline(path string) (*Baseline, error) {
+	d, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline failed (path: %s): %w", path, err)
+	}
+	baseline := NewBaseline()
+	err = json.Unmarshal(d, baseline)
+	if err != nil {
+		return nil, fmt.Errorf("decoding baseline failed (path: %s): %w", path, err)
+	}
+	return baseline, nil
+}
+
+// Write writes the baseline to the given file.
+func (b *Baseline) Write(path string) error {
+	d, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding baseline failed: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return fmt.Errorf("creating baseline directory failed: %w", err)
+	}
+	err = os.WriteFile(path, d, 0644)
+	if err != nil {
+		return fmt.Errorf("writing baseline failed (path: %s): %w", path, err)
+	}
+	return nil
+}
+
+// add stores the metrics of a benchmark result in the baseline.
+func (b *Baseline) add(benchmark string, result *BenchmarkResult) {
+	var numDocs int
+	for _, param := range result.Parameters {
+		if param.Name == "doc_count" {
+			numDocs, _ = param.Value.(int)
+		}
+	}
+
+	metrics := make(map[string]float64)
+	for _, test := range result.Tests {
+		for _, value := range test.Results {
+			switch {
+			case test.Name == "pipeline_performance" && value.Name == "eps":
+				if eps, ok := value.Value.(float64); ok {
+					metrics[baselineEPSMetric] = eps
+				}
+			case test.Name == "pipeline_performance" && value.Name == "processing_time":
+				if seconds, ok := value.Value.(float64); ok && numDocs > 0 {
+					metrics[baselineAvgTimePerDocMetric] = seconds * float64(time.Second) / float64(numDocs)
+				}
+			case test.Name == "procs_by_avg_time_per_doc":
+				if d, ok := value.Value.(time.Duration); ok {
+					metrics[baselineProcessorMetricPrefix+value.Name] = float64(d)
+				}
+			}
+		}
+	}
+	b.Benchmarks[benchmark] = metrics
+}
+
+// gatedMetric returns true for metrics whose regressions fail the comparison. The time of each
+// processor is too noisy to gate on it, it is only reported.
+func gatedMetric(metric string) bool {
+	return !strings.HasPrefix(metric, baselineProcessorMetricPrefix)
+}
+
+// higherIsBetter returns true for metrics that improve when their values increase.
+func higherIsBetter(metric string) bool {
+	return metric == baselineEPSMetric
+}
+
+// ParseTolerance parses a tolerance expressed as a percentage, like "10%" or "10",
+// returning it as a fraction.
+func ParseTolerance(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid tolerance %q, a percentage is expected: %w", s, err)
+	}
+	if v < 0 {
+		return 0, fmt.Errorf("invalid tolerance %q, it cannot be negative", s)
+	}
+	return v / 100, nil
+}
+
+// MetricComparison contains the comparison of a metric between the baseline and the current run.
+type MetricComparison struct {
+	Benchmark string
+	Metric    string
+	Baseline  float64
+	Current   float64
+
+	// Change is the relative change of the metric, positive when the metric improves.
+	Change float64
+
+	// Comparable is false when the baseline value doesn't allow to calculate a relative change.
+	Comparable bool
+}
+
+// BaselineComparison contains the result of comparing the metrics of a benchmark run with a baseline.
+type BaselineComparison struct {
+	Tolerance float64
+
+	Metrics []MetricComparison
+
+	// Regressions are the gated metrics whose change is worse than the tolerance.
+	Regressions []MetricComparison
+
+	// Added are the metrics found in the current run that are not in the baseline.
+	Added []string
+	// Removed are the metrics found in the baseline that are not in the current run.
+	Removed []string
+}
+
+// Compare compares the metrics of the current run with the ones in the baseline. Throughput and time
+// per document whose change is worse than the tolerance are reported as regressions, the time of each
+// processor is compared but it is not considered for regressions. Metrics found only in one of the runs
+// are reported as added or removed, but they are not considered regressions either.
+func (b *Baseline) Compare(current *Baseline, tolerance float64) BaselineComparison {
+	comparison := BaselineComparison{Tolerance: tolerance}
+	for benchmark, currentMetrics := range current.Benchmarks {
+		baselineMetrics := b.Benchmarks[benchmark]
+		for metric, value := range currentMetrics {
+			baselineValue, found := baselineMetrics[metric]
+			if !found {
+				comparison.Added = append(comparison.Added, benchmark+": "+metric)
+				continue
+			}
+			mc := compareMetric(benchmark, metric, baselineValue, value)
+			comparison.Metrics = append(comparison.Metrics, mc)
+			if gatedMetric(metric) && mc.Comparable && mc.Change < -tolerance {
+				comparison.Regressions = append(comparison.Regressions, mc)
+			}
+		}
+	}
+	for benchmark, baselineMetrics := range b.Benchmarks {
+		currentMetrics := current.Benchmarks[benchmark]
+		for metric := range baselineMetrics {
+			if _, found := currentMetrics[metric]; !found {
+				comparison.Removed = append(comparison.Removed, benchmark+": "+metric)
+			}
+		}
+	}
+
+	sortComparisons(comparison.Metrics)
+	sortComparisons(comparison.Regressions)
+	sort.Strings(comparison.Added)
+	sort.Strings(comparison.Removed)
+	return comparison
+}
+
+func compareMetric(benchmark, metric string, baseline, current float64) MetricComparison {
+	mc := MetricComparison{
+		Benchmark: benchmark,
+		Metric:    metric,
+		Baseline:  baseline,
+		Current:   current,
+	}
+	if baseline == 0 {
+		return mc
+	}
+	mc.Comparable = true
+	mc.Change = (current - baseline) / baseline
+	if !higherIsBetter(metric) {
+		mc.Change = -mc.Change
+	}
+	return mc
+}
+
+func sortComparisons(comparisons []MetricComparison) {
+	sort.Slice(comparisons, func(i, j int) bool {
+		if comparisons[i].Benchmark != comparisons[j].Benchmark {
+			return comparisons[i].Benchmark < comparisons[j].Benchmark
+		}
+		return comparisons[i].Metric < comparisons[j].Metric
+	})
+}
+
+// Err returns an error if regressions were found.
+func (c BaselineComparison) Err() error {
+	if len(c.Regressions) == 0 {
+		return nil
+	}
+	var names []string
+	for _, r := range c.Regressions {
+		names = append(names, fmt.Sprintf("%s: %s (%+.2f%%)", r.Benchmark, r.Metric, r.Change*100))
+	}
+	return fmt.Errorf("benchmark regressions found beyond the tolerance of %.2f%%: %s", c.Tolerance*100, strings.Join(names, ", "))
+}
+
+// String returns the comparison in a human-readable format.
+func (c BaselineComparison) String() string {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleRounded)
+	t.SetTitle("comparison with baseline")
+	t.AppendHeader(table.Row{"benchmark", "metric", "baseline", "current", "change", "result"})
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 3, Align: text.AlignRight},
+		{Number: 4, Align: text.AlignRight},
+		{Number: 5, Align: text.AlignRight},
+	})
+	for _, mc := range c.Metrics {
+		change, result := "-", "not comparable"
+		if mc.Comparable {
+			change = fmt.Sprintf("%+.2f%%", mc.Change*100)
+			switch {
+			case mc.Change < -c.Tolerance && !gatedMetric(mc.Metric):
+				result = "slower (not gated)"
+			case mc.Change < -c.Tolerance:
+				result = "regression"
+			case mc.Change > c.Tolerance:
+				result = "improvement"
+			default:
+				result = "no change"
+			}
+		}
+		t.AppendRow(table.Row{mc.Benchmark, mc.Metric, formatBaselineValue(mc.Metric, mc.Baseline), formatBaselineValue(mc.Metric, mc.Current), change, result})
+	}
+
+	var report strings.Builder
+	report.WriteString(t.Render() + "\n")
+	if len(c.Added) > 0 {
+		report.WriteString("Metrics not found in baseline: " + strings.Join(c.Added, ", ") + "\n")
+	}
+	if len(c.Removed) > 0 {
+		report.WriteString("Metrics not found in current run: " + strings.Join(c.Removed, ", ") + "\n")
+	}
+	return report.String()
+}
+
+func formatBaselineValue(metric string, value float64) string {
+	if higherIsBetter(metric) {
+		return fmt.Sprintf("%.02f", value)
+	}
+	return time.Duration(value).String()
+}
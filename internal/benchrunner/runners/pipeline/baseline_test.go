@@ -0,0 +1,126 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package pipeline
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTolerance(t *testing.T) {
+	cases := map[string]float64{
+		"10%":  0.1,
+		"10":   0.1,
+		" 5% ": 0.05,
+		"0":    0,
+	}
+	for value, expected := range cases {
+		t.Run(value, func(t *testing.T) {
+			tolerance, err := ParseTolerance(value)
+			require.NoError(t, err)
+			assert.InDelta(t, expected, tolerance, 1e-9)
+		})
+	}
+
+	for _, value := range []string{"", "ten", "-10%"} {
+		t.Run(value, func(t *testing.T) {
+			_, err := ParseTolerance(value)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestBaselineAdd(t *testing.T) {
+	result := &BenchmarkResult{
+		Parameters: []BenchmarkValue{
+			{Name: "doc_count", Value: 1000},
+		},
+		Tests: []BenchmarkTest{
+			{
+				Name: "pipeline_performance",
+				Results: []BenchmarkValue{
+					{Name: "processing_time", Value: 0.5},
+					{Name: "eps", Value: 2000.0},
+				},
+			},
+			{
+				Name: "procs_by_avg_time_per_doc",
+				Results: []BenchmarkValue{
+					{Name: "kv @ default.yml:4", Value: 50 * time.Microsecond},
+				},
+			},
+		},
+	}
+
+	baseline := NewBaseline()
+	baseline.add("data_stream/test/_dev/benchmark/pipeline", result)
+	assert.Equal(t, map[string]float64{
+		"eps":              2000,
+		"avg_time_per_doc": float64(500 * time.Microsecond),
+		"procs_by_avg_time_per_doc/kv @ default.yml:4": float64(50 * time.Microsecond),
+	}, baseline.Benchmarks["data_stream/test/_dev/benchmark/pipeline"])
+}
+
+func TestBaselineCompare(t *testing.T) {
+	baseline := &Baseline{
+		Benchmarks: map[string]map[string]float64{
+			"a": {
+				"eps":                               1000,
+				"avg_time_per_doc":                  1000,
+				"procs_by_avg_time_per_doc/kv":      10,
+				"procs_by_avg_time_per_doc/removed": 10,
+			},
+			"removed": {
+				"eps": 1000,
+			},
+		},
+	}
+	current := &Baseline{
+		Benchmarks: map[string]map[string]float64{
+			"a": {
+				"eps":                             850,
+				"avg_time_per_doc":                1050,
+				"procs_by_avg_time_per_doc/kv":    20,
+				"procs_by_avg_time_per_doc/added": 10,
+			},
+			"added": {
+				"eps": 1000,
+			},
+		},
+	}
+
+	comparison := baseline.Compare(current, 0.1)
+	require.Len(t, comparison.Metrics, 3)
+	require.Len(t, comparison.Regressions, 1)
+	assert.Equal(t, "eps", comparison.Regressions[0].Metric)
+	assert.InDelta(t, -0.15, comparison.Regressions[0].Change, 1e-9)
+	assert.Equal(t, []string{"a: procs_by_avg_time_per_doc/added", "added: eps"}, comparison.Added)
+	assert.Equal(t, []string{"a: procs_by_avg_time_per_doc/removed", "removed: eps"}, comparison.Removed)
+	assert.ErrorContains(t, comparison.Err(), "a: eps (-15.00%)")
+	assert.NotContains(t, comparison.Err().Error(), "procs_by_avg_time_per_doc/kv")
+	assert.Contains(t, comparison.String(), "slower (not gated)")
+
+	comparison = baseline.Compare(current, 0.2)
+	assert.Empty(t, comparison.Regressions)
+	assert.NoError(t, comparison.Err())
+}
+
+func TestBaselineReadWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline", "baseline.json")
+	baseline := &Baseline{
+		Benchmarks: map[string]map[string]float64{
+			"a": {"eps": 1000},
+		},
+	}
+	require.NoError(t, baseline.Write(path))
+
+	read, err := ReadBaseline(path)
+	require.NoError(t, err)
+	assert.Equal(t, baseline, read)
+}
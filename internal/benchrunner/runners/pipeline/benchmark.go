@@ -30,6 +30,9 @@ type BenchmarkResult struct {
 	Parameters []BenchmarkValue `xml:"parameters,omitempty" json:"parameters,omitempty"`
 	// Tests holds the results for the benchmark.
 	Tests []BenchmarkTest `xml:"test" json:"test"`
+
+	// rallyMetrics holds the metrics of the benchmark in the format used by Rally.
+	rallyMetrics []rallyMetric
 }
 
 // BenchmarkTest models a particular test performed during a benchmark.
@@ -187,6 +190,13 @@ func (r *runner) benchmarkPipeline(ctx context.Context, b *benchmark, entryPipel
 		},
 	}
 
+	if r.options.Format == ReportFormatRally {
+		result.rallyMetrics, err = collectRallyMetrics(r.options.Folder, entryPipeline, bench)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return result, nil
 }
 
@@ -304,14 +314,20 @@ func (r *runner) runSingleBenchmark(ctx context.Context, entryPipeline string, d
 		return ingestResult{}, errors.New("no docs supplied for benchmark")
 	}
 
+	statsBefore, err := ingest.GetPipelineStats(r.options.API, r.pipelines)
+	if err != nil {
+		return ingestResult{}, fmt.Errorf("error fetching pipeline stats: %w", err)
+	}
+
 	if _, err := ingest.SimulatePipeline(ctx, r.options.API, entryPipeline, docs, "test-generic-default"); err != nil {
 		return ingestResult{}, fmt.Errorf("simulate failed: %w", err)
 	}
 
-	stats, err := ingest.GetPipelineStats(r.options.API, r.pipelines)
+	statsAfter, err := ingest.GetPipelineStats(r.options.API, r.pipelines)
 	if err != nil {
 		return ingestResult{}, fmt.Errorf("error fetching pipeline stats: %w", err)
 	}
+	stats := statsAfter.Delta(statsBefore)
 	var took time.Duration
 	for _, pSt := range stats {
 		took += time.Millisecond * time.Duration(pSt.TimeInMillis)
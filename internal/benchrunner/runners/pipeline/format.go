@@ -22,6 +22,8 @@ const (
 	ReportFormatJSON Format = "json"
 	// ReportFormatXUnit reports benchmark results in the xUnit format
 	ReportFormatXUnit Format = "xUnit"
+	// ReportFormatRally reports benchmark results as metrics in the format used by Rally
+	ReportFormatRally Format = "rally"
 )
 
 // Format represents a benchmark report format
@@ -36,6 +38,8 @@ func formatResult(name Format, result *BenchmarkResult) (report []byte, err erro
 		return reportJSONFormat(result)
 	case ReportFormatXUnit:
 		return reportXUnitFormat(result)
+	case ReportFormatRally:
+		return reportRallyFormat(result)
 	}
 	return nil, fmt.Errorf("unknown format: %s", name)
 }
@@ -106,7 +110,7 @@ func filenameByFormat(pkg string, format Format) string {
 	switch format {
 	default:
 		fallthrough
-	case ReportFormatJSON:
+	case ReportFormatJSON, ReportFormatRally:
 		ext = "json"
 	case ReportFormatXUnit:
 		ext = "xml"
@@ -17,6 +17,7 @@ type Options struct {
 	API             *elasticsearch.API
 	NumTopProcs     int
 	Format          Format
+	Baseline        *Baseline
 }
 
 type OptionFunc func(*Options)
@@ -64,3 +65,10 @@ func WithBenchmarkName(name string) OptionFunc {
 		opts.BenchName = name
 	}
 }
+
+// WithBaseline configures a baseline where the metrics of the benchmark are stored.
+func WithBaseline(baseline *Baseline) OptionFunc {
+	return func(opts *Options) {
+		opts.Baseline = baseline
+	}
+}
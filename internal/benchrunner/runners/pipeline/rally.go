@@ -0,0 +1,118 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/elastic/elastic-package/internal/elasticsearch/ingest"
+	"github.com/elastic/elastic-package/internal/testrunner"
+)
+
+const (
+	rallyOperationType = "ingest-pipeline"
+	rallySampleType    = "normal"
+)
+
+// rallyMetric is a metric record with the shape of the documents stored by Rally in its metrics
+// store. Ingest pipeline metrics use the same names and metadata as the ingest-pipeline-stats
+// telemetry device of Rally, so they can be compared with the results of Rally tracks.
+type rallyMetric struct {
+	Track         string            `json:"track"`
+	Task          string            `json:"task"`
+	Operation     string            `json:"operation"`
+	OperationType string            `json:"operation-type"`
+	SampleType    string            `json:"sample-type"`
+	Name          string            `json:"name"`
+	Value         float64           `json:"value"`
+	Unit          string            `json:"unit,omitempty"`
+	Meta          map[string]string `json:"meta,omitempty"`
+}
+
+// collectRallyMetrics builds the Rally metrics of a benchmark from the ingest stats collected
+// during the benchmark run, keyed by pipeline and processor.
+func collectRallyMetrics(folder testrunner.TestFolder, entryPipeline string, bench ingestResult) ([]rallyMetric, error) {
+	pipelines := make([]ingest.Pipeline, 0, len(bench.stats))
+	operation := entryPipeline
+	for _, pipeline := range bench.pipelines {
+		if pipeline.Name == entryPipeline {
+			operation = pipeline.Filename()
+		}
+		if _, found := bench.stats[pipeline.Name]; found {
+			pipelines = append(pipelines, pipeline)
+		}
+	}
+	sort.Slice(pipelines, func(i, j int) bool {
+		return pipelines[i].Filename() < pipelines[j].Filename()
+	})
+
+	metric := func(name string, value float64, unit string, meta map[string]string) rallyMetric {
+		return rallyMetric{
+			Track:         folder.Package,
+			Task:          folder.DataStream,
+			Operation:     operation,
+			OperationType: rallyOperationType,
+			SampleType:    rallySampleType,
+			Name:          name,
+			Value:         value,
+			Unit:          unit,
+			Meta:          meta,
+		}
+	}
+
+	var throughput float64
+	if bench.elapsed > 0 {
+		throughput = float64(bench.numDocs) / bench.elapsed.Seconds()
+	}
+	metrics := []rallyMetric{
+		metric("throughput", throughput, "docs/s", nil),
+	}
+
+	for _, pipeline := range pipelines {
+		stats := bench.stats[pipeline.Name]
+		processors, err := pipeline.Processors()
+		if err != nil {
+			return nil, err
+		}
+		if nSrc, nStats := len(processors), len(stats.Processors); nSrc != nStats {
+			return nil, fmt.Errorf("pipeline '%s' processor count mismatch. source=%d stats=%d", pipeline.Name, nSrc, nStats)
+		}
+
+		pipelineMeta := map[string]string{
+			"ingest_pipeline": pipeline.Filename(),
+		}
+		metrics = append(metrics, rallyStatsMetrics("ingest_pipeline_pipeline", stats.StatsRecord, pipelineMeta, metric)...)
+
+		for i, processorStats := range stats.Processors {
+			processor := processors[i]
+			processorMeta := map[string]string{
+				"ingest_pipeline": pipeline.Filename(),
+				"processor_name":  fmt.Sprintf("%s @ %s:%d", processor.Type, pipeline.Filename(), processor.FirstLine),
+				"type":            processor.Type,
+			}
+			metrics = append(metrics, rallyStatsMetrics("ingest_pipeline_processor", processorStats.Stats, processorMeta, metric)...)
+		}
+	}
+
+	return metrics, nil
+}
+
+func rallyStatsMetrics(prefix string, stats ingest.StatsRecord, meta map[string]string, metric func(string, float64, string, map[string]string) rallyMetric) []rallyMetric {
+	return []rallyMetric{
+		metric(prefix+"_count", float64(stats.Count), "", meta),
+		metric(prefix+"_time", float64(stats.TimeInMillis), "ms", meta),
+		metric(prefix+"_failed", float64(stats.Failed), "", meta),
+	}
+}
+
+func reportRallyFormat(b *BenchmarkResult) ([]byte, error) {
+	out, err := json.MarshalIndent(b.rallyMetrics, "", " ")
+	if err != nil {
+		return nil, fmt.Errorf("unable to format benchmark results as rally metrics: %w", err)
+	}
+	return out, nil
+}
@@ -80,6 +80,10 @@ func (r *runner) run(ctx context.Context) (reporters.Reportable, error) {
 		return nil, err
 	}
 
+	if r.options.Baseline != nil {
+		r.options.Baseline.add(r.baselineKey(), benchmark)
+	}
+
 	formattedReport, err := formatResult(r.options.Format, benchmark)
 	if err != nil {
 		return nil, err
@@ -97,6 +101,16 @@ func (r *runner) run(ctx context.Context) (reporters.Reportable, error) {
 	), nil
 }
 
+// baselineKey returns the key used to store the metrics of the benchmark in baselines. It is the path
+// of the benchmark folder relative to the package root, so it is stable between runs.
+func (r *runner) baselineKey() string {
+	rel, err := filepath.Rel(r.options.PackageRootPath, r.options.Folder.Path)
+	if err != nil {
+		return r.options.Folder.Path
+	}
+	return filepath.ToSlash(rel)
+}
+
 // FindBenchmarkFolders finds benchmark folders for the given package and, optionally, benchmark type and data streams
 func FindBenchmarkFolders(packageRootPath string, dataStreams []string) ([]testrunner.TestFolder, error) {
 	// Expected folder structure:
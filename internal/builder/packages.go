@@ -30,6 +30,10 @@ type BuildOptions struct {
 	CreateZip      bool
 	SignPackage    bool
 	SkipValidation bool
+
+	// SkipPrereleaseChangelogValidation allows to build prerelease versions (-SNAPSHOT or -next)
+	// that don't have a changelog entry yet.
+	SkipPrereleaseChangelogValidation bool
 }
 
 // BuildDirectory function locates the target build directory. If the directory doesn't exist, it will create it.
@@ -143,6 +147,13 @@ func FindBuildPackagesDirectory() (string, bool, error) {
 
 // BuildPackage function builds the package.
 func BuildPackage(options BuildOptions) (string, error) {
+	if options.SkipPrereleaseChangelogValidation {
+		err := checkPrereleaseVersion(options.PackageRoot)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	destinationDir, err := BuildPackagesDirectory(options.PackageRoot)
 	if err != nil {
 		return "", fmt.Errorf("can't locate build directory: %w", err)
@@ -195,6 +206,7 @@ func BuildPackage(options BuildOptions) (string, error) {
 
 	logger.Debugf("Validating built package (path: %s)", destinationDir)
 	errs, skipped := validation.ValidateAndFilterFromPath(destinationDir)
+	errs = filterPrereleaseChangelogErrors(options, errs)
 	if skipped != nil {
 		logger.Infof("Skipped errors: %v", skipped)
 	}
@@ -221,6 +233,7 @@ func buildZippedPackage(options BuildOptions, destinationDir string) (string, er
 	} else {
 		logger.Debugf("Validating built .zip package (path: %s)", zippedPackagePath)
 		errs, skipped := validation.ValidateAndFilterFromZip(zippedPackagePath)
+		errs = filterPrereleaseChangelogErrors(options, errs)
 		if skipped != nil {
 			logger.Infof("Skipped errors: %v", skipped)
 		}
@@ -239,6 +252,33 @@ func buildZippedPackage(options BuildOptions, destinationDir string) (string, er
 	return zippedPackagePath, nil
 }
 
+// checkPrereleaseVersion checks that the package has a prerelease version that can be built
+// without changelog validation.
+func checkPrereleaseVersion(packageRoot string) error {
+	m, err := packages.ReadPackageManifestFromPackageRoot(packageRoot)
+	if err != nil {
+		return fmt.Errorf("reading package manifest failed (path: %s): %w", packageRoot, err)
+	}
+	if !validation.IsUnreleasedPrerelease(m.Version) {
+		return fmt.Errorf("changelog validation can only be skipped for -SNAPSHOT or -next prerelease versions, found version %s", m.Version)
+	}
+	logger.Warnf("Changelog validation is skipped for prerelease version %s, this package must not be released", m.Version)
+	return nil
+}
+
+// filterPrereleaseChangelogErrors removes the errors about the missing changelog entry, when
+// this validation is skipped for prerelease versions.
+func filterPrereleaseChangelogErrors(options BuildOptions, errs error) error {
+	if errs == nil || !options.SkipPrereleaseChangelogValidation {
+		return errs
+	}
+	errs, removed := validation.FilterPrereleaseChangelogErrors(errs)
+	if removed != nil {
+		logger.Warnf("Ignored errors in prerelease version: %v", removed)
+	}
+	return errs
+}
+
 func signZippedPackage(options BuildOptions, zippedPackagePath string) error {
 	logger.Debug("Sign the package")
 	m, err := packages.ReadPackageManifestFromPackageRoot(options.PackageRoot)
@@ -58,6 +58,15 @@ const (
 	BenchThresholdFlagName        = "threshold"
 	BenchThresholdFlagDescription = "threshold to assume a benchmark report has significantly changed"
 
+	BenchBaselineFlagName        = "baseline"
+	BenchBaselineFlagDescription = "path of the baseline file used to record benchmark metrics and compare with them"
+
+	BenchRecordBaselineFlagName        = "record-baseline"
+	BenchRecordBaselineFlagDescription = "record the metrics of this run in the baseline file instead of comparing with it"
+
+	BenchFailOnRegressionFlagName        = "fail-on-regression"
+	BenchFailOnRegressionFlagDescription = "fail if metrics are worse than in the baseline beyond the given tolerance (e.g. 10%)"
+
 	BenchWithTestSamplesFlagName        = "use-test-samples"
 	BenchWithTestSamplesFlagDescription = "use test samples for the benchmarks"
 
@@ -88,6 +97,12 @@ const (
 	BenchStreamTimestampFieldFlagName        = "timestamp-field"
 	BenchStreamTimestampFieldFlagDescription = "name of the field that's used in the generator config as `@timestamp`"
 
+	BaseRefFlagName        = "base-ref"
+	BaseRefFlagDescription = "git reference of a previous version of the package to check for breaking changes"
+
+	BuildSkipPrereleaseChangelogValidationFlagName        = "skip-changelog-validation-for-prerelease"
+	BuildSkipPrereleaseChangelogValidationFlagDescription = "allow building -SNAPSHOT or -next prerelease versions without a matching changelog entry, the rest of the package is still validated"
+
 	BuildSkipValidationFlagName        = "skip-validation"
 	BuildSkipValidationFlagDescription = "skip validation of the built package, use only if all validation issues have been acknowledged"
 
@@ -98,7 +113,7 @@ const (
 	ChangelogAddNextFlagDescription = "changelog entry is added in the next `major`, `minor` or `patch` version"
 
 	ChangelogAddVersionFlagName        = "version"
-	ChangelogAddVersionFlagDescription = "changelog entry is added in the given version"
+	ChangelogAddVersionFlagDescription = "changelog entry is added in the given version, that is created if missing"
 
 	ChangelogAddDescriptionFlagName        = "description"
 	ChangelogAddDescriptionFlagDescription = "description for the changelog entry"
@@ -112,6 +127,18 @@ const (
 	CheckConditionFlagName        = "check-condition"
 	CheckConditionFlagDescription = "check if the condition is met for the package, but don't install the package (e.g. kibana.version=7.10.0)"
 
+	CheckECSConsistentFlagName        = "consistent"
+	CheckECSConsistentFlagDescription = "fail if the packages don't use the same ECS reference"
+
+	CheckECSReferenceFlagName        = "reference"
+	CheckECSReferenceFlagDescription = "ECS reference expected in all packages, by default the one used by most packages"
+
+	CheckTransformsPreviewFlagName        = "preview"
+	CheckTransformsPreviewFlagDescription = "preview the transforms in the running stack and validate the documents they generate"
+
+	ContainerLogsTailFlagName        = "container-logs-tail"
+	ContainerLogsTailFlagDescription = "number of lines to dump from the logs of the service and agent containers when a test fails, 0 to dump all lines"
+
 	DaemonModeFlagName        = "daemon"
 	DaemonModeFlagDescription = "daemon mode"
 
@@ -130,14 +157,29 @@ const (
 	DumpOutputFlagName        = "output"
 	DumpOutputFlagDescription = "path to directory where exported assets will be stored"
 
+	DiffAssetsDataStreamFlagDescription = "only compare the assets that reference the dataset of this data stream"
+
+	DumpFieldsDataStreamFlagDescription = "data stream to dump the fields of, if not set the fields of the package are dumped"
+
+	EnableChecksFlagName        = "enable-checks"
+	EnableChecksFlagDescription = "comma-separated optional checks to run (%s)"
+
 	FailOnMissingFlagName        = "fail-on-missing"
 	FailOnMissingFlagDescription = "fail if tests are missing"
 
+	FixFlagName        = "fix"
+	FixFlagDescription = "fix the issues that can be safely fixed, rewriting the affected files"
+
 	FailFastFlagName                  = "fail-fast"
 	FailFastFlagDescription           = "fail immediately if any file requires updates (do not overwrite)"
 	GenerateTestResultFlagName        = "generate"
 	GenerateTestResultFlagDescription = "generate test result file"
 
+	GenerateSampleEventDataStreamFlagDescription = "data stream to generate the sample event for"
+
+	InstallForceFlagName        = "force"
+	InstallForceFlagDescription = "install the package even if its Kibana version conditions are not satisfied by the stack"
+
 	ProfileFlagName        = "profile"
 	ProfileFlagDescription = "select a profile to use for the stack configuration. Can also be set with %s"
 
@@ -147,6 +189,9 @@ const (
 	ProfileFormatFlagName        = "format"
 	ProfileFormatFlagDescription = "format of the profiles list (table | json)"
 
+	QuietFlagName        = "quiet"
+	QuietFlagDescription = "disable the progress reporting of long-running operations"
+
 	ReportFormatFlagName        = "report-format"
 	ReportFormatFlagDescription = "format of test report"
 
@@ -159,6 +204,9 @@ const (
 	ReportOutputPathFlagName        = "report-output-path"
 	ReportOutputPathFlagDescription = "output path for test report (defaults to %q in build directory)"
 
+	ReuseAgentPolicyFlagName        = "reuse-agent-policy"
+	ReuseAgentPolicyFlagDescription = "reuse test policies between scenarios that configure the data stream with the same variables, cleaning up the data stream between them"
+
 	ShowAllFlagName        = "all"
 	ShowAllFlagDescription = "show all deployed package revisions"
 
@@ -178,6 +226,12 @@ const (
 	StackServicesFlagName        = "services"
 	StackServicesFlagDescription = "component services (comma-separated values: \"%s\")"
 
+	StackStatusWaitFlagName        = "wait"
+	StackStatusWaitFlagDescription = "wait till Elasticsearch, Kibana and Fleet are ready, and fail if they are not ready before the timeout"
+
+	StackStatusWaitTimeoutFlagName        = "timeout"
+	StackStatusWaitTimeoutFlagDescription = "maximum time to wait for the services to be ready"
+
 	StackVersionFlagName        = "version"
 	StackVersionFlagDescription = "stack version"
 
@@ -197,15 +251,50 @@ const (
 	StatusFormatFlagName        = "format"
 	StatusFormatFlagDescription = "output format (\"%s\")"
 
+	TestChangedOnlyFlagName        = "changed-only"
+	TestChangedOnlyFlagDescription = "run tests only if the package changed since the base git reference, and only for the changed data streams when possible"
+
+	TestChangedOnlyBaseRefFlagDescription = "git reference to look for changes when running tests with --changed-only"
+
 	TestCoverageFlagName        = "test-coverage"
 	TestCoverageFlagDescription = "enable test coverage reports"
 
 	TestCoverageFormatFlagName        = "coverage-format"
 	TestCoverageFormatFlagDescription = "set format for coverage reports: %s"
 
+	TestCoverageFieldsFlagName        = "coverage-fields"
+	TestCoverageFieldsFlagDescription = "show a summary of the defined fields not exercised by the tests"
+
+	TestCoverageHTMLFlagName        = "coverage-html"
+	TestCoverageHTMLFlagDescription = "write an HTML report of the defined fields exercised by the tests in the given file"
+
+	TestSlowestFieldsFlagName        = "report-slowest-fields"
+	TestSlowestFieldsFlagDescription = "show the given number of fields that took longer to validate"
+
+	TestValidationTimeBudgetFlagName        = "validation-time-budget"
+	TestValidationTimeBudgetFlagDescription = "fail test cases whose documents take longer than this to validate (e.g. 30s)"
+
+	TestValidationMemoryBudgetFlagName        = "validation-memory-budget"
+	TestValidationMemoryBudgetFlagDescription = "fail test cases whose documents allocate more memory than this to validate (e.g. 512MB)"
+
+	TestWorkersFlagName        = "workers"
+	TestWorkersFlagDescription = "number of test cases to run in parallel"
+
 	VariantFlagName        = "variant"
 	VariantFlagDescription = "service variant"
 
+	VersionBumpDryRunFlagName        = "dry-run"
+	VersionBumpDryRunFlagDescription = "show the changes to the manifest and the changelog without writing them"
+
+	VersionBumpMajorFlagName        = "major"
+	VersionBumpMajorFlagDescription = "bump the major version"
+
+	VersionBumpMinorFlagName        = "minor"
+	VersionBumpMinorFlagDescription = "bump the minor version"
+
+	VersionBumpPatchFlagName        = "patch"
+	VersionBumpPatchFlagDescription = "bump the patch version (default)"
+
 	ConfigFileFlagName        = "config-file"
 	ConfigFileFlagDescription = "configuration file to setup service and test"
 
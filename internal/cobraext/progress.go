@@ -0,0 +1,24 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cobraext
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/elastic-package/internal/progress"
+)
+
+// GetProgressReporter returns a progress reporter writing to the error output of the command,
+// or nil if progress reporting is disabled with the quiet flag.
+func GetProgressReporter(cmd *cobra.Command) (*progress.Reporter, error) {
+	quiet, err := cmd.Flags().GetBool(QuietFlagName)
+	if err != nil {
+		return nil, FlagParsingError(err, QuietFlagName)
+	}
+	if quiet {
+		return nil, nil
+	}
+	return progress.NewReporter(cmd.ErrOrStderr()), nil
+}
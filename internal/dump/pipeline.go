@@ -0,0 +1,185 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package dump
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/elastic/elastic-package/internal/elasticsearch"
+)
+
+// packageIngestPipelinesDir is the directory where ingest pipelines are stored in packages.
+var packageIngestPipelinesDir = filepath.Join("elasticsearch", "ingest_pipeline")
+
+// fleetPipelineMetaKeys are the keys added by Fleet to the metadata of the pipelines it installs.
+var fleetPipelineMetaKeys = []string{"managed", "managed_by", "package"}
+
+// IngestPipelineDumper dumps ingest pipelines from Elasticsearch, including the pipelines they call,
+// as files with the layout used in packages.
+type IngestPipelineDumper struct {
+	client *elasticsearch.API
+}
+
+// NewIngestPipelineDumper creates an IngestPipelineDumper.
+func NewIngestPipelineDumper(client *elasticsearch.API) *IngestPipelineDumper {
+	return &IngestPipelineDumper{
+		client: client,
+	}
+}
+
+// DumpByName dumps the ingest pipeline with the given name, and the pipelines called by it with
+// pipeline processors, in the ingest pipelines directory of the given package or data stream
+// directory. The given pipeline is stored as the default pipeline, and references to other
+// pipelines are replaced by references to their files.
+func (d *IngestPipelineDumper) DumpByName(ctx context.Context, dir, name string) (count int, err error) {
+	pipelines, err := getIngestPipelines(ctx, d.client, name)
+	if err != nil {
+		return 0, err
+	}
+	if len(pipelines) == 0 {
+		return 0, fmt.Errorf("ingest pipeline %q not found", name)
+	}
+
+	fileNames := make(map[string]string)
+	for _, pipeline := range pipelines {
+		fileNames[pipeline.Name()] = pipelineFileName(name, pipeline.Name())
+	}
+
+	pipelinesDir := filepath.Join(dir, packageIngestPipelinesDir)
+	err = os.MkdirAll(pipelinesDir, 0755)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create directory %s: %w", pipelinesDir, err)
+	}
+	for _, pipeline := range pipelines {
+		content, err := packagePipelineYAML(pipeline.JSON(), fileNames)
+		if err != nil {
+			return count, fmt.Errorf("failed to convert ingest pipeline %s: %w", pipeline.Name(), err)
+		}
+		path := filepath.Join(pipelinesDir, fileNames[pipeline.Name()]+".yml")
+		err = os.WriteFile(path, content, 0644)
+		if err != nil {
+			return count, fmt.Errorf("failed to write ingest pipeline %s: %w", pipeline.Name(), err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// pipelineFileName returns the name of the file, without extension, for a pipeline called from the
+// root pipeline. Fleet names the pipelines of packages with the name of the default pipeline as prefix.
+func pipelineFileName(root, name string) string {
+	if name == root {
+		return "default"
+	}
+	if suffix, found := strings.CutPrefix(name, root+"-"); found && suffix != "" {
+		return suffix
+	}
+	return name
+}
+
+// packagePipelineYAML converts an ingest pipeline, as returned by Elasticsearch, to the YAML format used
+// in packages. The order of the keys is kept, metadata added by Fleet is removed, and references to the
+// dumped pipelines are replaced with the template used in packages.
+func packagePipelineYAML(pipeline []byte, fileNames map[string]string) ([]byte, error) {
+	var document yaml.Node
+	err := yaml.Unmarshal(pipeline, &document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode pipeline: %w", err)
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) != 1 || document.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("unexpected pipeline format")
+	}
+
+	removeFleetMetadata(document.Content[0])
+	replacePipelineReferences(document.Content[0], fileNames)
+	resetYAMLStyle(&document)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	err = enc.Encode(&document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode pipeline: %w", err)
+	}
+	err = enc.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode pipeline: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func removeFleetMetadata(pipeline *yaml.Node) {
+	meta := mappingValue(pipeline, "_meta")
+	if meta == nil || meta.Kind != yaml.MappingNode {
+		return
+	}
+	for _, key := range fleetPipelineMetaKeys {
+		removeMappingKey(meta, key)
+	}
+	if len(meta.Content) == 0 {
+		removeMappingKey(pipeline, "_meta")
+	}
+}
+
+// replacePipelineReferences replaces the names in pipeline processors with references to the
+// files of the dumped pipelines.
+func replacePipelineReferences(node *yaml.Node, fileNames map[string]string) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "pipeline" && value.Kind == yaml.MappingNode {
+				name := mappingValue(value, "name")
+				if name != nil && name.Kind == yaml.ScalarNode {
+					if fileName, found := fileNames[name.Value]; found {
+						name.Value = fmt.Sprintf(`{{ IngestPipeline "%s" }}`, fileName)
+					}
+				}
+			}
+			replacePipelineReferences(value, fileNames)
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			replacePipelineReferences(item, fileNames)
+		}
+	}
+}
+
+// resetYAMLStyle sets the default block style in all the nodes, as they are decoded from JSON with
+// flow style. Multi-line strings, like scripts, are formatted as literal blocks.
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" && strings.Contains(node.Value, "\n") {
+		node.Style = yaml.LiteralStyle
+	}
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func removeMappingKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package dump
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipelineFileName(t *testing.T) {
+	root := "logs-apache.access-1.0.0"
+	cases := map[string]string{
+		"logs-apache.access-1.0.0":             "default",
+		"logs-apache.access-1.0.0-third-party": "third-party",
+		"global@custom":                        "global@custom",
+	}
+	for name, expected := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, expected, pipelineFileName(root, name))
+		})
+	}
+}
+
+func TestPackagePipelineYAML(t *testing.T) {
+	testDir := filepath.Join("testdata", "ingest-pipeline-yaml")
+	pipeline, err := os.ReadFile(filepath.Join(testDir, "pipeline.json"))
+	require.NoError(t, err)
+	expected, err := os.ReadFile(filepath.Join(testDir, "default.yml"))
+	require.NoError(t, err)
+
+	fileNames := map[string]string{
+		"logs-apache.access-1.0.0":             "default",
+		"logs-apache.access-1.0.0-third-party": "third-party",
+	}
+	content, err := packagePipelineYAML(pipeline, fileNames)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(content))
+}
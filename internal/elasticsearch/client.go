@@ -38,6 +38,9 @@ type clientOptions struct {
 	username string
 	password string
 
+	// apiKey is used for authentication instead of username and password when set.
+	apiKey string
+
 	// certificateAuthority is the certificate to validate the server certificate.
 	certificateAuthority string
 
@@ -68,6 +71,14 @@ func OptionWithPassword(password string) ClientOption {
 	}
 }
 
+// OptionWithAPIKey sets the API key to be used by the client. When set, it takes
+// precedence over basic authentication.
+func OptionWithAPIKey(apiKey string) ClientOption {
+	return func(opts *clientOptions) {
+		opts.apiKey = apiKey
+	}
+}
+
 // OptionWithCertificateAuthority sets the certificate authority to be used by the client.
 func OptionWithCertificateAuthority(certificateAuthority string) ClientOption {
 	return func(opts *clientOptions) {
@@ -112,6 +123,11 @@ func NewConfig(customOptions ...ClientOption) (elasticsearch.Config, error) {
 		Username:  options.username,
 		Password:  options.password,
 	}
+	if options.apiKey != "" {
+		config.APIKey = options.apiKey
+		config.Username = ""
+		config.Password = ""
+	}
 	if options.skipTLSVerify {
 		config.Transport = &http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
@@ -54,6 +54,64 @@ func TestClientWithTLS(t *testing.T) {
 	})
 }
 
+func TestClientAuthentication(t *testing.T) {
+	var authorization string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("X-elastic-product", "Elasticsearch")
+	}))
+	t.Cleanup(server.Close)
+
+	caCertFile := writeCACertFile(t, server.Certificate())
+
+	cases := []struct {
+		title    string
+		options  []elasticsearch.ClientOption
+		expected string
+	}{
+		{
+			title: "basic auth",
+			options: []elasticsearch.ClientOption{
+				elasticsearch.OptionWithUsername("elastic"),
+				elasticsearch.OptionWithPassword("changeme"),
+			},
+			expected: "Basic ZWxhc3RpYzpjaGFuZ2VtZQ==",
+		},
+		{
+			title: "api key",
+			options: []elasticsearch.ClientOption{
+				elasticsearch.OptionWithAPIKey("dGVzdDprZXk="),
+			},
+			expected: "APIKey dGVzdDprZXk=",
+		},
+		{
+			title: "api key takes precedence over basic auth",
+			options: []elasticsearch.ClientOption{
+				elasticsearch.OptionWithUsername("elastic"),
+				elasticsearch.OptionWithPassword("changeme"),
+				elasticsearch.OptionWithAPIKey("dGVzdDprZXk="),
+			},
+			expected: "APIKey dGVzdDprZXk=",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			authorization = ""
+			options := append([]elasticsearch.ClientOption{
+				elasticsearch.OptionWithAddress(server.URL),
+				elasticsearch.OptionWithCertificateAuthority(caCertFile),
+			}, c.options...)
+			client, err := elasticsearch.NewClient(options...)
+			require.NoError(t, err)
+
+			_, err = client.Ping()
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, authorization)
+		})
+	}
+}
+
 func TestClusterHealth(t *testing.T) {
 	cases := []struct {
 		Record   string
@@ -0,0 +1,91 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+)
+
+// RefreshDataStream refreshes the backing indices of the given data stream, so all the documents
+// ingested till now are visible for searches. Nothing is done if the data stream doesn't exist.
+func (client *Client) RefreshDataStream(ctx context.Context, name string) error {
+	resp, err := client.Indices.Refresh(
+		client.Indices.Refresh.WithContext(ctx),
+		client.Indices.Refresh.WithIndex(name),
+		client.Indices.Refresh.WithIgnoreUnavailable(true),
+	)
+	if err != nil {
+		return fmt.Errorf("refresh request failed for data stream %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		// Data stream doesn't exist, there is nothing to refresh.
+		return nil
+	}
+	if resp.IsError() {
+		return fmt.Errorf("failed to refresh data stream %s: %s", name, resp.String())
+	}
+	return nil
+}
+
+// CountDocuments refreshes the given data stream and counts its documents. If queries are given,
+// only the documents matching all of them are counted. Zero is returned if the data stream
+// doesn't exist.
+func (client *Client) CountDocuments(ctx context.Context, dataStream string, queries ...map[string]any) (int, error) {
+	err := client.RefreshDataStream(ctx, dataStream)
+	if err != nil {
+		return 0, err
+	}
+
+	options := []func(*esapi.CountRequest){
+		client.Count.WithContext(ctx),
+		client.Count.WithIndex(dataStream),
+		client.Count.WithIgnoreUnavailable(true),
+	}
+	if len(queries) > 0 {
+		query := queries[0]
+		if len(queries) > 1 {
+			query = map[string]any{
+				"bool": map[string]any{
+					"filter": queries,
+				},
+			}
+		}
+		body, err := json.Marshal(map[string]any{"query": query})
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode count query: %w", err)
+		}
+		options = append(options, client.Count.WithBody(bytes.NewReader(body)))
+	}
+
+	resp, err := client.Count(options...)
+	if err != nil {
+		return 0, fmt.Errorf("count request failed for data stream %s: %w", dataStream, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+	if resp.IsError() {
+		return 0, fmt.Errorf("failed to count documents in data stream %s: %s", dataStream, resp.String())
+	}
+
+	var result struct {
+		Count int `json:"count"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode count response for data stream %s: %w", dataStream, err)
+	}
+	return result.Count, nil
+}
@@ -0,0 +1,111 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package elasticsearch_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/elasticsearch"
+)
+
+func TestCountDocuments(t *testing.T) {
+	var requests []string
+	var lastBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-elastic-product", "Elasticsearch")
+		if r.URL.Path == "/" {
+			// Info request done by the client to check that it is connected to Elasticsearch.
+			w.Write([]byte(`{"version":{"number":"8.12.0","build_flavor":"default"},"tagline":"You Know, for Search"}`))
+			return
+		}
+		requests = append(requests, r.URL.Path)
+		switch r.URL.Path {
+		case "/logs-test-default/_refresh":
+			w.Write([]byte(`{"_shards":{"total":1,"successful":1,"failed":0}}`))
+		case "/logs-test-default/_count":
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			lastBody = body
+			w.Write([]byte(`{"count":42}`))
+		case "/logs-missing-default/_refresh", "/logs-missing-default/_count":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"type":"index_not_found_exception"},"status":404}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":{"type":"unexpected"},"status":500}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := elasticsearch.NewClient(elasticsearch.OptionWithAddress(server.URL))
+	require.NoError(t, err)
+
+	lastQuery := func(t *testing.T) map[string]any {
+		if len(lastBody) == 0 {
+			return nil
+		}
+		var query map[string]any
+		require.NoError(t, json.Unmarshal(lastBody, &query))
+		return query
+	}
+
+	t.Run("all documents", func(t *testing.T) {
+		requests = nil
+		lastBody = nil
+		count, err := client.CountDocuments(context.Background(), "logs-test-default")
+		require.NoError(t, err)
+		assert.Equal(t, 42, count)
+		assert.Nil(t, lastQuery(t))
+		assert.Equal(t, []string{"/logs-test-default/_refresh", "/logs-test-default/_count"}, requests)
+	})
+
+	t.Run("single query", func(t *testing.T) {
+		query := map[string]any{"term": map[string]any{"event.kind": "event"}}
+		_, err := client.CountDocuments(context.Background(), "logs-test-default", query)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"query": map[string]any{"term": map[string]any{"event.kind": "event"}}}, lastQuery(t))
+	})
+
+	t.Run("multiple queries", func(t *testing.T) {
+		_, err := client.CountDocuments(context.Background(), "logs-test-default",
+			map[string]any{"term": map[string]any{"event.kind": "event"}},
+			map[string]any{"exists": map[string]any{"field": "message"}},
+		)
+		require.NoError(t, err)
+		expected := map[string]any{
+			"query": map[string]any{
+				"bool": map[string]any{
+					"filter": []any{
+						map[string]any{"term": map[string]any{"event.kind": "event"}},
+						map[string]any{"exists": map[string]any{"field": "message"}},
+					},
+				},
+			},
+		}
+		assert.Equal(t, expected, lastQuery(t))
+	})
+
+	t.Run("missing data stream", func(t *testing.T) {
+		count, err := client.CountDocuments(context.Background(), "logs-missing-default")
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		err := client.RefreshDataStream(context.Background(), "logs-other-default")
+		assert.ErrorContains(t, err, "failed to refresh data stream logs-other-default")
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -51,7 +52,7 @@ func InstallDataStreamPipelines(api *elasticsearch.API, dataStreamPath string) (
 		return "", nil, fmt.Errorf("reading data stream manifest failed: %w", err)
 	}
 
-	nonce := time.Now().UnixNano()
+	nonce := newNonce()
 
 	mainPipeline := getPipelineNameWithNonce(dataStreamManifest.GetPipelineNameOrDefault(), nonce)
 	pipelines, err := loadIngestPipelineFiles(dataStreamPath, nonce)
@@ -305,6 +306,24 @@ func getIngestPipeline(api *elasticsearch.API, pipeline Pipeline) error {
 	return nil
 }
 
+// lastNonce is the last nonce used to install pipelines.
+var lastNonce atomic.Int64
+
+// newNonce returns a nonce based on the current time, different to any other returned before,
+// so pipelines installed concurrently don't get the same names.
+func newNonce() int64 {
+	for {
+		last := lastNonce.Load()
+		nonce := time.Now().UnixNano()
+		if nonce <= last {
+			nonce = last + 1
+		}
+		if lastNonce.CompareAndSwap(last, nonce) {
+			return nonce
+		}
+	}
+}
+
 func getPipelineNameWithNonce(pipelineName string, nonce int64) string {
 	return fmt.Sprintf("%s-%d", pipelineName, nonce)
 }
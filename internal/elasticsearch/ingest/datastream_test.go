@@ -5,6 +5,7 @@
 package ingest
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -130,3 +131,26 @@ func TestLoadRoutingRuleFileBadMissingConfigs(t *testing.T) {
 	assert.Equal(t, 0, len(rerouteProcessors))
 	assert.Error(t, err)
 }
+
+func TestNewNonceIsUnique(t *testing.T) {
+	const routines, count = 4, 100
+	nonces := make(chan int64, routines*count)
+	var wg sync.WaitGroup
+	for i := 0; i < routines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < count; j++ {
+				nonces <- newNonce()
+			}
+		}()
+	}
+	wg.Wait()
+	close(nonces)
+
+	seen := make(map[int64]bool)
+	for nonce := range nonces {
+		assert.False(t, seen[nonce], "duplicated nonce %d", nonce)
+		seen[nonce] = true
+	}
+}
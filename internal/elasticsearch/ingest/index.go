@@ -0,0 +1,320 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/elastic/elastic-package/internal/elasticsearch"
+	"github.com/elastic/elastic-package/internal/logger"
+)
+
+// ignoredFieldsSearchBatchSize is the maximum number of documents requested in each search
+// of ignored fields, below the default limit of results of the search API.
+const ignoredFieldsSearchBatchSize = 1000
+
+// IndexedDocument is the result of indexing a document.
+type IndexedDocument struct {
+	// IgnoredFields contains the fields ignored when indexing the document.
+	IgnoredFields []string
+
+	// Failure contains the reason of the failure when the document couldn't be indexed.
+	Failure string
+}
+
+type simulateTemplateResponse struct {
+	Template struct {
+		Settings map[string]any `json:"settings"`
+		Mappings map[string]any `json:"mappings"`
+	} `json:"template"`
+}
+
+type bulkResponse struct {
+	Items []map[string]struct {
+		ID    string          `json:"_id"`
+		Error json.RawMessage `json:"error"`
+	} `json:"items"`
+}
+
+type searchIgnoredResponse struct {
+	Hits struct {
+		Hits []struct {
+			ID      string   `json:"_id"`
+			Ignored []string `json:"_ignored"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// IndexPipelineDocuments indexes documents already processed by a pipeline in a temporary index
+// created with the mappings and settings of the given index template, so the results include
+// the effects of the mappings, as when documents are actually ingested. The index template must be
+// installed. Documents are indexed without running any pipeline, so they are stored in the temporary
+// index even if the pipeline would reroute them. Nil documents, as the ones dropped by the pipeline,
+// are not indexed. The temporary index is deleted afterwards.
+func IndexPipelineDocuments(ctx context.Context, api *elasticsearch.API, documents []json.RawMessage, indexTemplate string) ([]IndexedDocument, error) {
+	indexName := fmt.Sprintf("elastic-package-pipeline-test-%d", time.Now().UnixNano())
+	err := createIndexFromTemplate(ctx, api, indexName, indexTemplate)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err := deleteIndex(context.WithoutCancel(ctx), api, indexName)
+		if err != nil {
+			logger.Warnf("failed to delete temporary index %s: %v", indexName, err)
+		}
+	}()
+
+	docs, err := bulkIndexDocuments(ctx, api, indexName, documents)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexed []string
+	for i, doc := range documents {
+		if doc != nil && docs[i].Failure == "" {
+			indexed = append(indexed, strconv.Itoa(i))
+		}
+	}
+	for start := 0; start < len(indexed); start += ignoredFieldsSearchBatchSize {
+		end := min(start+ignoredFieldsSearchBatchSize, len(indexed))
+		ignored, err := searchIgnoredFields(ctx, api, indexName, indexed[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for id, fields := range ignored {
+			i, err := strconv.Atoi(id)
+			if err != nil || i < 0 || i >= len(docs) {
+				return nil, fmt.Errorf("unexpected document ID %q in search response", id)
+			}
+			docs[i].IgnoredFields = fields
+		}
+	}
+	return docs, nil
+}
+
+func createIndexFromTemplate(ctx context.Context, api *elasticsearch.API, indexName, indexTemplate string) error {
+	resp, err := api.Indices.SimulateTemplate(
+		api.Indices.SimulateTemplate.WithContext(ctx),
+		api.Indices.SimulateTemplate.WithName(indexTemplate),
+	)
+	if err != nil {
+		return fmt.Errorf("simulate template API call failed (template: %s): %w", indexTemplate, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read simulate template response body: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("index template %s not found, the package must be installed to index documents", indexTemplate)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("unexpected response status for simulate template (%d): %s: %w", resp.StatusCode, resp.Status(), elasticsearch.NewError(body))
+	}
+
+	request, err := createIndexRequest(body)
+	if err != nil {
+		return err
+	}
+
+	createResp, err := api.Indices.Create(indexName,
+		api.Indices.Create.WithContext(ctx),
+		api.Indices.Create.WithBody(bytes.NewReader(request)),
+	)
+	if err != nil {
+		return fmt.Errorf("create index API call failed (index: %s): %w", indexName, err)
+	}
+	defer createResp.Body.Close()
+
+	if createResp.IsError() {
+		return fmt.Errorf("failed to create index %s: %s", indexName, createResp.String())
+	}
+	return nil
+}
+
+// createIndexRequest builds the body of a create index request from the response of the
+// simulate index template API.
+func createIndexRequest(simulateTemplateBody []byte) ([]byte, error) {
+	var template simulateTemplateResponse
+	err := json.Unmarshal(simulateTemplateBody, &template)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalling simulate template response failed: %w", err)
+	}
+
+	// Data stream mappings cannot be used in regular indexes.
+	delete(template.Template.Mappings, "_data_stream_timestamp")
+
+	// Only the mapping settings are kept, other settings as pipelines or lifecycle policies
+	// don't apply to the temporary index.
+	settings := map[string]any{}
+	if index, ok := template.Template.Settings["index"].(map[string]any); ok {
+		if mapping, ok := index["mapping"]; ok {
+			settings["index"] = map[string]any{"mapping": mapping}
+		}
+	}
+
+	request, err := json.Marshal(map[string]any{
+		"settings": settings,
+		"mappings": template.Template.Mappings,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshalling create index request failed: %w", err)
+	}
+	return request, nil
+}
+
+func bulkIndexDocuments(ctx context.Context, api *elasticsearch.API, indexName string, documents []json.RawMessage) ([]IndexedDocument, error) {
+	request, err := bulkRequestBody(documents)
+	if err != nil {
+		return nil, err
+	}
+	if len(request) == 0 {
+		return make([]IndexedDocument, len(documents)), nil
+	}
+
+	resp, err := api.Bulk(bytes.NewReader(request),
+		api.Bulk.WithContext(ctx),
+		api.Bulk.WithIndex(indexName),
+		// Documents are already processed, the default pipeline of the index must not be used.
+		api.Bulk.WithPipeline("_none"),
+		api.Bulk.WithRefresh("true"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("bulk API call failed (index: %s): %w", indexName, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bulk response body: %w", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("unexpected response status for bulk (%d): %s: %w", resp.StatusCode, resp.Status(), elasticsearch.NewError(body))
+	}
+
+	return indexedDocuments(body, len(documents))
+}
+
+// bulkRequestBody builds the body of a bulk request to create the documents. Documents are identified
+// by their position, so results can be matched with them, nil documents are skipped. Documents are
+// compacted, as each one must be in a single line of the request.
+func bulkRequestBody(documents []json.RawMessage) ([]byte, error) {
+	var request bytes.Buffer
+	for i, doc := range documents {
+		if doc == nil {
+			continue
+		}
+		fmt.Fprintf(&request, `{"create":{"_id":"%d"}}`+"\n", i)
+		err := json.Compact(&request, doc)
+		if err != nil {
+			return nil, fmt.Errorf("compacting document %d failed: %w", i, err)
+		}
+		request.WriteString("\n")
+	}
+	return request.Bytes(), nil
+}
+
+// indexedDocuments returns the result of indexing each document from the response of the bulk API.
+// Items in bulk responses are matched with the documents by their IDs, that are their positions.
+func indexedDocuments(bulkBody []byte, count int) ([]IndexedDocument, error) {
+	var response bulkResponse
+	err := json.Unmarshal(bulkBody, &response)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalling bulk response failed: %w", err)
+	}
+
+	docs := make([]IndexedDocument, count)
+	for _, item := range response.Items {
+		for _, result := range item {
+			i, err := strconv.Atoi(result.ID)
+			if err != nil || i < 0 || i >= count {
+				return nil, fmt.Errorf("unexpected document ID %q in bulk response", result.ID)
+			}
+			if len(result.Error) > 0 {
+				docs[i].Failure = string(result.Error)
+			}
+		}
+	}
+	return docs, nil
+}
+
+// searchIgnoredFields returns the fields ignored in the documents with the given IDs, for the
+// documents with ignored fields.
+func searchIgnoredFields(ctx context.Context, api *elasticsearch.API, indexName string, ids []string) (map[string][]string, error) {
+	query, err := json.Marshal(map[string]any{
+		"_source": false,
+		"query": map[string]any{
+			"bool": map[string]any{
+				"filter": []map[string]any{
+					{"ids": map[string]any{"values": ids}},
+					{"exists": map[string]any{"field": "_ignored"}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshalling search request failed: %w", err)
+	}
+
+	resp, err := api.Search(
+		api.Search.WithContext(ctx),
+		api.Search.WithIndex(indexName),
+		api.Search.WithBody(bytes.NewReader(query)),
+		api.Search.WithSize(len(ids)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search API call failed (index: %s): %w", indexName, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read search response body: %w", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("unexpected response status for search (%d): %s: %w", resp.StatusCode, resp.Status(), elasticsearch.NewError(body))
+	}
+
+	return ignoredFields(body)
+}
+
+// ignoredFields returns the fields ignored in each document, by document ID, from the response
+// of a search of documents with ignored fields.
+func ignoredFields(searchBody []byte) (map[string][]string, error) {
+	var response searchIgnoredResponse
+	err := json.Unmarshal(searchBody, &response)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalling search response failed: %w", err)
+	}
+
+	ignored := make(map[string][]string)
+	for _, hit := range response.Hits.Hits {
+		ignored[hit.ID] = hit.Ignored
+	}
+	return ignored, nil
+}
+
+func deleteIndex(ctx context.Context, api *elasticsearch.API, indexName string) error {
+	resp, err := api.Indices.Delete([]string{indexName},
+		api.Indices.Delete.WithContext(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("delete index API call failed (index: %s): %w", indexName, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return fmt.Errorf("failed to delete index %s: %s", indexName, resp.String())
+	}
+	return nil
+}
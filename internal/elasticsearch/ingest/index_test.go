@@ -0,0 +1,100 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package ingest
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateIndexRequest(t *testing.T) {
+	body, err := os.ReadFile("testdata/index-documents/simulate-template.json")
+	require.NoError(t, err)
+
+	request, err := createIndexRequest(body)
+	require.NoError(t, err)
+
+	expected := `{
+  "settings": {
+    "index": {
+      "mapping": {
+        "ignore_malformed": "true",
+        "total_fields": {"limit": "1000"}
+      }
+    }
+  },
+  "mappings": {
+    "properties": {
+      "@timestamp": {"type": "date"},
+      "message": {"type": "keyword", "ignore_above": 10}
+    }
+  }
+}`
+	assert.JSONEq(t, expected, string(request))
+}
+
+func TestBulkRequestBody(t *testing.T) {
+	documents := []json.RawMessage{
+		json.RawMessage(`{"message": "first"}`),
+		nil,
+		json.RawMessage("  {\"message\": \"second\"}\n"),
+		json.RawMessage(`{
+    "message": "third",
+    "tags": [
+        "multi",
+        "line"
+    ]
+}`),
+	}
+
+	expected := `{"create":{"_id":"0"}}
+{"message":"first"}
+{"create":{"_id":"2"}}
+{"message":"second"}
+{"create":{"_id":"3"}}
+{"message":"third","tags":["multi","line"]}
+`
+	body, err := bulkRequestBody(documents)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(body))
+
+	body, err = bulkRequestBody([]json.RawMessage{nil})
+	require.NoError(t, err)
+	assert.Empty(t, body)
+
+	_, err = bulkRequestBody([]json.RawMessage{json.RawMessage(`{"message": `)})
+	assert.ErrorContains(t, err, "compacting document 0 failed")
+}
+
+func TestIndexedDocuments(t *testing.T) {
+	body, err := os.ReadFile("testdata/index-documents/bulk.json")
+	require.NoError(t, err)
+
+	docs, err := indexedDocuments(body, 5)
+	require.NoError(t, err)
+	require.Len(t, docs, 5)
+
+	assert.Equal(t, IndexedDocument{}, docs[0])
+	assert.Equal(t, IndexedDocument{}, docs[1])
+	assert.Contains(t, docs[2].Failure, "document_parsing_exception")
+	assert.Equal(t, IndexedDocument{}, docs[3])
+	assert.Equal(t, IndexedDocument{}, docs[4])
+
+	_, err = indexedDocuments(body, 4)
+	assert.ErrorContains(t, err, `unexpected document ID "4" in bulk response`)
+}
+
+func TestIgnoredFields(t *testing.T) {
+	body, err := os.ReadFile("testdata/index-documents/search-ignored.json")
+	require.NoError(t, err)
+
+	ignored, err := ignoredFields(body)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"4": {"message"}}, ignored)
+}
@@ -36,6 +36,40 @@ type PipelineStats struct {
 // PipelineStatsMap holds the stats for a set of pipelines.
 type PipelineStatsMap map[string]PipelineStats
 
+// Sub returns the stats accumulated since the given previous stats were collected.
+// Current is the number of operations in progress, so it is not subtracted.
+func (r StatsRecord) Sub(prev StatsRecord) StatsRecord {
+	return StatsRecord{
+		Count:        r.Count - prev.Count,
+		Current:      r.Current,
+		Failed:       r.Failed - prev.Failed,
+		TimeInMillis: r.TimeInMillis - prev.TimeInMillis,
+	}
+}
+
+// Delta returns the stats accumulated since the given previous stats were collected. Pipelines
+// not found in the previous stats, or whose processors changed, are returned as they are.
+func (m PipelineStatsMap) Delta(prev PipelineStatsMap) PipelineStatsMap {
+	delta := make(PipelineStatsMap, len(m))
+	for name, stats := range m {
+		prevStats, found := prev[name]
+		if !found || len(prevStats.Processors) != len(stats.Processors) {
+			delta[name] = stats
+			continue
+		}
+		pipelineDelta := PipelineStats{
+			StatsRecord: stats.StatsRecord.Sub(prevStats.StatsRecord),
+			Processors:  make([]ProcessorStats, len(stats.Processors)),
+		}
+		for i, processor := range stats.Processors {
+			processor.Stats = processor.Stats.Sub(prevStats.Processors[i].Stats)
+			pipelineDelta.Processors[i] = processor
+		}
+		delta[name] = pipelineDelta
+	}
+	return delta
+}
+
 type wrappedProcessor map[string]ProcessorStats
 
 // Extract ProcessorStats from an object in the form:
@@ -196,3 +196,41 @@ func TestGetPipelineStats(t *testing.T) {
 		})
 	}
 }
+
+func TestPipelineStatsDelta(t *testing.T) {
+	prev := PipelineStatsMap{
+		"p1": PipelineStats{
+			StatsRecord: StatsRecord{Count: 10, TimeInMillis: 5},
+			Processors: []ProcessorStats{
+				{Type: "set", Stats: StatsRecord{Count: 10, TimeInMillis: 2}},
+				{Type: "drop", Conditional: true, Stats: StatsRecord{Count: 4, Failed: 1, TimeInMillis: 1}},
+			},
+		},
+	}
+	current := PipelineStatsMap{
+		"p1": PipelineStats{
+			StatsRecord: StatsRecord{Count: 110, Current: 1, TimeInMillis: 55},
+			Processors: []ProcessorStats{
+				{Type: "set", Stats: StatsRecord{Count: 110, TimeInMillis: 22}},
+				{Type: "drop", Conditional: true, Stats: StatsRecord{Count: 14, Failed: 3, TimeInMillis: 2}},
+			},
+		},
+		"p2": PipelineStats{
+			StatsRecord: StatsRecord{Count: 7, TimeInMillis: 3},
+		},
+	}
+
+	expected := PipelineStatsMap{
+		"p1": PipelineStats{
+			StatsRecord: StatsRecord{Count: 100, Current: 1, TimeInMillis: 50},
+			Processors: []ProcessorStats{
+				{Type: "set", Stats: StatsRecord{Count: 100, TimeInMillis: 20}},
+				{Type: "drop", Conditional: true, Stats: StatsRecord{Count: 10, Failed: 2, TimeInMillis: 1}},
+			},
+		},
+		"p2": PipelineStats{
+			StatsRecord: StatsRecord{Count: 7, TimeInMillis: 3},
+		},
+	}
+	assert.Equal(t, expected, current.Delta(prev))
+}
@@ -12,6 +12,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -29,10 +30,27 @@ type simulatePipelineResponse struct {
 type pipelineDocument struct {
 	Index  string          `json:"_index"`
 	Source json.RawMessage `json:"_source"`
+	Ingest *ingestMetadata `json:"_ingest,omitempty"`
+}
+
+type ingestMetadata struct {
+	Timestamp string `json:"timestamp"`
 }
 
 type pipelineIngestedDocument struct {
-	Doc pipelineDocument `json:"doc"`
+	Doc *pipelineDocument `json:"doc"`
+}
+
+// SimulatedDocument is a document processed by a pipeline in a simulation. The Simulate
+// pipeline API doesn't apply mappings, so fields ignored at index time are not reported,
+// use IndexPipelineDocuments to get them.
+type SimulatedDocument struct {
+	// Source is the processed document, it is nil if the document was dropped.
+	Source json.RawMessage
+
+	// Index is the index where the document would be stored, it is different to the simulated
+	// data stream if the document was rerouted.
+	Index string
 }
 
 // Pipeline represents a pipeline resource loaded from a file
@@ -73,12 +91,35 @@ func (p *Pipeline) MarshalJSON() (asJSON []byte, err error) {
 	return asJSON, nil
 }
 
+// SimulatePipeline processes the events with the given pipeline, and returns the processed events.
 func SimulatePipeline(ctx context.Context, api *elasticsearch.API, pipelineName string, events []json.RawMessage, simulateDataStream string) ([]json.RawMessage, error) {
+	docs, err := SimulatePipelineDocuments(ctx, api, pipelineName, events, simulateDataStream, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	processedEvents := make([]json.RawMessage, len(docs))
+	for i, doc := range docs {
+		processedEvents[i] = doc.Source
+	}
+	return processedEvents, nil
+}
+
+// SimulatePipelineDocuments processes the events with the given pipeline, and returns the processed
+// documents with the details reported by the simulation. If ingestTimestamp is not zero, it is used
+// as the ingest timestamp of the documents instead of the current time.
+func SimulatePipelineDocuments(ctx context.Context, api *elasticsearch.API, pipelineName string, events []json.RawMessage, simulateDataStream string, ingestTimestamp time.Time) ([]SimulatedDocument, error) {
+	var ingest *ingestMetadata
+	if !ingestTimestamp.IsZero() {
+		ingest = &ingestMetadata{Timestamp: ingestTimestamp.Format(time.RFC3339Nano)}
+	}
+
 	var request simulatePipelineRequest
 	for _, event := range events {
 		request.Docs = append(request.Docs, pipelineDocument{
 			Index:  simulateDataStream,
 			Source: event,
+			Ingest: ingest,
 		})
 	}
 
@@ -111,11 +152,19 @@ func SimulatePipeline(ctx context.Context, api *elasticsearch.API, pipelineName
 		return nil, fmt.Errorf("unmarshalling simulate request failed: %w", err)
 	}
 
-	processedEvents := make([]json.RawMessage, len(response.Docs))
+	return simulatedDocuments(response), nil
+}
+
+func simulatedDocuments(response simulatePipelineResponse) []SimulatedDocument {
+	docs := make([]SimulatedDocument, len(response.Docs))
 	for i, doc := range response.Docs {
-		processedEvents[i] = doc.Doc.Source
+		if doc.Doc == nil {
+			continue
+		}
+		docs[i].Source = doc.Doc.Source
+		docs[i].Index = doc.Doc.Index
 	}
-	return processedEvents, nil
+	return docs
 }
 
 func UninstallPipelines(ctx context.Context, api *elasticsearch.API, pipelines []Pipeline) error {
@@ -5,9 +5,11 @@
 package ingest
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPipelineFileName(t *testing.T) {
@@ -86,3 +88,24 @@ func TestPipelineMarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestSimulatedDocuments(t *testing.T) {
+	body := `{
+  "docs": [
+    {"doc": {"_index": "logs-test-default", "_source": {"message": "short"}}},
+    {"doc": {"_index": "logs-test.other-default", "_source": {"message": "long"}}},
+    null
+  ]
+}`
+	var response simulatePipelineResponse
+	err := json.Unmarshal([]byte(body), &response)
+	require.NoError(t, err)
+
+	docs := simulatedDocuments(response)
+	require.Len(t, docs, 3)
+	assert.JSONEq(t, `{"message": "short"}`, string(docs[0].Source))
+	assert.Equal(t, "logs-test-default", docs[0].Index)
+	assert.JSONEq(t, `{"message": "long"}`, string(docs[1].Source))
+	assert.Equal(t, "logs-test.other-default", docs[1].Index)
+	assert.Nil(t, docs[2].Source)
+}
@@ -0,0 +1,46 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/elastic/elastic-package/internal/common"
+)
+
+// PreviewTransform returns the documents that a transform with the given definition would
+// generate with the data currently available in its source indexes. The transform doesn't
+// need to be installed.
+func (client *Client) PreviewTransform(ctx context.Context, definition map[string]any) ([]common.MapStr, error) {
+	body, err := json.Marshal(definition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transform definition: %w", err)
+	}
+
+	resp, err := client.TransformPreviewTransform(
+		client.TransformPreviewTransform.WithContext(ctx),
+		client.TransformPreviewTransform.WithBody(bytes.NewReader(body)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("transform preview request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return nil, fmt.Errorf("failed to preview transform: %s", resp.String())
+	}
+
+	var preview struct {
+		Documents []common.MapStr `json:"preview"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&preview)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return preview.Documents, nil
+}
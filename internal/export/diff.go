@@ -0,0 +1,319 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/kibana"
+	"github.com/elastic/elastic-package/internal/logger"
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+// diffableAssetTypes are the folders of the Kibana assets that can be exported from Kibana
+// to be compared with their source. Index patterns are not exported, as they are managed by Fleet.
+var diffableAssetTypes = []string{
+	"dashboard",
+	"lens",
+	"map",
+	"search",
+	"tag",
+	"visualization",
+}
+
+// ignoredDiffKeys are the properties of saved objects that Kibana sets or updates when
+// installing them, they are not compared.
+var ignoredDiffKeys = []string{
+	"coreMigrationVersion",
+	"created_at",
+	"managed",
+	"migrationVersion",
+	"namespaces",
+	"typeMigrationVersion",
+	"updated_at",
+	"version",
+}
+
+// AssetDiff contains the differences between a Kibana asset in the package and the
+// saved object installed in Kibana.
+type AssetDiff struct {
+	Type string
+	ID   string
+
+	// Path is the path of the source file of the asset, relative to the package root.
+	Path string
+
+	// NotInstalled is true if the asset is not installed in Kibana.
+	NotInstalled bool
+
+	Changes []FieldChange
+}
+
+// FieldChange is a field with a different value in the source of an asset and in Kibana.
+type FieldChange struct {
+	// Path is the path of the field in the saved object, with dots between keys and
+	// indexes between brackets.
+	Path string
+
+	Source    any
+	Installed any
+
+	// InSource and InInstalled indicate if the field is present in each version of
+	// the asset.
+	InSource    bool
+	InInstalled bool
+}
+
+// DiffAssets exports from Kibana the saved objects of the Kibana assets of the package, and
+// compares them with their source files. If a data stream is given, only the assets that
+// reference its dataset are compared. Only assets with differences are returned.
+func DiffAssets(ctx context.Context, kibanaClient *kibana.Client, packageRoot string, dataStream string) ([]AssetDiff, error) {
+	m, err := packages.ReadPackageManifestFromPackageRoot(packageRoot)
+	if err != nil {
+		return nil, fmt.Errorf("reading package manifest failed (path: %s): %w", packageRoot, err)
+	}
+
+	sources, err := readSourceAssets(packageRoot)
+	if err != nil {
+		return nil, err
+	}
+	if dataStream != "" {
+		sources = filterAssetsByDataset(sources, m.Name+"."+dataStream)
+	}
+	if len(sources) == 0 {
+		return nil, nil
+	}
+
+	requested := make([]kibana.ExportSavedObjectsRequestObject, len(sources))
+	for i, source := range sources {
+		requested[i] = kibana.ExportSavedObjectsRequestObject{ID: source.id, Type: source.objectType}
+	}
+	found, err := kibanaClient.BulkGetSavedObjects(ctx, requested)
+	if err != nil {
+		return nil, fmt.Errorf("looking for installed assets failed: %w", err)
+	}
+	var installedRequest kibana.ExportSavedObjectsRequest
+	installedRequest.ExcludeExportDetails = true
+	for _, object := range found {
+		if object.Error != nil {
+			logger.Debugf("Asset %s %s not found in Kibana: %s", object.Type, object.ID, object.Error.Message)
+			continue
+		}
+		installedRequest.Objects = append(installedRequest.Objects, kibana.ExportSavedObjectsRequestObject{ID: object.ID, Type: object.Type})
+	}
+
+	installed := make(map[string]any)
+	if len(installedRequest.Objects) > 0 {
+		exported, err := kibanaClient.ExportSavedObjects(ctx, installedRequest)
+		if err != nil {
+			return nil, fmt.Errorf("exporting installed assets failed: %w", err)
+		}
+		objects := make([]common.MapStr, len(exported))
+		for i, object := range exported {
+			objects[i] = object
+		}
+		objects, err = applyTransformations(&transformationContext{packageName: m.Name}, objects)
+		if err != nil {
+			return nil, fmt.Errorf("can't transform Kibana objects: %w", err)
+		}
+		for _, object := range objects {
+			normalized, err := normalizeAsset(object)
+			if err != nil {
+				return nil, err
+			}
+			installed[assetKey(object["type"], object["id"])] = normalized
+		}
+	}
+
+	var diffs []AssetDiff
+	for _, source := range sources {
+		diff := AssetDiff{
+			Type: source.objectType,
+			ID:   source.id,
+			Path: source.path,
+		}
+		object, found := installed[assetKey(source.objectType, source.id)]
+		if !found {
+			diff.NotInstalled = true
+			diffs = append(diffs, diff)
+			continue
+		}
+		diff.Changes = diffValues("", source.object, object)
+		if len(diff.Changes) > 0 {
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs, nil
+}
+
+type sourceAsset struct {
+	objectType string
+	id         string
+	path       string
+	content    []byte
+	object     any
+}
+
+func readSourceAssets(packageRoot string) ([]sourceAsset, error) {
+	var assets []sourceAsset
+	for _, assetType := range diffableAssetTypes {
+		paths, err := filepath.Glob(filepath.Join(packageRoot, "kibana", assetType, "*.json"))
+		if err != nil {
+			return nil, fmt.Errorf("failed matching Kibana assets: %w", err)
+		}
+		for _, path := range paths {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read Kibana asset: %w", err)
+			}
+			var object common.MapStr
+			err = json.Unmarshal(content, &object)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode Kibana asset (path: %s): %w", path, err)
+			}
+			id, _ := object["id"].(string)
+			objectType, _ := object["type"].(string)
+			if id == "" || objectType == "" {
+				return nil, fmt.Errorf("missing id or type in Kibana asset (path: %s)", path)
+			}
+			normalized, err := normalizeAsset(object)
+			if err != nil {
+				return nil, err
+			}
+			rel, err := filepath.Rel(packageRoot, path)
+			if err != nil {
+				rel = path
+			}
+			assets = append(assets, sourceAsset{
+				objectType: objectType,
+				id:         id,
+				path:       rel,
+				content:    content,
+				object:     normalized,
+			})
+		}
+	}
+	return assets, nil
+}
+
+func filterAssetsByDataset(assets []sourceAsset, dataset string) []sourceAsset {
+	var filtered []sourceAsset
+	for _, asset := range assets {
+		if bytes.Contains(asset.content, []byte(dataset)) {
+			filtered = append(filtered, asset)
+		}
+	}
+	return filtered
+}
+
+// normalizeAsset removes the ignored properties of the saved object, and encodes and decodes it
+// again, so values of source and installed objects have the same types.
+func normalizeAsset(object common.MapStr) (any, error) {
+	stripped := make(common.MapStr, len(object))
+	for key, value := range object {
+		if !slices.Contains(ignoredDiffKeys, key) {
+			stripped[key] = value
+		}
+	}
+	d, err := json.Marshal(stripped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode saved object: %w", err)
+	}
+	var normalized any
+	err = json.Unmarshal(d, &normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode saved object: %w", err)
+	}
+	return normalized, nil
+}
+
+func assetKey(objectType, id any) string {
+	return fmt.Sprintf("%v/%v", objectType, id)
+}
+
+// diffValues compares the source and installed values, returning the paths of the fields
+// that are different.
+func diffValues(path string, source, installed any) []FieldChange {
+	switch source := source.(type) {
+	case map[string]any:
+		installed, ok := installed.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make(map[string]struct{})
+		for key := range source {
+			keys[key] = struct{}{}
+		}
+		for key := range installed {
+			keys[key] = struct{}{}
+		}
+		sortedKeys := make([]string, 0, len(keys))
+		for key := range keys {
+			sortedKeys = append(sortedKeys, key)
+		}
+		sort.Strings(sortedKeys)
+
+		var changes []FieldChange
+		for _, key := range sortedKeys {
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			sourceValue, inSource := source[key]
+			installedValue, inInstalled := installed[key]
+			if !inSource || !inInstalled {
+				changes = append(changes, FieldChange{
+					Path:        keyPath,
+					Source:      sourceValue,
+					Installed:   installedValue,
+					InSource:    inSource,
+					InInstalled: inInstalled,
+				})
+				continue
+			}
+			changes = append(changes, diffValues(keyPath, sourceValue, installedValue)...)
+		}
+		return changes
+	case []any:
+		installed, ok := installed.([]any)
+		if !ok {
+			break
+		}
+		var changes []FieldChange
+		for i := 0; i < max(len(source), len(installed)); i++ {
+			indexPath := path + "[" + strconv.Itoa(i) + "]"
+			switch {
+			case i >= len(installed):
+				changes = append(changes, FieldChange{Path: indexPath, Source: source[i], InSource: true})
+			case i >= len(source):
+				changes = append(changes, FieldChange{Path: indexPath, Installed: installed[i], InInstalled: true})
+			default:
+				changes = append(changes, diffValues(indexPath, source[i], installed[i])...)
+			}
+		}
+		return changes
+	}
+
+	if reflect.DeepEqual(source, installed) {
+		return nil
+	}
+	return []FieldChange{{
+		Path:        path,
+		Source:      source,
+		Installed:   installed,
+		InSource:    true,
+		InInstalled: true,
+	}}
+}
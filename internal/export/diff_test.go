@@ -0,0 +1,67 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package export
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/common"
+)
+
+func TestDiffValues(t *testing.T) {
+	source, err := normalizeAsset(common.MapStr{
+		"id":   "example-dashboard",
+		"type": "dashboard",
+		"attributes": map[string]any{
+			"title":       "[Example] Overview",
+			"description": "Overview of the example data",
+			"panelsJSON": []any{
+				map[string]any{"panelIndex": "1", "gridData": map[string]any{"w": 24, "h": 15}},
+				map[string]any{"panelIndex": "2"},
+			},
+		},
+		"coreMigrationVersion": "8.8.0",
+	})
+	require.NoError(t, err)
+
+	installed, err := normalizeAsset(common.MapStr{
+		"id":   "example-dashboard",
+		"type": "dashboard",
+		"attributes": map[string]any{
+			"title": "[Example] Overview",
+			"panelsJSON": []any{
+				map[string]any{"panelIndex": "1", "gridData": map[string]any{"w": 48, "h": 15}},
+			},
+			"timeRestore": true,
+		},
+		"coreMigrationVersion": "8.15.0",
+		"managed":              true,
+	})
+	require.NoError(t, err)
+
+	changes := diffValues("", source, installed)
+	expected := []FieldChange{
+		{Path: "attributes.description", Source: "Overview of the example data", InSource: true},
+		{Path: "attributes.panelsJSON[0].gridData.w", Source: float64(24), Installed: float64(48), InSource: true, InInstalled: true},
+		{Path: "attributes.panelsJSON[1]", Source: map[string]any{"panelIndex": "2"}, InSource: true},
+		{Path: "attributes.timeRestore", Installed: true, InInstalled: true},
+	}
+	assert.Equal(t, expected, changes)
+
+	assert.Empty(t, diffValues("", source, source))
+}
+
+func TestFilterAssetsByDataset(t *testing.T) {
+	assets := []sourceAsset{
+		{id: "logs", content: []byte(`{"query": "data_stream.dataset : \"example.logs\""}`)},
+		{id: "metrics", content: []byte(`{"query": "data_stream.dataset : \"example.metrics\""}`)},
+	}
+	filtered := filterAssetsByDataset(assets, "example.logs")
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "logs", filtered[0].id)
+}
@@ -5,9 +5,12 @@
 package fields
 
 import (
+	"archive/zip"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -17,8 +20,10 @@ import (
 
 	"github.com/elastic/elastic-package/internal/common"
 	"github.com/elastic/elastic-package/internal/configuration/locations"
+	"github.com/elastic/elastic-package/internal/environment"
 	"github.com/elastic/elastic-package/internal/logger"
 	"github.com/elastic/elastic-package/internal/packages/buildmanifest"
+	"github.com/elastic/elastic-package/internal/registry"
 )
 
 const (
@@ -26,10 +31,22 @@ const (
 	gitReferencePrefix = "git@"
 	localFilePrefix    = "file://"
 
+	packagesCacheDir = "packages"
+
 	ecsSchemaFile = "ecs_nested.yml"
 	ecsSchemaURL  = "https://raw.githubusercontent.com/elastic/ecs/%s/generated/ecs/%s"
 )
 
+var (
+	// enablePackageDependenciesEnv enables the resolution of external fields from other packages.
+	// The package spec doesn't allow yet to declare package dependencies in build.yml, nor other
+	// external sources than ecs, so packages using them don't pass spec validation.
+	enablePackageDependenciesEnv = environment.WithElasticPackagePrefix("FIELDS_ENABLE_PACKAGE_DEPENDENCIES")
+
+	// packageDependenciesRegistry is the package registry where package dependencies are downloaded from.
+	packageDependenciesRegistry = registry.Production
+)
+
 // DependencyManager is responsible for resolving external field dependencies.
 type DependencyManager struct {
 	schema map[string][]FieldDefinition
@@ -53,6 +70,20 @@ func buildFieldsSchema(deps buildmanifest.Dependencies) (map[string][]FieldDefin
 		return nil, fmt.Errorf("can't load fields: %w", err)
 	}
 	schema[ecsSchemaName] = ecsSchema
+
+	if len(deps.Packages) > 0 && !packageDependenciesEnabled() {
+		return nil, fmt.Errorf("package dependencies are not supported by the package spec yet, set %s=true to use them", enablePackageDependenciesEnv)
+	}
+	for _, dep := range deps.Packages {
+		if dep.Name == ecsSchemaName {
+			return nil, fmt.Errorf("package dependency cannot be named %q", ecsSchemaName)
+		}
+		packageSchema, err := loadPackageFieldsSchema(dep)
+		if err != nil {
+			return nil, fmt.Errorf("can't resolve package dependency %s (version: %s): %w", dep.Name, dep.Version, err)
+		}
+		schema[dep.Name] = packageSchema
+	}
 	return schema, nil
 }
 
@@ -116,7 +147,7 @@ func readECSFieldsSchemaFile(dep buildmanifest.ECSDependency) ([]byte, error) {
 		}
 
 		logger.Debugf("Cache downloaded schema: %s", cachedSchemaPath)
-		err = os.WriteFile(cachedSchemaPath, content, 0644)
+		err = writeFileAtomically(cachedSchemaPath, content)
 		if err != nil {
 			return nil, fmt.Errorf("can't write cached schema (path: %s): %w", cachedSchemaPath, err)
 		}
@@ -127,6 +158,120 @@ func readECSFieldsSchemaFile(dep buildmanifest.ECSDependency) ([]byte, error) {
 	return content, nil
 }
 
+// writeFileAtomically writes the file through a temporary file in the same directory, so
+// validators created concurrently never read a partially written file.
+func writeFileAtomically(path string, content []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(f.Name(), 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// packageDependenciesEnabled returns true if the resolution of fields from package dependencies
+// has been enabled with its environment variable.
+func packageDependenciesEnabled() bool {
+	v, ok := os.LookupEnv(enablePackageDependenciesEnv)
+	return ok && strings.ToLower(v) == "true"
+}
+
+func loadPackageFieldsSchema(dep buildmanifest.PackageDependency) ([]FieldDefinition, error) {
+	if dep.Name == "" || dep.Version == "" {
+		return nil, errors.New("name and version are required")
+	}
+
+	content, err := readPackageDependencyFile(dep)
+	if err != nil {
+		return nil, fmt.Errorf("error reading package: %w", err)
+	}
+
+	return parsePackageFieldsSchema(content)
+}
+
+func readPackageDependencyFile(dep buildmanifest.PackageDependency) ([]byte, error) {
+	loc, err := locations.NewLocationManager()
+	if err != nil {
+		return nil, fmt.Errorf("error fetching profile path: %w", err)
+	}
+	cachedPackagePath := filepath.Join(loc.CacheDir(locations.FieldsCacheName), packagesCacheDir, dep.Name, fmt.Sprintf("%s-%s.zip", dep.Name, dep.Version))
+	content, err := os.ReadFile(cachedPackagePath)
+	if err == nil {
+		return content, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("can't read cached package (path: %s): %w", cachedPackagePath, err)
+	}
+
+	logger.Debugf("Pulling package dependency %s (version: %s)", dep.Name, dep.Version)
+	content, err = packageDependenciesRegistry.DownloadPackage(dep.Name, dep.Version)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("Downloaded %d bytes", len(content))
+
+	cachedPackageDir := filepath.Dir(cachedPackagePath)
+	err = os.MkdirAll(cachedPackageDir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("can't create cache directories for package (path: %s): %w", cachedPackageDir, err)
+	}
+
+	logger.Debugf("Cache downloaded package: %s", cachedPackagePath)
+	err = writeFileAtomically(cachedPackagePath, content)
+	if err != nil {
+		return nil, fmt.Errorf("can't write cached package (path: %s): %w", cachedPackagePath, err)
+	}
+	return content, nil
+}
+
+// parsePackageFieldsSchema reads the fields defined in a built package, in its root
+// and in its data streams. External fields are already resolved in built packages.
+func parsePackageFieldsSchema(content []byte) ([]FieldDefinition, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("can't open zip package: %w", err)
+	}
+
+	var files []string
+	for _, pattern := range []string{"*/fields/*.yml", "*/data_stream/*/fields/*.yml"} {
+		matches, err := fs.Glob(zipReader, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("reading fields in zip package failed: %w", err)
+		}
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no fields files found in package")
+	}
+
+	var fields []FieldDefinition
+	for _, file := range files {
+		body, err := fs.ReadFile(zipReader, file)
+		if err != nil {
+			return nil, fmt.Errorf("reading fields file from zip package failed (path: %s): %w", file, err)
+		}
+		var u FieldDefinitions
+		err = yaml.Unmarshal(body, &u)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshalling field body failed (path: %s): %w", file, err)
+		}
+		fields = append(fields, u...)
+	}
+	return fields, nil
+}
+
 func parseECSFieldsSchema(content []byte) ([]FieldDefinition, error) {
 	var fields FieldDefinitions
 	err := yaml.Unmarshal(content, &fields)
@@ -285,6 +430,11 @@ func allowedTypeOverride(fromType, toType string) bool {
 	return false
 }
 
+// ImportField method returns the definition of a single external field in the schema with the given name.
+func (dm *DependencyManager) ImportField(schemaName, fieldPath string) (FieldDefinition, error) {
+	return dm.importField(schemaName, fieldPath)
+}
+
 // importField method resolves dependency on a single external field using available schemas.
 func (dm *DependencyManager) importField(schemaName, fieldPath string) (FieldDefinition, error) {
 	if dm == nil {
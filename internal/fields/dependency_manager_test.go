@@ -5,7 +5,11 @@
 package fields
 
 import (
+	"archive/zip"
+	"bytes"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,6 +17,7 @@ import (
 
 	"github.com/elastic/elastic-package/internal/common"
 	"github.com/elastic/elastic-package/internal/packages/buildmanifest"
+	"github.com/elastic/elastic-package/internal/registry"
 )
 
 func TestDependencyManagerInjectExternalFields(t *testing.T) {
@@ -785,3 +790,112 @@ func TestDependencyManagerWithECS(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePackageFieldsSchema(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	files := map[string]string{
+		"example-1.0.0/manifest.yml":                         "name: example\n",
+		"example-1.0.0/fields/base-fields.yml":               "- name: '@timestamp'\n  type: date\n",
+		"example-1.0.0/data_stream/log/fields/fields.yml":    "- name: example.log\n  type: group\n  fields:\n    - name: level\n      type: keyword\n",
+		"example-1.0.0/data_stream/metric/fields/fields.yml": "- name: example.metric.value\n  type: long\n",
+	}
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	schema, err := parsePackageFieldsSchema(buf.Bytes())
+	require.NoError(t, err)
+
+	dm := &DependencyManager{schema: map[string][]FieldDefinition{"example": schema}}
+	field, err := dm.ImportField("example", "example.log.level")
+	require.NoError(t, err)
+	assert.Equal(t, "keyword", field.Type)
+
+	field, err = dm.ImportField("example", "example.metric.value")
+	require.NoError(t, err)
+	assert.Equal(t, "long", field.Type)
+
+	_, err = dm.ImportField("other", "example.metric.value")
+	assert.Error(t, err)
+
+	buf.Reset()
+	w = zip.NewWriter(&buf)
+	_, err = w.Create("example-1.0.0/manifest.yml")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	_, err = parsePackageFieldsSchema(buf.Bytes())
+	assert.ErrorContains(t, err, "no fields files found")
+}
+
+func TestDependencyManagerWithInvalidPackageDependency(t *testing.T) {
+	t.Setenv(enablePackageDependenciesEnv, "true")
+	_, err := CreateFieldDependencyManager(buildmanifest.Dependencies{
+		Packages: []buildmanifest.PackageDependency{
+			{Name: "example"},
+		},
+	})
+	assert.ErrorContains(t, err, "can't resolve package dependency example")
+}
+
+func TestDependencyManagerWithDisabledPackageDependencies(t *testing.T) {
+	t.Setenv(enablePackageDependenciesEnv, "")
+	_, err := CreateFieldDependencyManager(buildmanifest.Dependencies{
+		Packages: []buildmanifest.PackageDependency{
+			{Name: "example", Version: "1.0.0"},
+		},
+	})
+	assert.ErrorContains(t, err, "package dependencies are not supported by the package spec yet")
+}
+
+func TestDependencyManagerWithPackageDependencyFromRegistry(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create("example-1.0.0/data_stream/log/fields/fields.yml")
+	require.NoError(t, err)
+	_, err = f.Write([]byte("- name: example.log.level\n  type: keyword\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		if r.URL.Path != "/epr/example/example-1.0.0.zip" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	defaultRegistry := packageDependenciesRegistry
+	packageDependenciesRegistry = registry.NewClient(server.URL)
+	defer func() { packageDependenciesRegistry = defaultRegistry }()
+
+	t.Setenv(enablePackageDependenciesEnv, "true")
+	t.Setenv("ELASTIC_PACKAGE_DATA_HOME", t.TempDir())
+
+	deps := buildmanifest.Dependencies{
+		Packages: []buildmanifest.PackageDependency{
+			{Name: "example", Version: "1.0.0"},
+		},
+	}
+	for i := 0; i < 2; i++ {
+		dm, err := CreateFieldDependencyManager(deps)
+		require.NoError(t, err)
+		field, err := dm.ImportField("example", "example.log.level")
+		require.NoError(t, err)
+		assert.Equal(t, "keyword", field.Type)
+	}
+	// The package is only downloaded once, then it is read from the cache.
+	assert.Equal(t, []string{"/epr/example/example-1.0.0.zip"}, requests)
+
+	deps.Packages[0].Version = "2.0.0"
+	_, err = CreateFieldDependencyManager(deps)
+	assert.ErrorContains(t, err, "package example-2.0.0 not found in the package registry")
+}
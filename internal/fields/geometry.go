@@ -0,0 +1,402 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// isGeometryType returns true for the types of fields whose values are geometries, that can be
+// given as objects, but are stored as single values.
+func isGeometryType(fieldType string) bool {
+	switch fieldType {
+	case "geo_shape", "shape", "point":
+		return true
+	}
+	return false
+}
+
+// validateShape validates the value of a geo_shape or shape field. Values can be GeoJSON
+// objects or WKT strings.
+func validateShape(val any) error {
+	switch val := val.(type) {
+	case map[string]any:
+		return validateGeoJSON(val)
+	case string:
+		return validateWKT(val)
+	default:
+		return fmt.Errorf("expected a GeoJSON object or a WKT string, found %T", val)
+	}
+}
+
+// validatePoint validates the value of a point field. Values can be objects with x and y
+// coordinates, strings with the "x,y" format, arrays with the [x, y] format, or points
+// in GeoJSON or WKT formats.
+func validatePoint(val any) error {
+	switch val := val.(type) {
+	case map[string]any:
+		if _, isGeoJSON := val["type"]; isGeoJSON {
+			return validateGeoJSONPoint(val)
+		}
+		for _, name := range []string{"x", "y"} {
+			coordinate, found := val[name]
+			if !found {
+				return fmt.Errorf("missing %q coordinate", name)
+			}
+			if _, ok := coordinate.(float64); !ok {
+				return fmt.Errorf("coordinate %q is not a number (%v)", name, coordinate)
+			}
+		}
+		for name := range val {
+			if name != "x" && name != "y" && name != "z" {
+				return fmt.Errorf("unexpected key %q, expected x and y coordinates", name)
+			}
+		}
+		return nil
+	case []any:
+		return validatePosition(val)
+	case string:
+		if geometryType, err := wktGeometryType(val); err == nil {
+			if geometryType != "point" {
+				return fmt.Errorf("expected a point, found a WKT %s", geometryType)
+			}
+			return validateWKT(val)
+		}
+		parts := strings.Split(val, ",")
+		if len(parts) != 2 && len(parts) != 3 {
+			return fmt.Errorf("expected a point with the \"x,y\" format, found %q", val)
+		}
+		for _, part := range parts {
+			if _, err := strconv.ParseFloat(strings.TrimSpace(part), 64); err != nil {
+				return fmt.Errorf("invalid coordinate %q in point %q", part, val)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("expected an object with x and y coordinates, or a string with the \"x,y\" format, found %T", val)
+	}
+}
+
+func validateGeoJSONPoint(geometry map[string]any) error {
+	geometryType, _ := geometry["type"].(string)
+	if !strings.EqualFold(geometryType, "point") {
+		return fmt.Errorf("expected a GeoJSON point, found type %q", geometry["type"])
+	}
+	return validateGeoJSON(geometry)
+}
+
+// validateGeoJSON validates the structure of a GeoJSON geometry, including the types supported
+// by Elasticsearch, and the envelope extension.
+func validateGeoJSON(geometry map[string]any) error {
+	t, found := geometry["type"]
+	if !found {
+		return errors.New("GeoJSON geometry without type")
+	}
+	geometryType, ok := t.(string)
+	if !ok {
+		return fmt.Errorf("GeoJSON geometry type is not a string (%v)", t)
+	}
+	geometryType = strings.ToLower(geometryType)
+
+	if geometryType == "geometrycollection" {
+		geometries, ok := geometry["geometries"].([]any)
+		if !ok {
+			return errors.New("GeoJSON geometry collection without a list of geometries")
+		}
+		for i, g := range geometries {
+			m, ok := g.(map[string]any)
+			if !ok {
+				return fmt.Errorf("geometry %d in collection is not an object", i)
+			}
+			if err := validateGeoJSON(m); err != nil {
+				return fmt.Errorf("geometry %d in collection: %w", i, err)
+			}
+		}
+		return nil
+	}
+
+	coordinates, found := geometry["coordinates"]
+	if !found {
+		return fmt.Errorf("GeoJSON %s without coordinates", geometryType)
+	}
+	err := validateCoordinates(geometryType, coordinates)
+	if err != nil {
+		return fmt.Errorf("invalid coordinates for GeoJSON %s: %w", geometryType, err)
+	}
+	return nil
+}
+
+// validateCoordinates validates the structure of the coordinates of a geometry of the given type,
+// as they are defined in GeoJSON.
+func validateCoordinates(geometryType string, coordinates any) error {
+	switch geometryType {
+	case "point":
+		return validatePosition(coordinates)
+	case "multipoint":
+		return validateList(coordinates, 0, validatePosition)
+	case "linestring":
+		return validateLineString(coordinates)
+	case "multilinestring":
+		return validateList(coordinates, 0, validateLineString)
+	case "polygon":
+		return validatePolygon(coordinates)
+	case "multipolygon":
+		return validateList(coordinates, 0, validatePolygon)
+	case "envelope":
+		return validateList(coordinates, 2, validatePosition)
+	default:
+		return fmt.Errorf("unknown geometry type %q", geometryType)
+	}
+}
+
+func validateList(val any, minLength int, validate func(any) error) error {
+	list, ok := val.([]any)
+	if !ok {
+		return fmt.Errorf("expected a list, found %v", val)
+	}
+	if len(list) < minLength {
+		return fmt.Errorf("expected at least %d elements, found %d", minLength, len(list))
+	}
+	for _, element := range list {
+		if err := validate(element); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePosition(val any) error {
+	position, ok := val.([]any)
+	if !ok {
+		return fmt.Errorf("expected a position, found %v", val)
+	}
+	if len(position) < 2 || len(position) > 3 {
+		return fmt.Errorf("expected a position with 2 or 3 coordinates, found %v", val)
+	}
+	for _, coordinate := range position {
+		if _, ok := coordinate.(float64); !ok {
+			return fmt.Errorf("coordinate %v is not a number", coordinate)
+		}
+	}
+	return nil
+}
+
+func validateLineString(val any) error {
+	return validateList(val, 2, validatePosition)
+}
+
+func validatePolygon(val any) error {
+	return validateList(val, 1, validateLinearRing)
+}
+
+func validateLinearRing(val any) error {
+	err := validateList(val, 4, validatePosition)
+	if err != nil {
+		return fmt.Errorf("invalid polygon ring: %w", err)
+	}
+	ring := val.([]any)
+	first := fmt.Sprint(ring[0])
+	last := fmt.Sprint(ring[len(ring)-1])
+	if first != last {
+		return fmt.Errorf("polygon ring is not closed, first position %s is different to last position %s", first, last)
+	}
+	return nil
+}
+
+// validateWKT validates the structure of a geometry in WKT format, including the BBOX extension
+// supported by Elasticsearch.
+func validateWKT(s string) error {
+	p := wktParser{input: s}
+	err := p.parseGeometry()
+	if err != nil {
+		return fmt.Errorf("invalid WKT %q: %w", s, err)
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return fmt.Errorf("invalid WKT %q: unexpected content at position %d", s, p.pos)
+	}
+	return nil
+}
+
+// wktGeometryType returns the geometry type of a WKT string, in lowercase.
+func wktGeometryType(s string) (string, error) {
+	p := wktParser{input: s}
+	keyword := p.parseKeyword()
+	p.skipSpaces()
+	if keyword == "" || (!strings.HasPrefix(p.input[p.pos:], "(") && !strings.EqualFold(p.input[p.pos:], "empty")) {
+		return "", fmt.Errorf("invalid WKT %q: expected a geometry type", s)
+	}
+	return keyword, nil
+}
+
+// wktParser is a minimal parser for WKT geometries. Coordinates are converted to the same
+// nested lists used in GeoJSON, so they can be validated in the same way.
+type wktParser struct {
+	input string
+	pos   int
+}
+
+func (p *wktParser) parseGeometry() error {
+	geometryType := p.parseKeyword()
+	if geometryType == "" {
+		return errors.New("expected a geometry type")
+	}
+	if p.parseKeyword() == "empty" {
+		return nil
+	}
+
+	switch geometryType {
+	case "geometrycollection":
+		return p.parseList(func() error {
+			return p.parseGeometry()
+		})
+	case "bbox":
+		var values []any
+		err := p.parseList(func() error {
+			n, err := p.parseNumber()
+			values = append(values, n)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if len(values) != 4 {
+			return fmt.Errorf("expected 4 values in bbox, found %d", len(values))
+		}
+		return nil
+	case "point", "multipoint", "linestring", "multilinestring", "polygon", "multipolygon":
+		coordinates, err := p.parseCoordinates()
+		if err != nil {
+			return err
+		}
+		switch geometryType {
+		case "point":
+			if len(coordinates) != 1 {
+				return fmt.Errorf("expected a single position in point, found %d", len(coordinates))
+			}
+			return validateCoordinates(geometryType, coordinates[0])
+		case "multipoint":
+			// Points of multipoints can be optionally enclosed in parenthesis.
+			for i, point := range coordinates {
+				if list, ok := point.([]any); ok && len(list) == 1 {
+					coordinates[i] = list[0]
+				}
+			}
+		}
+		return validateCoordinates(geometryType, coordinates)
+	default:
+		return fmt.Errorf("unknown geometry type %q", geometryType)
+	}
+}
+
+// parseCoordinates parses a list of positions, or of lists of positions, enclosed in parenthesis.
+func (p *wktParser) parseCoordinates() ([]any, error) {
+	var coordinates []any
+	err := p.parseList(func() error {
+		p.skipSpaces()
+		if p.pos < len(p.input) && p.input[p.pos] == '(' {
+			list, err := p.parseCoordinates()
+			coordinates = append(coordinates, list)
+			return err
+		}
+		position, err := p.parsePosition()
+		coordinates = append(coordinates, position)
+		return err
+	})
+	return coordinates, err
+}
+
+// parseList parses a list of comma-separated elements enclosed in parenthesis, calling
+// parseElement for each one of them.
+func (p *wktParser) parseList(parseElement func() error) error {
+	p.skipSpaces()
+	if p.pos >= len(p.input) || p.input[p.pos] != '(' {
+		return fmt.Errorf("expected '(' at position %d", p.pos)
+	}
+	p.pos++
+	for {
+		err := parseElement()
+		if err != nil {
+			return err
+		}
+		p.skipSpaces()
+		if p.pos >= len(p.input) {
+			return errors.New("expected ')' at end of input")
+		}
+		switch p.input[p.pos] {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return nil
+		default:
+			return fmt.Errorf("expected ',' or ')' at position %d", p.pos)
+		}
+	}
+}
+
+func (p *wktParser) parsePosition() ([]any, error) {
+	var position []any
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.input) || p.input[p.pos] == ',' || p.input[p.pos] == ')' {
+			break
+		}
+		n, err := p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+		position = append(position, n)
+	}
+	return position, nil
+}
+
+func (p *wktParser) parseNumber() (float64, error) {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.input) && strings.ContainsRune("0123456789+-.eE", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	n, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected a number at position %d", start)
+	}
+	return n, nil
+}
+
+// parseKeyword parses a keyword, returning it in lowercase, or an empty string if there is none.
+func (p *wktParser) parseKeyword() string {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.input) && unicode.IsLetter(rune(p.input[p.pos])) {
+		p.pos++
+	}
+	return strings.ToLower(p.input[start:p.pos])
+}
+
+func (p *wktParser) skipSpaces() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+// isCoordinatesArray returns true if the value is an array of numbers, as the ones used to give
+// the coordinates of a point.
+func isCoordinatesArray(val any) bool {
+	list, ok := val.([]any)
+	if !ok || len(list) == 0 {
+		return false
+	}
+	for _, element := range list {
+		if _, ok := element.(float64); !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,164 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateShape(t *testing.T) {
+	cases := []struct {
+		value string
+		err   string
+	}{
+		{value: `"POINT (-77.03653 38.897676)"`},
+		{value: `"point (1 2)"`},
+		{value: `"POINT EMPTY"`},
+		{value: `"LINESTRING (-77.03 38.89, -77.00 38.88)"`},
+		{value: `"POLYGON ((100 0, 101 0, 101 1, 100 1, 100 0), (100.2 0.2, 100.8 0.2, 100.8 0.8, 100.2 0.8, 100.2 0.2))"`},
+		{value: `"MULTIPOINT (1 2, 3 4)"`},
+		{value: `"MULTIPOINT ((1 2), (3 4))"`},
+		{value: `"MULTIPOLYGON (((102 2, 103 2, 103 3, 102 3, 102 2)), ((100 0, 101 0, 101 1, 100 1, 100 0)))"`},
+		{value: `"GEOMETRYCOLLECTION (POINT (100 0), LINESTRING (101 0, 102 1))"`},
+		{value: `"BBOX (100.0, 102.0, 2.0, 0.0)"`},
+		{value: `{"type": "Point", "coordinates": [1, 2]}`},
+		{value: `{"type": "polygon", "coordinates": [[[1, 1], [2, 1], [2, 2], [1, 1]]]}`},
+		{value: `{"type": "envelope", "coordinates": [[1, 2], [3, 0]]}`},
+		{value: `{"type": "GeometryCollection", "geometries": [{"type": "Point", "coordinates": [1, 2]}]}`},
+		{
+			value: `"POINT (1)"`,
+			err:   `invalid WKT "POINT (1)": expected a position with 2 or 3 coordinates, found [1]`,
+		},
+		{
+			value: `"POINT (1 2"`,
+			err:   `invalid WKT "POINT (1 2": expected ')' at end of input`,
+		},
+		{
+			value: `"POINT (1 2) foo"`,
+			err:   `invalid WKT "POINT (1 2) foo": unexpected content at position 12`,
+		},
+		{
+			value: `"POLYGON ((100 0, 101 0, 101 1, 100 0.5))"`,
+			err:   `invalid WKT "POLYGON ((100 0, 101 0, 101 1, 100 0.5))": polygon ring is not closed, first position [100 0] is different to last position [100 0.5]`,
+		},
+		{
+			value: `"CIRCLE (1 2)"`,
+			err:   `invalid WKT "CIRCLE (1 2)": unknown geometry type "circle"`,
+		},
+		{
+			value: `"BBOX (1, 2, 3)"`,
+			err:   `invalid WKT "BBOX (1, 2, 3)": expected 4 values in bbox, found 3`,
+		},
+		{
+			value: `{"type": "Point"}`,
+			err:   `GeoJSON point without coordinates`,
+		},
+		{
+			value: `{"coordinates": [1, 2]}`,
+			err:   `GeoJSON geometry without type`,
+		},
+		{
+			value: `{"type": "LineString", "coordinates": [[1, 2]]}`,
+			err:   `invalid coordinates for GeoJSON linestring: expected at least 2 elements, found 1`,
+		},
+		{
+			value: `1`,
+			err:   `expected a GeoJSON object or a WKT string, found float64`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.value, func(t *testing.T) {
+			var value any
+			require.NoError(t, json.Unmarshal([]byte(c.value), &value))
+			err := validateShape(value)
+			if c.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, c.err)
+			}
+		})
+	}
+}
+
+func TestValidatePoint(t *testing.T) {
+	cases := []struct {
+		value string
+		err   string
+	}{
+		{value: `{"x": 1, "y": 2}`},
+		{value: `"1.5, 2"`},
+		{value: `"POINT (1 2)"`},
+		{value: `[1, 2]`},
+		{value: `{"type": "Point", "coordinates": [1, 2]}`},
+		{
+			value: `{"x": 1}`,
+			err:   `missing "y" coordinate`,
+		},
+		{
+			value: `{"x": 1, "y": "a"}`,
+			err:   `coordinate "y" is not a number (a)`,
+		},
+		{
+			value: `"1"`,
+			err:   `expected a point with the "x,y" format, found "1"`,
+		},
+		{
+			value: `"a,b"`,
+			err:   `invalid coordinate "a" in point "a,b"`,
+		},
+		{
+			value: `"LINESTRING (1 2, 3 4)"`,
+			err:   `expected a point, found a WKT linestring`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.value, func(t *testing.T) {
+			var value any
+			require.NoError(t, json.Unmarshal([]byte(c.value), &value))
+			err := validatePoint(value)
+			if c.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, c.err)
+			}
+		})
+	}
+}
+
+func TestValidate_Geometries(t *testing.T) {
+	v := Validator{
+		Schema: []FieldDefinition{
+			{Name: "area", Type: "geo_shape"},
+			{Name: "location", Type: "point"},
+		},
+		disabledDependencyManagement: true,
+		specVersion:                  *semver3_0_1,
+	}
+
+	errs := v.ValidateDocumentMap(map[string]any{
+		"area": map[string]any{
+			"type":        "LineString",
+			"coordinates": []any{[]any{1.0, 2.0}, []any{3.0, 4.0}},
+		},
+		"location": []any{1.0, 2.0},
+	})
+	require.Empty(t, errs)
+
+	errs = v.ValidateDocumentMap(map[string]any{
+		"area": map[string]any{
+			"type": "LineString",
+		},
+		"location": map[string]any{"x": 1.0},
+	})
+	require.Len(t, errs, 2)
+	assert.ErrorContains(t, errs, `field "area" has an invalid geo_shape value: GeoJSON linestring without coordinates`)
+	assert.ErrorContains(t, errs, `field "location" has an invalid point value: missing "y" coordinate`)
+}
@@ -17,32 +17,127 @@ import (
 // FieldDefinition describes a single field with its properties.
 type FieldDefinition struct {
 	Name           string            `yaml:"name"`
-	Description    string            `yaml:"description"`
-	Type           string            `yaml:"type"`
-	ObjectType     string            `yaml:"object_type"`
-	Value          string            `yaml:"value"` // The value to associate with a constant_keyword field.
-	AllowedValues  AllowedValues     `yaml:"allowed_values"`
-	ExpectedValues []string          `yaml:"expected_values"`
-	Pattern        string            `yaml:"pattern"`
-	Unit           string            `yaml:"unit"`
-	MetricType     string            `yaml:"metric_type"`
-	External       string            `yaml:"external"`
-	Index          *bool             `yaml:"index"`
-	DocValues      *bool             `yaml:"doc_values"`
+	Description    string            `yaml:"description,omitempty"`
+	Type           string            `yaml:"type,omitempty"`
+	ObjectType     string            `yaml:"object_type,omitempty"`
+	Value          string            `yaml:"value,omitempty"` // The value to associate with a constant_keyword field.
+	AllowedValues  AllowedValues     `yaml:"allowed_values,omitempty"`
+	ExpectedValues []string          `yaml:"expected_values,omitempty"`
+	Pattern        string            `yaml:"pattern,omitempty"`
+	Unit           string            `yaml:"unit,omitempty"`
+	MetricType     string            `yaml:"metric_type,omitempty"`
+	Dimension      bool              `yaml:"dimension,omitempty"`
+	External       string            `yaml:"external,omitempty"`
+	Index          *bool             `yaml:"index,omitempty"`
+	DocValues      *bool             `yaml:"doc_values,omitempty"`
+	IndexPrefixes  *IndexPrefixes    `yaml:"index_prefixes,omitempty"`
+	DepthLimit     *int              `yaml:"depth_limit,omitempty"` // Maximum depth of flattened fields.
+	Dynamic        string            `yaml:"dynamic,omitempty"`     // Dynamic mapping of objects: true, false, strict or runtime.
 	Normalize      []string          `yaml:"normalize,omitempty"`
+	ScalingFactor  float64           `yaml:"scaling_factor,omitempty"` // Scaling factor of scaled_float fields.
+	IgnoreAbove    int               `yaml:"ignore_above,omitempty"`   // Longer values of keyword fields are not indexed.
+	DateFormat     string            `yaml:"date_format,omitempty"`    // Custom format of date fields.
 	Fields         FieldDefinitions  `yaml:"fields,omitempty"`
 	MultiFields    []FieldDefinition `yaml:"multi_fields,omitempty"`
 	Reusable       *ReusableConfig   `yaml:"reusable,omitempty"`
 
+	// Runtime is set for runtime fields, that are defined with a script in the mappings
+	// instead of being stored in the documents.
+	Runtime bool `yaml:"-"`
+
+	// Source is the location of the definition in the package files, when known.
+	Source *SourceLocation `yaml:"-"`
+
+	// line is the line of the definition in the document it was decoded from.
+	line int
+
 	// disallowAtTopLevel transfers the reusability config from parent groups to nested fields.
 	// It is negated respect to Reusable.TopLevel, so it is disabled by default.
 	disallowAtTopLevel bool
 }
 
+// IndexPrefixes contains the settings to index term prefixes in text fields.
+type IndexPrefixes struct {
+	MinChars *int `yaml:"min_chars,omitempty"`
+	MaxChars *int `yaml:"max_chars,omitempty"`
+}
+
+// Default values and limits of the index_prefixes settings, as enforced by Elasticsearch.
+const (
+	defaultIndexPrefixesMinChars = 2
+	defaultIndexPrefixesMaxChars = 5
+	indexPrefixesMaxCharsLimit   = 20
+)
+
+// Validate checks that the settings are within the bounds allowed by Elasticsearch.
+func (p IndexPrefixes) Validate() error {
+	minChars, maxChars := defaultIndexPrefixesMinChars, defaultIndexPrefixesMaxChars
+	if p.MinChars != nil {
+		minChars = *p.MinChars
+	}
+	if p.MaxChars != nil {
+		maxChars = *p.MaxChars
+	}
+
+	if minChars < 1 {
+		return fmt.Errorf("min_chars [%d] must be greater than zero", minChars)
+	}
+	if maxChars >= indexPrefixesMaxCharsLimit {
+		return fmt.Errorf("max_chars [%d] must be less than %d", maxChars, indexPrefixesMaxCharsLimit)
+	}
+	if minChars > maxChars {
+		return fmt.Errorf("min_chars [%d] must be less than or equal to max_chars [%d]", minChars, maxChars)
+	}
+	return nil
+}
+
 type ReusableConfig struct {
 	TopLevel bool `yaml:"top_level"`
 }
 
+// SourceLocation is the location of a field definition in the files of a package.
+type SourceLocation struct {
+	File string
+	Line int
+}
+
+func (l SourceLocation) String() string {
+	return fmt.Sprintf("%s:%d", l.File, l.Line)
+}
+
+// UnmarshalYAML decodes a field definition, keeping the line where it is defined.
+func (fd *FieldDefinition) UnmarshalYAML(value *yaml.Node) error {
+	// Use a type without this method to decode the definition with the default behaviour.
+	type rawFieldDefinition FieldDefinition
+	var raw rawFieldDefinition
+	err := value.Decode(&raw)
+	if err != nil {
+		return err
+	}
+	*fd = FieldDefinition(raw)
+	fd.line = value.Line
+
+	// Runtime fields can be declared with a boolean or with the script that calculates them.
+	var runtime struct {
+		Runtime yaml.Node `yaml:"runtime"`
+	}
+	err = value.Decode(&runtime)
+	if err != nil {
+		return err
+	}
+	switch runtime.Runtime.Tag {
+	case "":
+	case "!!bool":
+		err = runtime.Runtime.Decode(&fd.Runtime)
+		if err != nil {
+			return err
+		}
+	default:
+		fd.Runtime = runtime.Runtime.Value != ""
+	}
+	return nil
+}
+
 func (orig *FieldDefinition) Update(fd FieldDefinition) {
 	if fd.Name != "" {
 		orig.Name = fd.Name
@@ -74,15 +169,39 @@ func (orig *FieldDefinition) Update(fd FieldDefinition) {
 	if fd.MetricType != "" {
 		orig.MetricType = fd.MetricType
 	}
+	if fd.Dimension {
+		orig.Dimension = true
+	}
 	if fd.External != "" {
 		orig.External = fd.External
 	}
+	if fd.Runtime {
+		orig.Runtime = true
+	}
 	if fd.Index != nil {
 		orig.Index = fd.Index
 	}
 	if fd.DocValues != nil {
 		orig.DocValues = fd.DocValues
 	}
+	if fd.IndexPrefixes != nil {
+		orig.IndexPrefixes = fd.IndexPrefixes
+	}
+	if fd.DepthLimit != nil {
+		orig.DepthLimit = fd.DepthLimit
+	}
+	if fd.Dynamic != "" {
+		orig.Dynamic = fd.Dynamic
+	}
+	if fd.ScalingFactor != 0 {
+		orig.ScalingFactor = fd.ScalingFactor
+	}
+	if fd.IgnoreAbove != 0 {
+		orig.IgnoreAbove = fd.IgnoreAbove
+	}
+	if fd.DateFormat != "" {
+		orig.DateFormat = fd.DateFormat
+	}
 
 	if len(fd.Normalize) > 0 {
 		orig.Normalize = common.StringSlicesUnion(orig.Normalize, fd.Normalize)
@@ -252,6 +371,6 @@ func (avs AllowedValues) ExpectedEventTypes(value string) []string {
 // AllowedValue is one of the allowed values for a field.
 type AllowedValue struct {
 	Name               string   `yaml:"name"`
-	Description        string   `yaml:"description"`
-	ExpectedEventTypes []string `yaml:"expected_event_types"`
+	Description        string   `yaml:"description,omitempty"`
+	ExpectedEventTypes []string `yaml:"expected_event_types,omitempty"`
 }
@@ -162,6 +162,56 @@ func TestFieldDefinitionUpdate(t *testing.T) {
 				},
 			},
 		},
+		{
+			"dynamic mapping override",
+			FieldDefinition{
+				Name: "labels",
+				Type: "object",
+			},
+			FieldDefinition{
+				Name:    "labels",
+				Dynamic: "strict",
+			},
+			FieldDefinition{
+				Name:    "labels",
+				Type:    "object",
+				Dynamic: "strict",
+			},
+		},
+		{
+			"scaling factor override",
+			FieldDefinition{
+				Name:          "system.cpu.total.pct",
+				Type:          "scaled_float",
+				ScalingFactor: 1000,
+			},
+			FieldDefinition{
+				Name:          "system.cpu.total.pct",
+				ScalingFactor: 100,
+			},
+			FieldDefinition{
+				Name:          "system.cpu.total.pct",
+				Type:          "scaled_float",
+				ScalingFactor: 100,
+			},
+		},
+		{
+			"ignore_above override",
+			FieldDefinition{
+				Name:        "message",
+				Type:        "keyword",
+				IgnoreAbove: 1024,
+			},
+			FieldDefinition{
+				Name:        "message",
+				IgnoreAbove: 4096,
+			},
+			FieldDefinition{
+				Name:        "message",
+				Type:        "keyword",
+				IgnoreAbove: 4096,
+			},
+		},
 	}
 
 	for _, c := range cases {
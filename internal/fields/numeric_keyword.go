@@ -0,0 +1,59 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NumericKeywordField is a field that has keyword type, but can be ingested as numeric type.
+// If When is set, numeric values are only accepted when their string representation matches
+// this regular expression.
+type NumericKeywordField struct {
+	Name string `config:"name" yaml:"name"`
+	When string `config:"when" yaml:"when"`
+}
+
+// Unpack decodes a numeric keyword field from a configuration, it can be declared with
+// the name of the field, or with an object with the name and the condition.
+func (f *NumericKeywordField) Unpack(v any) error {
+	switch v := v.(type) {
+	case string:
+		f.Name = v
+	case map[string]any:
+		for key, value := range v {
+			s, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("invalid value for %q in numeric keyword field, string expected", key)
+			}
+			switch key {
+			case "name":
+				f.Name = s
+			case "when":
+				f.When = s
+			default:
+				return fmt.Errorf("unknown setting %q in numeric keyword field", key)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid numeric keyword field, name or object expected, found %T", v)
+	}
+	if f.Name == "" {
+		return errors.New("numeric keyword field without name")
+	}
+	return nil
+}
+
+// UnmarshalYAML decodes a numeric keyword field from YAML, in any of the forms accepted by Unpack.
+func (f *NumericKeywordField) UnmarshalYAML(value *yaml.Node) error {
+	var v any
+	if err := value.Decode(&v); err != nil {
+		return err
+	}
+	return f.Unpack(v)
+}
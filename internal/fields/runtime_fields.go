@@ -0,0 +1,247 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/multierror"
+)
+
+// RuntimeField is a runtime field defined in a Kibana data view.
+type RuntimeField struct {
+	// Name is the name of the field, subfields of composite runtime fields are
+	// named after their parent.
+	Name string
+
+	// Type is the type of the runtime field, as defined in Kibana.
+	Type string
+
+	// Path is the path of the Kibana saved object where the field is defined.
+	Path string
+}
+
+type runtimeFieldDefinition struct {
+	Type   string `json:"type"`
+	Fields map[string]struct {
+		Type string `json:"type"`
+	} `json:"fields"`
+}
+
+// runtimeFieldCompatibleTypes contains the field types that are compatible with each
+// type of runtime field. Runtime fields of other types are not verified.
+var runtimeFieldCompatibleTypes = map[string][]string{
+	"keyword":   {"keyword", "constant_keyword", "wildcard", "text", "match_only_text", "version"},
+	"long":      {"long", "integer", "short", "byte", "unsigned_long"},
+	"double":    {"double", "float", "half_float", "scaled_float", "long", "integer", "short", "byte", "unsigned_long"},
+	"date":      {"date", "date_nanos"},
+	"boolean":   {"boolean"},
+	"ip":        {"ip"},
+	"geo_point": {"geo_point"},
+}
+
+// ReadRuntimeFields returns the runtime fields defined in the Kibana saved objects of
+// the package, in data views or in data views embedded in other objects.
+func ReadRuntimeFields(packageRootPath string) ([]RuntimeField, error) {
+	kibanaDir := filepath.Join(packageRootPath, "kibana")
+	var runtimeFields []RuntimeField
+	err := filepath.WalkDir(kibanaDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading file failed (path: %s): %w", path, err)
+		}
+		var object any
+		err = json.Unmarshal(content, &object)
+		if err != nil {
+			return fmt.Errorf("failed to parse saved object %q: %w", path, err)
+		}
+		found, err := findRuntimeFields(object)
+		if err != nil {
+			return fmt.Errorf("invalid runtime fields in saved object %q: %w", path, err)
+		}
+		for i := range found {
+			found[i].Path = path
+		}
+		runtimeFields = append(runtimeFields, found...)
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return runtimeFields, nil
+}
+
+// findRuntimeFields looks recursively for runtime field maps in the given saved object. Maps
+// can be encoded as JSON strings, as in the attributes of data views, or as objects.
+func findRuntimeFields(object any) ([]RuntimeField, error) {
+	var runtimeFields []RuntimeField
+	switch object := object.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value := object[key]
+			if key == "runtimeFieldMap" {
+				found, err := parseRuntimeFieldMap(value)
+				if err != nil {
+					return nil, err
+				}
+				runtimeFields = append(runtimeFields, found...)
+				continue
+			}
+			found, err := findRuntimeFields(value)
+			if err != nil {
+				return nil, err
+			}
+			runtimeFields = append(runtimeFields, found...)
+		}
+	case []any:
+		for _, value := range object {
+			found, err := findRuntimeFields(value)
+			if err != nil {
+				return nil, err
+			}
+			runtimeFields = append(runtimeFields, found...)
+		}
+	}
+	return runtimeFields, nil
+}
+
+func parseRuntimeFieldMap(value any) ([]RuntimeField, error) {
+	var content []byte
+	switch value := value.(type) {
+	case string:
+		content = []byte(value)
+	case map[string]any:
+		d, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		content = d
+	default:
+		return nil, nil
+	}
+
+	var definitions map[string]runtimeFieldDefinition
+	err := json.Unmarshal(content, &definitions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse runtime field map: %w", err)
+	}
+
+	var runtimeFields []RuntimeField
+	for name, definition := range definitions {
+		if definition.Type != "composite" {
+			runtimeFields = append(runtimeFields, RuntimeField{Name: name, Type: definition.Type})
+			continue
+		}
+		for subfield, subdefinition := range definition.Fields {
+			runtimeFields = append(runtimeFields, RuntimeField{Name: name + "." + subfield, Type: subdefinition.Type})
+		}
+	}
+	sort.Slice(runtimeFields, func(i, j int) bool {
+		return runtimeFields[i].Name < runtimeFields[j].Name
+	})
+	return runtimeFields, nil
+}
+
+// ValidateRuntimeFields checks that the types of the runtime fields are compatible with the
+// types of the fields they shadow, if they are defined in the schema of the validator.
+func (v *Validator) ValidateRuntimeFields(runtimeFields []RuntimeField) multierror.Error {
+	var errs multierror.Error
+	for _, runtimeField := range runtimeFields {
+		compatible, found := runtimeFieldCompatibleTypes[runtimeField.Type]
+		if !found {
+			continue
+		}
+		definition := FindElementDefinition(runtimeField.Name, v.Schema)
+		if definition == nil {
+			definition = findMultiFieldDefinition(runtimeField.Name, v.Schema)
+		}
+		if definition == nil || definition.Type == "" || definition.Type == "group" {
+			continue
+		}
+		if !slices.Contains(compatible, definition.Type) {
+			errs = append(errs, fmt.Errorf("runtime field %q of type %s defined in %s conflicts with field of type %s%s",
+				runtimeField.Name, runtimeField.Type, runtimeField.Path, definition.Type, definedAt(*definition)))
+		}
+	}
+	return errs
+}
+
+// ValidateRuntimeFieldsInDocument checks that the values of the document are compatible with the
+// types of the runtime fields with the same name.
+func ValidateRuntimeFieldsInDocument(runtimeFields []RuntimeField, doc common.MapStr) multierror.Error {
+	var errs multierror.Error
+	for _, runtimeField := range runtimeFields {
+		value, err := doc.GetValue(runtimeField.Name)
+		if err != nil {
+			continue
+		}
+		values, isArray := value.([]any)
+		if !isArray {
+			values = []any{value}
+		}
+		for _, value := range values {
+			if !isCompatibleWithRuntimeField(runtimeField.Type, value) {
+				errs = append(errs, fmt.Errorf("value %v of field %q conflicts with runtime field of type %s defined in %s",
+					value, runtimeField.Name, runtimeField.Type, runtimeField.Path))
+				break
+			}
+		}
+	}
+	return errs
+}
+
+func isCompatibleWithRuntimeField(runtimeType string, value any) bool {
+	if value == nil {
+		return true
+	}
+	switch runtimeType {
+	case "keyword":
+		_, ok := value.(string)
+		return ok
+	case "date":
+		switch value.(type) {
+		case string, float64:
+			return true
+		}
+		return false
+	case "long":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "double":
+		_, ok := value.(float64)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "ip":
+		s, ok := value.(string)
+		return ok && net.ParseIP(strings.TrimSpace(s)) != nil
+	}
+	return true
+}
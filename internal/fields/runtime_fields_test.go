@@ -0,0 +1,109 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/common"
+)
+
+func TestReadRuntimeFields(t *testing.T) {
+	dir := t.TempDir()
+	indexPatternDir := filepath.Join(dir, "kibana", "index_pattern")
+	dashboardDir := filepath.Join(dir, "kibana", "dashboard")
+	require.NoError(t, os.MkdirAll(indexPatternDir, 0755))
+	require.NoError(t, os.MkdirAll(dashboardDir, 0755))
+
+	indexPattern := `{
+  "attributes": {
+    "title": "logs-example.*",
+    "runtimeFieldMap": "{\"http.status\":{\"type\":\"keyword\",\"script\":{\"source\":\"emit('ok')\"}},\"client\":{\"type\":\"composite\",\"fields\":{\"port\":{\"type\":\"long\"}}}}"
+  }
+}`
+	dashboard := `{
+  "attributes": {
+    "panelsJSON": "[]",
+    "adHocDataViews": {
+      "example": {"runtimeFieldMap": {"event.slow": {"type": "boolean"}}}
+    }
+  }
+}`
+	require.NoError(t, os.WriteFile(filepath.Join(indexPatternDir, "example.json"), []byte(indexPattern), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dashboardDir, "example.json"), []byte(dashboard), 0644))
+
+	runtimeFields, err := ReadRuntimeFields(dir)
+	require.NoError(t, err)
+
+	expected := []RuntimeField{
+		{Name: "event.slow", Type: "boolean", Path: filepath.Join(dashboardDir, "example.json")},
+		{Name: "client.port", Type: "long", Path: filepath.Join(indexPatternDir, "example.json")},
+		{Name: "http.status", Type: "keyword", Path: filepath.Join(indexPatternDir, "example.json")},
+	}
+	assert.Equal(t, expected, runtimeFields)
+
+	runtimeFields, err = ReadRuntimeFields(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, runtimeFields)
+}
+
+func TestValidateRuntimeFields(t *testing.T) {
+	v := Validator{
+		Schema: []FieldDefinition{
+			{Name: "http.status", Type: "long"},
+			{Name: "http.method", Type: "keyword"},
+			{Name: "event.duration", Type: "long"},
+			{Name: "message", Type: "match_only_text"},
+		},
+		disabledDependencyManagement: true,
+		specVersion:                  *semver3_0_1,
+	}
+
+	runtimeFields := []RuntimeField{
+		{Name: "http.status", Type: "keyword", Path: "index_pattern.json"},
+		{Name: "http.method", Type: "keyword", Path: "index_pattern.json"},
+		{Name: "event.duration", Type: "double", Path: "index_pattern.json"},
+		{Name: "message", Type: "keyword", Path: "index_pattern.json"},
+		{Name: "undefined", Type: "long", Path: "index_pattern.json"},
+	}
+	errs := v.ValidateRuntimeFields(runtimeFields)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), `runtime field "http.status" of type keyword`)
+	assert.Contains(t, errs[0].Error(), "conflicts with field of type long")
+}
+
+func TestValidateRuntimeFieldsInDocument(t *testing.T) {
+	runtimeFields := []RuntimeField{
+		{Name: "http.status", Type: "long", Path: "index_pattern.json"},
+		{Name: "client.ip", Type: "ip", Path: "index_pattern.json"},
+		{Name: "event.slow", Type: "boolean", Path: "index_pattern.json"},
+		{Name: "missing", Type: "keyword", Path: "index_pattern.json"},
+	}
+
+	doc := common.MapStr{
+		"http": common.MapStr{
+			"status": 200.0,
+		},
+		"client.ip":  "10.0.0.1",
+		"event.slow": []any{true, false},
+	}
+	errs := ValidateRuntimeFieldsInDocument(runtimeFields, doc)
+	assert.Empty(t, errs)
+
+	doc = common.MapStr{
+		"http": common.MapStr{
+			"status": "OK",
+		},
+		"client.ip":  "localhost",
+		"event.slow": []any{true, "no"},
+	}
+	errs = ValidateRuntimeFieldsInDocument(runtimeFields, doc)
+	assert.Len(t, errs, 3)
+}
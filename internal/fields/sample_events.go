@@ -0,0 +1,77 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/elastic/elastic-package/internal/multierror"
+)
+
+const (
+	// SampleEventFile is the sample event of a data stream, or of a package without data streams.
+	SampleEventFile = "sample_event.json"
+
+	// SampleEventsDir is the directory, relative to the data stream or package, with
+	// additional sample events, for example for documents with different shapes.
+	SampleEventsDir = "_dev/sample_events"
+)
+
+// SampleEventResult contains the errors found when validating a sample event.
+type SampleEventResult struct {
+	// Path is the path of the sample event file.
+	Path string
+
+	Errors multierror.Error
+}
+
+// SampleEventFiles returns the paths of the sample events found in the given data stream or
+// package directory. The sample_event.json file goes first if it exists, followed by the JSON
+// files in the sample events directory, sorted by name.
+func SampleEventFiles(dir string) ([]string, error) {
+	var paths []string
+	sampleEventPath := filepath.Join(dir, SampleEventFile)
+	_, err := os.Stat(sampleEventPath)
+	switch {
+	case err == nil:
+		paths = append(paths, sampleEventPath)
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("stat file failed: %w", err)
+	}
+
+	samples, err := filepath.Glob(filepath.Join(dir, SampleEventsDir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look for sample events: %w", err)
+	}
+	sort.Strings(samples)
+	return append(paths, samples...), nil
+}
+
+// ValidateSampleEvents validates all the sample events found in the given data stream or
+// package directory. It returns a result for each one of them, in the order returned by
+// SampleEventFiles.
+func (v *Validator) ValidateSampleEvents(dir string) ([]SampleEventResult, error) {
+	paths, err := SampleEventFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]SampleEventResult, len(paths))
+	for i, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("can't read file: %w", err)
+		}
+		results[i] = SampleEventResult{
+			Path:   path,
+			Errors: v.ValidateDocumentBody(content),
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,58 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fields
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSampleEvents(t *testing.T) {
+	dir := t.TempDir()
+	samplesDir := filepath.Join(dir, SampleEventsDir)
+	require.NoError(t, os.MkdirAll(samplesDir, 0755))
+
+	files := map[string]string{
+		filepath.Join(dir, SampleEventFile):      `{"log":{"type":"access","bytes":42}}`,
+		filepath.Join(samplesDir, "error.json"):  `{"log":{"type":"error","message":"failed"}}`,
+		filepath.Join(samplesDir, "broken.json"): `{"log":{"type":"access","bytes":"many","unknown":1}}`,
+		filepath.Join(samplesDir, "notes.txt"):   `not a sample event`,
+	}
+	for path, content := range files {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	v := Validator{
+		Schema: []FieldDefinition{
+			{Name: "log.type", Type: "keyword"},
+			{Name: "log.bytes", Type: "long"},
+			{Name: "log.message", Type: "text"},
+		},
+		disabledDependencyManagement: true,
+		specVersion:                  *semver3_0_1,
+	}
+	results, err := v.ValidateSampleEvents(dir)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, filepath.Join(dir, SampleEventFile), results[0].Path)
+	assert.Empty(t, results[0].Errors)
+
+	assert.Equal(t, filepath.Join(samplesDir, "broken.json"), results[1].Path)
+	assert.Len(t, results[1].Errors, 2)
+
+	assert.Equal(t, filepath.Join(samplesDir, "error.json"), results[2].Path)
+	assert.Empty(t, results[2].Errors)
+}
+
+func TestSampleEventFilesWithoutSampleEvents(t *testing.T) {
+	paths, err := SampleEventFiles(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+}
@@ -5,19 +5,29 @@
 package fields
 
 import (
+	"archive/zip"
 	"bufio"
+	"bytes"
 	_ "embed"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
+	"math"
+	"math/big"
 	"net"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/cbroglie/mustache"
@@ -122,6 +132,9 @@ var (
 	}
 
 	defaultExternal = "ecs"
+
+	// defaultFlattenedDepthLimit is the default depth_limit of flattened fields in Elasticsearch.
+	defaultFlattenedDepthLimit = 20
 )
 
 // Validator is responsible for fields validation.
@@ -129,35 +142,154 @@ type Validator struct {
 	// Schema contains definition records.
 	Schema []FieldDefinition
 
+	// packageSchema contains the definition records defined in the package, without
+	// the imported external schemas.
+	packageSchema []FieldDefinition
+
 	// SpecVersion contains the version of the spec used by the package.
 	specVersion semver.Version
 
+	// fieldsDir is the location where the fields of the schema are defined.
+	fieldsDir string
+
 	// expectedDatasets contains the value expected for dataset fields.
 	expectedDatasets []string
 
+	// expectedDataStream contains the values expected for the data stream fields.
+	expectedDataStream *expectedDataStream
+
+	// timestampBounds returns the range of values allowed for the @timestamp field.
+	timestampBounds func() (time.Time, time.Time)
+
 	defaultNumericConversion bool
 
 	// fields that store keywords, but can be received as numeric types.
 	numericKeywordFields []string
 
+	// fields that store keywords, but can be received as numeric types when their values
+	// match any of the patterns.
+	conditionalNumericKeywordFields map[string][]*regexp.Regexp
+
 	// fields that store numbers, but can be received as strings.
 	stringNumberFields []string
 
+	// acceptCoercibleNumericStrings enables accepting strings that Elasticsearch can coerce
+	// to the type of numeric fields.
+	acceptCoercibleNumericStrings bool
+
+	// acceptStringBooleans enables accepting the strings "true" and "false" in boolean fields.
+	acceptStringBooleans bool
+
+	// enabledIgnoreAboveErrors reports keyword values longer than their ignore_above setting
+	// as errors, instead of logging warnings.
+	enabledIgnoreAboveErrors bool
+
 	disabledDependencyManagement bool
 
 	enabledAllowedIPCheck bool
 	allowedCIDRs          []*net.IPNet
 
+	enabledUnitsCheck bool
+
+	enabledDeadFieldsCheck bool
+
+	enabledPIICheck bool
+	deniedDomains   []string
+	deniedPatterns  []*regexp.Regexp
+
 	enabledImportAllECSSchema bool
 
+	// ecsVersion is the version of ECS used to validate fields, instead of the one
+	// referenced in the build manifest of the package.
+	ecsVersion string
+
+	// flattenedKeysWarningThreshold is the number of unique keys in a flattened object
+	// above which a warning is logged. Zero disables the warning.
+	flattenedKeysWarningThreshold int
+
 	disabledNormalization bool
 
+	// fieldsAPIFormat is set when validating documents obtained with the fields API of
+	// search requests, instead of from their _source.
+	fieldsAPIFormat bool
+
 	injectFieldsOptions InjectFieldsOptions
+
+	customFieldChecks []FieldCheck
+
+	// requiredNestedSubfields contains the subfields that must be present in each object of
+	// nested fields, by key of the nested field.
+	requiredNestedSubfields map[string][]string
+
+	enabledFieldsCoverage bool
+	exercisedKeysMutex    sync.Mutex
+	exercisedKeys         map[string]struct{}
+
+	enabledFieldTimings bool
+	fieldTimingsMutex   sync.Mutex
+	fieldTimings        map[string]*FieldTiming
+
+	budget          ValidationBudget
+	budgetMutex     sync.Mutex
+	spentTime       time.Duration
+	allocatedMemory uint64
+	budgetExceeded  bool
+}
+
+// ValidationBudget limits the resources used by a validator to validate documents. Zero values
+// don't set any limit.
+type ValidationBudget struct {
+	// Time is the maximum time spent validating documents.
+	Time time.Duration
+
+	// Memory is the maximum number of bytes allocated while validating documents. Allocations are
+	// measured for the whole process, so they include the ones done concurrently by other tasks.
+	Memory uint64
+}
+
+// FieldTiming contains the time spent validating the values of a field.
+type FieldTiming struct {
+	Name  string
+	Count int
+	Total time.Duration
+}
+
+// ValidationError is an error found when validating the value of a field in a document.
+type ValidationError struct {
+	// Field is the key of the field in the document.
+	Field string
+
+	// Source is the location of the definition of the field, if known.
+	Source *SourceLocation
+
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// newValidationError wraps an error found when validating a field, including the location of
+// its definition when available.
+func (v *Validator) newValidationError(key string, err error) error {
+	validationErr := &ValidationError{Field: key, Err: err}
+	if definition := FindElementDefinition(key, v.Schema); definition != nil {
+		validationErr.Source = definition.Source
+	}
+	return validationErr
 }
 
 // ValidatorOption represents an optional flag that can be passed to  CreateValidatorForDirectory.
 type ValidatorOption func(*Validator) error
 
+// FieldCheck is a custom check for the values of fields, it receives the key of the field in
+// the document, its definition and its value, and returns an error if the check fails.
+type FieldCheck func(key string, definition FieldDefinition, val any) error
+
 // WithSpecVersion enables validation dependant of the spec version used by the package.
 func WithSpecVersion(version string) ValidatorOption {
 	return func(v *Validator) error {
@@ -187,6 +319,29 @@ func WithNumericKeywordFields(fields []string) ValidatorOption {
 	}
 }
 
+// WithConditionalNumericKeywordFields configures the validator to accept specific fields to have numeric-type
+// while defined as keyword or constant_keyword. Fields with a condition only accept numeric values whose
+// string representation matches it.
+func WithConditionalNumericKeywordFields(fields []NumericKeywordField) ValidatorOption {
+	return func(v *Validator) error {
+		for _, field := range fields {
+			if field.When == "" {
+				v.numericKeywordFields = common.StringSlicesUnion(v.numericKeywordFields, []string{field.Name})
+				continue
+			}
+			pattern, err := regexp.Compile(field.When)
+			if err != nil {
+				return fmt.Errorf("invalid condition for numeric keyword field %q: %w", field.Name, err)
+			}
+			if v.conditionalNumericKeywordFields == nil {
+				v.conditionalNumericKeywordFields = make(map[string][]*regexp.Regexp)
+			}
+			v.conditionalNumericKeywordFields[field.Name] = append(v.conditionalNumericKeywordFields[field.Name], pattern)
+		}
+		return nil
+	}
+}
+
 // WithStringNumberFields configures the validator to accept specific fields to have fields defined as numbers
 // as their string representation.
 func WithStringNumberFields(fields []string) ValidatorOption {
@@ -196,6 +351,50 @@ func WithStringNumberFields(fields []string) ValidatorOption {
 	}
 }
 
+// WithCoercibleNumericStrings configures the validator to accept strings in numeric fields, if they
+// can be parsed as the type of the field, as Elasticsearch does when coercing them. Scientific
+// notation is accepted.
+func WithCoercibleNumericStrings() ValidatorOption {
+	return func(v *Validator) error {
+		v.acceptCoercibleNumericStrings = true
+		return nil
+	}
+}
+
+// WithFieldsAPIFormat configures the validator for documents obtained with the fields parameter of
+// search requests, instead of from _source. In this format all values are returned as arrays, and
+// Elasticsearch formats some of them. When enabled, the following checks change:
+//   - Single-element arrays are unwrapped before validating them, except for fields normalized as
+//     arrays.
+//   - Values of multi-fields are returned as fields, they are validated with the type of the
+//     multi-field instead of being reported as undefined.
+//   - Date fields without a custom date_format are expected in the canonical format of
+//     Elasticsearch (e.g. "2006-01-02T15:04:05.000Z"), numeric dates are reported as errors.
+func WithFieldsAPIFormat() ValidatorOption {
+	return func(v *Validator) error {
+		v.fieldsAPIFormat = true
+		return nil
+	}
+}
+
+// WithStringBooleans configures the validator to accept the strings "true" and "false" as values
+// of boolean fields, as Elasticsearch does when indexing them.
+func WithStringBooleans() ValidatorOption {
+	return func(v *Validator) error {
+		v.acceptStringBooleans = true
+		return nil
+	}
+}
+
+// WithEnabledIgnoreAboveErrors configures the validator to fail on values of keyword fields longer
+// than their ignore_above setting. By default only a warning is logged for them.
+func WithEnabledIgnoreAboveErrors() ValidatorOption {
+	return func(v *Validator) error {
+		v.enabledIgnoreAboveErrors = true
+		return nil
+	}
+}
+
 // WithDisabledDependencyManagement configures the validator to ignore external fields and won't follow dependencies.
 func WithDisabledDependencyManagement() ValidatorOption {
 	return func(v *Validator) error {
@@ -212,6 +411,42 @@ func WithEnabledAllowedIPCheck() ValidatorOption {
 	}
 }
 
+// WithEnabledUnitsCheck configures the validator to check that the values of fields with known units
+// are in the range of values expected for these units.
+func WithEnabledUnitsCheck() ValidatorOption {
+	return func(v *Validator) error {
+		v.enabledUnitsCheck = true
+		return nil
+	}
+}
+
+// WithEnabledDeadFieldsCheck configures the validator to warn about documents populating fields
+// defined with `index: false` and `doc_values: false`, whose values cannot be queried.
+func WithEnabledDeadFieldsCheck() ValidatorOption {
+	return func(v *Validator) error {
+		v.enabledDeadFieldsCheck = true
+		return nil
+	}
+}
+
+// WithEnabledPIICheck configures the validator to check that string values don't contain likely real
+// personal data. Email addresses are only allowed in domains reserved for documentation (RFC 2606),
+// and values cannot contain hostnames in the denied domains, or match any of the denied patterns.
+func WithEnabledPIICheck(deniedDomains []string, deniedPatterns []string) ValidatorOption {
+	return func(v *Validator) error {
+		v.enabledPIICheck = true
+		v.deniedDomains = common.StringSlicesUnion(v.deniedDomains, deniedDomains)
+		for _, pattern := range deniedPatterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("invalid denied pattern %q: %w", pattern, err)
+			}
+			v.deniedPatterns = append(v.deniedPatterns, re)
+		}
+		return nil
+	}
+}
+
 // WithExpectedDatasets configures the validator to check if the dataset field value matches one of the expected values.
 func WithExpectedDatasets(datasets []string) ValidatorOption {
 	return func(v *Validator) error {
@@ -220,6 +455,57 @@ func WithExpectedDatasets(datasets []string) ValidatorOption {
 	}
 }
 
+// WithExpectedDataStream configures the validator to check if the data stream type and dataset
+// fields match the given values. The namespace is checked to be a valid namespace.
+func WithExpectedDataStream(dataStreamType, dataset string) ValidatorOption {
+	return func(v *Validator) error {
+		v.expectedDataStream = &expectedDataStream{
+			dataStreamType: dataStreamType,
+			dataset:        dataset,
+		}
+		return nil
+	}
+}
+
+// WithTimestampBounds configures the validator to check that the @timestamp field of the
+// documents is in the given range, both ends included.
+func WithTimestampBounds(from, to time.Time) ValidatorOption {
+	return func(v *Validator) error {
+		if from.After(to) {
+			return fmt.Errorf("invalid timestamp bounds, %s is after %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+		}
+		v.timestampBounds = func() (time.Time, time.Time) {
+			return from, to
+		}
+		return nil
+	}
+}
+
+// WithTimestampWithin configures the validator to check that the @timestamp field of the documents
+// is not further than the given duration from the time of the validation, in the past or in the future.
+func WithTimestampWithin(window time.Duration) ValidatorOption {
+	return func(v *Validator) error {
+		if window < 0 {
+			return fmt.Errorf("invalid timestamp window %s, it cannot be negative", window)
+		}
+		v.timestampBounds = func() (time.Time, time.Time) {
+			now := time.Now()
+			return now.Add(-window), now.Add(window)
+		}
+		return nil
+	}
+}
+
+// WithECSVersion configures the validator to use the schema of the given ECS version, instead of
+// the one referenced in the build manifest of the package. It can be a version, or a reference in
+// the same format as the ones used in build manifests.
+func WithECSVersion(version string) ValidatorOption {
+	return func(v *Validator) error {
+		v.ecsVersion = version
+		return nil
+	}
+}
+
 // WithEnabledImportAllECSSchema configures the validator to check or not the fields with the complete ECS schema.
 func WithEnabledImportAllECSSChema(importSchema bool) ValidatorOption {
 	return func(v *Validator) error {
@@ -236,6 +522,63 @@ func WithDisableNormalization(disabledNormalization bool) ValidatorOption {
 	}
 }
 
+// WithCustomFieldChecks configures the validator to run the given checks on all the defined fields
+// found in the documents, after the built-in checks.
+func WithCustomFieldChecks(checks []FieldCheck) ValidatorOption {
+	return func(v *Validator) error {
+		v.customFieldChecks = append(v.customFieldChecks, checks...)
+		return nil
+	}
+}
+
+// WithRequiredNestedSubfields configures the validator to check that each one of the objects
+// of the given nested fields contains the listed subfields. Subfields are listed by key of the
+// nested field in the documents.
+func WithRequiredNestedSubfields(subfields map[string][]string) ValidatorOption {
+	return func(v *Validator) error {
+		v.requiredNestedSubfields = subfields
+		return nil
+	}
+}
+
+// WithEnabledFieldsCoverage configures the validator to keep track of the fields found in the
+// validated documents, so fields not exercised by them can be reported with CoverageReport.
+func WithEnabledFieldsCoverage() ValidatorOption {
+	return func(v *Validator) error {
+		v.enabledFieldsCoverage = true
+		v.exercisedKeys = make(map[string]struct{})
+		return nil
+	}
+}
+
+// WithFlattenedKeysWarningThreshold configures the validator to warn about flattened objects with more
+// unique keys than the given threshold.
+func WithFlattenedKeysWarningThreshold(threshold int) ValidatorOption {
+	return func(v *Validator) error {
+		v.flattenedKeysWarningThreshold = threshold
+		return nil
+	}
+}
+
+// WithEnabledFieldTimings configures the validator to measure the time spent validating the
+// values of each field, so the slowest fields can be reported with FieldTimings.
+func WithEnabledFieldTimings() ValidatorOption {
+	return func(v *Validator) error {
+		v.enabledFieldTimings = true
+		v.fieldTimings = make(map[string]*FieldTiming)
+		return nil
+	}
+}
+
+// WithValidationBudget configures the validator to fail the validation of the document that makes
+// the resources used to validate all the documents exceed the given budget.
+func WithValidationBudget(budget ValidationBudget) ValidatorOption {
+	return func(v *Validator) error {
+		v.budget = budget
+		return nil
+	}
+}
+
 // WithInjectFieldsOptions configures fields injection.
 func WithInjectFieldsOptions(options InjectFieldsOptions) ValidatorOption {
 	return func(v *Validator) error {
@@ -260,8 +603,62 @@ func CreateValidatorForDirectory(fieldsParentDir string, opts ...ValidatorOption
 	return createValidatorForDirectoryAndPackageRoot(fieldsParentDir, p, opts...)
 }
 
-func createValidatorForDirectoryAndPackageRoot(fieldsParentDir string, finder packageRootFinder, opts ...ValidatorOption) (v *Validator, err error) {
-	v = new(Validator)
+// CreateValidatorForDataStreams function creates a validator for documents that can belong to any of
+// the data streams in the given directories. A field is valid if it is valid for any of the data
+// streams, if it is defined in more than one, the definition of the first data stream is used.
+func CreateValidatorForDataStreams(dataStreamDirs []string, opts ...ValidatorOption) (*Validator, error) {
+	p := packageRoot{}
+	return createValidatorForDirectoriesAndPackageRoot(dataStreamDirs, p, opts...)
+}
+
+// CreateValidatorForBuiltPackage function creates a validator for a data stream of a package built
+// as a zip file. Field definitions are read directly from the archive. External fields are already
+// resolved in built packages, so dependency management is disabled. If the data stream is empty, the
+// fields defined at the root of the package are used, as in input packages.
+func CreateValidatorForBuiltPackage(zipPath, dataStream string, opts ...ValidatorOption) (*Validator, error) {
+	v, err := newValidator(append(opts, WithDisabledDependencyManagement())...)
+	if err != nil {
+		return nil, err
+	}
+
+	zipReader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, fmt.Errorf("can't open zip package (path: %s): %w", zipPath, err)
+	}
+	defer zipReader.Close()
+
+	// Built packages contain all files under a folder named "package-version".
+	v.fieldsDir = "fields"
+	if dataStream != "" {
+		v.fieldsDir = path.Join("data_stream", dataStream, "fields")
+	}
+	files, err := fs.Glob(zipReader, path.Join("*", v.fieldsDir, "*.yml"))
+	if err != nil {
+		return nil, fmt.Errorf("reading fields in zip package failed (path: %s): %w", zipPath, err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no fields files found in zip package (path: %s, data stream: %q)", zipPath, dataStream)
+	}
+
+	for _, file := range files {
+		body, err := fs.ReadFile(zipReader, file)
+		if err != nil {
+			return nil, fmt.Errorf("reading fields file from zip package failed (path: %s): %w", file, err)
+		}
+
+		var u []FieldDefinition
+		err = yaml.Unmarshal(body, &u)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshalling field body failed (path: %s): %w", file, err)
+		}
+		v.Schema = append(v.Schema, u...)
+	}
+	v.packageSchema = v.Schema
+	return v, nil
+}
+
+func newValidator(opts ...ValidatorOption) (*Validator, error) {
+	v := new(Validator)
 	// In validator, inject fields with settings used for validation, such as `allowed_values`.
 	v.injectFieldsOptions.IncludeValidationSettings = true
 	for _, opt := range opts {
@@ -271,8 +668,28 @@ func createValidatorForDirectoryAndPackageRoot(fieldsParentDir string, finder pa
 	}
 
 	v.allowedCIDRs = initializeAllowedCIDRsList()
+	return v, nil
+}
+
+func createValidatorForDirectoryAndPackageRoot(fieldsParentDir string, finder packageRootFinder, opts ...ValidatorOption) (v *Validator, err error) {
+	return createValidatorForDirectoriesAndPackageRoot([]string{fieldsParentDir}, finder, opts...)
+}
+
+func createValidatorForDirectoriesAndPackageRoot(fieldsParentDirs []string, finder packageRootFinder, opts ...ValidatorOption) (v *Validator, err error) {
+	if len(fieldsParentDirs) == 0 {
+		return nil, errors.New("no directories with fields provided")
+	}
 
-	fieldsDir := filepath.Join(fieldsParentDir, "fields")
+	v, err = newValidator(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var fieldsDirs []string
+	for _, fieldsParentDir := range fieldsParentDirs {
+		fieldsDirs = append(fieldsDirs, filepath.Join(fieldsParentDir, "fields"))
+	}
+	v.fieldsDir = strings.Join(fieldsDirs, ", ")
 
 	var fdm *DependencyManager
 	if !v.disabledDependencyManagement {
@@ -283,33 +700,52 @@ func createValidatorForDirectoryAndPackageRoot(fieldsParentDir string, finder pa
 		if !found {
 			return nil, errors.New("package root not found and dependency management is enabled")
 		}
-		fdm, v.Schema, err = initDependencyManagement(packageRoot, v.specVersion, v.enabledImportAllECSSchema)
+		fdm, v.Schema, err = initDependencyManagement(packageRoot, v.specVersion, v.enabledImportAllECSSchema, v.ecsVersion)
 		if err != nil {
 			return nil, fmt.Errorf("failed to initialize dependency management: %w", err)
 		}
 	}
 
-	fields, err := loadFieldsFromDir(fieldsDir, fdm, v.injectFieldsOptions)
-	if err != nil {
-		return nil, fmt.Errorf("can't load fields from directory (path: %s): %w", fieldsDir, err)
+	var fields []FieldDefinition
+	for _, fieldsDir := range fieldsDirs {
+		dirFields, err := loadFieldsFromDir(fieldsDir, fdm, v.injectFieldsOptions)
+		if err != nil {
+			return nil, fmt.Errorf("can't load fields from directory (path: %s): %w", fieldsDir, err)
+		}
+		fields = append(fields, dirFields...)
 	}
 
+	v.packageSchema = fields
 	v.Schema = append(fields, v.Schema...)
 	return v, nil
 }
 
-func initDependencyManagement(packageRoot string, specVersion semver.Version, importECSSchema bool) (*DependencyManager, []FieldDefinition, error) {
+func initDependencyManagement(packageRoot string, specVersion semver.Version, importECSSchema bool, ecsVersion string) (*DependencyManager, []FieldDefinition, error) {
 	buildManifest, ok, err := buildmanifest.ReadBuildManifest(packageRoot)
 	if err != nil {
 		return nil, nil, fmt.Errorf("can't read build manifest: %w", err)
 	}
 	if !ok {
-		// There is no build manifest, nothing to do.
-		return nil, nil, nil
+		if ecsVersion == "" {
+			// There is no build manifest, nothing to do.
+			return nil, nil, nil
+		}
+		buildManifest = &buildmanifest.BuildManifest{}
+	}
+
+	dependencies := buildManifest.Dependencies
+	if ecsVersion != "" {
+		dependencies.ECS.Reference, err = ecsReferenceForVersion(ecsVersion)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
-	fdm, err := CreateFieldDependencyManager(buildManifest.Dependencies)
+	fdm, err := CreateFieldDependencyManager(dependencies)
 	if err != nil {
+		if ecsVersion != "" {
+			return nil, nil, fmt.Errorf("can't load schema of ECS version %q: %w", ecsVersion, err)
+		}
 		return nil, nil, fmt.Errorf("can't create field dependency manager: %w", err)
 	}
 
@@ -345,6 +781,19 @@ func initDependencyManagement(packageRoot string, specVersion semver.Version, im
 	return fdm, schema, nil
 }
 
+// ecsReferenceForVersion returns the reference to the schema of the given ECS version. Versions
+// can be also given as references, as used in the build manifest.
+func ecsReferenceForVersion(version string) (string, error) {
+	if strings.HasPrefix(version, gitReferencePrefix) || strings.HasPrefix(version, localFilePrefix) {
+		return version, nil
+	}
+	v, err := semver.StrictNewVersion(strings.TrimPrefix(version, "v"))
+	if err != nil {
+		return "", fmt.Errorf("invalid ECS version %q, a version like 8.11.0 or a reference like git@v8.11.0 expected: %w", version, err)
+	}
+	return gitReferencePrefix + "v" + v.String(), nil
+}
+
 // supportsECSMappings check if all the versions of the stack the package can run on support ECS mappings.
 func supportsECSMappings(packageRoot string) (bool, error) {
 	packageManifest, err := packages.ReadPackageManifestFromPackageRoot(packageRoot)
@@ -497,6 +946,12 @@ func initializeAllowedCIDRsList() (cidrs []*net.IPNet) {
 	return cidrs
 }
 
+// LoadFieldsFromDataStream loads the field definitions declared in the fields directory
+// of the given data stream, without resolving external fields.
+func LoadFieldsFromDataStream(dataStreamRoot string) ([]FieldDefinition, error) {
+	return loadFieldsFromDir(filepath.Join(dataStreamRoot, "fields"), nil, InjectFieldsOptions{})
+}
+
 func loadFieldsFromDir(fieldsDir string, fdm *DependencyManager, injectOptions InjectFieldsOptions) ([]FieldDefinition, error) {
 	files, err := filepath.Glob(filepath.Join(fieldsDir, "*.yml"))
 	if err != nil {
@@ -510,23 +965,73 @@ func loadFieldsFromDir(fieldsDir string, fdm *DependencyManager, injectOptions I
 			return nil, fmt.Errorf("reading fields file failed: %w", err)
 		}
 
+		var u []FieldDefinition
+		err = yaml.Unmarshal(body, &u)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshalling field body failed: %w", err)
+		}
+
+		// Keep the lines of the original definitions, they are lost when injecting external fields.
+		lines := make(map[string]int)
+		collectDefinitionLines("", u, lines)
+
 		if fdm != nil {
 			body, err = injectFields(body, fdm, injectOptions)
 			if err != nil {
 				return nil, fmt.Errorf("loading external fields failed: %w", err)
 			}
-		}
 
-		var u []FieldDefinition
-		err = yaml.Unmarshal(body, &u)
-		if err != nil {
-			return nil, fmt.Errorf("unmarshalling field body failed: %w", err)
+			u = nil
+			err = yaml.Unmarshal(body, &u)
+			if err != nil {
+				return nil, fmt.Errorf("unmarshalling field body failed: %w", err)
+			}
 		}
+
+		sourceFile := filepath.Join(filepath.Base(fieldsDir), filepath.Base(file))
+		setSourceLocations("", u, sourceFile, lines)
 		fields = append(fields, u...)
 	}
 	return fields, nil
 }
 
+func collectDefinitionLines(root string, definitions []FieldDefinition, lines map[string]int) {
+	for _, definition := range definitions {
+		key := strings.TrimLeft(root+"."+definition.Name, ".")
+		lines[key] = definition.line
+		collectDefinitionLines(key, definition.Fields, lines)
+		collectDefinitionLines(key, definition.MultiFields, lines)
+	}
+}
+
+func setSourceLocations(root string, definitions []FieldDefinition, file string, lines map[string]int) {
+	for i := range definitions {
+		definition := &definitions[i]
+		key := strings.TrimLeft(root+"."+definition.Name, ".")
+		if line, found := lines[key]; found {
+			definition.Source = &SourceLocation{File: file, Line: line}
+		}
+		setSourceLocations(key, definition.Fields, file, lines)
+		setSourceLocations(key, definition.MultiFields, file, lines)
+	}
+}
+
+// definedAt returns a description of the location of the definition, to be included in error messages.
+func definedAt(definition FieldDefinition) string {
+	if definition.Source == nil {
+		return ""
+	}
+	return fmt.Sprintf(" (defined at %s)", definition.Source)
+}
+
+// undefinedFieldHint returns a hint about where undefined fields are expected to be defined.
+func (v *Validator) undefinedFieldHint() string {
+	if v.fieldsDir == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (fields directory: %s)", v.fieldsDir)
+}
+
 func injectFields(d []byte, dm *DependencyManager, options InjectFieldsOptions) ([]byte, error) {
 	var fields []common.MapStr
 	err := yaml.Unmarshal(d, &fields)
@@ -544,8 +1049,7 @@ func injectFields(d []byte, dm *DependencyManager, options InjectFieldsOptions)
 
 // ValidateDocumentBody validates the provided document body.
 func (v *Validator) ValidateDocumentBody(body json.RawMessage) multierror.Error {
-	var c common.MapStr
-	err := json.Unmarshal(body, &c)
+	c, err := decodeDocument(body)
 	if err != nil {
 		var errs multierror.Error
 		errs = append(errs, fmt.Errorf("unmarshalling document body failed: %w", err))
@@ -555,16 +1059,125 @@ func (v *Validator) ValidateDocumentBody(body json.RawMessage) multierror.Error
 	return v.ValidateDocumentMap(c)
 }
 
+// decodeDocument decodes the body of a document. Numbers are decoded as float64, except
+// integers that cannot be exactly represented in a float64, as the big values of unsigned_long
+// fields, that are kept as json.Number to validate them without losing precision.
+func decodeDocument(body []byte) (common.MapStr, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var doc common.MapStr
+	err := decoder.Decode(&doc)
+	if err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected content after the document")
+	}
+	for key, value := range doc {
+		doc[key] = decodeNumbers(value)
+	}
+	return doc, nil
+}
+
+// maxExactFloat is the greatest integer below which all integers can be exactly represented
+// in a float64.
+const maxExactFloat = 1 << 53
+
+func decodeNumbers(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, element := range value {
+			value[key] = decodeNumbers(element)
+		}
+	case []any:
+		for i, element := range value {
+			value[i] = decodeNumbers(element)
+		}
+	case json.Number:
+		if !strings.ContainsAny(value.String(), ".eE") {
+			if n, err := value.Int64(); err != nil || n > maxExactFloat || n < -maxExactFloat {
+				return value
+			}
+		}
+		if f, err := value.Float64(); err == nil {
+			return f
+		}
+	}
+	return value
+}
+
 // ValidateDocumentMap validates the provided document as common.MapStr.
 func (v *Validator) ValidateDocumentMap(body common.MapStr) multierror.Error {
+	usage := v.startBudgetUsage()
+	if v.fieldsAPIFormat {
+		body = v.unwrapFieldsAPIValues("", body)
+	}
 	errs := v.validateDocumentValues(body)
 	errs = append(errs, v.validateMapElement("", body, body)...)
+	if err := v.spendBudget(usage); err != nil {
+		errs = append(errs, err)
+	}
 	if len(errs) == 0 {
 		return nil
 	}
 	return errs
 }
 
+// unwrapFieldsAPIValues returns a copy of a document obtained with the fields API, replacing
+// single-element arrays with their element, so they can be validated as values in _source.
+// Objects are returned as map[string]any, as they are when decoding documents, so they are
+// traversed when validating the document.
+func (v *Validator) unwrapFieldsAPIValues(root string, doc map[string]any) map[string]any {
+	unwrapped := make(map[string]any, len(doc))
+	for name, value := range doc {
+		key := strings.TrimLeft(root+"."+name, ".")
+		list, isList := value.([]any)
+		if !isList {
+			unwrapped[name] = value
+			continue
+		}
+
+		elements := make([]any, len(list))
+		for i, element := range list {
+			// Nested objects are returned as arrays of documents with their own fields.
+			if m, ok := element.(map[string]any); ok {
+				element = v.unwrapFieldsAPIValues(key, m)
+			}
+			elements[i] = element
+		}
+		if len(elements) == 1 && !v.isNormalizedAsArray(key) {
+			unwrapped[name] = elements[0]
+			continue
+		}
+		unwrapped[name] = elements
+	}
+	return unwrapped
+}
+
+func (v *Validator) isNormalizedAsArray(key string) bool {
+	definition := FindElementDefinition(key, v.Schema)
+	return definition != nil && slices.Contains(definition.Normalize, "array")
+}
+
+// canonicalDatePattern matches dates formatted by Elasticsearch with the default date format,
+// with milliseconds, or up to nanoseconds for date_nanos fields.
+var canonicalDatePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{3,9}Z$`)
+
+// findMultiFieldDefinition returns the definition of the multi-field with the given key, if any.
+func findMultiFieldDefinition(key string, schema []FieldDefinition) *FieldDefinition {
+	parent := findParentElementDefinition(key, schema)
+	if parent == nil {
+		return nil
+	}
+	name := key[strings.LastIndex(key, ".")+1:]
+	for i := range parent.MultiFields {
+		if parent.MultiFields[i].Name == name {
+			return &parent.MultiFields[i]
+		}
+	}
+	return nil
+}
+
 var datasetFieldNames = []string{
 	"event.dataset",
 	"data_stream.dataset",
@@ -609,6 +1222,116 @@ func (v *Validator) validateDocumentValues(body common.MapStr) multierror.Error
 			}
 		}
 	}
+	if v.expectedDataStream != nil {
+		errs = append(errs, v.validateDataStreamValues(body)...)
+	}
+	if v.timestampBounds != nil {
+		if err := v.validateTimestampBounds(body); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// timestampLayouts are the layouts used to parse string values of @timestamp. Values without
+// timezone are considered to be in UTC, as Elasticsearch does.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+func (v *Validator) validateTimestampBounds(body common.MapStr) error {
+	value, err := body.GetValue("@timestamp")
+	if errors.Is(err, common.ErrKeyNotFound) {
+		return nil
+	}
+	if values, ok := value.([]any); ok && len(values) == 1 {
+		// Values are stored in arrays when synthetic source is enabled.
+		value = values[0]
+	}
+
+	timestamp, err := parseTimestamp(value)
+	if err != nil {
+		return fmt.Errorf("field \"@timestamp\" has value \"%v\", its range cannot be checked: %w", value, err)
+	}
+
+	from, to := v.timestampBounds()
+	if timestamp.Before(from) || timestamp.After(to) {
+		return fmt.Errorf("field \"@timestamp\" has value \"%v\", out of the expected range from %s to %s",
+			value, from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// parseTimestamp parses dates formatted as strings or as milliseconds since epoch.
+func parseTimestamp(value any) (time.Time, error) {
+	switch value := value.(type) {
+	case string:
+		for _, layout := range timestampLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, errors.New("unknown date format")
+	case float64:
+		return time.UnixMilli(int64(value)), nil
+	case json.Number:
+		millis, err := value.Int64()
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.UnixMilli(millis), nil
+	default:
+		return time.Time{}, fmt.Errorf("unexpected type %T", value)
+	}
+}
+
+// expectedDataStream contains the values expected for the data stream fields of the documents.
+type expectedDataStream struct {
+	dataStreamType string
+	dataset        string
+}
+
+// maxNamespaceLength is the maximum length of the namespace of data streams, in bytes.
+const maxNamespaceLength = 100
+
+// invalidNamespaceCharacters contains the characters not allowed in the namespace of data streams.
+var invalidNamespaceCharacters = regexp.MustCompile(`[A-Z*\\/?"<>|\s,#:-]`)
+
+func (v *Validator) validateDataStreamValues(body common.MapStr) multierror.Error {
+	var errs multierror.Error
+	expected := []struct {
+		field string
+		value string
+	}{
+		{field: "data_stream.type", value: v.expectedDataStream.dataStreamType},
+		{field: "data_stream.dataset", value: v.expectedDataStream.dataset},
+	}
+	for _, e := range expected {
+		value, err := body.GetValue(e.field)
+		if errors.Is(err, common.ErrKeyNotFound) {
+			continue
+		}
+		str, ok := valueToString(value, v.disabledNormalization)
+		if !ok || str != e.value {
+			errs = append(errs, fmt.Errorf("field %q should have value %q, it has \"%v\"", e.field, e.value, value))
+		}
+	}
+
+	value, err := body.GetValue("data_stream.namespace")
+	if errors.Is(err, common.ErrKeyNotFound) {
+		return errs
+	}
+	namespace, ok := valueToString(value, v.disabledNormalization)
+	switch {
+	case !ok || namespace == "":
+		errs = append(errs, fmt.Errorf("field \"data_stream.namespace\" should have a non-empty string value, it has \"%v\"", value))
+	case len(namespace) > maxNamespaceLength:
+		errs = append(errs, fmt.Errorf("field \"data_stream.namespace\" has value %q, longer than %d bytes", namespace, maxNamespaceLength))
+	case invalidNamespaceCharacters.MatchString(namespace):
+		errs = append(errs, fmt.Errorf("field \"data_stream.namespace\" has value %q, namespaces must be lowercase and can't contain spaces or any of these characters: \\/*?\"<>|,#:-", namespace))
+	}
 	return errs
 }
 
@@ -652,11 +1375,54 @@ func (v *Validator) validateMapElement(root string, elem common.MapStr, doc comm
 				}
 			}
 		case map[string]any:
-			if isFieldTypeFlattened(key, v.Schema) {
+			definition := FindElementDefinition(key, v.Schema)
+			if definition != nil && isGeometryType(definition.Type) {
+				// Geometries can be objects, but they are a single value.
+				err := v.validateScalarElement(key, val, doc)
+				if err != nil {
+					errs = append(errs, v.newValidationError(key, err))
+				}
+				continue
+			}
+			if definition != nil && definition.Type == "semantic_text" {
+				// Semantic text can be stored as an object with the original text and the
+				// inference results, only the text is validated.
+				text, found := val["text"]
+				if !found {
+					errs = append(errs, v.newValidationError(key, fmt.Errorf("field %q of type semantic_text is an object without text%s", key, definedAt(*definition))))
+					continue
+				}
+				err := v.validateScalarElement(key, text, doc)
+				if err != nil {
+					errs = append(errs, v.newValidationError(key, err))
+				}
+				continue
+			}
+			if definition != nil && definition.Type == "flattened" {
 				// Do not traverse into objects with flattened data types
 				// because the entire object is mapped as a single field.
+				v.markExercised(key)
+				err := v.validateFlattenedObject(key, *definition, val)
+				if err != nil {
+					errs = append(errs, &ValidationError{Field: key, Source: definition.Source, Err: err})
+				}
 				continue
 			}
+			if definition != nil {
+				// The object is not traversed, its subfields would be reported as undefined,
+				// hiding the actual problem.
+				if err := objectInScalarFieldError(key, *definition, val); err != nil {
+					errs = append(errs, v.newValidationError(key, err))
+					continue
+				}
+			}
+			if definition != nil && definition.Type == "nested" {
+				// Nested fields with a single object are traversed as any other object,
+				// but their required subfields still need to be checked.
+				if err := ensureRequiredNestedSubfields(key, v.requiredNestedSubfields[key], val); err != nil {
+					errs = append(errs, v.newValidationError(key, err))
+				}
+			}
 			err := v.validateMapElement(key, val, doc)
 			if err != nil {
 				errs = append(errs, err...)
@@ -667,48 +1433,318 @@ func (v *Validator) validateMapElement(root string, elem common.MapStr, doc comm
 				break
 			}
 
-			err := v.validateScalarElement(key, val, doc)
-			if err != nil {
-				errs = append(errs, err)
-			}
+			err := v.validateScalarElement(key, val, doc)
+			if err != nil {
+				errs = append(errs, v.newValidationError(key, err))
+			}
+		}
+	}
+	return errs
+}
+
+func (v *Validator) validateScalarElement(key string, val any, doc common.MapStr) error {
+	if key == "" {
+		return nil // root key is always valid
+	}
+
+	if v.enabledFieldTimings {
+		defer v.recordFieldTiming(key, time.Now())
+	}
+
+	definition := FindElementDefinition(key, v.Schema)
+	if definition == nil && v.fieldsAPIFormat {
+		// The fields API also returns the values of multi-fields.
+		definition = findMultiFieldDefinition(key, v.Schema)
+	}
+	if definition == nil {
+		objectKey, dynamic := dynamicMappingOfSubfield(key, v.Schema)
+		switch {
+		case skipValidationForField(key):
+			return nil // generic field, let's skip validation for now
+		case isRuntimeSubfield(key, v.Schema):
+			return nil // subfield of a composite runtime field, calculated by its script.
+		case isFlattenedSubfield(key, v.Schema):
+			return nil // flattened subfield, it will be stored as member of the flattened ancestor.
+		case isSemanticTextSubfield(key, v.Schema):
+			return nil // inference results of a semantic_text field, generated by the model.
+		case dynamic == "true":
+			return nil // subfield of an object with dynamic mapping, any subfield is accepted.
+		case dynamic == "strict":
+			return fmt.Errorf(`field %q is undefined, and object %q doesn't accept undefined subfields (dynamic: strict)%s`, key, objectKey, v.undefinedFieldHint())
+		case isArrayOfObjects(val):
+			return fmt.Errorf(`field %q is used as array of objects, expected explicit definition with type group or nested%s`, key, v.undefinedFieldHint())
+		case couldBeMultifield(key, v.Schema):
+			return fmt.Errorf(`field %q is undefined, could be a multifield%s`, key, v.undefinedFieldHint())
+		default:
+			return fmt.Errorf(`field %q is undefined%s`, key, v.undefinedFieldHint())
+		}
+	}
+
+	v.markExercised(key)
+
+	if definition.Runtime {
+		// Runtime fields are calculated on query time, they are valid if present.
+		return nil
+	}
+
+	if !v.disabledNormalization {
+		err := v.validateExpectedNormalization(*definition, val)
+		if err != nil {
+			return fmt.Errorf("field %q is not normalized as expected: %w", key, err)
+		}
+	}
+
+	err := v.parseElementValue(key, *definition, val, doc)
+	if err != nil {
+		return fmt.Errorf("parsing field value failed: %w", err)
+	}
+
+	if v.enabledDeadFieldsCheck && isDeadField(*definition) {
+		logger.Warnf("field %q is populated, but it is not indexed and has no doc values, so its values cannot be queried%s", key, definedAt(*definition))
+	}
+
+	if v.enabledUnitsCheck {
+		err := forEachElementValue(key, *definition, val, doc, ensureValueInUnitRange)
+		if err != nil {
+			return err
+		}
+	}
+
+	if v.enabledPIICheck {
+		err := forEachElementValue(key, *definition, val, doc, v.ensureNoPII)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, check := range v.customFieldChecks {
+		err := check(key, *definition, val)
+		if err != nil {
+			return fmt.Errorf("custom check failed for field %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// isDeadField returns true if the field is defined with both indexing and doc values disabled.
+func isDeadField(definition FieldDefinition) bool {
+	return definition.Index != nil && !*definition.Index &&
+		definition.DocValues != nil && !*definition.DocValues
+}
+
+// validateFlattenedObject checks that the object stored in a flattened field doesn't exceed its depth limit.
+func (v *Validator) validateFlattenedObject(key string, definition FieldDefinition, val map[string]any) error {
+	depthLimit := defaultFlattenedDepthLimit
+	if definition.DepthLimit != nil {
+		depthLimit = *definition.DepthLimit
+	}
+
+	keys := make(map[string]struct{})
+	depth := flattenedObjectDepth("", val, keys)
+	if depth > depthLimit {
+		return fmt.Errorf("flattened field %q exceeds the maximum depth limit of %d (depth: %d)", key, depthLimit, depth)
+	}
+
+	if v.flattenedKeysWarningThreshold > 0 && len(keys) > v.flattenedKeysWarningThreshold {
+		logger.Warnf("flattened field %q has %d unique keys, more than the threshold of %d", key, len(keys), v.flattenedKeysWarningThreshold)
+	}
+	return nil
+}
+
+// flattenedObjectDepth returns the depth of the object and collects the keys of its leaf values.
+func flattenedObjectDepth(root string, val any, keys map[string]struct{}) int {
+	switch val := val.(type) {
+	case map[string]any:
+		maxDepth := 0
+		for name, elem := range val {
+			key := strings.TrimLeft(root+"."+name, ".")
+			maxDepth = max(maxDepth, flattenedObjectDepth(key, elem, keys))
+		}
+		return maxDepth + 1
+	case []any:
+		// Arrays don't increase the depth.
+		maxDepth := 0
+		for _, elem := range val {
+			maxDepth = max(maxDepth, flattenedObjectDepth(root, elem, keys))
+		}
+		return maxDepth
+	default:
+		keys[root] = struct{}{}
+		return 0
+	}
+}
+
+func (v *Validator) markExercised(key string) {
+	if !v.enabledFieldsCoverage {
+		return
+	}
+	v.exercisedKeysMutex.Lock()
+	defer v.exercisedKeysMutex.Unlock()
+	v.exercisedKeys[key] = struct{}{}
+}
+
+func (v *Validator) recordFieldTiming(key string, start time.Time) {
+	elapsed := time.Since(start)
+
+	v.fieldTimingsMutex.Lock()
+	defer v.fieldTimingsMutex.Unlock()
+	timing, found := v.fieldTimings[key]
+	if !found {
+		timing = &FieldTiming{Name: key}
+		v.fieldTimings[key] = timing
+	}
+	timing.Count++
+	timing.Total += elapsed
+}
+
+// budgetUsage is the state of the resources of the process when the validation of a document starts.
+type budgetUsage struct {
+	start     time.Time
+	allocated uint64
+}
+
+func (v *Validator) startBudgetUsage() budgetUsage {
+	var usage budgetUsage
+	if v.budget.Time > 0 {
+		usage.start = time.Now()
+	}
+	if v.budget.Memory > 0 {
+		usage.allocated = totalAllocatedMemory()
+	}
+	return usage
+}
+
+// spendBudget adds the resources used since the given usage was started to the ones spent by the
+// validator, and returns an error the first time that the budget is exceeded.
+func (v *Validator) spendBudget(usage budgetUsage) error {
+	if v.budget.Time <= 0 && v.budget.Memory == 0 {
+		return nil
+	}
+
+	v.budgetMutex.Lock()
+	defer v.budgetMutex.Unlock()
+	var err error
+	if v.budget.Time > 0 {
+		v.spentTime += time.Since(usage.start)
+		if v.spentTime > v.budget.Time {
+			err = fmt.Errorf("validation exceeded its time budget of %s (spent %s)", v.budget.Time, v.spentTime)
 		}
 	}
-	return errs
+	if v.budget.Memory > 0 {
+		v.allocatedMemory += totalAllocatedMemory() - usage.allocated
+		if err == nil && v.allocatedMemory > v.budget.Memory {
+			err = fmt.Errorf("validation exceeded its memory budget of %d bytes (allocated %d bytes)", v.budget.Memory, v.allocatedMemory)
+		}
+	}
+	if err == nil || v.budgetExceeded {
+		return nil
+	}
+	v.budgetExceeded = true
+	return err
 }
 
-func (v *Validator) validateScalarElement(key string, val any, doc common.MapStr) error {
-	if key == "" {
-		return nil // root key is always valid
+func totalAllocatedMemory() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.TotalAlloc
+}
+
+// FieldTimings returns the time spent validating each field in the documents validated till now,
+// sorted from the slowest to the fastest. Field timings need to be enabled with WithEnabledFieldTimings.
+func (v *Validator) FieldTimings() []FieldTiming {
+	v.fieldTimingsMutex.Lock()
+	defer v.fieldTimingsMutex.Unlock()
+
+	timings := make([]FieldTiming, 0, len(v.fieldTimings))
+	for _, timing := range v.fieldTimings {
+		timings = append(timings, *timing)
 	}
+	SortFieldTimings(timings)
+	return timings
+}
 
-	definition := FindElementDefinition(key, v.Schema)
-	if definition == nil {
-		switch {
-		case skipValidationForField(key):
-			return nil // generic field, let's skip validation for now
-		case isFlattenedSubfield(key, v.Schema):
-			return nil // flattened subfield, it will be stored as member of the flattened ancestor.
-		case isArrayOfObjects(val):
-			return fmt.Errorf(`field %q is used as array of objects, expected explicit definition with type group or nested`, key)
-		case couldBeMultifield(key, v.Schema):
-			return fmt.Errorf(`field %q is undefined, could be a multifield`, key)
-		default:
-			return fmt.Errorf(`field %q is undefined`, key)
+// SortFieldTimings sorts field timings from the slowest to the fastest, and by name for equal times.
+func SortFieldTimings(timings []FieldTiming) {
+	sort.Slice(timings, func(i, j int) bool {
+		if timings[i].Total != timings[j].Total {
+			return timings[i].Total > timings[j].Total
+		}
+		return timings[i].Name < timings[j].Name
+	})
+}
+
+// DumpSchema serializes to YAML the schema used by the validator, after resolving the fields
+// imported from external sources, like ECS, so it includes all the definitions that are used
+// to validate documents.
+func (v *Validator) DumpSchema() ([]byte, error) {
+	d, err := yaml.Marshal(v.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode schema: %w", err)
+	}
+	return d, nil
+}
+
+// CoverageReport returns the fields defined in the package that didn't match any value in the
+// documents validated till now, sorted by name. Names of the returned definitions are the full
+// names of the fields. Fields coverage needs to be enabled with WithEnabledFieldsCoverage.
+func (v *Validator) CoverageReport() []FieldDefinition {
+	var unexercised []FieldDefinition
+	for _, coverage := range v.FieldsCoverage() {
+		if !coverage.Exercised {
+			unexercised = append(unexercised, coverage.Definition)
 		}
 	}
+	return unexercised
+}
 
-	if !v.disabledNormalization {
-		err := v.validateExpectedNormalization(*definition, val)
-		if err != nil {
-			return fmt.Errorf("field %q is not normalized as expected: %w", key, err)
+// FieldCoverage describes if a field defined in the package matched any value in the validated documents.
+type FieldCoverage struct {
+	// Definition is the definition of the field, with its full name.
+	Definition FieldDefinition
+	Exercised  bool
+}
+
+// FieldsCoverage returns all the fields defined in the package that can store values, sorted by name,
+// indicating if they matched any value in the documents validated till now. Fields coverage needs to
+// be enabled with WithEnabledFieldsCoverage.
+func (v *Validator) FieldsCoverage() []FieldCoverage {
+	v.exercisedKeysMutex.Lock()
+	defer v.exercisedKeysMutex.Unlock()
+
+	coverage := v.collectFieldsCoverage("", v.packageSchema)
+	sort.Slice(coverage, func(i, j int) bool {
+		return coverage[i].Definition.Name < coverage[j].Definition.Name
+	})
+	return coverage
+}
+
+func (v *Validator) collectFieldsCoverage(root string, definitions []FieldDefinition) []FieldCoverage {
+	var coverage []FieldCoverage
+	for _, def := range definitions {
+		key := strings.TrimLeft(root+"."+def.Name, ".")
+		if len(def.Fields) > 0 {
+			coverage = append(coverage, v.collectFieldsCoverage(key, def.Fields)...)
+			continue
+		}
+		if def.Type == "group" || def.Runtime {
+			// Empty groups don't store values, and runtime fields are not stored in documents.
+			continue
 		}
+		exercised := v.isFieldExercised(key, def)
+		def.Name = key
+		coverage = append(coverage, FieldCoverage{Definition: def, Exercised: exercised})
 	}
+	return coverage
+}
 
-	err := v.parseElementValue(key, *definition, val, doc)
-	if err != nil {
-		return fmt.Errorf("parsing field value failed: %w", err)
+func (v *Validator) isFieldExercised(key string, def FieldDefinition) bool {
+	for exercised := range v.exercisedKeys {
+		// Objects, nested and flattened fields are exercised by any of their subfields.
+		if compareKeys(key, def, exercised) || strings.HasPrefix(exercised, key+".") {
+			return true
+		}
 	}
-	return nil
+	return false
 }
 
 func (v *Validator) SanitizeSyntheticSourceDocs(docs []common.MapStr) ([]common.MapStr, error) {
@@ -822,7 +1858,8 @@ func skipValidationForField(key string) bool {
 		isFieldFamilyMatching("event", key) || // too many common fields
 		isFieldFamilyMatching("host", key) || // too many common fields
 		isFieldFamilyMatching("metricset", key) || // field is deprecated
-		isFieldFamilyMatching("event.module", key) // field is deprecated
+		isFieldFamilyMatching("event.module", key) || // field is deprecated
+		isFieldFamilyMatching("_inference_fields", key) // inference results of semantic_text fields
 }
 
 // skipLeafOfObject checks if the element is a child of an object that was skipped in some previous
@@ -860,11 +1897,6 @@ func isFieldFamilyMatching(family, key string) bool {
 	return key == family || strings.HasPrefix(key, family+".")
 }
 
-func isFieldTypeFlattened(key string, fieldDefinitions []FieldDefinition) bool {
-	definition := FindElementDefinition(key, fieldDefinitions)
-	return definition != nil && definition.Type == "flattened"
-}
-
 func couldBeMultifield(key string, fieldDefinitions []FieldDefinition) bool {
 	parent := findParentElementDefinition(key, fieldDefinitions)
 	if parent == nil {
@@ -879,6 +1911,13 @@ func couldBeMultifield(key string, fieldDefinitions []FieldDefinition) bool {
 	return true
 }
 
+func isRuntimeSubfield(key string, schema []FieldDefinition) bool {
+	_, ancestor := findAncestorElementDefinition(key, schema, func(_ string, def *FieldDefinition) bool {
+		return def.Runtime
+	})
+	return ancestor != nil
+}
+
 func isArrayOfObjects(val any) bool {
 	switch val := val.(type) {
 	case []map[string]any:
@@ -893,6 +1932,39 @@ func isArrayOfObjects(val any) bool {
 	return false
 }
 
+// scalarFieldTypes contains the types of fields that can only store single values, or arrays
+// of them, but never objects.
+var scalarFieldTypes = []string{
+	"keyword", "constant_keyword", "wildcard", "text", "match_only_text", "version",
+	"date", "date_nanos", "ip", "boolean",
+	"long", "integer", "short", "byte", "unsigned_long",
+	"double", "float", "half_float", "scaled_float",
+}
+
+// objectInScalarFieldError returns an error if the value is an object, but the field is defined
+// with a type that only stores single values. This usually happens when a processor writes an
+// object in the path of a field, or a value in the wrong path.
+func objectInScalarFieldError(key string, definition FieldDefinition, val any) error {
+	obj, isObject := val.(map[string]any)
+	if !isObject || !slices.Contains(scalarFieldTypes, definition.Type) {
+		return nil
+	}
+	subfields := make([]string, 0, len(obj))
+	for name := range obj {
+		subfields = append(subfields, name)
+	}
+	sort.Strings(subfields)
+	return fmt.Errorf("field %q is defined as %s, but it contains an object with subfields [%s], expected a single value%s",
+		key, definition.Type, strings.Join(subfields, ", "), definedAt(definition))
+}
+
+func isSemanticTextSubfield(key string, schema []FieldDefinition) bool {
+	_, ancestor := findAncestorElementDefinition(key, schema, func(_ string, def *FieldDefinition) bool {
+		return def.Type == "semantic_text"
+	})
+	return ancestor != nil
+}
+
 func isFlattenedSubfield(key string, schema []FieldDefinition) bool {
 	_, ancestor := findAncestorElementDefinition(key, schema, func(_ string, def *FieldDefinition) bool {
 		return def.Type == "flattened"
@@ -901,6 +1973,18 @@ func isFlattenedSubfield(key string, schema []FieldDefinition) bool {
 	return ancestor != nil
 }
 
+// dynamicMappingOfSubfield returns the closest ancestor object of the given key that
+// sets the dynamic mapping parameter, and the value of this parameter.
+func dynamicMappingOfSubfield(key string, schema []FieldDefinition) (string, string) {
+	objectKey, ancestor := findAncestorElementDefinition(key, schema, func(_ string, def *FieldDefinition) bool {
+		return (def.Type == "object" || def.Type == "group") && def.Dynamic != ""
+	})
+	if ancestor == nil {
+		return "", ""
+	}
+	return objectKey, ancestor.Dynamic
+}
+
 func findElementDefinitionForRoot(root, searchedKey string, fieldDefinitions []FieldDefinition) *FieldDefinition {
 	for _, def := range fieldDefinitions {
 		key := strings.TrimLeft(root+"."+def.Name, ".")
@@ -1024,6 +2108,12 @@ func (v *Validator) validateExpectedNormalization(definition FieldDefinition, va
 			if _, isArray := val.([]any); val != nil && !isArray {
 				return fmt.Errorf("expected array, found %q (%T)", val, val)
 			}
+		case "lowercase":
+			for _, s := range valueToStringsSlice(val) {
+				if s != strings.ToLower(s) {
+					return fmt.Errorf("expected lowercase value, found %q", s)
+				}
+			}
 		}
 	}
 	return nil
@@ -1065,6 +2155,11 @@ func validSubField(def FieldDefinition, extraPart string) bool {
 // parseElementValue checks that the value stored in a field matches the field definition. For
 // arrays it checks it for each Element.
 func (v *Validator) parseElementValue(key string, definition FieldDefinition, val any, doc common.MapStr) error {
+	// Points can be given as arrays of coordinates, validate them as a single value.
+	if definition.Type == "point" && isCoordinatesArray(val) {
+		return v.parseSingleElementValue(key, definition, val, doc)
+	}
+
 	// Validate types first for each element, so other checks don't need to worry about types.
 	err := forEachElementValue(key, definition, val, doc, v.parseSingleElementValue)
 	if err != nil {
@@ -1084,34 +2179,73 @@ func (v *Validator) parseElementValue(key string, definition FieldDefinition, va
 // case that there are multiple values.
 func (v *Validator) parseAllElementValues(key string, definition FieldDefinition, val any, doc common.MapStr) error {
 	switch definition.Type {
-	case "constant_keyword", "keyword", "text":
+	case "constant_keyword", "keyword", "text", "match_only_text":
 		if !v.specVersion.LessThan(semver2_0_0) {
 			if err := ensureExpectedEventType(key, val, definition, doc); err != nil {
 				return err
 			}
 		}
+	case "nested":
+		if err := ensureRequiredNestedSubfields(key, v.requiredNestedSubfields[key], val); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// acceptsNumericKeyword returns true if the keyword field with the given key accepts the given non-string value.
+func (v *Validator) acceptsNumericKeyword(key string, val any) bool {
+	if v.defaultNumericConversion || slices.Contains(v.numericKeywordFields, key) {
+		return true
+	}
+	patterns := v.conditionalNumericKeywordFields[key]
+	if len(patterns) == 0 {
+		return false
+	}
+	s := numericKeywordString(val)
+	for _, pattern := range patterns {
+		if pattern.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// numericKeywordString returns the value as it would be stored in a keyword field. Numbers
+// are formatted without exponent, as they appear in the original document.
+func numericKeywordString(val any) string {
+	switch val := val.(type) {
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case json.Number:
+		return val.String()
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
 // parseSingeElementValue performs validations on individual values of each element.
 func (v *Validator) parseSingleElementValue(key string, definition FieldDefinition, val any, doc common.MapStr) error {
 	invalidTypeError := func() error {
-		return fmt.Errorf("field %q's Go type, %T, does not match the expected field type: %s (field value: %v)", key, val, definition.Type, val)
+		return fmt.Errorf("field %q's Go type, %T, does not match the expected field type: %s (field value: %v)%s", key, val, definition.Type, val, definedAt(definition))
 	}
 
 	stringValue := func() (string, bool) {
 		switch val := val.(type) {
 		case string:
 			return val, true
-		case bool, float64:
-			if v.defaultNumericConversion || slices.Contains(v.numericKeywordFields, key) {
+		case bool, float64, json.Number:
+			if v.acceptsNumericKeyword(key, val) {
 				return fmt.Sprintf("%v", val), true
 			}
 		}
 		return "", false
 	}
 
+	if err := objectInScalarFieldError(key, definition, val); err != nil {
+		return err
+	}
+
 	switch definition.Type {
 	// Constant keywords can define a value in the definition, if they do, all
 	// values stored in this field should be this one.
@@ -1131,9 +2265,11 @@ func (v *Validator) parseSingleElementValue(key string, definition FieldDefiniti
 		if err := ensureAllowedValues(key, valStr, definition); err != nil {
 			return err
 		}
-	// Normal text fields should be of type string.
+	// Normal text fields should be of type string. The same applies to match_only_text,
+	// a space-optimized variant of text, and to semantic_text, whose embeddings are
+	// generated by an inference endpoint.
 	// If a pattern is provided, it checks if the value matches.
-	case "keyword", "text":
+	case "keyword", "text", "match_only_text", "semantic_text":
 		valStr, valid := stringValue()
 		if !valid {
 			return invalidTypeError()
@@ -1145,16 +2281,46 @@ func (v *Validator) parseSingleElementValue(key string, definition FieldDefiniti
 		if err := ensureAllowedValues(key, valStr, definition); err != nil {
 			return err
 		}
+		if definition.Type == "keyword" {
+			if err := v.ensureIgnoreAbove(key, valStr, definition); err != nil {
+				return err
+			}
+		}
+	// Version fields store semantic versions, pre-release and build metadata are
+	// allowed.
+	// If a pattern is provided, it checks if the value matches.
+	case "version":
+		valStr, valid := val.(string)
+		if !valid {
+			return invalidTypeError()
+		}
+
+		if _, err := semver.StrictNewVersion(valStr); err != nil {
+			return fmt.Errorf("field %q has value %q, expected a semantic version: %w%s", key, valStr, err, definedAt(definition))
+		}
+		if err := ensurePatternMatches(key, valStr, definition.Pattern); err != nil {
+			return err
+		}
+		if err := ensureAllowedValues(key, valStr, definition); err != nil {
+			return err
+		}
 	// Dates are expected to be formatted as strings or as seconds or milliseconds
 	// since epoch.
 	// If it is a string and a pattern is provided, it checks if the value matches.
+	// In documents obtained with the fields API, dates are formatted by Elasticsearch.
 	case "date":
 		switch val := val.(type) {
 		case string:
+			if v.fieldsAPIFormat && definition.DateFormat == "" && !canonicalDatePattern.MatchString(val) {
+				return fmt.Errorf("field %q has value %q, expected a date in the canonical format returned by the fields API (e.g. 2006-01-02T15:04:05.000Z)%s", key, val, definedAt(definition))
+			}
 			if err := ensurePatternMatches(key, val, definition.Pattern); err != nil {
 				return err
 			}
-		case float64:
+		case float64, json.Number:
+			if v.fieldsAPIFormat && definition.DateFormat == "" {
+				return fmt.Errorf("field %q has the numeric value %v, expected a date in the canonical format returned by the fields API%s", key, val, definedAt(definition))
+			}
 			// date as seconds or milliseconds since epoch
 			if definition.Pattern != "" {
 				return fmt.Errorf("numeric date in field %q, but pattern defined", key)
@@ -1215,7 +2381,7 @@ func (v *Validator) parseSingleElementValue(key string, definition FieldDefiniti
 				return nil
 			}
 
-			return fmt.Errorf("field %q is a group of fields of type %s, it cannot store values", key, definition.Type)
+			return fmt.Errorf("field %q is defined as an object of type %s, but it contains the single value %v, expected an object with subfields%s", key, definition.Type, val, definedAt(definition))
 		}
 	// Numbers should have been parsed as float64, otherwise they are not numbers.
 	case "float", "long", "double":
@@ -1223,15 +2389,70 @@ func (v *Validator) parseSingleElementValue(key string, definition FieldDefiniti
 		case float64:
 		case json.Number:
 		case string:
-			if !slices.Contains(v.stringNumberFields, key) {
-				return invalidTypeError()
+			if slices.Contains(v.stringNumberFields, key) {
+				if _, err := strconv.ParseFloat(val, 64); err != nil {
+					return invalidTypeError()
+				}
+				break
 			}
-			if _, err := strconv.ParseFloat(val, 64); err != nil {
+			if !v.acceptCoercibleNumericStrings {
 				return invalidTypeError()
 			}
+			if err := ensureCoercibleNumericString(definition.Type, val); err != nil {
+				return fmt.Errorf("field %q has the string value %q that cannot be coerced to %s: %w%s", key, val, definition.Type, err, definedAt(definition))
+			}
+		default:
+			return invalidTypeError()
+		}
+	// Unsigned longs can hold values greater than the ones representable in a float64 or
+	// an int64, these values are decoded as json.Number to be validated without losing
+	// precision. They can be also given as strings, as many JSON encoders cannot represent them.
+	case "unsigned_long":
+		var s string
+		switch val := val.(type) {
+		case float64:
+			// Float64 cannot represent exactly the maximum value of unsigned_long, so values
+			// rounded to it are accepted.
+			if val < 0 || val > math.MaxUint64 {
+				return fmt.Errorf("field %q has the value %v out of the range of unsigned_long [0, %d]%s", key, val, uint64(math.MaxUint64), definedAt(definition))
+			}
+			return nil
+		case json.Number:
+			s = val.String()
+		case string:
+			s = val
+		default:
+			return invalidTypeError()
+		}
+		if err := ensureUnsignedLong(s); err != nil {
+			return fmt.Errorf("field %q has the value %q that is not a valid unsigned_long: %w%s", key, s, err, definedAt(definition))
+		}
+	// Booleans should have been parsed as bool. The strings "true" and "false" are
+	// only accepted when enabled, other strings and numbers are not valid.
+	case "boolean":
+		switch val := val.(type) {
+		case bool:
+		case string:
+			if val != "true" && val != "false" {
+				return fmt.Errorf("field %q has value %q, expected a boolean (true or false)%s", key, val, definedAt(definition))
+			}
+			if !v.acceptStringBooleans {
+				return fmt.Errorf("field %q has the string value %q, expected a boolean, not a string%s", key, val, definedAt(definition))
+			}
 		default:
 			return invalidTypeError()
 		}
+	// Shapes can be GeoJSON objects or WKT strings, with geographic coordinates in geo_shape
+	// fields, and cartesian coordinates in shape fields.
+	case "geo_shape", "shape":
+		if err := validateShape(val); err != nil {
+			return fmt.Errorf("field %q has an invalid %s value: %w%s", key, definition.Type, err, definedAt(definition))
+		}
+	// Cartesian points can be given in several formats, as objects, strings or arrays.
+	case "point":
+		if err := validatePoint(val); err != nil {
+			return fmt.Errorf("field %q has an invalid point value: %w%s", key, err, definedAt(definition))
+		}
 	// All other types are considered valid not blocking validation.
 	default:
 		return nil
@@ -1271,6 +2492,60 @@ func (v *Validator) isAllowedIPValue(s string) bool {
 	return false
 }
 
+var (
+	emailAddressRegexp = regexp.MustCompile(`[A-Za-z0-9._%+-]+@([A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)+)`)
+	hostnameRegexp     = regexp.MustCompile(`[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)+`)
+
+	// Domains reserved for documentation and testing purposes (RFC 2606).
+	reservedDomains = []string{
+		"example.com",
+		"example.net",
+		"example.org",
+		"example",
+		"invalid",
+		"localhost",
+		"test",
+	}
+)
+
+// ensureNoPII checks that the value doesn't look like real personal data, as email
+// addresses or hostnames of real domains.
+func (v *Validator) ensureNoPII(key string, _ FieldDefinition, val any, _ common.MapStr) error {
+	str, ok := val.(string)
+	if !ok {
+		return nil
+	}
+
+	for _, match := range emailAddressRegexp.FindAllStringSubmatch(str, -1) {
+		if !isSubdomainOfAny(match[1], reservedDomains) {
+			return fmt.Errorf("field %q contains a likely real email address %q, use a domain reserved for documentation, like example.com", key, match[0])
+		}
+	}
+	for _, hostname := range hostnameRegexp.FindAllString(str, -1) {
+		if isSubdomainOfAny(hostname, v.deniedDomains) {
+			return fmt.Errorf("field %q contains the hostname %q of a denied domain", key, hostname)
+		}
+	}
+	for _, pattern := range v.deniedPatterns {
+		if pattern.MatchString(str) {
+			return fmt.Errorf("field %q's value %q matches the denied pattern %q", key, str, pattern.String())
+		}
+	}
+	return nil
+}
+
+// isSubdomainOfAny checks if the given hostname is one of the given domains, or a subdomain of them.
+func isSubdomainOfAny(hostname string, domains []string) bool {
+	hostname = strings.ToLower(hostname)
+	for _, domain := range domains {
+		domain = strings.ToLower(domain)
+		if hostname == domain || strings.HasSuffix(hostname, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
 // forEachElementValue visits a function for each element in the given value if
 // it is an array. If it is not an array, it calls the function with it.
 func forEachElementValue(key string, definition FieldDefinition, val any, doc common.MapStr, fn func(string, FieldDefinition, any, common.MapStr) error) error {
@@ -1287,6 +2562,153 @@ func forEachElementValue(key string, definition FieldDefinition, val any, doc co
 	return nil
 }
 
+// ensureIgnoreAbove checks that the value of a keyword field is not longer than the ignore_above
+// setting of its definition. Elasticsearch doesn't index these values, so they cannot be searched.
+func (v *Validator) ensureIgnoreAbove(key, val string, definition FieldDefinition) error {
+	if definition.IgnoreAbove <= 0 {
+		return nil
+	}
+	length := utf8.RuneCountInString(val)
+	if length <= definition.IgnoreAbove {
+		return nil
+	}
+	err := fmt.Errorf("field %q has a value of length %d, longer than its ignore_above of %d, it won't be searchable%s", key, length, definition.IgnoreAbove, definedAt(definition))
+	if v.enabledIgnoreAboveErrors {
+		return err
+	}
+	logger.Warn(err.Error())
+	return nil
+}
+
+// IgnoredFields returns the fields of the document whose values would be ignored when indexing it
+// with the mappings of the field definitions. Only keyword values longer than their ignore_above
+// setting are detected, other ignored values depend on the mappings installed in Elasticsearch.
+func (v *Validator) IgnoredFields(body json.RawMessage) ([]string, error) {
+	doc, err := decodeDocument(body)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalling document body failed: %w", err)
+	}
+
+	var ignored []string
+	v.collectIgnoredFields("", doc, &ignored)
+	sort.Strings(ignored)
+	return ignored, nil
+}
+
+func (v *Validator) collectIgnoredFields(root string, elem map[string]any, ignored *[]string) {
+	for name, val := range elem {
+		key := strings.TrimLeft(root+"."+name, ".")
+
+		if m, ok := val.(map[string]any); ok {
+			v.collectIgnoredFields(key, m, ignored)
+			continue
+		}
+
+		definition := FindElementDefinition(key, v.Schema)
+		if definition == nil || definition.Type != "keyword" || definition.IgnoreAbove <= 0 {
+			continue
+		}
+		values, isArray := val.([]any)
+		if !isArray {
+			values = []any{val}
+		}
+		for _, value := range values {
+			str, ok := value.(string)
+			if ok && utf8.RuneCountInString(str) > definition.IgnoreAbove {
+				*ignored = append(*ignored, key)
+				break
+			}
+		}
+	}
+}
+
+// ensureCoercibleNumericString checks that the string can be coerced by Elasticsearch to a number
+// of the given type. Fractions in values of long fields are truncated when coerced, so they are
+// accepted.
+func ensureCoercibleNumericString(fieldType string, val string) error {
+	bitSize := 64
+	if fieldType == "float" {
+		bitSize = 32
+	}
+	n, err := strconv.ParseFloat(val, bitSize)
+	if err != nil {
+		return errors.Unwrap(err)
+	}
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return errors.New("only finite values are supported")
+	}
+	if fieldType == "long" {
+		if _, err := strconv.ParseInt(val, 10, 64); err == nil {
+			return nil
+		}
+		// Float64 cannot represent exactly the limits of long, but values out of them are
+		// rejected anyway.
+		if n < math.MinInt64 || n >= math.MaxInt64 {
+			return errors.New("value out of range")
+		}
+	}
+	return nil
+}
+
+// ensureUnsignedLong checks that the number in the string is in the range of unsigned_long,
+// from 0 to 2^64-1. Fractions are truncated by Elasticsearch, so they are accepted.
+func ensureUnsignedLong(s string) error {
+	if _, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return nil
+	}
+	f, _, err := big.ParseFloat(s, 10, 256, big.ToZero)
+	if err != nil {
+		return errors.New("invalid number")
+	}
+	if f.Sign() < 0 {
+		return errors.New("negative values are not supported")
+	}
+	if f.IsInf() {
+		return errors.New("value out of range")
+	}
+	n, _ := f.Int(nil)
+	if !n.IsUint64() {
+		return errors.New("value out of range")
+	}
+	return nil
+}
+
+// unitRanges contains the ranges of values expected for known units. Some ranges only apply to
+// gauges, percentages can exceed 100 or be negative in other metrics, as in deltas.
+var unitRanges = map[string]struct {
+	min, max   float64
+	onlyGauges bool
+}{
+	"byte":    {min: 0, max: math.Inf(1)},
+	"percent": {min: 0, max: 100, onlyGauges: true},
+}
+
+// ensureValueInUnitRange checks that numeric values of fields with known units are in the
+// range of values expected for the unit. Other values are not checked.
+func ensureValueInUnitRange(key string, definition FieldDefinition, val any, _ common.MapStr) error {
+	r, found := unitRanges[definition.Unit]
+	if !found || (r.onlyGauges && definition.MetricType != "gauge") {
+		return nil
+	}
+	var num float64
+	switch val := val.(type) {
+	case float64:
+		num = val
+	case json.Number:
+		n, err := val.Float64()
+		if err != nil {
+			return nil
+		}
+		num = n
+	default:
+		return nil
+	}
+	if num < r.min || num > r.max {
+		return fmt.Errorf("field %q with unit %q has value out of the expected range [%v, %v]: %v", key, definition.Unit, r.min, r.max, num)
+	}
+	return nil
+}
+
 // ensurePatternMatches validates the document's field value matches the field
 // definitions regular expression pattern.
 func ensurePatternMatches(key, value, pattern string) error {
@@ -1316,13 +2738,62 @@ func ensureConstantKeywordValueMatches(key, value, constantKeywordValue string)
 }
 
 // ensureAllowedValues validates that the document's field value
-// is one of the allowed values.
+// is one of the allowed values. Mismatches in fields that are not
+// indexed are only reported as warnings, as these values cannot be
+// used in queries.
 func ensureAllowedValues(key, value string, definition FieldDefinition) error {
-	if !definition.AllowedValues.IsAllowed(value) {
-		return fmt.Errorf("field %q's value %q is not one of the allowed values (%s)", key, value, strings.Join(definition.AllowedValues.Values(), ", "))
+	var err error
+	switch {
+	case !definition.AllowedValues.IsAllowed(value):
+		err = fmt.Errorf("field %q's value %q is not one of the allowed values (%s)", key, value, strings.Join(definition.AllowedValues.Values(), ", "))
+	case len(definition.ExpectedValues) > 0 && !slices.Contains(definition.ExpectedValues, value):
+		err = fmt.Errorf("field %q's value %q is not one of the expected values (%s)", key, value, strings.Join(definition.ExpectedValues, ", "))
+	}
+	if err != nil && definition.Index != nil && !*definition.Index {
+		logger.Warnf("%s, ignored because the field is not indexed", err)
+		return nil
+	}
+	return err
+}
+
+// ensureRequiredNestedSubfields validates that each one of the objects of a nested field
+// contains the required subfields.
+func ensureRequiredNestedSubfields(key string, required []string, val any) error {
+	if len(required) == 0 {
+		return nil
+	}
+
+	_, isArray := val.([]any)
+	var errs multierror.Error
+	for i, object := range nestedObjects(val) {
+		for _, field := range required {
+			if value, err := common.MapStr(object).GetValue(field); err == nil && value != nil {
+				continue
+			}
+			path := key + "." + field
+			if isArray {
+				path = fmt.Sprintf("%s[%d].%s", key, i, field)
+			}
+			errs = append(errs, fmt.Errorf("%s is required", path))
+		}
 	}
-	if e := definition.ExpectedValues; len(e) > 0 && !slices.Contains(e, value) {
-		return fmt.Errorf("field %q's value %q is not one of the expected values (%s)", key, value, strings.Join(e, ", "))
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// nestedObjects returns the objects in the value of a nested field, flattening arrays.
+func nestedObjects(val any) []map[string]any {
+	switch val := val.(type) {
+	case map[string]any:
+		return []map[string]any{val}
+	case []any:
+		var objects []map[string]any
+		for _, elem := range val {
+			objects = append(objects, nestedObjects(elem)...)
+		}
+		return objects
 	}
 	return nil
 }
@@ -5,15 +5,23 @@
 package fields
 
 import (
+	"archive/zip"
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
 	"github.com/elastic/elastic-package/internal/common"
 	"github.com/elastic/elastic-package/internal/multierror"
@@ -53,6 +61,29 @@ func TestValidate_WithWildcardFields(t *testing.T) {
 	require.Empty(t, errs)
 }
 
+func TestValidate_BuiltPackage(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "testpackage-0.0.1.zip")
+	createZipPackage(t, zipPath, map[string]string{
+		"testpackage-0.0.1/manifest.yml":                        "testdata/manifest.yml",
+		"testpackage-0.0.1/data_stream/first/fields/fields.yml": "testdata/fields/fields.yml",
+	})
+
+	validator, err := CreateValidatorForBuiltPackage(zipPath, "first")
+	require.NoError(t, err)
+	require.NotNil(t, validator)
+
+	e := readSampleEvent(t, "testdata/constant-keyword-valid.json")
+	errs := validator.ValidateDocumentBody(e)
+	require.Empty(t, errs)
+
+	e = readSampleEvent(t, "testdata/constant-keyword-invalid.json")
+	errs = validator.ValidateDocumentBody(e)
+	require.NotEmpty(t, errs)
+
+	_, err = CreateValidatorForBuiltPackage(zipPath, "second")
+	require.Error(t, err)
+}
+
 func TestValidate_WithFlattenedFields(t *testing.T) {
 	validator, err := CreateValidatorForDirectory("testdata",
 		WithDisabledDependencyManagement())
@@ -64,6 +95,176 @@ func TestValidate_WithFlattenedFields(t *testing.T) {
 	require.Empty(t, errs)
 }
 
+func TestValidate_FlattenedDepthLimit(t *testing.T) {
+	validator, err := CreateValidatorForDirectory("testdata",
+		WithDisabledDependencyManagement())
+	require.NoError(t, err)
+
+	nested := func(depth int) map[string]any {
+		obj := map[string]any{"value": "a"}
+		for i := 1; i < depth; i++ {
+			obj = map[string]any{"level": obj}
+		}
+		return obj
+	}
+
+	errs := validator.ValidateDocumentMap(common.MapStr{
+		"http": map[string]any{
+			"request": map[string]any{
+				"headers": map[string]any{
+					"accept":       []any{"text/html", "application/json"},
+					"content-type": map[string]any{"value": "text/plain"},
+				},
+			},
+		},
+	})
+	require.Empty(t, errs)
+
+	errs = validator.ValidateDocumentMap(common.MapStr{
+		"http": map[string]any{
+			"request": map[string]any{
+				"headers": nested(3),
+			},
+		},
+	})
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], `flattened field "http.request.headers" exceeds the maximum depth limit of 2 (depth: 3)`)
+
+	// Default limit.
+	errs = validator.ValidateDocumentMap(common.MapStr{
+		"foo": map[string]any{
+			"flattened": map[string]any{
+				"request_parameters": nested(20),
+			},
+		},
+	})
+	require.Empty(t, errs)
+
+	errs = validator.ValidateDocumentMap(common.MapStr{
+		"foo": map[string]any{
+			"flattened": map[string]any{
+				"request_parameters": nested(21),
+			},
+		},
+	})
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], `flattened field "foo.flattened.request_parameters" exceeds the maximum depth limit of 20 (depth: 21)`)
+}
+
+func TestValidate_DynamicObjects(t *testing.T) {
+	v := Validator{
+		Schema: []FieldDefinition{
+			{
+				Name:    "labels",
+				Type:    "object",
+				Dynamic: "true",
+				Fields: []FieldDefinition{
+					{Name: "count", Type: "long"},
+				},
+			},
+			{
+				Name:    "config",
+				Type:    "object",
+				Dynamic: "strict",
+				Fields: []FieldDefinition{
+					{Name: "name", Type: "keyword"},
+					{
+						Name:    "extra",
+						Type:    "object",
+						Dynamic: "true",
+					},
+				},
+			},
+		},
+		disabledDependencyManagement: true,
+		specVersion:                  *semver3_0_1,
+	}
+
+	errs := v.ValidateDocumentMap(common.MapStr{
+		"labels": map[string]any{
+			"count": 3.0,
+			"env":   "production",
+			"owner": map[string]any{"team": "security"},
+		},
+		"config": map[string]any{
+			"name":  "default",
+			"extra": map[string]any{"anything": "goes"},
+		},
+	})
+	require.Empty(t, errs)
+
+	// Declared subfields are still validated.
+	errs = v.ValidateDocumentMap(common.MapStr{
+		"labels": map[string]any{
+			"count": "many",
+		},
+	})
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), `field "labels.count"`)
+
+	errs = v.ValidateDocumentMap(common.MapStr{
+		"config": map[string]any{
+			"name":    "default",
+			"unknown": "value",
+		},
+	})
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], `field "config.unknown" is undefined, and object "config" doesn't accept undefined subfields (dynamic: strict)`)
+}
+
+func TestValidate_StructuralMismatches(t *testing.T) {
+	v := Validator{
+		Schema: []FieldDefinition{
+			{
+				Name: "source",
+				Type: "group",
+				Fields: []FieldDefinition{
+					{Name: "ip", Type: "ip"},
+					{Name: "port", Type: "long"},
+				},
+			},
+			{
+				Name: "process",
+				Type: "group",
+				Fields: []FieldDefinition{
+					{Name: "name", Type: "keyword"},
+					{
+						Name: "parent",
+						Type: "group",
+						Fields: []FieldDefinition{
+							{Name: "name", Type: "keyword"},
+						},
+					},
+				},
+			},
+		},
+		disabledDependencyManagement: true,
+		specVersion:                  *semver3_0_1,
+	}
+
+	errs := v.ValidateDocumentMap(common.MapStr{
+		"source": map[string]any{
+			"ip":   map[string]any{"address": "10.0.0.1", "type": "private"},
+			"port": "http",
+		},
+		"process": map[string]any{
+			"name":   "bash",
+			"parent": "init",
+		},
+	})
+	require.Len(t, errs, 3)
+
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	allMessages := strings.Join(messages, "\n")
+	assert.Contains(t, allMessages, `field "source.ip" is defined as ip, but it contains an object with subfields [address, type], expected a single value`)
+	assert.Contains(t, allMessages, `field "process.parent" is defined as an object of type group, but it contains the single value init, expected an object with subfields`)
+	// Siblings are still validated.
+	assert.Contains(t, allMessages, `field "source.port"`)
+}
+
 func TestValidate_ObjectTypeWithoutWildcard(t *testing.T) {
 	validator, err := CreateValidatorForDirectory("testdata",
 		WithDisabledDependencyManagement())
@@ -101,6 +302,64 @@ func TestValidate_WithNumericKeywordFields(t *testing.T) {
 	require.Empty(t, errs)
 }
 
+func TestValidate_WithConditionalNumericKeywordFields(t *testing.T) {
+	e := readSampleEvent(t, "testdata/numeric.json")
+
+	for _, c := range []struct {
+		title string
+		when  string
+		valid bool
+	}{
+		{title: "unconditional", valid: true},
+		{title: "matching condition", when: "^[0-9]+$", valid: true},
+		{title: "not matching condition", when: "^1[0-9]*$", valid: false},
+	} {
+		t.Run(c.title, func(t *testing.T) {
+			validator, err := CreateValidatorForDirectory("testdata",
+				WithConditionalNumericKeywordFields([]NumericKeywordField{
+					{Name: "foo.code", When: c.when},
+					{Name: "foo.pid"},
+					{Name: "foo.ppid"},
+					{Name: "tags"},
+				}),
+				WithSpecVersion("2.3.0"), // Needed to validate normalization.
+				WithDisabledDependencyManagement())
+			require.NoError(t, err)
+
+			errs := validator.ValidateDocumentBody(e)
+			if c.valid {
+				require.Empty(t, errs)
+				return
+			}
+			require.Len(t, errs, 1)
+			assert.Contains(t, errs[0].Error(), `field "foo.code"'s Go type, float64, does not match the expected field type: keyword`)
+		})
+	}
+}
+
+func TestValidate_ConditionalNumericKeywordLargeNumbers(t *testing.T) {
+	v := Validator{
+		Schema: []FieldDefinition{
+			{Name: "error.code", Type: "keyword"},
+		},
+		disabledDependencyManagement: true,
+		specVersion:                  *semver3_0_1,
+	}
+	err := WithConditionalNumericKeywordFields([]NumericKeywordField{
+		{Name: "error.code", When: "^[0-9]+$"},
+	})(&v)
+	require.NoError(t, err)
+
+	for _, value := range []any{1234567.0, json.Number("98765432109876543210")} {
+		errs := v.ValidateDocumentMap(common.MapStr{"error.code": value})
+		assert.Empty(t, errs, "value: %v", value)
+	}
+
+	errs := v.ValidateDocumentMap(common.MapStr{"error.code": 1234567.5})
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), `field "error.code"'s Go type, float64, does not match the expected field type: keyword`)
+}
+
 func TestValidate_WithStringNumberFields(t *testing.T) {
 	validator, err := CreateValidatorForDirectory("testdata",
 		WithStringNumberFields([]string{
@@ -132,6 +391,79 @@ func TestValidate_WithEnabledImportAllECSSchema(t *testing.T) {
 	require.Empty(t, errs)
 }
 
+func TestValidate_WithECSVersion(t *testing.T) {
+	finder := packageRootTestFinder{"../../test/packages/other/imported_mappings_tests"}
+
+	validator, err := createValidatorForDirectoryAndPackageRoot("../../test/packages/other/imported_mappings_tests/data_stream/first",
+		finder,
+		WithSpecVersion("2.3.0"),
+		WithEnabledImportAllECSSChema(true),
+		WithECSVersion("file://./testdata/ecs_nested_v8.10.0.yml"))
+	require.NoError(t, err)
+	require.NotNil(t, validator)
+
+	e := readSampleEvent(t, "../../test/packages/other/imported_mappings_tests/data_stream/first/sample_event.json")
+	errs := validator.ValidateDocumentBody(e)
+	require.Empty(t, errs)
+
+	_, err = createValidatorForDirectoryAndPackageRoot("../../test/packages/other/imported_mappings_tests/data_stream/first",
+		finder,
+		WithSpecVersion("2.3.0"),
+		WithEnabledImportAllECSSChema(true),
+		WithECSVersion("file://./testdata/not-found.yml"))
+	assert.ErrorContains(t, err, `can't load schema of ECS version "file://./testdata/not-found.yml"`)
+}
+
+func TestECSReferenceForVersion(t *testing.T) {
+	cases := []struct {
+		version   string
+		reference string
+		valid     bool
+	}{
+		{version: "8.11.0", reference: "git@v8.11.0", valid: true},
+		{version: "v8.11.0", reference: "git@v8.11.0", valid: true},
+		{version: "git@1.9", reference: "git@1.9", valid: true},
+		{version: "file://./ecs_nested.yml", reference: "file://./ecs_nested.yml", valid: true},
+		{version: "8.11", valid: false},
+		{version: "latest", valid: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.version, func(t *testing.T) {
+			reference, err := ecsReferenceForVersion(c.version)
+			if !c.valid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.reference, reference)
+		})
+	}
+}
+
+func TestValidate_CreateValidatorForDataStreams(t *testing.T) {
+	docs := map[string]string{
+		"a": `{"source": {"address": "10.0.0.1"}, "a": {"message": "hello"}}`,
+		"b": `{"source": {"address": "10.0.0.1"}, "b": {"bytes": 1024}}`,
+	}
+
+	validator, err := CreateValidatorForDataStreams([]string{"testdata/data_streams/a"}, WithDisabledDependencyManagement())
+	require.NoError(t, err)
+	require.Empty(t, validator.ValidateDocumentBody(json.RawMessage(docs["a"])))
+	errs := validator.ValidateDocumentBody(json.RawMessage(docs["b"]))
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), `field "b.bytes" is undefined`)
+
+	validator, err = CreateValidatorForDataStreams([]string{"testdata/data_streams/a", "testdata/data_streams/b"}, WithDisabledDependencyManagement())
+	require.NoError(t, err)
+	for name, doc := range docs {
+		assert.Empty(t, validator.ValidateDocumentBody(json.RawMessage(doc)), "document of data stream %s", name)
+	}
+
+	errs = validator.ValidateDocumentBody(json.RawMessage(`{"b": {"bytes": "many"}}`))
+	require.Len(t, errs, 1)
+}
+
 func TestValidate_WithDisabledImportAllECSSchema(t *testing.T) {
 	finder := packageRootTestFinder{"../../test/packages/other/imported_mappings_tests"}
 
@@ -183,6 +515,74 @@ func TestValidate_ipAddress(t *testing.T) {
 	require.Empty(t, errs)
 }
 
+func TestValidate_SourceLocations(t *testing.T) {
+	validator, err := CreateValidatorForDirectory("testdata", WithDisabledDependencyManagement())
+	require.NoError(t, err)
+	require.NotNil(t, validator)
+
+	def := FindElementDefinition("foo.count", validator.Schema)
+	require.NotNil(t, def)
+	require.NotNil(t, def.Source)
+	assert.Equal(t, SourceLocation{File: filepath.Join("fields", "fields.yml"), Line: 20}, *def.Source)
+
+	errs := validator.ValidateDocumentMap(common.MapStr{
+		"foo": map[string]any{
+			"count":     "not a number",
+			"undefined": "value",
+		},
+	})
+	require.Len(t, errs, 2)
+
+	errorMessages := []string{}
+	for _, err := range errs {
+		errorMessages = append(errorMessages, err.Error())
+	}
+	sort.Strings(errorMessages)
+	assert.Contains(t, errorMessages[0], `field "foo.undefined" is undefined (fields directory: testdata/fields)`)
+	assert.Contains(t, errorMessages[1], `field "foo.count"'s Go type, string, does not match the expected field type: long (field value: not a number) (defined at fields/fields.yml:20)`)
+
+	validationErrors := map[string]*ValidationError{}
+	for _, err := range errs {
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		validationErrors[validationErr.Field] = validationErr
+	}
+	require.Contains(t, validationErrors, "foo.count")
+	assert.Equal(t, def.Source, validationErrors["foo.count"].Source)
+	require.Contains(t, validationErrors, "foo.undefined")
+	assert.Nil(t, validationErrors["foo.undefined"].Source)
+}
+
+func TestValidate_PII(t *testing.T) {
+	validator, err := CreateValidatorForDirectory("testdata",
+		WithEnabledPIICheck([]string{"corp.internal"}, nil),
+		WithDisabledDependencyManagement())
+	require.NoError(t, err)
+	require.NotNil(t, validator)
+
+	e := readSampleEvent(t, "testdata/pii-real-email.json")
+	errs := validator.ValidateDocumentBody(e)
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0].Error(), `field "user.email" contains a likely real email address "john.doe@acme-corp.com"`)
+
+	e = readSampleEvent(t, "testdata/pii-denied-hostname.json")
+	errs = validator.ValidateDocumentBody(e)
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0].Error(), `field "source.domain" contains the hostname "db01.corp.internal" of a denied domain`)
+
+	e = readSampleEvent(t, "testdata/pii-example-email.json")
+	errs = validator.ValidateDocumentBody(e)
+	require.Empty(t, errs)
+
+	// Check now that this validation is only enabled on demand.
+	validator, err = CreateValidatorForDirectory("testdata", WithDisabledDependencyManagement())
+	require.NoError(t, err)
+
+	e = readSampleEvent(t, "testdata/pii-real-email.json")
+	errs = validator.ValidateDocumentBody(e)
+	require.Empty(t, errs)
+}
+
 func TestValidate_undefinedArrayOfObjects(t *testing.T) {
 	validator, err := CreateValidatorForDirectory("testdata", WithSpecVersion("2.0.0"), WithDisabledDependencyManagement())
 	require.NoError(t, err)
@@ -216,6 +616,224 @@ func TestValidate_WithSpecVersion(t *testing.T) {
 	require.Empty(t, errs)
 }
 
+func TestValidate_LowercaseNormalization(t *testing.T) {
+	validator, err := CreateValidatorForDirectory("testdata", WithSpecVersion("2.0.0"), WithDisabledDependencyManagement())
+	require.NoError(t, err)
+
+	e := readSampleEvent(t, "testdata/invalid-lowercase-normalization.json")
+	errs := validator.ValidateDocumentBody(e)
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0].Error(), `field "url.domain" is not normalized as expected: expected lowercase value, found "Www.Example.COM"`)
+
+	e = readSampleEvent(t, "testdata/valid-lowercase-normalization.json")
+	errs = validator.ValidateDocumentBody(e)
+	require.Empty(t, errs)
+
+	// Check now that this validation was only enabled for 2.0.0.
+	validator, err = CreateValidatorForDirectory("testdata", WithSpecVersion("1.99.99"), WithDisabledDependencyManagement())
+	require.NoError(t, err)
+
+	e = readSampleEvent(t, "testdata/invalid-lowercase-normalization.json")
+	errs = validator.ValidateDocumentBody(e)
+	require.Empty(t, errs)
+}
+
+func TestValidate_RuntimeFields(t *testing.T) {
+	validator, err := CreateValidatorForDirectory("testdata", WithDisabledDependencyManagement())
+	require.NoError(t, err)
+
+	definition := FindElementDefinition("http.response.duration_ms", validator.Schema)
+	require.NotNil(t, definition)
+	assert.True(t, definition.Runtime)
+
+	e := readSampleEvent(t, "testdata/runtime-fields.json")
+	errs := validator.ValidateDocumentBody(e)
+	require.Empty(t, errs)
+
+	// Runtime fields are not required.
+	errs = validator.ValidateDocumentMap(common.MapStr{"foo.code": "200"})
+	require.Empty(t, errs)
+}
+
+func TestValidate_FieldsCoverage(t *testing.T) {
+	validator, err := CreateValidatorForDirectory("testdata", WithDisabledDependencyManagement(), WithEnabledFieldsCoverage())
+	require.NoError(t, err)
+
+	errs := validator.ValidateDocumentMap(common.MapStr{
+		"foo": map[string]any{
+			"code": "200",
+			"flattened": map[string]any{
+				"request_parameters": map[string]any{"page": "1"},
+			},
+		},
+		"attributes": map[string]any{"color": "blue"},
+	})
+	require.Empty(t, errs)
+	errs = validator.ValidateDocumentMap(common.MapStr{"process.name": "elastic-agent"})
+	require.Empty(t, errs)
+
+	var unexercised []string
+	for _, definition := range validator.CoverageReport() {
+		unexercised = append(unexercised, definition.Name)
+	}
+	assert.Contains(t, unexercised, "foo.pid")
+	assert.Contains(t, unexercised, "user.email")
+	assert.NotContains(t, unexercised, "foo.code")
+	assert.NotContains(t, unexercised, "foo.flattened.request_parameters")
+	assert.NotContains(t, unexercised, "attributes")
+	assert.NotContains(t, unexercised, "process.name")
+	assert.NotContains(t, unexercised, "process.name.text")
+	assert.NotContains(t, unexercised, "http.response.duration_ms")
+	assert.True(t, sort.StringsAreSorted(unexercised))
+}
+
+func TestValidate_FieldTimings(t *testing.T) {
+	validator, err := CreateValidatorForDirectory("testdata", WithDisabledDependencyManagement(), WithEnabledFieldTimings())
+	require.NoError(t, err)
+
+	for range 3 {
+		errs := validator.ValidateDocumentMap(common.MapStr{
+			"foo": map[string]any{
+				"code": "200",
+				"pid":  "1234",
+			},
+			"process.name": "elastic-agent",
+		})
+		require.Empty(t, errs)
+	}
+
+	timings := validator.FieldTimings()
+	require.Len(t, timings, 3)
+
+	var names []string
+	for i, timing := range timings {
+		names = append(names, timing.Name)
+		assert.Equal(t, 3, timing.Count)
+		if i > 0 {
+			assert.GreaterOrEqual(t, timings[i-1].Total, timing.Total)
+		}
+	}
+	assert.ElementsMatch(t, []string{"foo.code", "foo.pid", "process.name"}, names)
+}
+
+func TestValidate_ValidationBudget(t *testing.T) {
+	doc := common.MapStr{
+		"foo": map[string]any{
+			"code": "200",
+			"pid":  "1234",
+		},
+		"process.name": "elastic-agent",
+	}
+
+	cases := []struct {
+		title    string
+		budget   ValidationBudget
+		expected string
+	}{
+		{
+			title:  "within budget",
+			budget: ValidationBudget{Time: time.Hour, Memory: 1 << 40},
+		},
+		{
+			title:    "time exceeded",
+			budget:   ValidationBudget{Time: time.Nanosecond},
+			expected: "validation exceeded its time budget of 1ns",
+		},
+		{
+			title:    "memory exceeded",
+			budget:   ValidationBudget{Memory: 1},
+			expected: "validation exceeded its memory budget of 1 bytes",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			validator, err := CreateValidatorForDirectory("testdata", WithDisabledDependencyManagement(), WithValidationBudget(c.budget))
+			require.NoError(t, err)
+
+			errs := validator.ValidateDocumentMap(doc)
+			if c.expected == "" {
+				require.Empty(t, errs)
+				return
+			}
+			require.Len(t, errs, 1)
+			assert.ErrorContains(t, errs[0], c.expected)
+
+			// The budget is only reported once.
+			errs = validator.ValidateDocumentMap(doc)
+			assert.Empty(t, errs)
+		})
+	}
+}
+
+func TestValidate_CustomFieldChecks(t *testing.T) {
+	// Example of naming rule, fields cannot use abbreviations.
+	noAbbreviations := func(key string, _ FieldDefinition, _ any) error {
+		for _, part := range strings.Split(key, ".") {
+			if slices.Contains([]string{"pid", "ppid"}, part) {
+				return fmt.Errorf("abbreviation %q used in field name", part)
+			}
+		}
+		return nil
+	}
+
+	validator, err := CreateValidatorForDirectory("testdata",
+		WithDisabledDependencyManagement(),
+		WithCustomFieldChecks([]FieldCheck{noAbbreviations}),
+	)
+	require.NoError(t, err)
+
+	errs := validator.ValidateDocumentMap(common.MapStr{
+		"foo": map[string]any{
+			"code": "42",
+			"pid":  "1234",
+		},
+	})
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], `custom check failed for field "foo.pid": abbreviation "pid" used in field name`)
+
+	errs = validator.ValidateDocumentMap(common.MapStr{
+		"foo": map[string]any{
+			"code": "42",
+		},
+	})
+	require.Empty(t, errs)
+}
+
+func TestValidate_UnitsCheck(t *testing.T) {
+	e := readSampleEvent(t, "testdata/percent-out-of-range.json")
+
+	validator, err := CreateValidatorForDirectory("testdata", WithDisabledDependencyManagement())
+	require.NoError(t, err)
+	errs := validator.ValidateDocumentBody(e)
+	require.Empty(t, errs)
+
+	validator, err = CreateValidatorForDirectory("testdata", WithDisabledDependencyManagement(), WithEnabledUnitsCheck())
+	require.NoError(t, err)
+	errs = validator.ValidateDocumentBody(e)
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], `field "system.cpu.total.pct" with unit "percent" has value out of the expected range [0, 100]: 150`)
+}
+
+func TestValidate_DeadFieldsCheck(t *testing.T) {
+	e := readSampleEvent(t, "testdata/dead-fields.json")
+
+	validator, err := CreateValidatorForDirectory("testdata", WithDisabledDependencyManagement())
+	require.NoError(t, err)
+	errs := validator.ValidateDocumentBody(e)
+	require.Empty(t, errs)
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	validator, err = CreateValidatorForDirectory("testdata", WithDisabledDependencyManagement(), WithEnabledDeadFieldsCheck())
+	require.NoError(t, err)
+	errs = validator.ValidateDocumentBody(e)
+	require.Empty(t, errs)
+	assert.Contains(t, logs.String(), `field "event.payload" is populated, but it is not indexed and has no doc values, so its values cannot be queried (defined at fields/fields.yml:`)
+}
+
 func TestValidate_ExpectedEventType(t *testing.T) {
 	validator, err := CreateValidatorForDirectory("testdata", WithSpecVersion("2.0.0"), WithDisabledDependencyManagement())
 	require.NoError(t, err)
@@ -361,7 +979,160 @@ func TestValidate_ExpectedDatasets(t *testing.T) {
 	}
 }
 
+func TestValidate_ExpectedDataStream(t *testing.T) {
+	validator, err := CreateValidatorForDirectory("testdata",
+		WithSpecVersion("2.0.0"),
+		WithExpectedDataStream("metrics", "apache.status"),
+		WithDisabledDependencyManagement(),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, validator)
+
+	cases := []struct {
+		title    string
+		doc      common.MapStr
+		expected []string
+	}{
+		{
+			title: "valid data stream",
+			doc: common.MapStr{
+				"data_stream.type":      "metrics",
+				"data_stream.dataset":   "apache.status",
+				"data_stream.namespace": "default",
+			},
+		},
+		{
+			title: "absent data stream fields",
+			doc:   common.MapStr{},
+		},
+		{
+			title: "wrong type",
+			doc: common.MapStr{
+				"data_stream.type":      "logs",
+				"data_stream.dataset":   "apache.status",
+				"data_stream.namespace": "default",
+			},
+			expected: []string{`field "data_stream.type" should have value "metrics", it has "logs"`},
+		},
+		{
+			title: "wrong type and dataset",
+			doc: common.MapStr{
+				"data_stream": map[string]any{
+					"type":      "logs",
+					"dataset":   "apache.access",
+					"namespace": "default",
+				},
+			},
+			expected: []string{
+				`field "data_stream.type" should have value "metrics", it has "logs"`,
+				`field "data_stream.dataset" should have value "apache.status", it has "apache.access"`,
+			},
+		},
+		{
+			title: "invalid namespace",
+			doc: common.MapStr{
+				"data_stream.type":      "metrics",
+				"data_stream.dataset":   "apache.status",
+				"data_stream.namespace": "My-Namespace",
+			},
+			expected: []string{`field "data_stream.namespace" has value "My-Namespace", namespaces must be lowercase`},
+		},
+		{
+			title: "empty namespace",
+			doc: common.MapStr{
+				"data_stream.namespace": "",
+			},
+			expected: []string{`field "data_stream.namespace" should have a non-empty string value`},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			errs := validator.validateDocumentValues(c.doc)
+			require.Len(t, errs, len(c.expected))
+			for i, expected := range c.expected {
+				assert.Contains(t, errs[i].Error(), expected)
+			}
+		})
+	}
+}
+
+func TestValidate_TimestampBounds(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	validator, err := CreateValidatorForDirectory("testdata",
+		WithTimestampBounds(from, to),
+		WithDisabledDependencyManagement(),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, validator)
+
+	cases := []struct {
+		title     string
+		timestamp any
+		expected  string
+	}{
+		{title: "in range", timestamp: "2024-01-15T10:00:00.000Z"},
+		{title: "in range with timezone", timestamp: "2024-01-15T10:00:00.123456+02:00"},
+		{title: "in range without timezone", timestamp: "2024-01-15T10:00:00"},
+		{title: "in range as date", timestamp: "2024-01-31"},
+		{title: "in range as epoch millis", timestamp: float64(from.Add(time.Hour).UnixMilli())},
+		{title: "in range in synthetic source", timestamp: []any{"2024-01-15T10:00:00Z"}},
+		{
+			title:     "in the past",
+			timestamp: "2023-01-15T10:00:00Z",
+			expected:  `field "@timestamp" has value "2023-01-15T10:00:00Z", out of the expected range from 2024-01-01T00:00:00Z to 2024-01-31T00:00:00Z`,
+		},
+		{
+			title:     "in the future because of timezone",
+			timestamp: "2024-01-30T23:00:00-02:00",
+			expected:  `out of the expected range`,
+		},
+		{
+			title:     "unknown format",
+			timestamp: "15/01/2024",
+			expected:  `field "@timestamp" has value "15/01/2024", its range cannot be checked`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			errs := validator.validateDocumentValues(common.MapStr{"@timestamp": c.timestamp})
+			if c.expected == "" {
+				require.Empty(t, errs)
+				return
+			}
+			require.Len(t, errs, 1)
+			assert.Contains(t, errs[0].Error(), c.expected)
+		})
+	}
+
+	t.Run("absent timestamp", func(t *testing.T) {
+		errs := validator.validateDocumentValues(common.MapStr{})
+		require.Empty(t, errs)
+	})
+
+	t.Run("relative window", func(t *testing.T) {
+		validator, err := CreateValidatorForDirectory("testdata",
+			WithTimestampWithin(7*24*time.Hour),
+			WithDisabledDependencyManagement(),
+		)
+		require.NoError(t, err)
+
+		errs := validator.validateDocumentValues(common.MapStr{"@timestamp": time.Now().Add(-time.Hour).Format(time.RFC3339)})
+		require.Empty(t, errs)
+		errs = validator.validateDocumentValues(common.MapStr{"@timestamp": time.Now().Add(-8 * 24 * time.Hour).Format(time.RFC3339)})
+		require.Len(t, errs, 1)
+	})
+
+	t.Run("invalid bounds", func(t *testing.T) {
+		_, err := CreateValidatorForDirectory("testdata", WithTimestampBounds(to, from))
+		require.Error(t, err)
+	})
+}
+
 func Test_parseElementValue(t *testing.T) {
+	indexFalse := false
 	for _, test := range []struct {
 		key         string
 		value       any
@@ -378,6 +1149,17 @@ func Test_parseElementValue(t *testing.T) {
 				Type: "keyword",
 			},
 		},
+		{
+			key:   "object in keyword array",
+			value: []any{"hello", map[string]any{"value": "world"}},
+			definition: FieldDefinition{
+				Type: "keyword",
+			},
+			fail: true,
+			assertError: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, `field "object in keyword array" is defined as keyword, but it contains an object with subfields [value], expected a single value`)
+			},
+		},
 		{
 			key:   "mixed numbers and strings in number array",
 			value: []any{123, "hi"},
@@ -547,6 +1329,100 @@ func Test_parseElementValue(t *testing.T) {
 			},
 			fail: true,
 		},
+		// match_only_text
+		{
+			key:   "match_only_text",
+			value: "some text",
+			definition: FieldDefinition{
+				Type: "match_only_text",
+			},
+		},
+		{
+			key:   "match_only_text with pattern",
+			value: "more text",
+			definition: FieldDefinition{
+				Type:    "match_only_text",
+				Pattern: "^[A-Z]+$",
+			},
+			fail: true,
+		},
+		{
+			key:   "match_only_text with number",
+			value: 42.0,
+			definition: FieldDefinition{
+				Type: "match_only_text",
+			},
+			fail: true,
+		},
+		// version
+		{
+			key:   "version",
+			value: "1.2.3-rc1",
+			definition: FieldDefinition{
+				Type: "version",
+			},
+		},
+		{
+			key:   "version with build metadata",
+			value: "1.2.3+build.5",
+			definition: FieldDefinition{
+				Type: "version",
+			},
+		},
+		{
+			key:   "version not semver",
+			value: "not.a.version",
+			definition: FieldDefinition{
+				Type: "version",
+			},
+			fail: true,
+		},
+		{
+			key:   "version with number",
+			value: 1.0,
+			definition: FieldDefinition{
+				Type: "version",
+			},
+			fail: true,
+		},
+		// boolean
+		{
+			key:   "boolean",
+			value: true,
+			definition: FieldDefinition{
+				Type: "boolean",
+			},
+		},
+		{
+			key:   "boolean as string",
+			value: "true",
+			definition: FieldDefinition{
+				Type: "boolean",
+			},
+			fail: true,
+			assertError: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, `field "boolean as string" has the string value "true", expected a boolean, not a string`)
+			},
+		},
+		{
+			key:   "boolean with other string",
+			value: "yes",
+			definition: FieldDefinition{
+				Type: "boolean",
+			},
+			fail: true,
+			assertError: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, `field "boolean with other string" has value "yes", expected a boolean (true or false)`)
+			},
+		},
+		{
+			key:   "boolean with number",
+			value: 1.0,
+			definition: FieldDefinition{
+				Type: "boolean",
+			},
+			fail: true,
+		},
 		// float
 		{
 			key:   "float",
@@ -621,6 +1497,39 @@ func Test_parseElementValue(t *testing.T) {
 			},
 			fail: true,
 		},
+		// allowed and expected values in fields that are not indexed
+		{
+			key:   "not allowed value in not indexed field",
+			value: "display",
+			definition: FieldDefinition{
+				Type:  "keyword",
+				Index: &indexFalse,
+				AllowedValues: AllowedValues{
+					{
+						Name: "configuration",
+					},
+				},
+			},
+		},
+		{
+			key:   "not expected value in not indexed field",
+			value: "bsd",
+			definition: FieldDefinition{
+				Type:           "keyword",
+				Index:          &indexFalse,
+				ExpectedValues: []string{"linux", "windows"},
+			},
+		},
+		{
+			key:   "wrong type in not indexed field",
+			value: 42,
+			definition: FieldDefinition{
+				Type:           "keyword",
+				Index:          &indexFalse,
+				ExpectedValues: []string{"linux", "windows"},
+			},
+			fail: true,
+		},
 		// fields shouldn't be stored in groups
 		{
 			key:   "host",
@@ -810,6 +1719,151 @@ func Test_parseElementValue(t *testing.T) {
 	}
 }
 
+func TestValidate_StringBooleans(t *testing.T) {
+	definition := FieldDefinition{Name: "foo.enabled", Type: "boolean"}
+	v := Validator{
+		Schema:                       []FieldDefinition{definition},
+		disabledDependencyManagement: true,
+	}
+	require.NoError(t, WithStringBooleans()(&v))
+
+	for _, value := range []any{true, false, "true", "false"} {
+		assert.NoError(t, v.parseElementValue("foo.enabled", definition, value, common.MapStr{}), "value: %v", value)
+	}
+	for _, value := range []any{"True", "1", "", 0.0, json.Number("1")} {
+		assert.Error(t, v.parseElementValue("foo.enabled", definition, value, common.MapStr{}), "value: %v", value)
+	}
+}
+
+func TestValidate_CoercibleNumericStrings(t *testing.T) {
+	cases := []struct {
+		fieldType string
+		value     string
+		err       string
+	}{
+		{fieldType: "long", value: "123"},
+		{fieldType: "long", value: "1.23e5"},
+		{fieldType: "long", value: "-9223372036854775808"},
+		{fieldType: "long", value: "10.5"},
+		{fieldType: "long", value: "1e19", err: "value out of range"},
+		{fieldType: "long", value: "twelve", err: "invalid syntax"},
+		{fieldType: "double", value: "1.5E-3"},
+		{fieldType: "double", value: "1e400", err: "value out of range"},
+		{fieldType: "double", value: "NaN", err: "only finite values are supported"},
+		{fieldType: "float", value: "3.4e38"},
+		{fieldType: "float", value: "3.5e38", err: "value out of range"},
+		{fieldType: "float", value: "", err: "invalid syntax"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.fieldType+" "+c.value, func(t *testing.T) {
+			definition := FieldDefinition{Name: "foo.count", Type: c.fieldType}
+			v := Validator{
+				Schema:                       []FieldDefinition{definition},
+				disabledDependencyManagement: true,
+			}
+
+			// Strings are rejected by default.
+			err := v.parseElementValue("foo.count", definition, c.value, common.MapStr{})
+			require.Error(t, err)
+
+			require.NoError(t, WithCoercibleNumericStrings()(&v))
+			err = v.parseElementValue("foo.count", definition, c.value, common.MapStr{})
+			if c.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, "cannot be coerced to "+c.fieldType+": "+c.err)
+			}
+		})
+	}
+}
+
+func TestValidate_IgnoreAbove(t *testing.T) {
+	definition := FieldDefinition{Name: "foo.name", Type: "keyword", IgnoreAbove: 5}
+
+	t.Run("warning", func(t *testing.T) {
+		v := Validator{
+			Schema:                       []FieldDefinition{definition},
+			disabledDependencyManagement: true,
+		}
+		for _, value := range []any{"short", "too long", []any{"a", "too long"}} {
+			assert.NoError(t, v.parseElementValue("foo.name", definition, value, common.MapStr{}), "value: %v", value)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		v := Validator{
+			Schema:                       []FieldDefinition{definition},
+			disabledDependencyManagement: true,
+		}
+		require.NoError(t, WithEnabledIgnoreAboveErrors()(&v))
+
+		for _, value := range []any{"short", "ñandú", []any{"a", "b"}} {
+			assert.NoError(t, v.parseElementValue("foo.name", definition, value, common.MapStr{}), "value: %v", value)
+		}
+
+		err := v.parseElementValue("foo.name", definition, "too long", common.MapStr{})
+		assert.ErrorContains(t, err, "value of length 8, longer than its ignore_above of 5")
+
+		err = v.parseElementValue("foo.name", definition, []any{"short", "much too long"}, common.MapStr{})
+		assert.ErrorContains(t, err, "value of length 13, longer than its ignore_above of 5")
+
+		unlimited := FieldDefinition{Name: "foo.name", Type: "keyword"}
+		assert.NoError(t, v.parseElementValue("foo.name", unlimited, "too long", common.MapStr{}))
+	})
+
+	t.Run("external field override", func(t *testing.T) {
+		fieldsDir := t.TempDir()
+		fieldsFile := `
+- name: container.id
+  external: test
+  ignore_above: 5
+`
+		require.NoError(t, os.WriteFile(filepath.Join(fieldsDir, "fields.yml"), []byte(fieldsFile), 0644))
+		fdm := &DependencyManager{schema: map[string][]FieldDefinition{
+			"test": {{Name: "container.id", Type: "keyword", IgnoreAbove: 1024}},
+		}}
+
+		fields, err := loadFieldsFromDir(fieldsDir, fdm, InjectFieldsOptions{IncludeValidationSettings: true})
+		require.NoError(t, err)
+		require.Len(t, fields, 1)
+		assert.Equal(t, 5, fields[0].IgnoreAbove)
+
+		v := Validator{
+			Schema:                       fields,
+			disabledDependencyManagement: true,
+		}
+		require.NoError(t, WithEnabledIgnoreAboveErrors()(&v))
+		errs := v.ValidateDocumentMap(common.MapStr{"container": map[string]any{"id": "too long"}})
+		if assert.Len(t, errs, 1) {
+			assert.ErrorContains(t, errs[0], "longer than its ignore_above of 5")
+		}
+	})
+}
+
+func TestValidator_IgnoredFields(t *testing.T) {
+	v := Validator{
+		Schema: []FieldDefinition{
+			{Name: "foo.name", Type: "keyword", IgnoreAbove: 5},
+			{Name: "foo.tags", Type: "keyword", IgnoreAbove: 3},
+			{Name: "foo.text", Type: "text"},
+			{Name: "foo.id", Type: "keyword"},
+		},
+		disabledDependencyManagement: true,
+	}
+
+	ignored, err := v.IgnoredFields(json.RawMessage(`{"foo": {"name": "short", "tags": ["a", "b"], "text": "long text", "id": "long id"}}`))
+	require.NoError(t, err)
+	assert.Empty(t, ignored)
+
+	ignored, err = v.IgnoredFields(json.RawMessage(`{"foo": {"name": "too long", "tags": ["a", "long"], "text": "long text"}, "foo.id": "long id"}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo.name", "foo.tags"}, ignored)
+
+	_, err = v.IgnoredFields(json.RawMessage(`{"foo": `))
+	assert.ErrorContains(t, err, "unmarshalling document body failed")
+}
+
 func TestCompareKeys(t *testing.T) {
 	cases := []struct {
 		key         string
@@ -1149,8 +2203,365 @@ func readTestResults(t *testing.T, path string) (f results) {
 	return
 }
 
+func createZipPackage(t *testing.T, zipPath string, files map[string]string) {
+	f, err := os.Create(zipPath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, source := range files {
+		content, err := os.ReadFile(source)
+		require.NoError(t, err)
+		entry, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = entry.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+}
+
+func FuzzValidateDocumentBody(f *testing.F) {
+	validator, err := CreateValidatorForDirectory("testdata",
+		WithSpecVersion("3.0.0"),
+		WithDisabledDependencyManagement(),
+		WithEnabledAllowedIPCheck(),
+	)
+	require.NoError(f, err)
+
+	sampleEvents, err := filepath.Glob("testdata/*.json")
+	require.NoError(f, err)
+	for _, path := range sampleEvents {
+		d, err := os.ReadFile(path)
+		require.NoError(f, err)
+		f.Add(d)
+	}
+	for _, seed := range []string{
+		``,
+		`{`,
+		`null`,
+		`[]`,
+		`"foo"`,
+		`{"foo": null}`,
+		`{"foo": {"count": "not a number", "ip_address": 42}}`,
+		`{"foo": [{"code": [[[{}]]]}]}`,
+		`{"foo.flattened.request_parameters": {"a": {"b": {"c": {"d": 1}}}}}`,
+		`{"event": {"category": ["authentication", {}], "type": [null]}}`,
+		`{"container.image.tag": [["nested"]], "data_stream": {"dataset": 1}}`,
+		`{"a": {"b": {"c": {"d": {"e": {"f": {"g": {"h": "deep"}}}}}}}}`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		errs := validator.ValidateDocumentBody(data)
+		for _, err := range errs {
+			require.NotNil(t, err)
+			require.NotEmpty(t, err.Error())
+		}
+
+		// Use the input also as value of defined fields of different types.
+		doc := common.MapStr{
+			"foo": map[string]any{
+				"code":       string(data),
+				"count":      string(data),
+				"ip_address": string(data),
+				"constant":   string(data),
+			},
+		}
+		errs = validator.ValidateDocumentMap(doc)
+		for _, err := range errs {
+			require.NotNil(t, err)
+			require.NotEmpty(t, err.Error())
+		}
+	})
+}
+
 func readSampleEvent(t *testing.T, path string) json.RawMessage {
 	c, err := os.ReadFile(path)
 	require.NoError(t, err)
 	return c
 }
+
+func TestDumpSchema(t *testing.T) {
+	v, err := CreateValidatorForDirectory("testdata",
+		WithSpecVersion("2.3.0"),
+		WithDisabledDependencyManagement(),
+	)
+	require.NoError(t, err)
+
+	d, err := v.DumpSchema()
+	require.NoError(t, err)
+	assert.NotContains(t, string(d), `description: ""`)
+
+	var schema []FieldDefinition
+	require.NoError(t, yaml.Unmarshal(d, &schema))
+	require.Len(t, schema, len(v.Schema))
+	for i := range schema {
+		assert.Equal(t, v.Schema[i].Name, schema[i].Name)
+		assert.Equal(t, v.Schema[i].Type, schema[i].Type)
+		assert.Len(t, schema[i].Fields, len(v.Schema[i].Fields))
+		assert.Len(t, schema[i].MultiFields, len(v.Schema[i].MultiFields))
+	}
+}
+
+func TestValidate_UnsignedLong(t *testing.T) {
+	cases := []struct {
+		value string
+		err   string
+	}{
+		{value: `0`},
+		{value: `42`},
+		{value: `9007199254740993`},
+		{value: `9223372036854775808`},
+		{value: `18446744073709551615`},
+		{value: `"18446744073709551615"`},
+		{value: `"1.5"`},
+		{value: `1.8e19`},
+		{value: `18446744073709551616`, err: "value out of range"},
+		{value: `"18446744073709551616"`, err: "value out of range"},
+		{value: `1e20`, err: "out of the range of unsigned_long"},
+		{value: `-1`, err: "out of the range of unsigned_long"},
+		{value: `"-18446744073709551615"`, err: "negative values are not supported"},
+		{value: `"Inf"`, err: "value out of range"},
+		{value: `"many"`, err: "invalid number"},
+		{value: `true`, err: "does not match the expected field type"},
+	}
+
+	v := Validator{
+		Schema: []FieldDefinition{
+			{Name: "foo.bytes", Type: "unsigned_long"},
+		},
+		disabledDependencyManagement: true,
+		specVersion:                  *semver3_0_1,
+	}
+	for _, c := range cases {
+		t.Run(c.value, func(t *testing.T) {
+			errs := v.ValidateDocumentBody(json.RawMessage(`{"foo":{"bytes":` + c.value + `}}`))
+			if c.err == "" {
+				assert.Empty(t, errs)
+			} else if assert.Len(t, errs, 1) {
+				assert.ErrorContains(t, errs[0], c.err)
+			}
+		})
+	}
+}
+
+func TestValidate_SemanticText(t *testing.T) {
+	v := Validator{
+		Schema: []FieldDefinition{
+			{Name: "message", Type: "semantic_text"},
+			{Name: "summary", Type: "semantic_text"},
+		},
+		disabledDependencyManagement: true,
+		specVersion:                  *semver3_0_1,
+	}
+
+	t.Run("text", func(t *testing.T) {
+		errs := v.ValidateDocumentMap(common.MapStr{
+			"message": "user logged in",
+			"summary": []any{"first", "second"},
+		})
+		assert.Empty(t, errs)
+	})
+
+	t.Run("inference results", func(t *testing.T) {
+		errs := v.ValidateDocumentMap(common.MapStr{
+			"message": map[string]any{
+				"text": "user logged in",
+				"inference": map[string]any{
+					"inference_id": ".elser-2-elasticsearch",
+					"model_settings": map[string]any{
+						"task_type": "sparse_embedding",
+					},
+					"chunks": []any{
+						map[string]any{
+							"text":       "user logged in",
+							"embeddings": map[string]any{"user": 1.2, "login": 0.8},
+						},
+					},
+				},
+			},
+			"_inference_fields": map[string]any{
+				"summary": map[string]any{
+					"inference": map[string]any{"inference_id": ".elser-2-elasticsearch"},
+				},
+			},
+		})
+		assert.Empty(t, errs)
+	})
+
+	t.Run("flattened inference results", func(t *testing.T) {
+		errs := v.ValidateDocumentMap(common.MapStr{
+			"message":                          "user logged in",
+			"message.inference.inference_id":   ".elser-2-elasticsearch",
+			"message.inference.chunks.offsets": []any{0.0, 14.0},
+		})
+		assert.Empty(t, errs)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		errs := v.ValidateDocumentMap(common.MapStr{
+			"message": 42.0,
+			"summary": map[string]any{
+				"inference": map[string]any{"inference_id": ".elser-2-elasticsearch"},
+			},
+		})
+		require.Len(t, errs, 2)
+	})
+}
+
+func TestValidate_NestedRequiredSubfields(t *testing.T) {
+	schema := []FieldDefinition{
+		{
+			Name: "hosts",
+			Type: "nested",
+			Fields: []FieldDefinition{
+				{Name: "id", Type: "keyword"},
+				{Name: "hostname", Type: "keyword"},
+			},
+		},
+	}
+	v := Validator{
+		Schema:                       schema,
+		disabledDependencyManagement: true,
+		specVersion:                  *semver3_0_1,
+	}
+	require.NoError(t, WithRequiredNestedSubfields(map[string][]string{"hosts": {"id"}})(&v))
+
+	t.Run("single object", func(t *testing.T) {
+		errs := v.ValidateDocumentMap(common.MapStr{
+			"hosts": map[string]any{
+				"id":       "somehost-id",
+				"hostname": "somehost",
+			},
+		})
+		assert.Empty(t, errs)
+	})
+
+	t.Run("single object without required subfield", func(t *testing.T) {
+		errs := v.ValidateDocumentMap(common.MapStr{
+			"hosts": map[string]any{
+				"hostname": "somehost",
+			},
+		})
+		require.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], "hosts.id is required")
+	})
+
+	t.Run("array", func(t *testing.T) {
+		errs := v.ValidateDocumentMap(common.MapStr{
+			"hosts": []any{
+				map[string]any{"id": "somehost-id", "hostname": "somehost"},
+				map[string]any{"id": "otherhost-id"},
+			},
+		})
+		assert.Empty(t, errs)
+	})
+
+	t.Run("array without required subfield", func(t *testing.T) {
+		errs := v.ValidateDocumentMap(common.MapStr{
+			"hosts": []any{
+				map[string]any{"id": "somehost-id"},
+				map[string]any{"id": "otherhost-id"},
+				map[string]any{"hostname": "thirdhost"},
+			},
+		})
+		// Each missing subfield is reported once.
+		require.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], "hosts[2].id is required")
+		assert.Equal(t, 1, strings.Count(errs[0].Error(), "is required"))
+	})
+
+	t.Run("not required", func(t *testing.T) {
+		v := Validator{
+			Schema:                       schema,
+			disabledDependencyManagement: true,
+			specVersion:                  *semver3_0_1,
+		}
+		errs := v.ValidateDocumentMap(common.MapStr{
+			"hosts": map[string]any{
+				"hostname": "somehost",
+			},
+		})
+		assert.Empty(t, errs)
+	})
+}
+
+func TestValidate_FieldsAPIFormat(t *testing.T) {
+	schema := []FieldDefinition{
+		{Name: "@timestamp", Type: "date"},
+		{Name: "event.created", Type: "date", DateFormat: "epoch_second"},
+		{Name: "message", Type: "keyword", MultiFields: []FieldDefinition{{Name: "text", Type: "match_only_text"}}},
+		{Name: "tags", Type: "keyword", Normalize: []string{"array"}},
+		{Name: "http.response.bytes", Type: "long"},
+		{Name: "process", Type: "nested", Fields: []FieldDefinition{
+			{Name: "pid", Type: "long"},
+		}},
+	}
+	doc := func() common.MapStr {
+		return common.MapStr{
+			"@timestamp":          []any{"2024-03-01T10:00:00.123Z"},
+			"event.created":       []any{1709287200.0},
+			"message":             []any{"GET /index.html"},
+			"message.text":        []any{"GET /index.html"},
+			"tags":                []any{"web"},
+			"http.response.bytes": []any{1024.0},
+			"process":             []any{map[string]any{"pid": []any{42.0}}},
+		}
+	}
+
+	newValidator := func(options ...ValidatorOption) *Validator {
+		v := Validator{
+			Schema:                       schema,
+			disabledDependencyManagement: true,
+			specVersion:                  *semver3_0_1,
+		}
+		for _, option := range options {
+			require.NoError(t, option(&v))
+		}
+		return &v
+	}
+
+	t.Run("fields API format", func(t *testing.T) {
+		v := newValidator(WithFieldsAPIFormat())
+		assert.Empty(t, v.ValidateDocumentMap(doc()))
+	})
+
+	t.Run("source format", func(t *testing.T) {
+		// Numeric dates are valid in _source, they are only reported in the fields API format.
+		d := doc()
+		d["@timestamp"] = []any{1709287200000.0}
+		assert.Empty(t, newValidator().ValidateDocumentMap(d))
+		assert.NotEmpty(t, newValidator(WithFieldsAPIFormat()).ValidateDocumentMap(d))
+	})
+
+	t.Run("nested objects", func(t *testing.T) {
+		v := newValidator(WithFieldsAPIFormat())
+		d := doc()
+		d["process"] = []any{map[string]any{"pid": []any{"unknown"}}}
+		errs := v.ValidateDocumentMap(d)
+		if assert.Len(t, errs, 1) {
+			assert.ErrorContains(t, errs[0], `field "process.pid"`)
+		}
+	})
+
+	t.Run("dates not in canonical format", func(t *testing.T) {
+		v := newValidator(WithFieldsAPIFormat())
+		for _, value := range []any{"2024-03-01 10:00:00", 1709287200000.0} {
+			d := doc()
+			d["@timestamp"] = []any{value}
+			errs := v.ValidateDocumentMap(d)
+			if assert.Len(t, errs, 1, "value: %v", value) {
+				assert.ErrorContains(t, errs[0], "canonical format")
+			}
+		}
+	})
+
+	t.Run("multiple values", func(t *testing.T) {
+		v := newValidator(WithFieldsAPIFormat())
+		d := doc()
+		d["http.response.bytes"] = []any{1024.0, "many"}
+		errs := v.ValidateDocumentMap(d)
+		assert.Len(t, errs, 1)
+	})
+}
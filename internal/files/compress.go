@@ -5,55 +5,212 @@
 package files
 
 import (
-	"compress/flate"
+	"archive/zip"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/elastic/elastic-package/internal/logger"
-
-	"github.com/mholt/archiver/v3"
 )
 
+// sourceDateEpochEnv is the standard environment variable used to set the timestamp of
+// the files in reproducible builds (https://reproducible-builds.org/specs/source-date-epoch/).
+const sourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+
+// defaultModificationTime is the modification time set to all the files in the archive when
+// SOURCE_DATE_EPOCH is not set. It is the minimum date that can be represented in zip files.
+var defaultModificationTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// compressedFormats contains the extensions of files that are already compressed, and
+// are stored in the archive without compressing them again.
+var compressedFormats = map[string]struct{}{
+	".7z":   {},
+	".avi":  {},
+	".br":   {},
+	".bz2":  {},
+	".cab":  {},
+	".docx": {},
+	".gif":  {},
+	".gz":   {},
+	".jar":  {},
+	".jpeg": {},
+	".jpg":  {},
+	".lz":   {},
+	".lz4":  {},
+	".lzma": {},
+	".m4v":  {},
+	".mov":  {},
+	".mp3":  {},
+	".mp4":  {},
+	".ogg":  {},
+	".png":  {},
+	".pptx": {},
+	".rar":  {},
+	".sz":   {},
+	".tbz2": {},
+	".tgz":  {},
+	".tsz":  {},
+	".txz":  {},
+	".webm": {},
+	".webp": {},
+	".xlsx": {},
+	".xz":   {},
+	".zip":  {},
+	".zipx": {},
+	".zst":  {},
+}
+
 // Zip function creates the .zip archive from the source path (built package content).
+// Archives are reproducible: entries are sorted by path, and all of them have the same
+// modification time and normalized permissions, so archives created from the same
+// contents are identical. Symbolic links are replaced by the files they point to, and
+// empty directories are not included.
 func Zip(sourcePath, destinationFile string) error {
-	logger.Debugf("Compress using archiver.Zip (destination: %s)", destinationFile)
+	logger.Debugf("Compress package (destination: %s)", destinationFile)
 
-	z := archiver.Zip{
-		CompressionLevel:       flate.DefaultCompression,
-		MkdirAll:               false,
-		SelectiveCompression:   true,
-		ContinueOnError:        false,
-		OverwriteExisting:      true,
-		ImplicitTopLevelFolder: false,
+	modTime, err := archiveModificationTime()
+	if err != nil {
+		return err
 	}
 
-	// Create a temporary work directory to properly name the root directory in the archive, e.g. aws-1.0.1
-	tempDir, err := os.MkdirTemp("", "elastic-package-")
+	f, err := os.Create(destinationFile)
 	if err != nil {
-		return fmt.Errorf("can't prepare a temporary directory: %w", err)
+		return fmt.Errorf("can't create archive file: %w", err)
 	}
-	defer os.RemoveAll(tempDir)
-	workDir := filepath.Join(tempDir, folderNameFromFileName(destinationFile))
-	err = os.MkdirAll(workDir, 0755)
+	defer f.Close()
+
+	// Name the root directory in the archive after the file, e.g. aws-1.0.1
+	rootDir := folderNameFromFileName(destinationFile)
+	w := zip.NewWriter(f)
+
+	// Directories are only added when a file is found in them, so empty directories are skipped.
+	// Entries are walked in lexical order, so pending directories that are not parents of the
+	// visited entry are empty.
+	var pendingDirs []string
+	err = filepath.WalkDir(sourcePath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(sourcePath, p)
+		if err != nil {
+			return err
+		}
+		name := path.Join(rootDir, filepath.ToSlash(rel))
+		for len(pendingDirs) > 0 && !strings.HasPrefix(name, pendingDirs[len(pendingDirs)-1]+"/") {
+			pendingDirs = pendingDirs[:len(pendingDirs)-1]
+		}
+		if d.IsDir() {
+			pendingDirs = append(pendingDirs, name)
+			return nil
+		}
+
+		for _, dir := range pendingDirs {
+			err := addDirToZip(w, dir, modTime)
+			if err != nil {
+				return err
+			}
+		}
+		pendingDirs = nil
+		return addFileToZip(w, p, name, modTime)
+	})
 	if err != nil {
-		return fmt.Errorf("can't prepare work directory: %s: %w", workDir, err)
+		return fmt.Errorf("can't archive source directory (source path: %s): %w", sourcePath, err)
 	}
 
-	logger.Debugf("Create work directory for archiving: %s", workDir)
-	err = CopyAll(sourcePath, workDir)
+	err = w.Close()
 	if err != nil {
-		return fmt.Errorf("can't create a work directory (path: %s): %w", workDir, err)
+		return fmt.Errorf("can't finalize archive: %w", err)
 	}
+	return f.Close()
+}
 
-	err = z.Archive([]string{workDir}, destinationFile)
+func addDirToZip(w *zip.Writer, name string, modTime time.Time) error {
+	header := &zip.FileHeader{
+		Name:     name + "/",
+		Modified: modTime,
+		Method:   zip.Store,
+	}
+	header.SetMode(fs.ModeDir | 0755)
+	_, err := w.CreateHeader(header)
 	if err != nil {
-		return fmt.Errorf("can't archive source directory (source path: %s): %w", sourcePath, err)
+		return fmt.Errorf("can't create header for %s: %w", name, err)
+	}
+	return nil
+}
+
+// addFileToZip adds a file to the archive. Symbolic links are followed, and the file they point
+// to is added with the name of the link.
+func addFileToZip(w *zip.Writer, sourcePath, name string, modTime time.Time) error {
+	info, err := os.Stat(sourcePath)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("unsupported file type in %s: %s", sourcePath, info.Mode().Type())
+	}
+
+	header := &zip.FileHeader{
+		Name:     name,
+		Modified: modTime,
+		Method:   zip.Deflate,
+	}
+	if _, found := compressedFormats[strings.ToLower(path.Ext(name))]; found {
+		header.Method = zip.Store
+	}
+	header.SetMode(normalizedFileMode(info.Mode()))
+
+	writer, err := w.CreateHeader(header)
+	if err != nil {
+		return fmt.Errorf("can't create header for %s: %w", name, err)
+	}
+
+	file, err := os.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(writer, file)
+	if err != nil {
+		return fmt.Errorf("can't write %s to archive: %w", name, err)
 	}
 	return nil
 }
 
+// normalizedFileMode returns the permissions of files in the archive, that only depend on
+// the file being executable or not, and not on the umask where the package is built.
+func normalizedFileMode(mode fs.FileMode) fs.FileMode {
+	if mode&0111 != 0 {
+		return 0755
+	}
+	return 0644
+}
+
+// archiveModificationTime returns the modification time to set to the files in the archive.
+func archiveModificationTime() (time.Time, error) {
+	epoch, found := os.LookupEnv(sourceDateEpochEnv)
+	if !found || epoch == "" {
+		return defaultModificationTime, nil
+	}
+
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid value in %s, expected number of seconds since Unix epoch: %w", sourceDateEpochEnv, err)
+	}
+	modTime := time.Unix(seconds, 0).UTC()
+	if modTime.Before(defaultModificationTime) {
+		return defaultModificationTime, nil
+	}
+	return modTime, nil
+}
+
 // folderNameFromFileName returns the folder name from the destination file.
 // Based on mholt/archiver: https://github.com/mholt/archiver/blob/d35d4ce7c5b2411973fb7bd96ca1741eb011011b/archiver.go#L397
 func folderNameFromFileName(filename string) string {
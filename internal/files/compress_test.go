@@ -0,0 +1,149 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package files
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZipReproducible(t *testing.T) {
+	sourcePath := t.TempDir()
+	writeTestFile(t, filepath.Join(sourcePath, "manifest.yml"), "name: example\nversion: 1.0.0\n")
+	writeTestFile(t, filepath.Join(sourcePath, "docs", "README.md"), "# Example\n")
+	writeTestFile(t, filepath.Join(sourcePath, "img", "logo.png"), "not really a png")
+
+	first := filepath.Join(t.TempDir(), "example-1.0.0.zip")
+	err := Zip(sourcePath, first)
+	require.NoError(t, err)
+
+	// Touch the files, as happens when building again from a different checkout.
+	later := time.Now().Add(time.Hour)
+	for _, path := range []string{"manifest.yml", "docs/README.md", "img/logo.png"} {
+		require.NoError(t, os.Chtimes(filepath.Join(sourcePath, path), later, later))
+	}
+
+	second := filepath.Join(t.TempDir(), "example-1.0.0.zip")
+	err = Zip(sourcePath, second)
+	require.NoError(t, err)
+
+	assert.Equal(t, fileSHA256(t, first), fileSHA256(t, second))
+
+	r, err := zip.OpenReader(first)
+	require.NoError(t, err)
+	defer r.Close()
+
+	var names []string
+	for _, f := range r.File {
+		names = append(names, f.Name)
+		assert.True(t, defaultModificationTime.Equal(f.Modified), "unexpected modification time for %s: %s", f.Name, f.Modified)
+	}
+	expected := []string{
+		"example-1.0.0/",
+		"example-1.0.0/docs/",
+		"example-1.0.0/docs/README.md",
+		"example-1.0.0/img/",
+		"example-1.0.0/img/logo.png",
+		"example-1.0.0/manifest.yml",
+	}
+	assert.Equal(t, expected, names)
+}
+
+func TestArchiveModificationTime(t *testing.T) {
+	t.Setenv(sourceDateEpochEnv, "1700000000")
+	modTime, err := archiveModificationTime()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2023, time.November, 14, 22, 13, 20, 0, time.UTC), modTime)
+
+	t.Setenv(sourceDateEpochEnv, "")
+	modTime, err = archiveModificationTime()
+	require.NoError(t, err)
+	assert.Equal(t, defaultModificationTime, modTime)
+
+	t.Setenv(sourceDateEpochEnv, "yesterday")
+	_, err = archiveModificationTime()
+	assert.Error(t, err)
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func fileSHA256(t *testing.T, path string) [sha256.Size]byte {
+	t.Helper()
+	d, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return sha256.Sum256(d)
+}
+
+func TestZipSymlinks(t *testing.T) {
+	sourcePath := t.TempDir()
+	writeTestFile(t, filepath.Join(sourcePath, "docs", "README.md"), "# Example\n")
+	require.NoError(t, os.Symlink(filepath.Join("docs", "README.md"), filepath.Join(sourcePath, "README.md")))
+
+	destination := filepath.Join(t.TempDir(), "example-1.0.0.zip")
+	err := Zip(sourcePath, destination)
+	require.NoError(t, err)
+
+	r, err := zip.OpenReader(destination)
+	require.NoError(t, err)
+	defer r.Close()
+
+	var found bool
+	for _, f := range r.File {
+		if f.Name != "example-1.0.0/README.md" {
+			continue
+		}
+		found = true
+		assert.True(t, f.Mode().IsRegular(), "symbolic link should be stored as a regular file")
+
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		assert.Equal(t, "# Example\n", string(content))
+	}
+	assert.True(t, found, "file linked by symbolic link not found in archive")
+}
+
+func TestZipSkipsEmptyDirectories(t *testing.T) {
+	sourcePath := t.TempDir()
+	writeTestFile(t, filepath.Join(sourcePath, "manifest.yml"), "name: example\n")
+	writeTestFile(t, filepath.Join(sourcePath, "kibana", "dashboard", "example.json"), "{}")
+	require.NoError(t, os.MkdirAll(filepath.Join(sourcePath, "kibana", "search"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(sourcePath, "img", "empty"), 0755))
+
+	destination := filepath.Join(t.TempDir(), "example-1.0.0.zip")
+	err := Zip(sourcePath, destination)
+	require.NoError(t, err)
+
+	r, err := zip.OpenReader(destination)
+	require.NoError(t, err)
+	defer r.Close()
+
+	var names []string
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	expected := []string{
+		"example-1.0.0/",
+		"example-1.0.0/kibana/",
+		"example-1.0.0/kibana/dashboard/",
+		"example-1.0.0/kibana/dashboard/example.json",
+		"example-1.0.0/manifest.yml",
+	}
+	assert.Equal(t, expected, names)
+}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/elastic/elastic-package/internal/logger"
@@ -36,6 +37,47 @@ type Agent struct {
 		} `json:"elastic"`
 	} `json:"local_metadata"`
 	Status string `json:"status"`
+
+	LastCheckinStatus  string           `json:"last_checkin_status,omitempty"`
+	LastCheckinMessage string           `json:"last_checkin_message,omitempty"`
+	Components         []AgentComponent `json:"components,omitempty"`
+}
+
+// AgentComponent is a component running in an agent, with the status reported on its last check-in.
+type AgentComponent struct {
+	ID      string               `json:"id"`
+	Type    string               `json:"type"`
+	Status  string               `json:"status"`
+	Message string               `json:"message"`
+	Units   []AgentComponentUnit `json:"units,omitempty"`
+}
+
+// AgentComponentUnit is a unit of a component running in an agent, as an input or an output.
+type AgentComponentUnit struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// Errors returns the messages of the components and units of the agent that are failed or
+// degraded.
+func (a *Agent) Errors() []string {
+	var errs []string
+	unhealthy := func(status string) bool {
+		return strings.EqualFold(status, "failed") || strings.EqualFold(status, "degraded")
+	}
+	for _, component := range a.Components {
+		if unhealthy(component.Status) {
+			errs = append(errs, fmt.Sprintf("component %s (%s): %s", component.ID, component.Status, component.Message))
+		}
+		for _, unit := range component.Units {
+			if unhealthy(unit.Status) {
+				errs = append(errs, fmt.Sprintf("unit %s of component %s (%s): %s", unit.ID, component.ID, unit.Status, unit.Message))
+			}
+		}
+	}
+	return errs
 }
 
 // String method returns string representation of an agent.
@@ -114,7 +156,7 @@ func (c *Client) waitUntilPolicyAssigned(ctx context.Context, a Agent, p Policy)
 	defer ticker.Stop()
 
 	for {
-		agent, err := c.getAgent(ctx, a.ID)
+		agent, err := c.GetAgent(ctx, a.ID)
 		if err != nil {
 			return fmt.Errorf("can't get the agent: %w", err)
 		}
@@ -141,7 +183,8 @@ func (c *Client) waitUntilPolicyAssigned(ctx context.Context, a Agent, p Policy)
 	return nil
 }
 
-func (c *Client) getAgent(ctx context.Context, agentID string) (*Agent, error) {
+// GetAgent returns the agent with the given ID, including the status of its components.
+func (c *Client) GetAgent(ctx context.Context, agentID string) (*Agent, error) {
 	statusCode, respBody, err := c.get(ctx, fmt.Sprintf("%s/agents/%s", FleetAPI, agentID))
 	if err != nil {
 		return nil, fmt.Errorf("could not list agents: %w", err)
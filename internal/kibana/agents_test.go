@@ -0,0 +1,64 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package kibana
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAgentErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/fleet/agents/agent-1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"item":{
+			"id": "agent-1",
+			"status": "online",
+			"last_checkin_status": "degraded",
+			"last_checkin_message": "1 or more components/units in a failed state",
+			"components": [
+				{
+					"id": "filestream-default",
+					"type": "filestream",
+					"status": "FAILED",
+					"message": "Failed: pid '42' exited",
+					"units": [
+						{"id": "filestream-default-logs", "type": "input", "status": "FAILED", "message": "open /var/log/app.log: no such file"},
+						{"id": "filestream-default", "type": "output", "status": "HEALTHY", "message": "Healthy"}
+					]
+				},
+				{"id": "system/metrics-default", "type": "system/metrics", "status": "HEALTHY", "message": "Healthy"}
+			]
+		}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	version := func(c *Client) {
+		c.versionInfo = VersionInfo{Number: "8.15.0"}
+		c.semver = semver.MustParse(c.versionInfo.Number)
+	}
+	client, err := NewClient(version, Address(server.URL))
+	require.NoError(t, err)
+
+	agent, err := client.GetAgent(context.Background(), "agent-1")
+	require.NoError(t, err)
+	assert.Equal(t, "online", agent.Status)
+	assert.Equal(t, "degraded", agent.LastCheckinStatus)
+	assert.Equal(t, []string{
+		"component filestream-default (FAILED): Failed: pid '42' exited",
+		"unit filestream-default-logs of component filestream-default (FAILED): open /var/log/app.log: no such file",
+	}, agent.Errors())
+
+	_, err = client.GetAgent(context.Background(), "unknown")
+	assert.Error(t, err)
+}
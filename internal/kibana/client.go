@@ -13,6 +13,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 
@@ -29,6 +30,7 @@ type Client struct {
 	host     string
 	username string
 	password string
+	apiKey   string
 
 	certificateAuthority string
 	tlSkipVerify         bool
@@ -36,7 +38,12 @@ type Client struct {
 	versionInfo VersionInfo
 	semver      *semver.Version
 
+	// spaceID is the Kibana space where requests are sent, the default one if empty.
+	spaceID string
+
 	retryMax        int
+	retryWaitMin    time.Duration
+	retryWaitMax    time.Duration
 	http            *http.Client
 	httpClientSetup func(*http.Client) *http.Client
 }
@@ -115,6 +122,14 @@ func Password(password string) ClientOption {
 	}
 }
 
+// APIKey option sets the API key to be used by the client. When set, it takes
+// precedence over basic authentication.
+func APIKey(apiKey string) ClientOption {
+	return func(c *Client) {
+		c.apiKey = apiKey
+	}
+}
+
 // RetryMax configures the number of retries before failing.
 func RetryMax(retryMax int) ClientOption {
 	return func(c *Client) {
@@ -122,6 +137,15 @@ func RetryMax(retryMax int) ClientOption {
 	}
 }
 
+// RetryBackoff configures the minimum and maximum time to wait between retries. Waits grow
+// exponentially between both values.
+func RetryBackoff(waitMin, waitMax time.Duration) ClientOption {
+	return func(c *Client) {
+		c.retryWaitMin = waitMin
+		c.retryWaitMax = waitMax
+	}
+}
+
 // CertificateAuthority sets the certificate authority to be used by the client.
 func CertificateAuthority(certificateAuthority string) ClientOption {
 	return func(c *Client) {
@@ -167,7 +191,7 @@ func (c *Client) newRequest(ctx context.Context, method, resourcePath string, re
 		return nil, fmt.Errorf("could not create base URL from host: %v: %w", c.host, err)
 	}
 
-	rel, err := url.Parse(resourcePath)
+	rel, err := url.Parse(c.spacePath(resourcePath))
 	if err != nil {
 		return nil, fmt.Errorf("could not create relative URL from resource path: %v: %w", resourcePath, err)
 	}
@@ -182,7 +206,11 @@ func (c *Client) newRequest(ctx context.Context, method, resourcePath string, re
 		return nil, fmt.Errorf("could not create %v request to Kibana API resource: %s: %w", method, resourcePath, err)
 	}
 
-	req.SetBasicAuth(c.username, c.password)
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "ApiKey "+c.apiKey)
+	} else {
+		req.SetBasicAuth(c.username, c.password)
+	}
 	req.Header.Add("content-type", "application/json")
 	req.Header.Add("kbn-xsrf", install.DefaultStackVersion)
 
@@ -222,7 +250,9 @@ func (c *Client) newHttpClient() (*http.Client, error) {
 
 	if c.retryMax > 0 {
 		opts := retry.HTTPOptions{
-			RetryMax: c.retryMax,
+			RetryMax:     c.retryMax,
+			RetryWaitMin: c.retryWaitMin,
+			RetryWaitMax: c.retryWaitMax,
 		}
 		client = retry.WrapHTTPClient(client, opts)
 	}
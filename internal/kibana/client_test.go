@@ -15,6 +15,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 
@@ -59,6 +60,112 @@ func TestClientWithTLS(t *testing.T) {
 	})
 }
 
+func TestClientAuthentication(t *testing.T) {
+	var authorization string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		fmt.Fprintln(w, "Hi!")
+	}))
+	t.Cleanup(server.Close)
+
+	caCertFile := writeCACertFile(t, server.Certificate())
+
+	version := func(c *Client) {
+		c.versionInfo = VersionInfo{Number: "8.0.0"}
+		c.semver = semver.MustParse(c.versionInfo.Number)
+	}
+
+	cases := []struct {
+		title    string
+		options  []ClientOption
+		expected string
+	}{
+		{
+			title:    "basic auth",
+			options:  []ClientOption{Username("elastic"), Password("changeme")},
+			expected: "Basic ZWxhc3RpYzpjaGFuZ2VtZQ==",
+		},
+		{
+			title:    "api key",
+			options:  []ClientOption{APIKey("dGVzdDprZXk=")},
+			expected: "ApiKey dGVzdDprZXk=",
+		},
+		{
+			title:    "api key takes precedence over basic auth",
+			options:  []ClientOption{Username("elastic"), Password("changeme"), APIKey("dGVzdDprZXk=")},
+			expected: "ApiKey dGVzdDprZXk=",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			authorization = ""
+			options := append([]ClientOption{version, Address(server.URL), CertificateAuthority(caCertFile)}, c.options...)
+			client, err := NewClient(options...)
+			require.NoError(t, err)
+
+			_, _, err = client.get(context.Background(), "/")
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, authorization)
+		})
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	version := func(c *Client) {
+		c.versionInfo = VersionInfo{Number: "8.0.0"}
+		c.semver = semver.MustParse(c.versionInfo.Number)
+	}
+	fastRetries := RetryBackoff(time.Millisecond, 5*time.Millisecond)
+
+	// newServer returns a server that responds with the given status code the given number of
+	// times, before responding successfully to package installations.
+	newServer := func(t *testing.T, failures int, statusCode int) (*httptest.Server, *int) {
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests <= failures {
+				http.Error(w, http.StatusText(statusCode), statusCode)
+				return
+			}
+			fmt.Fprintln(w, `{"items":[{"id":"test","type":"dashboard"}]}`)
+		}))
+		t.Cleanup(server.Close)
+		return server, &requests
+	}
+
+	t.Run("retries on unavailable Kibana", func(t *testing.T) {
+		server, requests := newServer(t, 3, http.StatusServiceUnavailable)
+		client, err := NewClient(version, Address(server.URL), RetryMax(5), fastRetries)
+		require.NoError(t, err)
+
+		assets, err := client.InstallPackage(context.Background(), "test", "1.0.0")
+		require.NoError(t, err)
+		assert.Len(t, assets, 1)
+		assert.Equal(t, 4, *requests)
+	})
+
+	t.Run("not enough retries", func(t *testing.T) {
+		server, requests := newServer(t, 3, http.StatusServiceUnavailable)
+		client, err := NewClient(version, Address(server.URL), RetryMax(2), fastRetries)
+		require.NoError(t, err)
+
+		_, err = client.InstallPackage(context.Background(), "test", "1.0.0")
+		assert.ErrorContains(t, err, "API status code = 503")
+		assert.Equal(t, 3, *requests)
+	})
+
+	t.Run("no retries on client errors", func(t *testing.T) {
+		server, requests := newServer(t, 3, http.StatusBadRequest)
+		client, err := NewClient(version, Address(server.URL), RetryMax(5), fastRetries)
+		require.NoError(t, err)
+
+		_, err = client.InstallPackage(context.Background(), "test", "1.0.0")
+		assert.ErrorContains(t, err, "API status code = 400")
+		assert.Equal(t, 1, *requests)
+	})
+}
+
 func writeCACertFile(t *testing.T, cert *x509.Certificate) string {
 	var d bytes.Buffer
 	err := pem.Encode(&d, &pem.Block{
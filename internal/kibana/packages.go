@@ -63,6 +63,33 @@ func (c *Client) RemovePackage(ctx context.Context, name, version string) ([]pac
 	return processResults("remove", statusCode, respBody)
 }
 
+// InstallPackageKibanaAssets installs the Kibana assets of an installed package in the space of
+// the client, so they are available in spaces other than the one where the package was installed.
+func (c *Client) InstallPackageKibanaAssets(ctx context.Context, name, version string) error {
+	path := c.epmPackageUrl(name, version) + "/kibana_assets"
+	statusCode, respBody, err := c.post(ctx, path, []byte(`{}`))
+	if err != nil {
+		return fmt.Errorf("could not install Kibana assets of package: %w", err)
+	}
+	if statusCode != http.StatusOK {
+		return fmt.Errorf("could not install Kibana assets of package; API status code = %d; response body = %s", statusCode, respBody)
+	}
+	return nil
+}
+
+// RemovePackageKibanaAssets removes the Kibana assets of a package from the space of the client.
+func (c *Client) RemovePackageKibanaAssets(ctx context.Context, name, version string) error {
+	path := c.epmPackageUrl(name, version) + "/kibana_assets"
+	statusCode, respBody, err := c.delete(ctx, path)
+	if err != nil {
+		return fmt.Errorf("could not remove Kibana assets of package: %w", err)
+	}
+	if statusCode != http.StatusOK && statusCode != http.StatusNotFound {
+		return fmt.Errorf("could not remove Kibana assets of package; API status code = %d; response body = %s", statusCode, respBody)
+	}
+	return nil
+}
+
 // FleetPackage contains information about a package in Fleet.
 type FleetPackage struct {
 	Name        string `json:"name"`
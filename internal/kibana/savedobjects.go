@@ -256,3 +256,61 @@ func (c *Client) ImportSavedObjects(ctx context.Context, importRequest ImportSav
 	}
 	return &results, nil
 }
+
+// SavedObjectReference is a reference from a saved object to another one.
+type SavedObjectReference struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+// SavedObject is a saved object as returned by the Saved Objects API.
+type SavedObject struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Attributes struct {
+		Title string `json:"title"`
+	} `json:"attributes"`
+	References []SavedObjectReference `json:"references"`
+
+	// Error is set when the object couldn't be retrieved, for example because it doesn't exist.
+	Error *SavedObjectError `json:"error,omitempty"`
+}
+
+// SavedObjectError is the error returned for a saved object that couldn't be retrieved.
+type SavedObjectError struct {
+	StatusCode int    `json:"statusCode"`
+	Error      string `json:"error"`
+	Message    string `json:"message"`
+}
+
+// BulkGetSavedObjects method retrieves the given saved objects. Objects that cannot be
+// retrieved are also included in the result, with their error set.
+func (c *Client) BulkGetSavedObjects(ctx context.Context, objects []ExportSavedObjectsRequestObject) ([]SavedObject, error) {
+	if len(objects) == 0 {
+		return nil, nil
+	}
+
+	body, err := json.Marshal(objects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	path := SavedObjectsAPI + "/_bulk_get"
+	statusCode, respBody, err := c.post(ctx, path, body)
+	if err != nil {
+		return nil, fmt.Errorf("could not get saved objects; API status code = %d; response body = %s: %w", statusCode, string(respBody), err)
+	}
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get saved objects; API status code = %d; response body = %s", statusCode, string(respBody))
+	}
+
+	var results struct {
+		SavedObjects []SavedObject `json:"saved_objects"`
+	}
+	err = json.Unmarshal(respBody, &results)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode response; response body: %s: %w", respBody, err)
+	}
+	return results.SavedObjects, nil
+}
@@ -0,0 +1,106 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package kibana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Space is a Kibana space.
+type Space struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// WithSpace returns a copy of the client that sends all its requests in the context of the
+// given Kibana space. The default space is used if the space ID is empty.
+func (c *Client) WithSpace(spaceID string) *Client {
+	client := *c
+	client.spaceID = spaceID
+	return &client
+}
+
+// SpaceID returns the ID of the Kibana space used by the client, empty for the default space.
+func (c *Client) SpaceID() string {
+	return c.spaceID
+}
+
+// GetSpace obtains the Kibana space with the given ID. It returns nil if the space doesn't exist.
+func (c *Client) GetSpace(ctx context.Context, id string) (*Space, error) {
+	statusCode, respBody, err := c.get(ctx, fmt.Sprintf("%s/space/%s", SpacesAPI, id))
+	if err != nil {
+		return nil, fmt.Errorf("could not get space: %w", err)
+	}
+	if statusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get space; API status code = %d; response body = %s", statusCode, respBody)
+	}
+
+	var space Space
+	err = json.Unmarshal(respBody, &space)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode space: %w", err)
+	}
+	return &space, nil
+}
+
+// CreateSpace creates a Kibana space.
+func (c *Client) CreateSpace(ctx context.Context, space Space) error {
+	reqBody, err := json.Marshal(space)
+	if err != nil {
+		return fmt.Errorf("could not convert space (request) to JSON: %w", err)
+	}
+
+	statusCode, respBody, err := c.post(ctx, fmt.Sprintf("%s/space", SpacesAPI), reqBody)
+	if err != nil {
+		return fmt.Errorf("could not create space: %w", err)
+	}
+	if statusCode != http.StatusOK {
+		return fmt.Errorf("could not create space; API status code = %d; response body = %s", statusCode, respBody)
+	}
+	return nil
+}
+
+// DeleteSpace deletes the Kibana space with the given ID, including all its saved objects.
+func (c *Client) DeleteSpace(ctx context.Context, id string) error {
+	statusCode, respBody, err := c.delete(ctx, fmt.Sprintf("%s/space/%s", SpacesAPI, id))
+	if err != nil {
+		return fmt.Errorf("could not delete space: %w", err)
+	}
+	if statusCode != http.StatusNoContent && statusCode != http.StatusOK && statusCode != http.StatusNotFound {
+		return fmt.Errorf("could not delete space; API status code = %d; response body = %s", statusCode, respBody)
+	}
+	return nil
+}
+
+// EnsureSpace creates the Kibana space with the given ID if it doesn't exist. It returns true
+// if the space has been created.
+func (c *Client) EnsureSpace(ctx context.Context, id string) (bool, error) {
+	space, err := c.GetSpace(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if space != nil {
+		return false, nil
+	}
+	err = c.CreateSpace(ctx, Space{ID: id, Name: id})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// spacePath returns the path of the resource in the context of the space of the client.
+func (c *Client) spacePath(resourcePath string) string {
+	if c.spaceID == "" {
+		return resourcePath
+	}
+	return fmt.Sprintf("/s/%s%s", c.spaceID, resourcePath)
+}
@@ -0,0 +1,76 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package kibana
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpaces(t *testing.T) {
+	var requests []string
+	spaces := map[string]bool{"existing": true}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/spaces/space/existing":
+			w.Write([]byte(`{"id":"existing","name":"Existing"}`))
+		case r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/spaces/space":
+			spaces["created"] = true
+			w.Write([]byte(`{"id":"created","name":"created"}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	version := func(c *Client) {
+		c.versionInfo = VersionInfo{Number: "8.15.0"}
+		c.semver = semver.MustParse(c.versionInfo.Number)
+	}
+	client, err := NewClient(version, Address(server.URL))
+	require.NoError(t, err)
+
+	t.Run("existing space", func(t *testing.T) {
+		requests = nil
+		created, err := client.EnsureSpace(context.Background(), "existing")
+		require.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, []string{"GET /api/spaces/space/existing"}, requests)
+	})
+
+	t.Run("missing space", func(t *testing.T) {
+		requests = nil
+		created, err := client.EnsureSpace(context.Background(), "created")
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.True(t, spaces["created"])
+		assert.Equal(t, []string{"GET /api/spaces/space/created", "POST /api/spaces/space"}, requests)
+	})
+
+	t.Run("requests in space", func(t *testing.T) {
+		requests = nil
+		spaceClient := client.WithSpace("created")
+		assert.Equal(t, "created", spaceClient.SpaceID())
+		assert.Empty(t, client.SpaceID())
+
+		err := spaceClient.InstallPackageKibanaAssets(context.Background(), "nginx", "1.0.0")
+		require.NoError(t, err)
+		_, _, err = client.get(context.Background(), "/api/fleet/agent_policies")
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"POST /s/created/api/fleet/epm/packages/nginx/1.0.0/kibana_assets",
+			"GET /api/fleet/agent_policies",
+		}, requests)
+	})
+}
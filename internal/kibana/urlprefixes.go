@@ -14,6 +14,9 @@ const (
 	// StatusAPI is the prefix for Kibana Status API resource.
 	StatusAPI = "/api/status"
 
+	// SpacesAPI is the prefix for all Kibana Spaces API resources.
+	SpacesAPI = "/api/spaces"
+
 	// FleetAPI is the prefix for all Kibana Fleet API resources.
 	FleetAPI = "/api/fleet"
 )
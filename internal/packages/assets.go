@@ -35,6 +35,11 @@ func newAssetTypeWithFolder(typeName AssetType, folderName string) assetTypeFold
 	}
 }
 
+// TypeName returns the type of the assets of this kind.
+func (t assetTypeFolder) TypeName() AssetType {
+	return t.typeName
+}
+
 // Supported asset types.
 var (
 	AssetTypeElasticsearchIndexTemplate  = newAssetType("index_template")
@@ -21,7 +21,8 @@ type BuildManifest struct {
 
 // Dependencies define external package dependencies.
 type Dependencies struct {
-	ECS ECSDependency `config:"ecs"`
+	ECS      ECSDependency       `config:"ecs"`
+	Packages []PackageDependency `config:"packages"`
 }
 
 // ECSDependency defines a dependency on ECS fields.
@@ -30,9 +31,16 @@ type ECSDependency struct {
 	ImportMappings bool   `config:"import_mappings"`
 }
 
+// PackageDependency defines a dependency on the fields of another package, available
+// in the package registry.
+type PackageDependency struct {
+	Name    string `config:"name"`
+	Version string `config:"version"`
+}
+
 // HasDependencies function checks if there are any dependencies defined.
 func (bm *BuildManifest) HasDependencies() bool {
-	return bm.Dependencies.ECS.Reference != ""
+	return bm.Dependencies.ECS.Reference != "" || len(bm.Dependencies.Packages) > 0
 }
 
 // ImportMappings function checks if there are any dependencies defined.
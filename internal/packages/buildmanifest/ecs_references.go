@@ -0,0 +1,98 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package buildmanifest
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+)
+
+// PackageECSReference is the ECS reference used by a package.
+type PackageECSReference struct {
+	PackageRoot string
+	Reference   string
+}
+
+// ignoredDirectories are directories that are not looked into when searching for packages.
+var ignoredDirectories = []string{".git", "build", "node_modules"}
+
+// FindECSReferences looks for all the packages under the given directory with ECS dependencies,
+// and returns the ECS references they use, sorted by package path.
+func FindECSReferences(rootDir string) ([]PackageECSReference, error) {
+	var references []PackageECSReference
+	err := filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		for _, ignored := range ignoredDirectories {
+			if d.Name() == ignored {
+				return filepath.SkipDir
+			}
+		}
+		if d.Name() != "_dev" {
+			return nil
+		}
+
+		packageRoot := filepath.Dir(path)
+		manifest, found, err := ReadBuildManifest(packageRoot)
+		if err != nil {
+			return fmt.Errorf("can't read build manifest of package in %s: %w", packageRoot, err)
+		}
+		if found && manifest.Dependencies.ECS.Reference != "" {
+			references = append(references, PackageECSReference{
+				PackageRoot: packageRoot,
+				Reference:   manifest.Dependencies.ECS.Reference,
+			})
+		}
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, fmt.Errorf("looking for packages failed (path: %s): %w", rootDir, err)
+	}
+
+	sort.Slice(references, func(i, j int) bool {
+		return references[i].PackageRoot < references[j].PackageRoot
+	})
+	return references, nil
+}
+
+// InconsistentECSReferences returns the references that are different to the expected one. If
+// no reference is expected, the one used by most packages is expected. The expected reference
+// is also returned.
+func InconsistentECSReferences(references []PackageECSReference, expected string) (string, []PackageECSReference) {
+	if expected == "" {
+		expected = mostUsedECSReference(references)
+	}
+
+	var inconsistent []PackageECSReference
+	for _, reference := range references {
+		if reference.Reference != expected {
+			inconsistent = append(inconsistent, reference)
+		}
+	}
+	return expected, inconsistent
+}
+
+// mostUsedECSReference returns the reference used by most packages. On ties, the greatest
+// one in lexicographical order is returned, to get the same result on every execution.
+func mostUsedECSReference(references []PackageECSReference) string {
+	counts := make(map[string]int)
+	for _, reference := range references {
+		counts[reference.Reference]++
+	}
+
+	var mostUsed string
+	for reference, count := range counts {
+		if count > counts[mostUsed] || (count == counts[mostUsed] && reference > mostUsed) {
+			mostUsed = reference
+		}
+	}
+	return mostUsed
+}
@@ -0,0 +1,42 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package buildmanifest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindECSReferences(t *testing.T) {
+	rootDir := filepath.Join("testdata", "repository")
+	references, err := FindECSReferences(rootDir)
+	require.NoError(t, err)
+
+	packageRoot := func(name string) string {
+		return filepath.Join(rootDir, "packages", name)
+	}
+	expected := []PackageECSReference{
+		{PackageRoot: packageRoot("a"), Reference: "git@v8.11.0"},
+		{PackageRoot: packageRoot("b"), Reference: "git@v8.11.0"},
+		{PackageRoot: packageRoot("c"), Reference: "git@v8.6.0"},
+		{PackageRoot: packageRoot("d"), Reference: "git@v8.11.0"},
+	}
+	assert.Equal(t, expected, references)
+
+	t.Run("most used", func(t *testing.T) {
+		reference, inconsistent := InconsistentECSReferences(references, "")
+		assert.Equal(t, "git@v8.11.0", reference)
+		assert.Equal(t, []PackageECSReference{{PackageRoot: packageRoot("c"), Reference: "git@v8.6.0"}}, inconsistent)
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		reference, inconsistent := InconsistentECSReferences(references, "git@v8.6.0")
+		assert.Equal(t, "git@v8.6.0", reference)
+		assert.Len(t, inconsistent, 3)
+	})
+}
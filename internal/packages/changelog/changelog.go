@@ -49,3 +49,18 @@ func ReadChangelog(path string) ([]Revision, error) {
 	}
 	return c, nil
 }
+
+// ParseChangelog parses the given contents of a package changelog file.
+func ParseChangelog(d []byte) ([]Revision, error) {
+	cfg, err := yaml.NewConfig(d, ucfg.PathSep("."))
+	if err != nil {
+		return nil, fmt.Errorf("parsing changelog failed: %w", err)
+	}
+
+	var c []Revision
+	err = cfg.Unpack(&c)
+	if err != nil {
+		return nil, fmt.Errorf("unpacking package changelog failed: %w", err)
+	}
+	return c, nil
+}
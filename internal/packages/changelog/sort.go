@@ -0,0 +1,112 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnsortedRevisions returns the pairs of consecutive versions in the changelog revisions that are
+// not sorted from newest to oldest, formatted as "<version> before <newer version>".
+func UnsortedRevisions(revisions []Revision) ([]string, error) {
+	var unsorted []string
+	for i := 1; i < len(revisions); i++ {
+		previous, err := semver.NewVersion(revisions[i-1].Version)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q: %w", revisions[i-1].Version, err)
+		}
+		current, err := semver.NewVersion(revisions[i].Version)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q: %w", revisions[i].Version, err)
+		}
+		if previous.LessThan(current) {
+			unsorted = append(unsorted, fmt.Sprintf("%s before %s", previous, current))
+		}
+	}
+	return unsorted, nil
+}
+
+// SortChangelogFromPackageRoot sorts the revisions of the changelog of the given package, from
+// newest to oldest version. It returns true if the file was rewritten.
+func SortChangelogFromPackageRoot(packageRoot string) (bool, error) {
+	path := filepath.Join(packageRoot, PackageChangelogFile)
+	d, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("reading changelog failed: %w", err)
+	}
+	sorted, changed, err := SortYAML(d)
+	if err != nil {
+		return false, fmt.Errorf("sorting changelog failed (path: %s): %w", path, err)
+	}
+	if !changed {
+		return false, nil
+	}
+	err = os.WriteFile(path, sorted, 0644)
+	if err != nil {
+		return false, fmt.Errorf("writing changelog failed (path: %s): %w", path, err)
+	}
+	return true, nil
+}
+
+// SortYAML sorts the revisions of the given changelog from newest to oldest version, conserving
+// the entries and comments of each revision. Content is returned as is if it is already sorted.
+func SortYAML(d []byte) ([]byte, bool, error) {
+	var nodes []yaml.Node
+	err := yaml.Unmarshal(d, &nodes)
+	if err != nil {
+		return nil, false, err
+	}
+
+	versions := make(map[*yaml.Node]*semver.Version, len(nodes))
+	for i := range nodes {
+		var revision Revision
+		err := nodes[i].Decode(&revision)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to decode revision: %w", err)
+		}
+		version, err := semver.NewVersion(revision.Version)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid version %q: %w", revision.Version, err)
+		}
+		versions[&nodes[i]] = version
+	}
+
+	sorted := make([]*yaml.Node, len(nodes))
+	for i := range nodes {
+		sorted[i] = &nodes[i]
+	}
+	slices.SortStableFunc(sorted, func(a, b *yaml.Node) int {
+		return versions[b].Compare(versions[a])
+	})
+	if slices.IsSortedFunc(sorted, func(a, b *yaml.Node) int {
+		return int(a.Line - b.Line)
+	}) {
+		return d, false, nil
+	}
+
+	// Comments on top of the file stay on top.
+	if sorted[0] != &nodes[0] && nodes[0].HeadComment != "" {
+		sorted[0].HeadComment = strings.TrimSpace(nodes[0].HeadComment + "\n" + sorted[0].HeadComment)
+		nodes[0].HeadComment = ""
+	}
+
+	result := make([]yaml.Node, len(sorted))
+	for i, node := range sorted {
+		result[i] = *node
+	}
+	d, err = formatResult(result)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to format changelog: %w", err)
+	}
+	return d, true, nil
+}
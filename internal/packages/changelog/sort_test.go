@@ -0,0 +1,45 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package changelog
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortYAML(t *testing.T) {
+	d, err := os.ReadFile("testdata/changelog-unsorted.yml")
+	require.NoError(t, err)
+	expected, err := os.ReadFile("testdata/changelog-unsorted-sorted.yml")
+	require.NoError(t, err)
+
+	sorted, changed, err := SortYAML(d)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, string(expected), string(sorted))
+
+	// Sorting again doesn't change anything.
+	again, changed, err := SortYAML(sorted)
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, string(sorted), string(again))
+}
+
+func TestUnsortedRevisions(t *testing.T) {
+	revisions, err := ReadChangelog("testdata/changelog-unsorted.yml")
+	require.NoError(t, err)
+	unsorted, err := UnsortedRevisions(revisions)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.0.0 before 1.1.0-next", "1.1.0-next before 1.1.0"}, unsorted)
+
+	revisions, err = ReadChangelog("testdata/changelog-unsorted-sorted.yml")
+	require.NoError(t, err)
+	unsorted, err = UnsortedRevisions(revisions)
+	require.NoError(t, err)
+	assert.Empty(t, unsorted)
+}
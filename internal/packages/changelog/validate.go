@@ -0,0 +1,88 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/multierror"
+)
+
+// DefaultLinkHost is the host expected in the links of changelog entries.
+const DefaultLinkHost = "github.com"
+
+// EntryTypes are the allowed types of changelog entries.
+var EntryTypes = []string{"bugfix", "enhancement", "breaking-change"}
+
+// ValidateChangelogFromPackageRoot validates the entries of the changelog file of the given package.
+func ValidateChangelogFromPackageRoot(packageRoot string) error {
+	return ValidateChangelog(filepath.Join(packageRoot, PackageChangelogFile), DefaultLinkHost)
+}
+
+// ValidateChangelog validates the entries of the given changelog file. Each entry must have one of
+// the allowed types, a description, and a link to the given host. An error is reported for each
+// invalid entry, with its index in the list of changes of its version.
+func ValidateChangelog(path string, linkHost string) error {
+	revisions, err := ReadChangelog(path)
+	if err != nil {
+		return err
+	}
+	return ValidateRevisions(revisions, linkHost)
+}
+
+// ValidateRevisions validates the entries of the given changelog revisions, as ValidateChangelog does.
+func ValidateRevisions(revisions []Revision, linkHost string) error {
+	var errs multierror.Error
+	for _, revision := range revisions {
+		for i, entry := range revision.Changes {
+			for _, err := range validateEntry(entry, linkHost) {
+				errs = append(errs, fmt.Errorf("version %s, entry %d: %w", revision.Version, i, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateEntry(entry Entry, linkHost string) []error {
+	var errs []error
+	if !slices.Contains(EntryTypes, entry.Type) {
+		errs = append(errs, fmt.Errorf("invalid type %q, expected one of: %s", entry.Type, strings.Join(EntryTypes, ", ")))
+	}
+	if strings.TrimSpace(entry.Description) == "" {
+		errs = append(errs, errors.New("missing description"))
+	}
+	if err := validateLink(entry.Link, linkHost); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+func validateLink(link string, host string) error {
+	if strings.TrimSpace(link) == "" {
+		return errors.New("missing link")
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return fmt.Errorf("invalid link %q: %w", link, err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return fmt.Errorf("invalid link %q: expected an http or https URL", link)
+	}
+	if !strings.EqualFold(u.Hostname(), host) {
+		return fmt.Errorf("invalid link %q: expected a link to %s", link, host)
+	}
+	if strings.Trim(u.Path, "/") == "" {
+		return fmt.Errorf("invalid link %q: expected a link to a pull request or issue", link)
+	}
+	return nil
+}
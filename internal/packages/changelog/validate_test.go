@@ -0,0 +1,85 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package changelog
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/multierror"
+)
+
+func TestValidateChangelog(t *testing.T) {
+	err := ValidateChangelog("testdata/changelog-one.yml", DefaultLinkHost)
+	assert.NoError(t, err)
+
+	err = ValidateChangelog("testdata/changelog-invalid.yml", DefaultLinkHost)
+	require.Error(t, err)
+
+	var errs multierror.Error
+	require.ErrorAs(t, err, &errs)
+
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	assert.Equal(t, []string{
+		`version 1.1.0, entry 0: invalid type "feature", expected one of: bugfix, enhancement, breaking-change`,
+		`version 1.1.0, entry 1: missing link`,
+		`version 1.1.0, entry 2: missing description`,
+		`version 1.1.0, entry 2: invalid link "github.com/elastic/integrations/pull/3": expected an http or https URL`,
+		`version 1.0.0, entry 0: invalid link "https://example.com/elastic/integrations/pull/1": expected a link to github.com`,
+	}, messages)
+}
+
+func TestValidateLink(t *testing.T) {
+	cases := []struct {
+		link  string
+		valid bool
+	}{
+		{link: "https://github.com/elastic/integrations/pull/1234", valid: true},
+		{link: "http://github.com/elastic/elastic-package", valid: true},
+		{link: "https://GitHub.com/elastic/integrations/issues/1", valid: true},
+		{link: "", valid: false},
+		{link: "https://github.com", valid: false},
+		{link: "https://github.com/", valid: false},
+		{link: "ftp://github.com/elastic/integrations", valid: false},
+		{link: "https://gitlab.com/elastic/integrations/pull/1", valid: false},
+		{link: "https://github.com/elastic/integrations/pull/%zz", valid: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.link, func(t *testing.T) {
+			err := validateLink(c.link, DefaultLinkHost)
+			if c.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestValidatePatchedChangelog(t *testing.T) {
+	d, err := os.ReadFile("testdata/changelog-one.yml")
+	require.NoError(t, err)
+
+	d, err = PatchYAML(d, Revision{
+		Version: "1.0.1",
+		Changes: []Entry{{Description: "Bump version to 1.0.1.", Type: "bugfix"}},
+	})
+	require.NoError(t, err)
+
+	revisions, err := ParseChangelog(d)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, "1.0.1", revisions[0].Version)
+
+	err = ValidateRevisions(revisions, DefaultLinkHost)
+	assert.EqualError(t, err, "[0] version 1.0.1, entry 0: missing link")
+}
@@ -16,7 +16,8 @@ import (
 )
 
 // PatchYAML looks for the proper place to add the new revision in the changelog,
-// trying to conserve original format and comments.
+// trying to conserve original format and comments. Changes are added to the revision
+// with the same version, or to a new revision placed before the first older version.
 func PatchYAML(d []byte, patch Revision) ([]byte, error) {
 	var nodes []yaml.Node
 	err := yaml.Unmarshal(d, &nodes)
@@ -50,7 +51,9 @@ func PatchYAML(d []byte, patch Revision) ([]byte, error) {
 		}
 
 		if foundVersion.GreaterThan(patchVersion) {
-			return nil, errors.New("cannot add change to old version")
+			// Newer versions go on top, keep looking.
+			result = append(result, node)
+			continue
 		}
 
 		var newNode yaml.Node
@@ -89,7 +92,14 @@ func PatchYAML(d []byte, patch Revision) ([]byte, error) {
 	}
 
 	if !patched {
-		return nil, errors.New("changelog entry was not added, this is probably a bug")
+		// All versions are newer, add the change at the end.
+		var newNode yaml.Node
+		err = newNode.Encode(patch)
+		if err != nil {
+			return nil, err
+		}
+		setYamlMapValueStyle(&newNode, "version", yaml.DoubleQuotedStyle)
+		result = append(result, newNode)
 	}
 
 	d, err = formatResult(result)
@@ -70,6 +70,51 @@ func TestPatchYAML(t *testing.T) {
 				},
 			},
 		},
+		{
+			title:    "Change in old version",
+			original: "testdata/changelog-two.yml",
+			expected: "testdata/changelog-two-patch-old-version.yml",
+			patch: Revision{
+				Version: "1.0.0",
+				Changes: []Entry{
+					{
+						Description: "One change",
+						Type:        "bugfix",
+						Link:        "http://github.com/elastic/elastic-package",
+					},
+				},
+			},
+		},
+		{
+			title:    "Change in missing version",
+			original: "testdata/changelog-two.yml",
+			expected: "testdata/changelog-two-patch-missing-version.yml",
+			patch: Revision{
+				Version: "1.0.1",
+				Changes: []Entry{
+					{
+						Description: "One change",
+						Type:        "bugfix",
+						Link:        "http://github.com/elastic/elastic-package",
+					},
+				},
+			},
+		},
+		{
+			title:    "Change in version older than all",
+			original: "testdata/changelog-two.yml",
+			expected: "testdata/changelog-two-patch-oldest-version.yml",
+			patch: Revision{
+				Version: "0.9.0",
+				Changes: []Entry{
+					{
+						Description: "One change",
+						Type:        "bugfix",
+						Link:        "http://github.com/elastic/elastic-package",
+					},
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -68,6 +68,7 @@ type Variable struct {
 	Name    string   `config:"name" json:"name" yaml:"name"`
 	Type    string   `config:"type" json:"type" yaml:"type"`
 	Default VarValue `config:"default" json:"default" yaml:"default"`
+	Secret  bool     `config:"secret" json:"secret,omitempty" yaml:"secret,omitempty"`
 }
 
 // Input is a single input configuration.
@@ -239,7 +240,12 @@ func FindPackageRoot() (string, bool, error) {
 	if err != nil {
 		return "", false, fmt.Errorf("locating working directory failed: %w", err)
 	}
+	return FindPackageRootFrom(workDir)
+}
 
+// FindPackageRootFrom finds and returns the path to the root folder of the package containing
+// the given directory.
+func FindPackageRootFrom(workDir string) (string, bool, error) {
 	// VolumeName() will return something like "C:" in Windows, and "" in other OSs
 	// rootDir will be something like "C:\" in Windows, and "/" everywhere else.
 	rootDir := filepath.VolumeName(workDir) + string(filepath.Separator)
@@ -0,0 +1,107 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package packages
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/elastic/elastic-package/internal/multierror"
+)
+
+// ReadTransformDefinition reads the definition of a transform as it is sent to Elasticsearch.
+func ReadTransformDefinition(path string) (map[string]any, error) {
+	d, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading file failed (path: %s): %w", path, err)
+	}
+	var definition map[string]any
+	err = yaml.Unmarshal(d, &definition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse transform file %q: %w", path, err)
+	}
+	if definition == nil {
+		return nil, fmt.Errorf("empty transform file %q", path)
+	}
+	return definition, nil
+}
+
+// ValidateTransform checks that the definition of the transform has the settings required by
+// Elasticsearch to create it: the source indexes, the destination index, and either a pivot or
+// a latest configuration.
+func ValidateTransform(transform Transform) error {
+	definition, err := ReadTransformDefinition(transform.Path)
+	if err != nil {
+		return err
+	}
+	errs := validateTransformDefinition(definition)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateTransformDefinition(definition map[string]any) multierror.Error {
+	var errs multierror.Error
+
+	source, _ := definition["source"].(map[string]any)
+	if !isNonEmptyIndex(source["index"]) {
+		errs = append(errs, errors.New("missing source.index, expected the indexes or data streams used as source"))
+	}
+
+	dest, _ := definition["dest"].(map[string]any)
+	if index, _ := dest["index"].(string); index == "" {
+		errs = append(errs, errors.New("missing dest.index, expected the destination index"))
+	}
+
+	pivot, hasPivot := definition["pivot"]
+	latest, hasLatest := definition["latest"]
+	switch {
+	case hasPivot && hasLatest:
+		errs = append(errs, errors.New("pivot and latest cannot be configured at the same time"))
+	case hasPivot:
+		pivot, _ := pivot.(map[string]any)
+		if groupBy, _ := pivot["group_by"].(map[string]any); len(groupBy) == 0 {
+			errs = append(errs, errors.New("missing pivot.group_by, expected at least one grouping"))
+		}
+		aggregations, _ := pivot["aggregations"].(map[string]any)
+		if len(aggregations) == 0 {
+			aggregations, _ = pivot["aggs"].(map[string]any)
+		}
+		if len(aggregations) == 0 {
+			errs = append(errs, errors.New("missing pivot.aggregations, expected at least one aggregation"))
+		}
+	case hasLatest:
+		latest, _ := latest.(map[string]any)
+		if uniqueKey, _ := latest["unique_key"].([]any); len(uniqueKey) == 0 {
+			errs = append(errs, errors.New("missing latest.unique_key, expected at least one field"))
+		}
+		if sort, _ := latest["sort"].(string); sort == "" {
+			errs = append(errs, errors.New("missing latest.sort, expected the field used to sort documents"))
+		}
+	default:
+		errs = append(errs, errors.New("missing pivot or latest configuration"))
+	}
+
+	return errs
+}
+
+func isNonEmptyIndex(index any) bool {
+	switch index := index.(type) {
+	case string:
+		return index != ""
+	case []any:
+		for _, i := range index {
+			if s, ok := i.(string); !ok || s == "" {
+				return false
+			}
+		}
+		return len(index) > 0
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package packages
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTransform(t *testing.T) {
+	cases := []struct {
+		title      string
+		definition string
+		errors     []string
+	}{
+		{
+			title: "latest",
+			definition: `
+source:
+  index: ["logs-ti_anomali.threatstream-*"]
+dest:
+  index: "logs-ti_anomali_latest.threatstream-2"
+latest:
+  unique_key: [event.dataset, anomali.threatstream.id]
+  sort: "@timestamp"
+`,
+		},
+		{
+			title: "pivot",
+			definition: `
+source:
+  index: "metrics-*"
+dest:
+  index: "metrics-summary"
+pivot:
+  group_by:
+    host:
+      terms:
+        field: host.name
+  aggs:
+    max_cpu:
+      max:
+        field: system.cpu.total.pct
+`,
+		},
+		{
+			title: "missing required settings",
+			definition: `
+source:
+  index: []
+description: Incomplete transform
+`,
+			errors: []string{
+				"missing source.index",
+				"missing dest.index",
+				"missing pivot or latest configuration",
+			},
+		},
+		{
+			title: "incomplete latest",
+			definition: `
+source:
+  index: ["logs-*"]
+dest:
+  index: "logs-latest"
+latest:
+  unique_key: []
+`,
+			errors: []string{
+				"missing latest.unique_key",
+				"missing latest.sort",
+			},
+		},
+		{
+			title: "pivot and latest",
+			definition: `
+source:
+  index: ["logs-*"]
+dest:
+  index: "logs-latest"
+pivot:
+  group_by:
+    host:
+      terms:
+        field: host.name
+latest:
+  unique_key: [host.name]
+  sort: "@timestamp"
+`,
+			errors: []string{
+				"pivot and latest cannot be configured at the same time",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "transform.yml")
+			require.NoError(t, os.WriteFile(path, []byte(c.definition), 0644))
+
+			err := ValidateTransform(Transform{Name: "test", Path: path})
+			if len(c.errors) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, expected := range c.errors {
+				assert.ErrorContains(t, err, expected)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"regexp"
 
 	"github.com/elastic/go-ucfg/yaml"
 	"github.com/mitchellh/mapstructure"
@@ -17,6 +18,10 @@ import (
 
 const currentVersion = 1
 
+// envVarReferencePattern matches references to environment variables in settings, in the form
+// ${NAME}, or ${NAME:-default} to use a default value when the variable is unset or empty.
+var envVarReferencePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
 type config struct {
 	settings common.MapStr
 }
@@ -36,9 +41,77 @@ func loadProfileConfig(path string) (config, error) {
 		return config{}, fmt.Errorf("can't unpack configuration: %w", err)
 	}
 
+	err = interpolateEnvSettings("", settings)
+	if err != nil {
+		return config{}, fmt.Errorf("can't resolve environment variables in profile configuration (%s): %w", path, err)
+	}
+
 	return config{settings: settings}, nil
 }
 
+// interpolateEnvSettings replaces the references to environment variables in the string values
+// of the settings. Resolved values are only kept in memory.
+func interpolateEnvSettings(prefix string, m map[string]any) error {
+	for k, v := range m {
+		name := k
+		if prefix != "" {
+			name = prefix + "." + k
+		}
+		resolved, err := interpolateEnvValue(name, v)
+		if err != nil {
+			return err
+		}
+		m[k] = resolved
+	}
+	return nil
+}
+
+func interpolateEnvValue(name string, value any) (any, error) {
+	switch v := value.(type) {
+	case common.MapStr:
+		return v, interpolateEnvSettings(name, v)
+	case map[string]any:
+		return v, interpolateEnvSettings(name, v)
+	case []any:
+		for i := range v {
+			resolved, err := interpolateEnvValue(name, v[i])
+			if err != nil {
+				return nil, err
+			}
+			v[i] = resolved
+		}
+		return v, nil
+	case string:
+		return interpolateEnv(name, v)
+	default:
+		return v, nil
+	}
+}
+
+func interpolateEnv(name string, value string) (string, error) {
+	var err error
+	result := envVarReferencePattern.ReplaceAllStringFunc(value, func(reference string) string {
+		match := envVarReferencePattern.FindStringSubmatch(reference)
+		envValue, found := os.LookupEnv(match[1])
+		hasDefault := match[2] != ""
+		switch {
+		case hasDefault && envValue == "":
+			return match[3]
+		case !found:
+			if err == nil {
+				err = fmt.Errorf("environment variable %q used in setting %q is not set", match[1], name)
+			}
+			return reference
+		default:
+			return envValue
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
 func (c *config) get(name string) (string, bool) {
 	raw, err := c.settings.GetValue(name)
 	if err != nil {
@@ -66,3 +139,29 @@ func (c *config) Decode(name string, out any) error {
 
 	return nil
 }
+
+// flatten returns the settings of the configuration, with the names of nested settings joined by dots.
+func (c *config) flatten() map[string]string {
+	settings := make(map[string]string)
+	flattenSettings("", c.settings, settings)
+	return settings
+}
+
+func flattenSettings(prefix string, m map[string]any, settings map[string]string) {
+	for k, v := range m {
+		name := k
+		if prefix != "" {
+			name = prefix + "." + k
+		}
+		switch v := v.(type) {
+		case common.MapStr:
+			flattenSettings(name, v, settings)
+		case map[string]any:
+			flattenSettings(name, v, settings)
+		case string:
+			settings[name] = v
+		default:
+			settings[name] = fmt.Sprintf("%v", v)
+		}
+	}
+}
@@ -5,6 +5,7 @@
 package profile
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -102,3 +103,61 @@ func TestConfigDecode(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigFlatten(t *testing.T) {
+	config, err := loadProfileConfig("testdata/config.yml")
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"stack.geoip_dir":        "/home/foo/Documents/ingest-geoip",
+		"stack.apm_enabled":      "true",
+		"stack.logstash_enabled": "true",
+		"other.empty":            "",
+		"other.nested":           "foo",
+		"other.number":           "42",
+		"other.float":            "0.12345",
+		"other.bool":             "false",
+		"other.array":            "[entry1 entry2 entry3]",
+	}
+	assert.Equal(t, expected, config.flatten())
+}
+
+func TestLoadProfileConfigEnvInterpolation(t *testing.T) {
+	t.Setenv("TEST_PROFILE_GEOIP_DIR", "/srv/geoip")
+	t.Setenv("TEST_PROFILE_REGION", "gcp-us-central1")
+	t.Setenv("TEST_PROFILE_AGENT_PORT", "127.0.0.1:8080")
+
+	original, err := os.ReadFile("testdata/config-env.yml")
+	require.NoError(t, err)
+
+	config, err := loadProfileConfig("testdata/config-env.yml")
+	require.NoError(t, err)
+
+	cases := map[string]string{
+		"stack.geoip_dir":          "/srv/geoip",
+		"stack.elastic_cloud.host": "https://cloud.elastic.co",
+		"stack.serverless.region":  "gcp-us-central1",
+		"other.literal":            "no references",
+	}
+	for name, expected := range cases {
+		value, found := config.get(name)
+		if assert.True(t, found, name) {
+			assert.Equal(t, expected, value, name)
+		}
+	}
+
+	var ports []string
+	require.NoError(t, config.Decode("stack.agent.ports", &ports))
+	assert.Equal(t, []string{"127.0.0.1:8080:8080"}, ports)
+
+	// Resolved values are not written back to the configuration file.
+	current, err := os.ReadFile("testdata/config-env.yml")
+	require.NoError(t, err)
+	assert.Equal(t, string(original), string(current))
+}
+
+func TestLoadProfileConfigEnvInterpolationUnset(t *testing.T) {
+	_, err := loadProfileConfig("testdata/config-env-unset.yml")
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `environment variable "TEST_PROFILE_UNSET_DOMAIN" used in setting "stack.elastic_cloud.host" is not set`)
+}
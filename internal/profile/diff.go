@@ -0,0 +1,51 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package profile
+
+import (
+	"sort"
+)
+
+// SettingDifference describes a setting that has different values in two sets of settings.
+type SettingDifference struct {
+	Name string
+
+	// A and B are the values of the setting in each set, empty if not set.
+	A, B string
+
+	// FoundA and FoundB are true if the setting is set in each set.
+	FoundA, FoundB bool
+}
+
+// DiffSettings returns the settings whose values differ between a and b, sorted by name.
+func DiffSettings(a, b map[string]string) []SettingDifference {
+	names := make(map[string]struct{})
+	for name := range a {
+		names[name] = struct{}{}
+	}
+	for name := range b {
+		names[name] = struct{}{}
+	}
+
+	var diff []SettingDifference
+	for name := range names {
+		valueA, foundA := a[name]
+		valueB, foundB := b[name]
+		if foundA == foundB && valueA == valueB {
+			continue
+		}
+		diff = append(diff, SettingDifference{
+			Name:   name,
+			A:      valueA,
+			B:      valueB,
+			FoundA: foundA,
+			FoundB: foundB,
+		})
+	}
+	sort.Slice(diff, func(i, j int) bool {
+		return diff[i].Name < diff[j].Name
+	})
+	return diff
+}
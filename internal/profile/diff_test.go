@@ -0,0 +1,35 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package profile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSettings(t *testing.T) {
+	a := map[string]string{
+		"stack.apm_enabled":             "true",
+		"stack.geoip_dir":               "./ingest-geoip",
+		"connection.elasticsearch_host": "https://127.0.0.1:9200",
+		"connection.ca_cert_file":       "<profile>/certs/ca-cert.pem",
+	}
+	b := map[string]string{
+		"stack.apm_enabled":             "false",
+		"stack.geoip_dir":               "./ingest-geoip",
+		"connection.elasticsearch_host": "https://es.example.com:9243",
+		"stack.logsdb_enabled":          "true",
+	}
+
+	expected := []SettingDifference{
+		{Name: "connection.ca_cert_file", A: "<profile>/certs/ca-cert.pem", FoundA: true},
+		{Name: "connection.elasticsearch_host", A: "https://127.0.0.1:9200", B: "https://es.example.com:9243", FoundA: true, FoundB: true},
+		{Name: "stack.apm_enabled", A: "true", B: "false", FoundA: true, FoundB: true},
+		{Name: "stack.logsdb_enabled", B: "true", FoundB: true},
+	}
+	assert.Equal(t, expected, DiffSettings(a, b))
+	assert.Empty(t, DiffSettings(a, a))
+}
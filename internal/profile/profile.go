@@ -167,6 +167,16 @@ func (profile Profile) Config(name string, def string) string {
 	return def
 }
 
+// Settings returns all the settings of the profile configuration, including runtime overrides.
+// Names of nested settings are joined by dots.
+func (profile Profile) Settings() map[string]string {
+	settings := profile.config.flatten()
+	for k, v := range profile.overrides {
+		settings[k] = v
+	}
+	return settings
+}
+
 func (profile *Profile) Decode(name string, dst any) error {
 	return profile.config.Decode(name, dst)
 }
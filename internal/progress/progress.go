@@ -0,0 +1,164 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/elastic/elastic-package/internal/logger"
+)
+
+const (
+	spinnerInterval    = 100 * time.Millisecond
+	defaultLogInterval = 30 * time.Second
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Reporter reports the progress of long-running operations. Steps are printed as they start.
+// While waiting for something, a spinner with the current wait is shown when writing to a
+// terminal, otherwise a line is printed periodically while the wait lasts.
+// All methods can be called on a nil reporter, doing nothing, so reporting can be disabled
+// by not creating it.
+type Reporter struct {
+	out         io.Writer
+	spinner     bool
+	logInterval time.Duration
+
+	mutex    sync.Mutex
+	waits    []*waitStep
+	stop     chan struct{}
+	stopped  chan struct{}
+	rendered bool
+}
+
+type waitStep struct {
+	message string
+	started time.Time
+}
+
+// NewReporter creates a progress reporter that writes to the given output. The spinner is only
+// used if the output is a terminal, and debug logging is not enabled, as log lines would
+// interfere with it.
+func NewReporter(out io.Writer) *Reporter {
+	r := Reporter{
+		out:         out,
+		logInterval: defaultLogInterval,
+	}
+	if f, ok := out.(*os.File); ok && !logger.IsDebugMode() {
+		r.spinner = term.IsTerminal(int(f.Fd()))
+	}
+	return &r
+}
+
+// Step reports the start of a new step of the operation.
+func (r *Reporter) Step(format string, a ...any) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.clearLine()
+	fmt.Fprintf(r.out, format+"\n", a...)
+}
+
+// Wait reports that the operation is waiting for something. The returned function must be
+// called when the wait finishes. Several waits can be active at the same time, as happens
+// when tests run in parallel, the most recent one is shown in the spinner.
+func (r *Reporter) Wait(format string, a ...any) (done func()) {
+	if r == nil {
+		return func() {}
+	}
+	step := &waitStep{
+		message: fmt.Sprintf(format, a...),
+		started: time.Now(),
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.waits = append(r.waits, step)
+	if !r.spinner {
+		fmt.Fprintf(r.out, "%s...\n", step.message)
+	}
+	if r.stop == nil {
+		r.stop = make(chan struct{})
+		r.stopped = make(chan struct{})
+		go r.run(r.stop, r.stopped)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { r.endWait(step) })
+	}
+}
+
+func (r *Reporter) endWait(step *waitStep) {
+	r.mutex.Lock()
+	r.waits = slices.DeleteFunc(r.waits, func(s *waitStep) bool { return s == step })
+	if len(r.waits) > 0 {
+		r.mutex.Unlock()
+		return
+	}
+	stop, stopped := r.stop, r.stopped
+	r.stop, r.stopped = nil, nil
+	r.mutex.Unlock()
+
+	close(stop)
+	<-stopped
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.clearLine()
+}
+
+func (r *Reporter) run(stop, stopped chan struct{}) {
+	defer close(stopped)
+
+	interval := r.logInterval
+	if r.spinner {
+		interval = spinnerInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	frame := 0
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		r.mutex.Lock()
+		if len(r.waits) > 0 {
+			current := r.waits[len(r.waits)-1]
+			elapsed := time.Since(current.started).Round(time.Second)
+			if r.spinner {
+				fmt.Fprintf(r.out, "\r%s %s (%s)\033[K", spinnerFrames[frame%len(spinnerFrames)], current.message, elapsed)
+				r.rendered = true
+				frame++
+			} else {
+				fmt.Fprintf(r.out, "%s... (%s elapsed)\n", current.message, elapsed)
+			}
+		}
+		r.mutex.Unlock()
+	}
+}
+
+// clearLine removes the spinner, if it is shown. The mutex must be held.
+func (r *Reporter) clearLine() {
+	if !r.rendered {
+		return
+	}
+	fmt.Fprint(r.out, "\r\033[K")
+	r.rendered = false
+}
@@ -0,0 +1,80 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// syncBuffer is a buffer that can be written by the reporter while it is read by the test.
+type syncBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.String()
+}
+
+func TestReporterNotTerminal(t *testing.T) {
+	var out syncBuffer
+	r := NewReporter(&out)
+	assert.False(t, r.spinner)
+	r.logInterval = 10 * time.Millisecond
+
+	r.Step("Installing package")
+	done := r.Wait("Waiting for documents")
+	assert.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "Waiting for documents... (")
+	}, time.Second, 10*time.Millisecond)
+	done()
+	done()
+
+	lines := strings.Split(out.String(), "\n")
+	assert.Equal(t, "Installing package", lines[0])
+	assert.Equal(t, "Waiting for documents...", lines[1])
+
+	// Nothing else is written after the wait is done.
+	written := out.String()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, written, out.String())
+}
+
+func TestReporterSpinner(t *testing.T) {
+	var out syncBuffer
+	r := &Reporter{out: &out, spinner: true}
+
+	first := r.Wait("Waiting for agents")
+	second := r.Wait("Waiting for documents")
+	assert.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "Waiting for documents (")
+	}, time.Second, 10*time.Millisecond)
+
+	second()
+	first()
+	assert.True(t, strings.HasSuffix(out.String(), "\r\033[K"))
+	assert.NotContains(t, out.String(), "\n")
+}
+
+func TestNilReporter(t *testing.T) {
+	var r *Reporter
+	r.Step("Installing package")
+	done := r.Wait("Waiting for documents")
+	done()
+}
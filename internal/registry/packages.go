@@ -0,0 +1,26 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package registry
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// DownloadPackage downloads the zip file of the given version of a package.
+func (c *Client) DownloadPackage(name, version string) ([]byte, error) {
+	path := fmt.Sprintf("/epr/%s/%s-%s.zip", name, name, version)
+	statusCode, respBody, err := c.get(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not download package %s-%s: %w", name, version, err)
+	}
+	if statusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("package %s-%s not found in the package registry", name, version)
+	}
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not download package %s-%s; API status code = %d", name, version, statusCode)
+	}
+	return respBody, nil
+}
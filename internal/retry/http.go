@@ -13,6 +13,7 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+	"regexp"
 	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
@@ -23,22 +24,28 @@ const (
 	defaultRetryWaitMax = 5 * time.Second
 )
 
+// HTTPOptions configures the retries of HTTP requests. Waits between retries grow exponentially
+// from RetryWaitMin up to RetryWaitMax.
 type HTTPOptions struct {
+	// RetryMax is the maximum number of retries, requests are not retried if it is zero.
 	RetryMax int
 
-	retryWaitMin time.Duration
-	retryWaitMax time.Duration
+	// RetryWaitMin is the minimum time to wait between retries, one second if not set.
+	RetryWaitMin time.Duration
+
+	// RetryWaitMax is the maximum time to wait between retries, five seconds if not set.
+	RetryWaitMax time.Duration
 }
 
 func WrapHTTPClient(client *http.Client, opts HTTPOptions) *http.Client {
 	if opts.RetryMax <= 0 {
 		return client
 	}
-	retryWaitMin := opts.retryWaitMin
+	retryWaitMin := opts.RetryWaitMin
 	if retryWaitMin == 0 {
 		retryWaitMin = defaultRetryWaitMin
 	}
-	retryWaitMax := opts.retryWaitMax
+	retryWaitMax := opts.RetryWaitMax
 	if retryWaitMax == 0 {
 		retryWaitMax = defaultRetryWaitMax
 	}
@@ -87,9 +94,11 @@ func checkRetry(ctx context.Context, resp *http.Response, err error) (bool, erro
 			return false, nil
 		}
 
+		// Errors returned by the HTTP client are always wrapped in url.Error, including network
+		// errors such as connection resets, so only check the ones caused by invalid requests.
 		var urlError *url.Error
-		if errors.As(err, &urlError) {
-			// URL is invalid, not recoverable.
+		if errors.As(err, &urlError) && isInvalidRequestError(urlError) {
+			// Request is invalid, not recoverable.
 			return false, nil
 		}
 
@@ -135,3 +144,15 @@ func checkRetry(ctx context.Context, resp *http.Response, err error) (bool, erro
 
 	return false, nil
 }
+
+var (
+	// Errors returned by the HTTP client for invalid requests, they are not exported as types.
+	unsupportedProtocolSchemeErrorRe = regexp.MustCompile(`unsupported protocol scheme`)
+	invalidHeaderErrorRe             = regexp.MustCompile(`invalid header`)
+)
+
+// isInvalidRequestError checks if the error is caused by an invalid request.
+func isInvalidRequestError(err *url.Error) bool {
+	return unsupportedProtocolSchemeErrorRe.MatchString(err.Error()) ||
+		invalidHeaderErrorRe.MatchString(err.Error())
+}
@@ -70,8 +70,8 @@ func TestRetryHTTPStatus(t *testing.T) {
 		t.Run(c.title, func(t *testing.T) {
 			opts := HTTPOptions{
 				RetryMax:     c.retryMax,
-				retryWaitMin: fastRetryWaitMin,
-				retryWaitMax: fastRetryWaitMax,
+				RetryWaitMin: fastRetryWaitMin,
+				RetryWaitMax: fastRetryWaitMax,
 			}
 			client := WrapHTTPClient(&http.Client{}, opts)
 
@@ -90,8 +90,8 @@ func TestRetryHTTPStatus(t *testing.T) {
 func TestUnrecoverableErrors(t *testing.T) {
 	opts := HTTPOptions{
 		RetryMax:     5,
-		retryWaitMin: fastRetryWaitMin,
-		retryWaitMax: fastRetryWaitMax,
+		RetryWaitMin: fastRetryWaitMin,
+		RetryWaitMax: fastRetryWaitMax,
 	}
 
 	t.Run("invalid URL", func(t *testing.T) {
@@ -135,6 +135,15 @@ func TestUnrecoverableErrors(t *testing.T) {
 		assert.ErrorIs(t, err, expectedErr)
 	})
 
+	t.Run("unsupported protocol scheme", func(t *testing.T) {
+		transport := &flakyTransport{}
+		client := WrapHTTPClient(&http.Client{Transport: transport}, opts)
+
+		_, err := client.Get("foo://localhost")
+		assert.ErrorContains(t, err, "unsupported protocol scheme")
+		assert.Equal(t, 1, transport.requests)
+	})
+
 	t.Run("consistent network error", func(t *testing.T) {
 		expectedErr := errors.New("network error")
 		brokenClient := http.Client{
@@ -150,6 +159,30 @@ func TestUnrecoverableErrors(t *testing.T) {
 	})
 }
 
+func TestRetryNetworkErrors(t *testing.T) {
+	opts := HTTPOptions{
+		RetryMax:     5,
+		RetryWaitMin: fastRetryWaitMin,
+		RetryWaitMax: fastRetryWaitMax,
+	}
+
+	server := httptest.NewServer(newStatusHandler("OK", http.StatusOK))
+	defer server.Close()
+
+	transport := &flakyTransport{
+		err:      errors.New("connection reset by peer"),
+		failures: 3,
+	}
+	client := WrapHTTPClient(&http.Client{Transport: transport}, opts)
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 4, transport.requests)
+}
+
 // flakyTestHandler deterministically succeeds only once every rate requests.
 type flakyTestHandler struct {
 	okHandler http.Handler
@@ -206,3 +239,18 @@ type brokenTransport struct {
 func (t *brokenTransport) RoundTrip(*http.Request) (*http.Response, error) {
 	return nil, t.err
 }
+
+// flakyTransport fails with the given error the given number of times before sending requests.
+type flakyTransport struct {
+	err      error
+	failures int
+	requests int
+}
+
+func (t *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	if t.requests <= t.failures {
+		return nil, t.err
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
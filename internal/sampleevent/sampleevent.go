@@ -0,0 +1,208 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package sampleevent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/elasticsearch"
+	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/formatter"
+	"github.com/elastic/elastic-package/internal/logger"
+	"github.com/elastic/elastic-package/internal/multierror"
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+const (
+	// SampleEventFile is the name of the file with the sample event of a data stream.
+	SampleEventFile = "sample_event.json"
+
+	// candidatesSize is the number of recent documents considered as candidates for the sample event.
+	candidatesSize = 50
+)
+
+// volatileFields are fields whose values change on every execution, or that are managed by the
+// agent, so they are removed from sample events.
+var volatileFields = []string{
+	"agent.ephemeral_id",
+	"agent.id",
+	"elastic_agent.id",
+	"event.ingested",
+}
+
+// Options contains the options to generate a sample event.
+type Options struct {
+	API         *elasticsearch.API
+	PackageRoot string
+	DataStream  string
+}
+
+// Generate looks for a recent document ingested in the given data stream that is valid according to
+// its field definitions, and writes it as the sample event of the data stream. Volatile fields are
+// removed from the document. It returns the path of the written file.
+func Generate(ctx context.Context, options Options) (string, error) {
+	pkgManifest, err := packages.ReadPackageManifestFromPackageRoot(options.PackageRoot)
+	if err != nil {
+		return "", fmt.Errorf("reading package manifest failed: %w", err)
+	}
+	specVersion, err := semver.NewVersion(pkgManifest.SpecVersion)
+	if err != nil {
+		return "", fmt.Errorf("parsing format version %q failed: %w", pkgManifest.SpecVersion, err)
+	}
+
+	dataStreamPath := filepath.Join(options.PackageRoot, "data_stream", options.DataStream)
+	dsManifest, err := packages.ReadDataStreamManifest(filepath.Join(dataStreamPath, packages.DataStreamManifestFile))
+	if err != nil {
+		return "", fmt.Errorf("reading data stream manifest failed: %w", err)
+	}
+	dataset := dataStreamDataset(*pkgManifest, *dsManifest)
+
+	fieldsValidator, err := fields.CreateValidatorForDirectory(dataStreamPath,
+		fields.WithSpecVersion(pkgManifest.SpecVersion),
+		fields.WithExpectedDatasets([]string{dataset}),
+		fields.WithExpectedDataStream(dsManifest.Type, dataset),
+		fields.WithEnabledImportAllECSSChema(true),
+	)
+	if err != nil {
+		return "", fmt.Errorf("creating fields validator for data stream failed (path: %s): %w", dataStreamPath, err)
+	}
+
+	indexPattern := fmt.Sprintf("%s-%s-*", dsManifest.Type, dataset)
+	docs, err := recentDocuments(ctx, options.API, indexPattern)
+	if err != nil {
+		return "", err
+	}
+	if len(docs) == 0 {
+		return "", fmt.Errorf("no documents found in %s, data must be ingested before generating the sample event", indexPattern)
+	}
+
+	doc, err := selectSampleEvent(fieldsValidator, docs)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dataStreamPath, SampleEventFile)
+	jsonFormatter := formatter.JSONFormatterBuilder(*specVersion)
+	body, err := jsonFormatter.Encode(doc)
+	if err != nil {
+		return "", fmt.Errorf("marshalling sample event failed: %w", err)
+	}
+	err = os.WriteFile(path, body, 0644)
+	if err != nil {
+		return "", fmt.Errorf("writing sample event failed: %w", err)
+	}
+	return path, nil
+}
+
+// selectSampleEvent returns the first document, without volatile fields, that is valid
+// according to the fields validator.
+func selectSampleEvent(validator *fields.Validator, docs []common.MapStr) (common.MapStr, error) {
+	var firstErrs multierror.Error
+	for i, doc := range docs {
+		stripVolatileFields(doc)
+		body, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("marshalling document failed: %w", err)
+		}
+		errs := validator.ValidateDocumentBody(body)
+		if len(errs) == 0 {
+			return doc, nil
+		}
+		logger.Debugf("Document %d is not valid as sample event: %s", i, errs.Error())
+		if firstErrs == nil {
+			firstErrs = errs.Unique()
+		}
+	}
+	return nil, fmt.Errorf("none of the %d recent documents is valid according to the field definitions, errors found in the most recent one: %w", len(docs), firstErrs)
+}
+
+func stripVolatileFields(doc common.MapStr) {
+	for _, field := range volatileFields {
+		err := doc.Delete(field)
+		if errors.Is(err, common.ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			logger.Debugf("Failed to remove field %s from document: %s", field, err)
+			continue
+		}
+		removeEmptyParents(doc, field)
+	}
+}
+
+// removeEmptyParents removes the objects containing the given field that are left empty.
+func removeEmptyParents(doc common.MapStr, field string) {
+	for i := strings.LastIndex(field, "."); i > 0; i = strings.LastIndex(field, ".") {
+		field = field[:i]
+		value, err := doc.GetValue(field)
+		if err != nil {
+			return
+		}
+		switch parent := value.(type) {
+		case common.MapStr:
+			if len(parent) > 0 {
+				return
+			}
+		case map[string]any:
+			if len(parent) > 0 {
+				return
+			}
+		default:
+			return
+		}
+		doc.Delete(field)
+	}
+}
+
+func recentDocuments(ctx context.Context, api *elasticsearch.API, indexPattern string) ([]common.MapStr, error) {
+	resp, err := api.Search(
+		api.Search.WithContext(ctx),
+		api.Search.WithIndex(indexPattern),
+		api.Search.WithSort("@timestamp:desc"),
+		api.Search.WithSize(candidatesSize),
+		api.Search.WithIgnoreUnavailable(true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not search documents in %s: %w", indexPattern, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return nil, fmt.Errorf("failed to search documents in %s: %s", indexPattern, resp.String())
+	}
+
+	var results struct {
+		Hits struct {
+			Hits []struct {
+				Source common.MapStr `json:"_source"`
+			}
+		}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("could not decode search results response: %w", err)
+	}
+
+	docs := make([]common.MapStr, 0, len(results.Hits.Hits))
+	for _, hit := range results.Hits.Hits {
+		docs = append(docs, hit.Source)
+	}
+	return docs, nil
+}
+
+func dataStreamDataset(pkg packages.PackageManifest, ds packages.DataStreamManifest) string {
+	if len(ds.Dataset) > 0 {
+		return ds.Dataset
+	}
+	return fmt.Sprintf("%s.%s", pkg.Name, ds.Name)
+}
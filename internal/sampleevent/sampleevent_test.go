@@ -0,0 +1,62 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package sampleevent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/fields"
+)
+
+func TestSelectSampleEvent(t *testing.T) {
+	validator, err := fields.CreateValidatorForDirectory("testdata", fields.WithDisabledDependencyManagement())
+	require.NoError(t, err)
+
+	t.Run("first valid document", func(t *testing.T) {
+		docs := []common.MapStr{
+			{
+				"@timestamp": "2024-01-01T00:00:02.000Z",
+				"message":    "undefined field",
+				"undefined":  "foo",
+			},
+			{
+				"@timestamp": "2024-01-01T00:00:01.000Z",
+				"message":    "valid",
+				"agent": common.MapStr{
+					"id":           "1234",
+					"ephemeral_id": "5678",
+				},
+				"event": common.MapStr{
+					"dataset":  "test.logs",
+					"ingested": "2024-01-01T00:00:03.000Z",
+				},
+			},
+		}
+		doc, err := selectSampleEvent(validator, docs)
+		require.NoError(t, err)
+		assert.Equal(t, common.MapStr{
+			"@timestamp": "2024-01-01T00:00:01.000Z",
+			"message":    "valid",
+			"event": common.MapStr{
+				"dataset": "test.logs",
+			},
+		}, doc)
+	})
+
+	t.Run("no valid documents", func(t *testing.T) {
+		docs := []common.MapStr{
+			{
+				"@timestamp": "2024-01-01T00:00:02.000Z",
+				"undefined":  "foo",
+			},
+		}
+		_, err := selectSampleEvent(validator, docs)
+		assert.ErrorContains(t, err, "none of the 1 recent documents is valid")
+	})
+}
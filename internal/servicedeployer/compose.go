@@ -233,6 +233,25 @@ func (s *dockerComposeDeployedService) ExitCode(ctx context.Context, service str
 	return p.ServiceExitCode(ctx, service, opts)
 }
 
+// Logs returns the logs from the service containers starting at the given time.
+func (s *dockerComposeDeployedService) Logs(ctx context.Context, t time.Time) ([]byte, error) {
+	p, err := compose.NewProject(s.project, s.ymlPaths...)
+	if err != nil {
+		return nil, fmt.Errorf("could not create Docker Compose project for service: %w", err)
+	}
+
+	opts := compose.CommandOptions{
+		Env: append(
+			s.env,
+			s.variant.Env...),
+	}
+	if !t.IsZero() {
+		opts.ExtraArgs = append(opts.ExtraArgs, "--since", t.UTC().Format("2006-01-02T15:04:05Z"))
+	}
+
+	return p.Logs(ctx, opts)
+}
+
 // TearDown tears down the service.
 func (s *dockerComposeDeployedService) TearDown(ctx context.Context) error {
 	logger.Debugf("tearing down service using Docker Compose runner")
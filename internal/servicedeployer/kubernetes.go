@@ -14,6 +14,7 @@ import (
 	"path/filepath"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/elastic/elastic-package/internal/install"
 	"github.com/elastic/elastic-package/internal/kind"
@@ -101,6 +102,10 @@ func (s kubernetesDeployedService) ExitCode(_ context.Context, _ string) (bool,
 	return false, -1, ErrNotSupported
 }
 
+func (s kubernetesDeployedService) Logs(_ context.Context, _ time.Time) ([]byte, error) {
+	return nil, ErrNotSupported
+}
+
 func (s kubernetesDeployedService) Info() ServiceInfo {
 	return s.svcInfo
 }
@@ -0,0 +1,4 @@
+resource "local_file" "log" {
+  source   = "./files/example.log"
+  filename = "/tmp/service_logs/file.log"
+}
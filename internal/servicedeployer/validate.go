@@ -0,0 +1,521 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package servicedeployer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/elastic/elastic-package/internal/multierror"
+)
+
+const devDeployDir = "_dev/deploy"
+
+// runtimeValue is used as value of the variables that are only known when the service is
+// deployed. Paths containing it are not checked.
+const runtimeValue = "\x00"
+
+var (
+	// composeRuntimeVars are the variables set by elastic-package when running Docker Compose services.
+	composeRuntimeVars = []string{serviceLogsDirEnv}
+
+	// customAgentRuntimeVars are the variables set by elastic-package when running custom agents.
+	customAgentRuntimeVars = []string{
+		serviceLogsDirEnv, localCACertEnv, fleetPolicyEnv,
+		"ELASTIC_AGENT_IMAGE_REF", "ELASTICSEARCH_IMAGE_REF", "KIBANA_IMAGE_REF", "LOGSTASH_IMAGE_REF",
+	}
+
+	// terraformRuntimeVars are the variables set by elastic-package when running the Terraform deployer.
+	terraformRuntimeVars = []string{serviceLogsDirEnv, tfTestRunID, tfDir, tfOutputDir}
+)
+
+var (
+	// placeholderPattern matches the variable placeholders supported by Docker Compose, that is
+	// "$$" (escaped dollar), "$VAR", "${VAR}" and "${VAR<op><value>}" with op one of ":-", "-",
+	// ":?", "?", ":+" or "+".
+	placeholderPattern = regexp.MustCompile(`\$(?:(\$)|\{([A-Za-z_][A-Za-z0-9_]*)(?:(:?[-?+])([^}]*))?\}|([A-Za-z_][A-Za-z0-9_]*))`)
+
+	// terraformPathPattern matches literal relative paths used as module or file sources, or
+	// as arguments of the functions that read files.
+	terraformPathPattern = regexp.MustCompile(`(?:\bsource\s*=\s*|\b(?:file|filebase64|templatefile|filemd5|filesha256)\(\s*)"((?:\$\{path\.module\}/|\.\.?/)[^"]*)"`)
+)
+
+// ValidateDevDeployConfigs checks the service deployer configurations defined in the "_dev/deploy"
+// directories of the package and its data streams. It looks for files referenced by these
+// configurations that don't exist and for required variables that cannot be resolved, so these
+// problems can be found before deploying any service.
+func ValidateDevDeployConfigs(packageRootPath string) error {
+	devDeployPaths := []string{filepath.Join(packageRootPath, devDeployDir)}
+	dataStreamPaths, err := filepath.Glob(filepath.Join(packageRootPath, "data_stream", "*", devDeployDir))
+	if err != nil {
+		return fmt.Errorf("can't find data stream deploy directories: %w", err)
+	}
+	devDeployPaths = append(devDeployPaths, dataStreamPaths...)
+
+	var errs multierror.Error
+	for _, devDeployPath := range devDeployPaths {
+		err := validateDevDeployPath(packageRootPath, devDeployPath)
+		if merr, ok := err.(multierror.Error); ok {
+			errs = append(errs, merr...)
+		} else if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs.Unique()
+	}
+	return nil
+}
+
+func validateDevDeployPath(packageRootPath, devDeployPath string) error {
+	fis, err := os.ReadDir(devDeployPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("can't read directory (path: %s): %w", devDeployPath, err)
+	}
+
+	v := deployValidator{packageRootPath: packageRootPath}
+	for _, fi := range fis {
+		if !fi.IsDir() {
+			continue
+		}
+		deployerPath := filepath.Join(devDeployPath, fi.Name())
+		switch fi.Name() {
+		case "docker":
+			envSets, err := variantEnvSets(devDeployPath)
+			if err != nil {
+				v.report(filepath.Join(devDeployPath, "variants.yml"), "%v", err)
+				envSets = []envSet{{}}
+			}
+			v.validateComposeFile(filepath.Join(deployerPath, "docker-compose.yml"), composeRuntimeVars, envSets, true)
+		case "agent":
+			v.validateComposeFile(filepath.Join(deployerPath, "custom-agent.yml"), customAgentRuntimeVars, []envSet{{}}, true)
+		case "k8s":
+			v.validateKubernetesDefinitions(deployerPath)
+		case "tf":
+			v.validateTerraformDefinitions(deployerPath)
+		default:
+			v.report(deployerPath, "unsupported service deployer %q", fi.Name())
+		}
+	}
+
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+// envSet contains the variables defined by a service variant.
+type envSet struct {
+	variant string
+	env     Environment
+}
+
+// variantEnvSets returns the variables defined by each one of the variants of the service,
+// or an empty set if the service has no variants.
+func variantEnvSets(devDeployPath string) ([]envSet, error) {
+	f, err := ReadVariantsFile(devDeployPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return []envSet{{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if f.Default == "" {
+		return nil, errors.New("default variant is undefined")
+	}
+	if _, found := f.Variants[f.Default]; !found {
+		return nil, fmt.Errorf("default variant %q is missing", f.Default)
+	}
+
+	var sets []envSet
+	for name, env := range f.Variants {
+		sets = append(sets, envSet{variant: name, env: env})
+	}
+	sort.Slice(sets, func(i, j int) bool { return sets[i].variant < sets[j].variant })
+	return sets, nil
+}
+
+type deployValidator struct {
+	packageRootPath string
+	errs            multierror.Error
+}
+
+func (v *deployValidator) report(path string, format string, a ...interface{}) {
+	if rel, err := filepath.Rel(v.packageRootPath, path); err == nil {
+		path = rel
+	}
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, a...)))
+}
+
+// composeFile contains the settings of Docker Compose files that can reference local files.
+type composeFile struct {
+	Services map[string]composeService `yaml:"services"`
+	Configs  map[string]composeFileRef `yaml:"configs"`
+	Secrets  map[string]composeFileRef `yaml:"secrets"`
+}
+
+type composeService struct {
+	Build   interface{}   `yaml:"build"`
+	EnvFile interface{}   `yaml:"env_file"`
+	Volumes []interface{} `yaml:"volumes"`
+	Extends interface{}   `yaml:"extends"`
+}
+
+type composeFileRef struct {
+	File string `yaml:"file"`
+}
+
+func (v *deployValidator) validateComposeFile(path string, runtimeVars []string, envSets []envSet, checkPaths bool) {
+	d, err := os.ReadFile(path)
+	if err != nil {
+		v.report(path, "can't read Docker Compose file: %v", err)
+		return
+	}
+
+	var doc yaml.Node
+	err = yaml.Unmarshal(d, &doc)
+	if err != nil {
+		v.report(path, "can't parse Docker Compose file: %v", err)
+		return
+	}
+
+	dir := filepath.Dir(path)
+	dotEnv, err := readDotEnv(filepath.Join(dir, ".env"))
+	if err != nil {
+		v.report(path, "can't read .env file: %v", err)
+	}
+
+	for _, set := range envSets {
+		lookup := func(name string) (string, bool) {
+			for _, runtimeVar := range runtimeVars {
+				if name == runtimeVar {
+					return runtimeValue, true
+				}
+			}
+			if value, found := set.env[name]; found {
+				return value, true
+			}
+			if value, found := dotEnv[name]; found {
+				return value, true
+			}
+			return os.LookupEnv(name)
+		}
+
+		var missing []string
+		interpolated := interpolateNode(&doc, lookup, &missing)
+		for _, name := range uniqueStrings(missing) {
+			if set.variant != "" {
+				v.report(path, "variable %q is not set (variant: %s)", name, set.variant)
+			} else {
+				v.report(path, "variable %q is not set", name)
+			}
+		}
+
+		if !checkPaths {
+			continue
+		}
+
+		var config composeFile
+		err = interpolated.Decode(&config)
+		if err != nil {
+			v.report(path, "can't decode Docker Compose file: %v", err)
+			return
+		}
+		v.validateComposePaths(path, config)
+	}
+}
+
+func (v *deployValidator) validateComposePaths(path string, config composeFile) {
+	dir := filepath.Dir(path)
+	checkPath := func(kind, p string) {
+		if p == "" || strings.Contains(p, runtimeValue) {
+			return
+		}
+		fullPath := p
+		if !filepath.IsAbs(p) {
+			fullPath = filepath.Join(dir, p)
+		}
+		if _, err := os.Stat(fullPath); err != nil {
+			v.report(path, "%s not found (path: %s)", kind, p)
+		}
+	}
+
+	names := make([]string, 0, len(config.Services))
+	for name := range config.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		service := config.Services[name]
+		switch build := service.Build.(type) {
+		case string:
+			checkPath(fmt.Sprintf("build context of service %q", name), build)
+			if !isRemoteBuildContext(build) {
+				checkPath(fmt.Sprintf("Dockerfile of service %q", name), filepath.Join(build, "Dockerfile"))
+			}
+		case map[string]interface{}:
+			context, _ := build["context"].(string)
+			if context == "" {
+				context = "."
+			}
+			if isRemoteBuildContext(context) {
+				break
+			}
+			checkPath(fmt.Sprintf("build context of service %q", name), context)
+			if _, inline := build["dockerfile_inline"]; !inline {
+				dockerfile, _ := build["dockerfile"].(string)
+				if dockerfile == "" {
+					dockerfile = "Dockerfile"
+				}
+				if !filepath.IsAbs(dockerfile) {
+					dockerfile = filepath.Join(context, dockerfile)
+				}
+				checkPath(fmt.Sprintf("Dockerfile of service %q", name), dockerfile)
+			}
+		}
+
+		switch envFile := service.EnvFile.(type) {
+		case string:
+			checkPath(fmt.Sprintf("env file of service %q", name), envFile)
+		case []interface{}:
+			for _, entry := range envFile {
+				switch entry := entry.(type) {
+				case string:
+					checkPath(fmt.Sprintf("env file of service %q", name), entry)
+				case map[string]interface{}:
+					if required, ok := entry["required"].(bool); ok && !required {
+						continue
+					}
+					p, _ := entry["path"].(string)
+					checkPath(fmt.Sprintf("env file of service %q", name), p)
+				}
+			}
+		}
+
+		for _, volume := range service.Volumes {
+			switch volume := volume.(type) {
+			case string:
+				source, _, found := strings.Cut(volume, ":")
+				if found && isRelativePath(source) {
+					checkPath(fmt.Sprintf("volume of service %q", name), source)
+				}
+			case map[string]interface{}:
+				source, _ := volume["source"].(string)
+				if volume["type"] == "bind" && isRelativePath(source) {
+					checkPath(fmt.Sprintf("volume of service %q", name), source)
+				}
+			}
+		}
+
+		if extends, ok := service.Extends.(map[string]interface{}); ok {
+			file, _ := extends["file"].(string)
+			checkPath(fmt.Sprintf("extended file of service %q", name), file)
+		}
+	}
+
+	for _, refs := range []struct {
+		kind string
+		refs map[string]composeFileRef
+	}{{"config", config.Configs}, {"secret", config.Secrets}} {
+		for name, ref := range refs.refs {
+			checkPath(fmt.Sprintf("%s %q", refs.kind, name), ref.File)
+		}
+	}
+}
+
+func isRelativePath(p string) bool {
+	return p == "." || p == ".." || strings.HasPrefix(p, "./") || strings.HasPrefix(p, "../")
+}
+
+func isRemoteBuildContext(context string) bool {
+	return strings.Contains(context, "://") || strings.HasPrefix(context, "git@")
+}
+
+func (v *deployValidator) validateKubernetesDefinitions(definitionsDir string) {
+	definitionPaths, err := findKubernetesDefinitions(definitionsDir)
+	if err != nil {
+		v.report(definitionsDir, "%v", err)
+		return
+	}
+
+	for _, path := range definitionPaths {
+		f, err := os.Open(path)
+		if err != nil {
+			v.report(path, "can't open Kubernetes definition: %v", err)
+			continue
+		}
+		dec := yaml.NewDecoder(f)
+		for {
+			var doc yaml.Node
+			err := dec.Decode(&doc)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				v.report(path, "can't parse Kubernetes definition: %v", err)
+				break
+			}
+		}
+		f.Close()
+	}
+}
+
+func (v *deployValidator) validateTerraformDefinitions(definitionsDir string) {
+	tfFiles, err := filepath.Glob(filepath.Join(definitionsDir, "*.tf"))
+	if err != nil {
+		v.report(definitionsDir, "can't find Terraform files: %v", err)
+		return
+	}
+	if len(tfFiles) == 0 {
+		v.report(definitionsDir, "no Terraform files found")
+	}
+
+	for _, path := range tfFiles {
+		d, err := os.ReadFile(path)
+		if err != nil {
+			v.report(path, "can't read Terraform file: %v", err)
+			continue
+		}
+		for _, match := range terraformPathPattern.FindAllSubmatch(d, -1) {
+			p := strings.TrimPrefix(string(match[1]), "${path.module}/")
+			if strings.Contains(p, "${") {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(definitionsDir, p)); err != nil {
+				v.report(path, "referenced file not found (path: %s)", p)
+			}
+		}
+	}
+
+	// Paths in env.yml are resolved relative to the Terraform deployer configuration, so
+	// only its variables are checked.
+	envYmlPath := filepath.Join(definitionsDir, envYmlFile)
+	if _, err := os.Stat(envYmlPath); err == nil {
+		v.validateComposeFile(envYmlPath, terraformRuntimeVars, []envSet{{}}, false)
+	}
+}
+
+// interpolateNode returns a copy of the given YAML node with the variables in its scalar values
+// replaced. Names of required variables that cannot be resolved are appended to missing.
+func interpolateNode(node *yaml.Node, lookup func(string) (string, bool), missing *[]string) *yaml.Node {
+	result := *node
+	if node.Kind == yaml.ScalarNode && node.Tag != "!!binary" {
+		result.Value = interpolate(node.Value, lookup, missing)
+		if result.Value != node.Value && result.Tag != "!!str" {
+			result.Tag = ""
+		}
+		return &result
+	}
+
+	result.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		// Keys of mappings are not interpolated.
+		if node.Kind == yaml.MappingNode && i%2 == 0 {
+			result.Content[i] = child
+			continue
+		}
+		result.Content[i] = interpolateNode(child, lookup, missing)
+	}
+	return &result
+}
+
+// interpolate replaces the variable placeholders in s following the rules of Docker Compose.
+func interpolate(s string, lookup func(string) (string, bool), missing *[]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(s, func(placeholder string) string {
+		m := placeholderPattern.FindStringSubmatch(placeholder)
+		if m[1] != "" {
+			return "$"
+		}
+		name, op, arg := m[2], m[3], m[4]
+		if name == "" {
+			name = m[5]
+		}
+
+		value, found := lookup(name)
+		switch op {
+		case ":-":
+			if !found || value == "" {
+				return interpolate(arg, lookup, missing)
+			}
+		case "-":
+			if !found {
+				return interpolate(arg, lookup, missing)
+			}
+		case ":+":
+			if found && value != "" {
+				return interpolate(arg, lookup, missing)
+			}
+			return ""
+		case "+":
+			if found {
+				return interpolate(arg, lookup, missing)
+			}
+			return ""
+		case ":?":
+			if !found || value == "" {
+				*missing = append(*missing, name)
+			}
+		default:
+			if !found {
+				*missing = append(*missing, name)
+			}
+		}
+		return value
+	})
+}
+
+// readDotEnv reads the variables defined in a .env file, if it exists.
+func readDotEnv(path string) (map[string]string, error) {
+	d, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	env := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(d))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !found {
+			continue
+		}
+		env[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return env, scanner.Err()
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	var result []string
+	for _, value := range values {
+		if _, found := seen[value]; found {
+			continue
+		}
+		seen[value] = struct{}{}
+		result = append(result, value)
+	}
+	return result
+}
@@ -0,0 +1,80 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package servicedeployer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/multierror"
+)
+
+func TestValidateDevDeployConfigs(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		err := ValidateDevDeployConfigs("testdata/deploy-configs/valid")
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		err := ValidateDevDeployConfigs("testdata/deploy-configs/invalid")
+		require.Error(t, err)
+
+		var errs multierror.Error
+		require.ErrorAs(t, err, &errs)
+
+		var messages []string
+		for _, err := range errs {
+			messages = append(messages, err.Error())
+		}
+		assert.ElementsMatch(t, []string{
+			`_dev/deploy/docker/docker-compose.yml: Dockerfile of service "service" not found (path: build/Dockerfile)`,
+			`_dev/deploy/docker/docker-compose.yml: build context of service "service" not found (path: ./build)`,
+			`_dev/deploy/docker/docker-compose.yml: env file of service "service" not found (path: ./service.env)`,
+			`_dev/deploy/docker/docker-compose.yml: variable "SERVICE_VERSION" is not set (variant: v2)`,
+			`_dev/deploy/docker/docker-compose.yml: volume of service "service" not found (path: ./file)`,
+			`data_stream/logs/_dev/deploy/tf/main.tf: referenced file not found (path: ./files/example.log)`,
+		}, messages)
+	})
+}
+
+func TestInterpolate(t *testing.T) {
+	lookup := func(name string) (string, bool) {
+		switch name {
+		case "SET":
+			return "value", true
+		case "EMPTY":
+			return "", true
+		}
+		return "", false
+	}
+
+	cases := []struct {
+		value    string
+		expected string
+		missing  []string
+	}{
+		{value: "plain", expected: "plain"},
+		{value: "$SET/${SET}", expected: "value/value"},
+		{value: "$$SET", expected: "$SET"},
+		{value: "${UNSET:-default}", expected: "default"},
+		{value: "${EMPTY:-default}", expected: "default"},
+		{value: "${EMPTY-default}", expected: ""},
+		{value: "${SET:+alternative}", expected: "alternative"},
+		{value: "${UNSET+alternative}", expected: ""},
+		{value: "${UNSET}", expected: "", missing: []string{"UNSET"}},
+		{value: "${EMPTY:?required}", expected: "", missing: []string{"EMPTY"}},
+		{value: "${UNSET:-$SET}", expected: "value"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.value, func(t *testing.T) {
+			var missing []string
+			assert.Equal(t, c.expected, interpolate(c.value, lookup, &missing))
+			assert.Equal(t, c.missing, missing)
+		})
+	}
+}
@@ -42,6 +42,7 @@ func BootUp(ctx context.Context, options Options) error {
 		ElasticsearchPassword: elasticsearchPassword,
 		KibanaHost:            "https://127.0.0.1:5601",
 		CACertFile:            options.Profile.Path(CACertificateFile),
+		StackVersion:          options.StackVersion,
 	}
 	printUserConfig(options.Printer, config)
 
@@ -80,11 +81,13 @@ func BootUp(ctx context.Context, options Options) error {
 		return fmt.Errorf("creating stack files failed: %w", err)
 	}
 
+	options.Progress.Step("Building Docker images")
 	err = dockerComposeBuild(ctx, options)
 	if err != nil {
 		return fmt.Errorf("building docker images failed: %w", err)
 	}
 
+	options.Progress.Step("Starting services")
 	err = dockerComposeUp(ctx, options)
 	if err != nil {
 		// At least starting on 8.6.0, fleet-server may be reconfigured or
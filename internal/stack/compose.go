@@ -7,11 +7,13 @@ package stack
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/elastic/elastic-package/internal/compose"
 	"github.com/elastic/elastic-package/internal/docker"
 	"github.com/elastic/elastic-package/internal/install"
+	"github.com/elastic/elastic-package/internal/logger"
 )
 
 type ServiceStatus struct {
@@ -135,6 +137,8 @@ func dockerComposeUp(ctx context.Context, options Options) error {
 }
 
 func dockerComposeDown(ctx context.Context, options Options) error {
+	options.StackVersion = stackVersionOrBooted(options.Profile, options.StackVersion)
+
 	c, err := compose.NewProject(DockerComposeProjectName(options.Profile), options.Profile.Path(ProfileStackPath, ComposeFile))
 	if err != nil {
 		return fmt.Errorf("could not create docker compose project: %w", err)
@@ -160,13 +164,35 @@ func dockerComposeDown(ctx context.Context, options Options) error {
 	return nil
 }
 
+// serviceDependencies are the services each service of the stack needs to work, as declared
+// in the docker-compose file.
+var serviceDependencies = map[string][]string{
+	"elasticsearch":    nil,
+	"package-registry": nil,
+	"kibana":           {"elasticsearch", "package-registry"},
+	"fleet-server":     {"elasticsearch", "kibana"},
+	"elastic-agent":    {"fleet-server"},
+	"logstash":         {"elasticsearch"},
+}
+
+// withDependentServices returns the given services with all the services they depend on, so
+// they are also started and their readiness is checked.
 func withDependentServices(services []string) []string {
-	for _, aService := range services {
-		if aService == "elastic-agent" {
-			return []string{} // elastic-agent service requires to load all other services
+	var result []string
+	pending := slices.Clone(services)
+	for len(pending) > 0 {
+		aService := pending[0]
+		pending = pending[1:]
+		if slices.Contains(result, aService) {
+			continue
 		}
+		result = append(result, aService)
+		pending = append(pending, serviceDependencies[aService]...)
+	}
+	if len(result) > len(services) {
+		logger.Debugf("Services required by the selected ones are also started: %s", strings.Join(result[len(services):], ", "))
 	}
-	return services
+	return result
 }
 
 func withIsReadyServices(services []string) []string {
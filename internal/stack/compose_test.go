@@ -5,6 +5,7 @@
 package stack
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -167,3 +168,23 @@ func TestNewServiceStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestWithDependentServices(t *testing.T) {
+	cases := []struct {
+		services []string
+		expected []string
+	}{
+		{nil, nil},
+		{[]string{"elasticsearch"}, []string{"elasticsearch"}},
+		{[]string{"kibana"}, []string{"kibana", "elasticsearch", "package-registry"}},
+		{[]string{"elasticsearch", "kibana"}, []string{"elasticsearch", "kibana", "package-registry"}},
+		{[]string{"elastic-agent"}, []string{"elastic-agent", "fleet-server", "elasticsearch", "kibana", "package-registry"}},
+		{[]string{"logstash"}, []string{"logstash", "elasticsearch"}},
+	}
+
+	for _, c := range cases {
+		t.Run(strings.Join(c.services, ","), func(t *testing.T) {
+			assert.Equal(t, c.expected, withDependentServices(c.services))
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/elastic/elastic-package/internal/install"
 	"github.com/elastic/elastic-package/internal/profile"
 )
 
@@ -23,8 +24,12 @@ type Config struct {
 	ElasticsearchHost     string `json:"elasticsearch_host,omitempty"`
 	ElasticsearchUsername string `json:"elasticsearch_username,omitempty"`
 	ElasticsearchPassword string `json:"elasticsearch_password,omitempty"`
+	ElasticsearchAPIKey   string `json:"elasticsearch_api_key,omitempty"`
 	KibanaHost            string `json:"kibana_host,omitempty"`
 	CACertFile            string `json:"ca_cert_file,omitempty"`
+
+	// StackVersion is the version of the stack booted up, when managed by elastic-package.
+	StackVersion string `json:"stack_version,omitempty"`
 }
 
 func configPath(profile *profile.Profile) string {
@@ -62,6 +67,19 @@ func LoadConfig(profile *profile.Profile) (Config, error) {
 	return config, nil
 }
 
+// stackVersionOrBooted returns the given stack version, or the version of the stack booted up
+// with the profile if empty, so commands operate on the same images and variant.
+func stackVersionOrBooted(profile *profile.Profile, version string) string {
+	if version != "" {
+		return version
+	}
+	config, err := LoadConfig(profile)
+	if err != nil || config.StackVersion == "" {
+		return install.DefaultStackVersion
+	}
+	return config.StackVersion
+}
+
 func storeConfig(profile *profile.Profile, config Config) error {
 	d, err := json.Marshal(config)
 	if err != nil {
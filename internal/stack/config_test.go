@@ -0,0 +1,30 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/install"
+	"github.com/elastic/elastic-package/internal/profile"
+)
+
+func TestStackVersionOrBooted(t *testing.T) {
+	p := &profile.Profile{ProfileName: "test", ProfilePath: t.TempDir()}
+
+	assert.Equal(t, install.DefaultStackVersion, stackVersionOrBooted(p, ""))
+	assert.Equal(t, "8.12.2", stackVersionOrBooted(p, "8.12.2"))
+
+	config := defaultConfig(p)
+	config.StackVersion = "8.11.4"
+	err := storeConfig(p, config)
+	require.NoError(t, err)
+
+	assert.Equal(t, "8.11.4", stackVersionOrBooted(p, ""))
+	assert.Equal(t, "8.12.2", stackVersionOrBooted(p, "8.12.2"))
+}
@@ -8,10 +8,15 @@ import (
 	"github.com/elastic/elastic-package/internal/profile"
 )
 
+// elasticsearchAPIKeyProfileConfig is the profile setting with the API key used to connect to
+// the stack services.
+const elasticsearchAPIKeyProfileConfig = "stack.elasticsearch_api_key"
+
 type InitConfig struct {
 	ElasticsearchHostPort string
 	ElasticsearchUsername string
 	ElasticsearchPassword string
+	ElasticsearchAPIKey   string
 	KibanaHostPort        string
 	CACertificatePath     string
 }
@@ -22,10 +27,17 @@ func StackInitConfig(profile *profile.Profile) (*InitConfig, error) {
 		return nil, err
 	}
 
+	// The API key can be set in the profile configuration when it is not provided by the stack.
+	apiKey := config.ElasticsearchAPIKey
+	if apiKey == "" {
+		apiKey = profile.Config(elasticsearchAPIKeyProfileConfig, "")
+	}
+
 	return &InitConfig{
 		ElasticsearchHostPort: config.ElasticsearchHost,
 		ElasticsearchUsername: config.ElasticsearchUsername,
 		ElasticsearchPassword: config.ElasticsearchPassword,
+		ElasticsearchAPIKey:   apiKey,
 		KibanaHostPort:        config.KibanaHost,
 		CACertificatePath:     config.CACertFile,
 	}, nil
@@ -17,7 +17,8 @@ import (
 )
 
 func dockerComposeLogsSince(ctx context.Context, serviceName string, profile *profile.Profile, since time.Time) ([]byte, error) {
-	appConfig, err := install.Configuration(install.OptionWithStackVersion(install.DefaultStackVersion))
+	stackVersion := stackVersionOrBooted(profile, "")
+	appConfig, err := install.Configuration(install.OptionWithStackVersion(stackVersion))
 	if err != nil {
 		return nil, fmt.Errorf("can't read application configuration: %w", err)
 	}
@@ -32,7 +33,7 @@ func dockerComposeLogsSince(ctx context.Context, serviceName string, profile *pr
 	opts := compose.CommandOptions{
 		Env: newEnvBuilder().
 			withEnvs(appConfig.StackImageRefs().AsEnv()).
-			withEnv(stackVariantAsEnv(install.DefaultStackVersion)).
+			withEnv(stackVariantAsEnv(stackVersion)).
 			withEnvs(profile.ComposeEnvVars()).
 			build(),
 		Services: []string{serviceName},
@@ -6,6 +6,7 @@ package stack
 
 import (
 	"github.com/elastic/elastic-package/internal/profile"
+	"github.com/elastic/elastic-package/internal/progress"
 )
 
 // Options defines available image booting options.
@@ -17,4 +18,7 @@ type Options struct {
 
 	Profile *profile.Profile
 	Printer Printer
+
+	// Progress reports the progress of long-running operations, it can be nil.
+	Progress *progress.Reporter
 }
@@ -93,7 +93,9 @@ func (sp *serverlessProvider) createProject(ctx context.Context, settings projec
 	}
 
 	logger.Debug("Waiting for creation plan to be completed")
+	done := options.Progress.Wait("Waiting for project %s to be initialized", project.Name)
 	err = sp.client.EnsureProjectInitialized(ctx, project)
+	done()
 	if err != nil {
 		return Config{}, fmt.Errorf("project not initialized: %w", err)
 	}
@@ -117,7 +119,9 @@ func (sp *serverlessProvider) createProject(ctx context.Context, settings projec
 		return Config{}, fmt.Errorf("failed to store config: %w", err)
 	}
 
+	done = options.Progress.Wait("Waiting for services to be healthy")
 	err = project.EnsureHealthy(ctx, sp.elasticsearchClient, sp.kibanaClient)
+	done()
 	if err != nil {
 		return Config{}, fmt.Errorf("not all services are healthy: %w", err)
 	}
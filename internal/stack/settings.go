@@ -0,0 +1,67 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stack
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/profile"
+)
+
+// profileSettingsDefaults are the default values of the profile settings used by the stack.
+var profileSettingsDefaults = map[string]string{
+	configAPMEnabled:         "false",
+	configGeoIPDir:           "./ingest-geoip",
+	configKibanaHTTP2Enabled: "true",
+	configLogsDBEnabled:      "false",
+	configLogstashEnabled:    "false",
+	configSelfMonitorEnabled: "false",
+}
+
+// EffectiveSettings returns the settings used by the stack commands with the given profile. They
+// include the settings of the profile configuration file, with their defaults, and the connection
+// settings of the stack, prefixed by "connection.". Paths inside the profile directory are made
+// relative to it, so they can be compared between profiles. Passwords are not included.
+func EffectiveSettings(profile *profile.Profile) (map[string]string, error) {
+	settings := make(map[string]string)
+	for name, value := range profileSettingsDefaults {
+		settings[name] = value
+	}
+	for name, value := range profile.Settings() {
+		settings[name] = value
+	}
+
+	config, err := LoadConfig(profile)
+	if err != nil {
+		return nil, err
+	}
+	connection := map[string]string{
+		"provider":               config.Provider,
+		"elasticsearch_host":     config.ElasticsearchHost,
+		"elasticsearch_username": config.ElasticsearchUsername,
+		"kibana_host":            config.KibanaHost,
+		"ca_cert_file":           profileRelativePath(profile, config.CACertFile),
+		"stack_version":          config.StackVersion,
+	}
+	for name, value := range config.Parameters {
+		connection["parameters."+name] = value
+	}
+	for name, value := range connection {
+		if value != "" {
+			settings["connection."+name] = value
+		}
+	}
+
+	return settings, nil
+}
+
+func profileRelativePath(profile *profile.Profile, path string) string {
+	rel, err := filepath.Rel(profile.ProfilePath, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.Join("<profile>", rel)
+}
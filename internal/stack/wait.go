@@ -0,0 +1,134 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/elastic/elastic-package/internal/logger"
+	"github.com/elastic/elastic-package/internal/profile"
+)
+
+// ErrServicesNotReady is returned when some services are not ready before the timeout.
+var ErrServicesNotReady = errors.New("services not ready")
+
+// ServiceHealth is the result of checking the health of a service of the stack.
+type ServiceHealth struct {
+	Name    string
+	Ready   bool
+	Message string
+}
+
+// healthProbe checks the health of a service, it returns an error while it is not ready.
+type healthProbe struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// WaitReady waits till Elasticsearch, Kibana and Fleet are ready, or the timeout elapses. Kibana
+// and Fleet are only checked if they are running. It returns the last health of each service, and
+// ErrServicesNotReady if some of them are not ready before the timeout.
+func WaitReady(ctx context.Context, options Options, timeout time.Duration) ([]ServiceHealth, error) {
+	status, err := Status(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check status of the stack: %w", err)
+	}
+	var running []string
+	for _, service := range status {
+		running = append(running, service.Name)
+	}
+	isRunning := func(name string) bool {
+		// Without local services, check all of them, as the stack can be running elsewhere.
+		return len(running) == 0 || slices.Contains(running, name)
+	}
+
+	probes := []healthProbe{
+		{name: "elasticsearch", check: elasticsearchHealthCheck(options.Profile)},
+	}
+	if isRunning("kibana") {
+		probes = append(probes, healthProbe{name: "kibana", check: kibanaHealthCheck(options.Profile)})
+	}
+	if isRunning("fleet-server") {
+		probes = append(probes, healthProbe{name: "fleet-server", check: fleetHealthCheck(options.Profile)})
+	}
+
+	return waitReady(ctx, probes, timeout, 5*time.Second)
+}
+
+func waitReady(ctx context.Context, probes []healthProbe, timeout, interval time.Duration) ([]ServiceHealth, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	health := make([]ServiceHealth, len(probes))
+	for i, probe := range probes {
+		health[i] = ServiceHealth{Name: probe.name, Message: "not checked"}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		ready := true
+		for i, probe := range probes {
+			if health[i].Ready {
+				continue
+			}
+			err := probe.check(ctx)
+			if err != nil {
+				logger.Debugf("Service %s is not ready yet: %v", probe.name, err)
+				health[i].Message = err.Error()
+				ready = false
+				continue
+			}
+			health[i] = ServiceHealth{Name: probe.name, Ready: true, Message: "ready"}
+		}
+		if ready {
+			return health, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return health, fmt.Errorf("%w after %s", ErrServicesNotReady, timeout)
+			}
+			return health, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func elasticsearchHealthCheck(profile *profile.Profile) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		client, err := NewElasticsearchClientFromProfile(profile)
+		if err != nil {
+			return err
+		}
+		return client.CheckHealth(ctx)
+	}
+}
+
+func kibanaHealthCheck(profile *profile.Profile) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		client, err := NewKibanaClientFromProfile(profile)
+		if err != nil {
+			return err
+		}
+		return client.CheckHealth(ctx)
+	}
+}
+
+func fleetHealthCheck(profile *profile.Profile) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		client, err := NewKibanaClientFromProfile(profile)
+		if err != nil {
+			return err
+		}
+		_, err = client.ListAgents(ctx)
+		return err
+	}
+}
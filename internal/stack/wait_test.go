@@ -0,0 +1,54 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stack
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitReady(t *testing.T) {
+	readyAfter := func(attempts int) func(context.Context) error {
+		count := 0
+		return func(context.Context) error {
+			count++
+			if count < attempts {
+				return errors.New("starting")
+			}
+			return nil
+		}
+	}
+
+	t.Run("ready", func(t *testing.T) {
+		probes := []healthProbe{
+			{name: "elasticsearch", check: readyAfter(1)},
+			{name: "kibana", check: readyAfter(3)},
+		}
+		health, err := waitReady(context.Background(), probes, time.Minute, time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, []ServiceHealth{
+			{Name: "elasticsearch", Ready: true, Message: "ready"},
+			{Name: "kibana", Ready: true, Message: "ready"},
+		}, health)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		probes := []healthProbe{
+			{name: "elasticsearch", check: readyAfter(1)},
+			{name: "fleet-server", check: func(context.Context) error { return errors.New("no agents API") }},
+		}
+		health, err := waitReady(context.Background(), probes, 20*time.Millisecond, time.Millisecond)
+		require.ErrorIs(t, err, ErrServicesNotReady)
+		assert.Equal(t, []ServiceHealth{
+			{Name: "elasticsearch", Ready: true, Message: "ready"},
+			{Name: "fleet-server", Ready: false, Message: "no agents API"},
+		}, health)
+	})
+}
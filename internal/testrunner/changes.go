@@ -0,0 +1,204 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package testrunner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+// PackageChanges describes the changes in a package since a git reference.
+type PackageChanges struct {
+	// Changed is true if there are changes that require testing the package.
+	Changed bool
+
+	// DataStreams contains the changed data streams. It is empty when all the data streams
+	// have to be tested, because files shared by all of them changed.
+	DataStreams []string
+}
+
+// FindPackageChanges looks for the changes in the package since the common ancestor of the given
+// git reference and the current commit, including uncommitted changes. Changes in files of the
+// package out of its data streams, or in files of the repository that don't belong to any package,
+// are considered to affect all the data streams. Changes in other packages are ignored.
+func FindPackageChanges(packageRoot, baseRef string) (*PackageChanges, error) {
+	repo, err := git.PlainOpenWithOptions(packageRoot, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repository of package: %w", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working tree of git repository: %w", err)
+	}
+	files, err := changedFiles(repo, wt, baseRef)
+	if err != nil {
+		return nil, err
+	}
+	return packageChangesFromFiles(wt.Filesystem.Root(), packageRoot, files)
+}
+
+// changedFiles returns the paths, relative to the root of the repository, of the files changed since
+// the common ancestor of the base reference and the current commit, and the uncommitted changes.
+func changedFiles(repo *git.Repository, wt *git.Worktree, baseRef string) ([]string, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(baseRef))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve git reference %q: %w", baseRef, err)
+	}
+	baseCommit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit for git reference %q: %w", baseRef, err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current commit: %w", err)
+	}
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current commit: %w", err)
+	}
+
+	// Compare with the common ancestor, so changes done in the base reference since
+	// the current branch was created are not included.
+	mergeBases, err := baseCommit.MergeBase(headCommit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find common ancestor of %q and current commit: %w", baseRef, err)
+	}
+	if len(mergeBases) > 0 {
+		baseCommit = mergeBases[0]
+	}
+
+	baseTree, err := baseCommit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree for git reference %q: %w", baseRef, err)
+	}
+	headTree, err := headCommit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree for current commit: %w", err)
+	}
+	changes, err := object.DiffTree(baseTree, headTree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare current commit with %q: %w", baseRef, err)
+	}
+
+	var files []string
+	for _, change := range changes {
+		for _, name := range []string{change.From.Name, change.To.Name} {
+			if name != "" && !slices.Contains(files, name) {
+				files = append(files, name)
+			}
+		}
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status of working tree: %w", err)
+	}
+	for name, fileStatus := range status {
+		if fileStatus.Staging == git.Unmodified && fileStatus.Worktree == git.Unmodified {
+			continue
+		}
+		if !slices.Contains(files, name) {
+			files = append(files, name)
+		}
+	}
+
+	return files, nil
+}
+
+// packageChangesFromFiles classifies the changed files, with paths relative to the root of the
+// repository, to find the changes that affect the package.
+func packageChangesFromFiles(repositoryRoot, packageRoot string, files []string) (*PackageChanges, error) {
+	repositoryRoot, err := filepath.EvalSymlinks(repositoryRoot)
+	if err != nil {
+		return nil, err
+	}
+	packageRoot, err = filepath.Abs(packageRoot)
+	if err != nil {
+		return nil, err
+	}
+	packageRoot, err = filepath.EvalSymlinks(packageRoot)
+	if err != nil {
+		return nil, err
+	}
+	packagePath, err := filepath.Rel(repositoryRoot, packageRoot)
+	if err != nil {
+		return nil, fmt.Errorf("package %s is not in repository %s: %w", packageRoot, repositoryRoot, err)
+	}
+
+	var changes PackageChanges
+	allDataStreams := false
+	for _, file := range files {
+		file = filepath.FromSlash(file)
+		relPath, err := filepath.Rel(packagePath, file)
+		if err == nil && relPath != ".." && !strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			changes.Changed = true
+			dataStream, found := dataStreamOfPackageFile(relPath)
+			if !found {
+				allDataStreams = true
+			} else if _, err := os.Stat(filepath.Join(packageRoot, "data_stream", dataStream)); err != nil {
+				// Removed data stream, there is nothing to test on it, but its removal could
+				// affect the rest of the package.
+				allDataStreams = true
+			} else if !slices.Contains(changes.DataStreams, dataStream) {
+				changes.DataStreams = append(changes.DataStreams, dataStream)
+			}
+			continue
+		}
+
+		inPackage, err := isInSomePackage(repositoryRoot, file)
+		if err != nil {
+			return nil, err
+		}
+		if !inPackage {
+			// Shared file of the repository, it could affect any package.
+			changes.Changed = true
+			allDataStreams = true
+		}
+	}
+
+	if allDataStreams {
+		changes.DataStreams = nil
+	}
+	slices.Sort(changes.DataStreams)
+	return &changes, nil
+}
+
+// dataStreamOfPackageFile returns the data stream containing the file, given its path
+// relative to the package root.
+func dataStreamOfPackageFile(path string) (string, bool) {
+	parts := strings.Split(path, string(filepath.Separator))
+	if len(parts) < 3 || parts[0] != "data_stream" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// isInSomePackage checks if the file, given its path relative to the root of the repository,
+// belongs to any package of the repository. Files don't need to exist, as they could have been
+// deleted, their closest existing directory is used.
+func isInSomePackage(repositoryRoot, file string) (bool, error) {
+	dir := filepath.Dir(filepath.Join(repositoryRoot, file))
+	packageRoot, found, err := packages.FindPackageRootFrom(dir)
+	if err != nil {
+		return false, fmt.Errorf("failed to find package of %s: %w", file, err)
+	}
+	if !found {
+		return false, nil
+	}
+	relPath, err := filepath.Rel(repositoryRoot, packageRoot)
+	if err != nil {
+		return false, nil
+	}
+	return relPath != ".." && !strings.HasPrefix(relPath, ".."+string(filepath.Separator)), nil
+}
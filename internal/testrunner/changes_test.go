@@ -0,0 +1,85 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package testrunner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackageChangesFromFiles(t *testing.T) {
+	repositoryRoot := t.TempDir()
+	writePackage := func(name string) {
+		dir := filepath.Join(repositoryRoot, "packages", name)
+		for _, dataStream := range []string{"access", "error"} {
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, "data_stream", dataStream), 0755))
+		}
+		manifest := "format_version: 3.0.0\nname: " + name + "\ntitle: Test\nversion: 1.0.0\ntype: integration\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "manifest.yml"), []byte(manifest), 0644))
+	}
+	writePackage("nginx")
+	writePackage("apache")
+	packageRoot := filepath.Join(repositoryRoot, "packages", "nginx")
+
+	cases := []struct {
+		title    string
+		files    []string
+		expected PackageChanges
+	}{
+		{
+			title:    "no changes",
+			expected: PackageChanges{},
+		},
+		{
+			title:    "changes in other package",
+			files:    []string{"packages/apache/manifest.yml", "packages/apache/data_stream/access/fields/fields.yml"},
+			expected: PackageChanges{},
+		},
+		{
+			title: "changes in data streams",
+			files: []string{
+				"packages/nginx/data_stream/error/fields/fields.yml",
+				"packages/nginx/data_stream/access/elasticsearch/ingest_pipeline/default.yml",
+				"packages/nginx/data_stream/access/_dev/test/pipeline/test-access.log",
+			},
+			expected: PackageChanges{Changed: true, DataStreams: []string{"access", "error"}},
+		},
+		{
+			title: "changes in package files",
+			files: []string{
+				"packages/nginx/data_stream/access/fields/fields.yml",
+				"packages/nginx/manifest.yml",
+			},
+			expected: PackageChanges{Changed: true},
+		},
+		{
+			title:    "changes in shared files",
+			files:    []string{"packages/apache/manifest.yml", ".buildkite/pipeline.yml"},
+			expected: PackageChanges{Changed: true},
+		},
+		{
+			title:    "removed data stream",
+			files:    []string{"packages/nginx/data_stream/removed/manifest.yml", "packages/nginx/data_stream/access/manifest.yml"},
+			expected: PackageChanges{Changed: true},
+		},
+		{
+			title:    "deleted files in other package",
+			files:    []string{"packages/apache/data_stream/removed/manifest.yml"},
+			expected: PackageChanges{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			changes, err := packageChangesFromFiles(repositoryRoot, packageRoot, c.files)
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, *changes)
+		})
+	}
+}
@@ -12,6 +12,10 @@ import (
 type ErrTestCaseFailed struct {
 	Reason  string
 	Details string
+
+	// Errors contains the individual errors that caused the failure, if available. They are
+	// used by report formats that can provide structured details about the failure.
+	Errors []error
 }
 
 // Error returns the message detailing the test case failure.
@@ -43,7 +43,7 @@ func ReadGlobalTestConfig(packageRootPath string) (*globalTestConfig, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unable to load global test configuration file: %s: %w", configFilePath, err)
 	}
-	if err := cfg.Unpack(&c); err != nil {
+	if err := UnpackConfigStrict(cfg, &c); err != nil {
 		return nil, fmt.Errorf("unable to unpack global test configuration file: %s: %w", configFilePath, err)
 	}
 
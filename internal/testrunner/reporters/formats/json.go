@@ -0,0 +1,124 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package formats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/testrunner"
+)
+
+func init() {
+	testrunner.RegisterReporterFormat(ReportFormatJSON, reportJSONFormat)
+}
+
+const (
+	// ReportFormatJSON reports test results in a JSON format
+	ReportFormatJSON testrunner.TestReportFormat = "json"
+)
+
+const (
+	jsonStatusPass  = "pass"
+	jsonStatusFail  = "fail"
+	jsonStatusError = "error"
+	jsonStatusSkip  = "skip"
+)
+
+type jsonReport struct {
+	Tests []jsonTest `json:"tests"`
+}
+
+type jsonTest struct {
+	Name            string      `json:"name"`
+	Package         string      `json:"package"`
+	DataStream      string      `json:"data_stream,omitempty"`
+	TestType        string      `json:"test_type"`
+	DurationSeconds float64     `json:"duration_seconds"`
+	Status          string      `json:"status"`
+	Message         string      `json:"message,omitempty"`
+	Errors          []jsonError `json:"errors,omitempty"`
+}
+
+type jsonError struct {
+	Message string           `json:"message"`
+	Field   string           `json:"field,omitempty"`
+	Source  *jsonFieldSource `json:"source,omitempty"`
+}
+
+type jsonFieldSource struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+func reportJSONFormat(results []testrunner.TestResult) (string, error) {
+	report := jsonReport{
+		Tests: make([]jsonTest, 0, len(results)),
+	}
+	for _, r := range results {
+		test := jsonTest{
+			Name:            r.Name,
+			Package:         r.Package,
+			DataStream:      r.DataStream,
+			TestType:        string(r.TestType),
+			DurationSeconds: r.TimeElapsed.Seconds(),
+		}
+
+		switch {
+		case r.ErrorMsg != "":
+			test.Status = jsonStatusError
+			test.Message = r.ErrorMsg
+			test.Errors = []jsonError{{Message: r.ErrorMsg}}
+		case r.FailureMsg != "":
+			test.Status = jsonStatusFail
+			test.Message = r.FailureMsg
+			test.Errors = jsonErrors(r)
+		case r.Skipped != nil:
+			test.Status = jsonStatusSkip
+			test.Message = r.Skipped.String()
+		default:
+			test.Status = jsonStatusPass
+		}
+
+		report.Tests = append(report.Tests, test)
+	}
+
+	d, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("unable to format test results as JSON: %w", err)
+	}
+	return string(d), nil
+}
+
+// jsonErrors returns the list of errors of a failed test. Structured errors are used when
+// available, otherwise the failure details are reported as a single error.
+func jsonErrors(r testrunner.TestResult) []jsonError {
+	if len(r.FailureErrors) == 0 {
+		message := r.FailureMsg
+		if r.FailureDetails != "" {
+			message += ": " + r.FailureDetails
+		}
+		return []jsonError{{Message: message}}
+	}
+
+	errs := make([]jsonError, 0, len(r.FailureErrors))
+	for _, err := range r.FailureErrors {
+		e := jsonError{Message: err.Error()}
+		var validationErr *fields.ValidationError
+		if errors.As(err, &validationErr) {
+			e.Field = validationErr.Field
+			if validationErr.Source != nil {
+				e.Source = &jsonFieldSource{
+					File: validationErr.Source.File,
+					Line: validationErr.Source.Line,
+				}
+			}
+		}
+		errs = append(errs, e)
+	}
+	return errs
+}
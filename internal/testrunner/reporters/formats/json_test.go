@@ -0,0 +1,126 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package formats
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/testrunner"
+)
+
+func TestReportJSONFormat(t *testing.T) {
+	results := []testrunner.TestResult{
+		{
+			Name:        "test-success",
+			Package:     "apache",
+			TestType:    "pipeline",
+			DataStream:  "access",
+			TimeElapsed: 1500 * time.Millisecond,
+		},
+		{
+			Name:           "test-fields",
+			Package:        "apache",
+			TestType:       "pipeline",
+			DataStream:     "access",
+			TimeElapsed:    time.Second,
+			FailureMsg:     "test case failed: one or more problems with fields found in documents",
+			FailureDetails: "[0] field \"foo\" is undefined\n[1] field \"bar\" is not a number",
+			FailureErrors: []error{
+				errors.New(`field "foo" is undefined`),
+				&fields.ValidationError{
+					Field:  "bar",
+					Source: &fields.SourceLocation{File: "fields/fields.yml", Line: 12},
+					Err:    errors.New(`field "bar" is not a number`),
+				},
+			},
+		},
+		{
+			Name:           "test-failure",
+			Package:        "apache",
+			TestType:       "pipeline",
+			DataStream:     "error",
+			FailureMsg:     "test case failed: expected results don't match",
+			FailureDetails: "diff",
+		},
+		{
+			Name:     "test-error",
+			Package:  "apache",
+			TestType: "system",
+			ErrorMsg: "can't start service",
+		},
+	}
+
+	expected := `{
+  "tests": [
+    {
+      "name": "test-success",
+      "package": "apache",
+      "data_stream": "access",
+      "test_type": "pipeline",
+      "duration_seconds": 1.5,
+      "status": "pass"
+    },
+    {
+      "name": "test-fields",
+      "package": "apache",
+      "data_stream": "access",
+      "test_type": "pipeline",
+      "duration_seconds": 1,
+      "status": "fail",
+      "message": "test case failed: one or more problems with fields found in documents",
+      "errors": [
+        {
+          "message": "field \"foo\" is undefined"
+        },
+        {
+          "message": "field \"bar\" is not a number",
+          "field": "bar",
+          "source": {
+            "file": "fields/fields.yml",
+            "line": 12
+          }
+        }
+      ]
+    },
+    {
+      "name": "test-failure",
+      "package": "apache",
+      "data_stream": "error",
+      "test_type": "pipeline",
+      "duration_seconds": 0,
+      "status": "fail",
+      "message": "test case failed: expected results don't match",
+      "errors": [
+        {
+          "message": "test case failed: expected results don't match: diff"
+        }
+      ]
+    },
+    {
+      "name": "test-error",
+      "package": "apache",
+      "test_type": "system",
+      "duration_seconds": 0,
+      "status": "error",
+      "message": "can't start service",
+      "errors": [
+        {
+          "message": "can't start service"
+        }
+      ]
+    }
+  ]
+}`
+
+	report, err := reportJSONFormat(results)
+	require.NoError(t, err)
+	assert.Equal(t, expected, report)
+}
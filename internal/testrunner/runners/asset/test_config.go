@@ -39,9 +39,15 @@ func newConfig(assetTestFolderPath string) (*testConfig, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unable to load asset loading test configuration file: %s: %w", configFilePath, err)
 	}
-	if err := cfg.Unpack(&c); err != nil {
+	if err := testrunner.UnpackConfigStrict(cfg, &c); err != nil {
 		return nil, fmt.Errorf("unable to unpack asset loading test configuration file: %s: %w", configFilePath, err)
 	}
 
 	return &c, nil
 }
+
+// ValidateConfig checks that the test configuration in the given test folder, if any, can be loaded.
+func ValidateConfig(testFolderPath string) error {
+	_, err := newConfig(testFolderPath)
+	return err
+}
@@ -175,9 +175,94 @@ func (r *tester) run(ctx context.Context) ([]testrunner.TestResult, error) {
 		results = append(results, result)
 	}
 
+	referencesResults, err := r.checkDashboardReferences(ctx, installedPackage.Name, installedAssets, expectedAssets)
+	if err != nil {
+		return result.WithError(fmt.Errorf("could not check dashboard references: %w", err))
+	}
+	results = append(results, referencesResults...)
+
+	return results, nil
+}
+
+// checkDashboardReferences checks that the objects referenced by the installed dashboards of the package
+// are also installed.
+func (r *tester) checkDashboardReferences(ctx context.Context, packageName string, installedAssets, expectedAssets []packages.Asset) ([]testrunner.TestResult, error) {
+	var requested []kibana.ExportSavedObjectsRequestObject
+	for _, e := range expectedAssets {
+		if e.Type != packages.AssetTypeKibanaDashboard.TypeName() || !findActualAsset(installedAssets, e) {
+			continue
+		}
+		requested = append(requested, kibana.ExportSavedObjectsRequestObject{ID: e.ID, Type: string(e.Type)})
+	}
+	dashboards, err := r.kibanaClient.BulkGetSavedObjects(ctx, requested)
+	if err != nil {
+		return nil, fmt.Errorf("could not get dashboards: %w", err)
+	}
+
+	var references []kibana.ExportSavedObjectsRequestObject
+	for _, dashboard := range dashboards {
+		for _, reference := range dashboard.References {
+			references = append(references, kibana.ExportSavedObjectsRequestObject{ID: reference.ID, Type: reference.Type})
+		}
+	}
+	referencedObjects, err := r.kibanaClient.BulkGetSavedObjects(ctx, references)
+	if err != nil {
+		return nil, fmt.Errorf("could not get objects referenced by dashboards: %w", err)
+	}
+
+	var results []testrunner.TestResult
+	for _, dashboard := range dashboards {
+		if dashboard.Error != nil {
+			// Not installed dashboards are already reported.
+			continue
+		}
+
+		rc := testrunner.NewResultComposer(testrunner.TestResult{
+			Name:     fmt.Sprintf("dashboard %s references are resolved", dashboard.ID),
+			Package:  packageName,
+			TestType: TestType,
+		})
+
+		var tr []testrunner.TestResult
+		if dangling := danglingReferences(dashboard, referencedObjects); len(dangling) > 0 {
+			tr, _ = rc.WithError(testrunner.ErrTestCaseFailed{
+				Reason:  fmt.Sprintf("dashboard %q references objects that are not installed", dashboard.Attributes.Title),
+				Details: formatDanglingReferences(dashboard, dangling),
+			})
+		} else {
+			tr, _ = rc.WithSuccess()
+		}
+		results = append(results, tr...)
+	}
 	return results, nil
 }
 
+// danglingReferences returns the references of the dashboard that don't resolve to any of the retrieved objects.
+func danglingReferences(dashboard kibana.SavedObject, retrieved []kibana.SavedObject) []kibana.SavedObjectReference {
+	var dangling []kibana.SavedObjectReference
+	for _, reference := range dashboard.References {
+		found := false
+		for _, o := range retrieved {
+			if o.Error == nil && o.ID == reference.ID && o.Type == reference.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			dangling = append(dangling, reference)
+		}
+	}
+	return dangling
+}
+
+func formatDanglingReferences(dashboard kibana.SavedObject, references []kibana.SavedObjectReference) string {
+	var sb strings.Builder
+	for _, reference := range references {
+		sb.WriteString(fmt.Sprintf("- dashboard %q (ID: %s) references missing %s \"%s\"\n", dashboard.Attributes.Title, dashboard.ID, reference.Type, reference.ID))
+	}
+	return sb.String()
+}
+
 func (r *tester) TearDown(ctx context.Context) error {
 	// Avoid cancellations during cleanup.
 	cleanupCtx := context.WithoutCancel(ctx)
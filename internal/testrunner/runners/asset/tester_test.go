@@ -0,0 +1,38 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package asset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/elastic-package/internal/kibana"
+)
+
+func TestDanglingReferences(t *testing.T) {
+	dashboard := kibana.SavedObject{
+		ID:   "nginx-dashboard",
+		Type: "dashboard",
+		References: []kibana.SavedObjectReference{
+			{ID: "nginx-visualization", Type: "visualization", Name: "panel_0"},
+			{ID: "nginx-search", Type: "search", Name: "panel_1"},
+			{ID: "nginx-lens", Type: "lens", Name: "panel_2"},
+			{ID: "logs-*", Type: "index-pattern", Name: "kibanaSavedObjectMeta.searchSourceJSON.index"},
+		},
+	}
+	retrieved := []kibana.SavedObject{
+		{ID: "nginx-visualization", Type: "visualization"},
+		{ID: "nginx-search", Type: "search", Error: &kibana.SavedObjectError{StatusCode: 404, Error: "Not Found"}},
+		{ID: "nginx-lens", Type: "visualization"},
+		{ID: "logs-*", Type: "index-pattern"},
+	}
+
+	dangling := danglingReferences(dashboard, retrieved)
+	assert.Equal(t, []kibana.SavedObjectReference{
+		{ID: "nginx-search", Type: "search", Name: "panel_1"},
+		{ID: "nginx-lens", Type: "lens", Name: "panel_2"},
+	}, dangling)
+}
@@ -0,0 +1,156 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package runners
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/packages"
+	"github.com/elastic/elastic-package/internal/testrunner/runners/pipeline"
+	"github.com/elastic/elastic-package/internal/testrunner/runners/system"
+)
+
+// DataStreamCoverage summarizes the tests of a data stream, and the fields exercised by the
+// documents of its pipeline tests and sample events.
+type DataStreamCoverage struct {
+	// DataStream is the name of the data stream, empty for packages without data streams.
+	DataStream      string `json:"data_stream"`
+	PipelineTests   int    `json:"pipeline_tests"`
+	SystemTests     int    `json:"system_tests"`
+	Fields          int    `json:"fields"`
+	ExercisedFields int    `json:"exercised_fields"`
+}
+
+// PackageCoverageSummary aggregates the coverage of all the data streams of a package.
+type PackageCoverageSummary struct {
+	DataStreams                  int     `json:"data_streams"`
+	DataStreamsWithPipelineTests int     `json:"data_streams_with_pipeline_tests"`
+	DataStreamsWithSystemTests   int     `json:"data_streams_with_system_tests"`
+	Fields                       int     `json:"fields"`
+	ExercisedFields              int     `json:"exercised_fields"`
+	FieldsCoverage               float64 `json:"fields_coverage"`
+}
+
+// PackageCoverage is the test coverage of a package.
+type PackageCoverage struct {
+	Package     string                 `json:"package"`
+	DataStreams []DataStreamCoverage   `json:"data_streams"`
+	Summary     PackageCoverageSummary `json:"summary"`
+}
+
+// CalculatePackageCoverage calculates the test coverage of the package without running the tests.
+// Fields are considered exercised if they are present in the expected results of the pipeline tests
+// or in the sample events. External fields are not resolved.
+func CalculatePackageCoverage(packageRootPath string) (*PackageCoverage, error) {
+	manifest, err := packages.ReadPackageManifestFromPackageRoot(packageRootPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading package manifest failed (path: %s): %w", packageRootPath, err)
+	}
+
+	dataStreamDirs, err := filepath.Glob(filepath.Join(packageRootPath, "data_stream", "*"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look for data streams: %w", err)
+	}
+	if len(dataStreamDirs) == 0 {
+		// Packages without data streams, as input packages, have their tests and fields in the root.
+		dataStreamDirs = []string{packageRootPath}
+	}
+
+	coverage := PackageCoverage{Package: manifest.Name}
+	for _, dir := range dataStreamDirs {
+		dataStreamCoverage, err := calculateDataStreamCoverage(dir)
+		if err != nil {
+			return nil, err
+		}
+		if dir != packageRootPath {
+			dataStreamCoverage.DataStream = filepath.Base(dir)
+		}
+		coverage.DataStreams = append(coverage.DataStreams, *dataStreamCoverage)
+	}
+
+	summary := &coverage.Summary
+	for _, dataStream := range coverage.DataStreams {
+		summary.DataStreams++
+		if dataStream.PipelineTests > 0 {
+			summary.DataStreamsWithPipelineTests++
+		}
+		if dataStream.SystemTests > 0 {
+			summary.DataStreamsWithSystemTests++
+		}
+		summary.Fields += dataStream.Fields
+		summary.ExercisedFields += dataStream.ExercisedFields
+	}
+	if summary.Fields > 0 {
+		summary.FieldsCoverage = float64(summary.ExercisedFields) / float64(summary.Fields)
+	}
+	return &coverage, nil
+}
+
+func calculateDataStreamCoverage(dir string) (*DataStreamCoverage, error) {
+	var coverage DataStreamCoverage
+
+	pipelineTestsPath := filepath.Join(dir, "_dev", "test", string(pipeline.TestType))
+	pipelineTests, err := pipeline.TestCaseFiles(pipelineTestsPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	coverage.PipelineTests = len(pipelineTests)
+
+	systemTests, err := system.ConfigFiles(filepath.Join(dir, "_dev", "test", string(system.TestType)))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading system tests failed (path: %s): %w", dir, err)
+	}
+	coverage.SystemTests = len(systemTests)
+
+	validator, err := fields.CreateValidatorForDirectory(dir,
+		fields.WithDisabledDependencyManagement(),
+		fields.WithEnabledFieldsCoverage(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating fields validator failed (path: %s): %w", dir, err)
+	}
+
+	var docs []common.MapStr
+	if coverage.PipelineTests > 0 {
+		docs, err = pipeline.ExpectedDocuments(pipelineTestsPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sampleEvents, err := fields.SampleEventFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range sampleEvents {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("can't read file: %w", err)
+		}
+		var doc common.MapStr
+		err = json.Unmarshal(content, &doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sample event %q: %w", path, err)
+		}
+		docs = append(docs, doc)
+	}
+	for _, doc := range docs {
+		// Only the coverage is relevant here, the documents are validated by the tests.
+		_ = validator.ValidateDocumentMap(doc)
+	}
+
+	for _, field := range validator.FieldsCoverage() {
+		coverage.Fields++
+		if field.Exercised {
+			coverage.ExercisedFields++
+		}
+	}
+	return &coverage, nil
+}
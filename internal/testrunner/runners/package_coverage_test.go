@@ -0,0 +1,74 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package runners
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculatePackageCoverage(t *testing.T) {
+	packageRoot := t.TempDir()
+	writeFile := func(path, content string) {
+		path = filepath.Join(packageRoot, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	writeFile("manifest.yml", "name: example\ntype: integration\nversion: 1.0.0\n")
+
+	// Data stream with pipeline and system tests.
+	writeFile("data_stream/logs/fields/fields.yml", `
+- name: message
+  type: keyword
+- name: http.status
+  type: long
+- name: http.method
+  type: keyword
+`)
+	writeFile("data_stream/logs/_dev/test/pipeline/test-access.log", "GET / 200\n")
+	writeFile("data_stream/logs/_dev/test/pipeline/test-access.log-config.yml", "multiline: {}\n")
+	writeFile("data_stream/logs/_dev/test/pipeline/test-access.log-expected.json", `{
+  "expected": [
+    {"message": "GET / 200", "http": {"status": 200}},
+    null
+  ]
+}`)
+	writeFile("data_stream/logs/_dev/test/system/test-default-config.yml", "vars: {}\n")
+	writeFile("data_stream/logs/_dev/test/system/test-tls-config.yml", "vars: {}\n")
+
+	// Data stream without tests, only with a sample event.
+	writeFile("data_stream/metrics/fields/fields.yml", `
+- name: system.cpu.pct
+  type: double
+- name: system.memory.pct
+  type: double
+`)
+	writeFile("data_stream/metrics/sample_event.json", `{"system": {"cpu": {"pct": 0.5}}}`)
+
+	coverage, err := CalculatePackageCoverage(packageRoot)
+	require.NoError(t, err)
+
+	expected := &PackageCoverage{
+		Package: "example",
+		DataStreams: []DataStreamCoverage{
+			{DataStream: "logs", PipelineTests: 1, SystemTests: 2, Fields: 3, ExercisedFields: 2},
+			{DataStream: "metrics", PipelineTests: 0, SystemTests: 0, Fields: 2, ExercisedFields: 1},
+		},
+		Summary: PackageCoverageSummary{
+			DataStreams:                  2,
+			DataStreamsWithPipelineTests: 1,
+			DataStreamsWithSystemTests:   1,
+			Fields:                       5,
+			ExercisedFields:              3,
+			FieldsCoverage:               0.6,
+		},
+	}
+	assert.Equal(t, expected, coverage)
+}
@@ -0,0 +1,68 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/elastic/elastic-package/internal/fields"
+)
+
+// fieldTimings aggregates the time spent validating each field in all the test cases.
+type fieldTimings struct {
+	mutex sync.Mutex
+
+	// limit is the number of fields included in the summary.
+	limit int
+
+	timings map[string]*fields.FieldTiming
+}
+
+func newFieldTimings(limit int) *fieldTimings {
+	return &fieldTimings{
+		limit:   limit,
+		timings: make(map[string]*fields.FieldTiming),
+	}
+}
+
+// add adds the timings measured by the validator of a test case.
+func (t *fieldTimings) add(timings []fields.FieldTiming) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for _, timing := range timings {
+		aggregated, found := t.timings[timing.Name]
+		if !found {
+			aggregated = &fields.FieldTiming{Name: timing.Name}
+			t.timings[timing.Name] = aggregated
+		}
+		aggregated.Count += timing.Count
+		aggregated.Total += timing.Total
+	}
+}
+
+// summary returns a human-readable summary of the slowest fields to validate.
+func (t *fieldTimings) summary() string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	timings := make([]fields.FieldTiming, 0, len(t.timings))
+	for _, timing := range t.timings {
+		timings = append(timings, *timing)
+	}
+	fields.SortFieldTimings(timings)
+	if len(timings) > t.limit {
+		timings = timings[:t.limit]
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Slowest fields to validate (%d):\n", len(timings))
+	for _, timing := range timings {
+		fmt.Fprintf(&sb, "  - %s: %s (%d values)\n", timing.Name, timing.Total, timing.Count)
+	}
+	return sb.String()
+}
@@ -0,0 +1,33 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package pipeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/elastic-package/internal/fields"
+)
+
+func TestFieldTimings(t *testing.T) {
+	timings := newFieldTimings(2)
+	timings.add([]fields.FieldTiming{
+		{Name: "message", Count: 2, Total: 3 * time.Millisecond},
+		{Name: "url.original", Count: 2, Total: 2 * time.Millisecond},
+		{Name: "user.name", Count: 1, Total: time.Millisecond},
+	})
+	timings.add([]fields.FieldTiming{
+		{Name: "user.name", Count: 3, Total: 4 * time.Millisecond},
+		{Name: "url.original", Count: 1, Total: time.Millisecond},
+	})
+
+	expected := `Slowest fields to validate (2):
+  - user.name: 5ms (4 values)
+  - message: 3ms (2 values)
+`
+	assert.Equal(t, expected, timings.summary())
+}
@@ -0,0 +1,181 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package pipeline
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/elastic/elastic-package/internal/fields"
+)
+
+// fieldsCoverage collects the fields that are not exercised by the test cases of each data stream.
+type fieldsCoverage struct {
+	mutex sync.Mutex
+
+	// unexercised contains, for each data stream, the fields not exercised by any of its test cases.
+	unexercised map[string][]string
+
+	// exercised contains, for each data stream, all its fields, and if they are exercised by
+	// any of its test cases.
+	exercised map[string]map[string]bool
+}
+
+func newFieldsCoverage() *fieldsCoverage {
+	return &fieldsCoverage{
+		unexercised: make(map[string][]string),
+		exercised:   make(map[string]map[string]bool),
+	}
+}
+
+// addCoverage adds the coverage of the fields of the data stream in a test case.
+func (c *fieldsCoverage) addCoverage(dataStream string, coverage []fields.FieldCoverage) {
+	var unexercised []fields.FieldDefinition
+	for _, field := range coverage {
+		if !field.Exercised {
+			unexercised = append(unexercised, field.Definition)
+		}
+	}
+	c.add(dataStream, unexercised)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	exercised, found := c.exercised[dataStream]
+	if !found {
+		exercised = make(map[string]bool)
+		c.exercised[dataStream] = exercised
+	}
+	for _, field := range coverage {
+		exercised[field.Definition.Name] = exercised[field.Definition.Name] || field.Exercised
+	}
+}
+
+// add adds the fields not exercised by a test case of the data stream. Only the fields not
+// exercised by any of the test cases are kept.
+func (c *fieldsCoverage) add(dataStream string, unexercised []fields.FieldDefinition) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var names []string
+	for _, definition := range unexercised {
+		names = append(names, definition.Name)
+	}
+
+	previous, found := c.unexercised[dataStream]
+	if !found {
+		c.unexercised[dataStream] = names
+		return
+	}
+
+	var kept []string
+	for _, name := range previous {
+		if slices.Contains(names, name) {
+			kept = append(kept, name)
+		}
+	}
+	c.unexercised[dataStream] = kept
+}
+
+// fieldsCoverageHTMLTemplate is the template of the HTML report of fields coverage.
+var fieldsCoverageHTMLTemplate = template.Must(template.New("fields_coverage").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Fields coverage</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+td, th { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+.exercised { background-color: #c8e6c9; }
+.unexercised { background-color: #ffcdd2; }
+</style>
+</head>
+<body>
+<h1>Fields coverage</h1>
+{{- range .}}
+<h2>Data stream {{.Name}}</h2>
+<p>{{.Exercised}} of {{len .Fields}} fields exercised by pipeline tests</p>
+<table>
+<tr><th>Field</th><th>Exercised</th></tr>
+{{- range .Fields}}
+<tr class="{{if .Exercised}}exercised{{else}}unexercised{{end}}"><td>{{.Name}}</td><td>{{if .Exercised}}yes{{else}}no{{end}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+type htmlDataStreamCoverage struct {
+	Name      string
+	Exercised int
+	Fields    []htmlFieldCoverage
+}
+
+type htmlFieldCoverage struct {
+	Name      string
+	Exercised bool
+}
+
+// writeHTML writes an HTML report with the fields of each data stream, marking if they are exercised by the tests.
+func (c *fieldsCoverage) writeHTML(w io.Writer) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	dataStreams := make([]string, 0, len(c.exercised))
+	for dataStream := range c.exercised {
+		dataStreams = append(dataStreams, dataStream)
+	}
+	sort.Strings(dataStreams)
+
+	var report []htmlDataStreamCoverage
+	for _, dataStream := range dataStreams {
+		coverage := htmlDataStreamCoverage{Name: dataStream}
+		for name, exercised := range c.exercised[dataStream] {
+			coverage.Fields = append(coverage.Fields, htmlFieldCoverage{Name: name, Exercised: exercised})
+			if exercised {
+				coverage.Exercised++
+			}
+		}
+		sort.Slice(coverage.Fields, func(i, j int) bool {
+			return coverage.Fields[i].Name < coverage.Fields[j].Name
+		})
+		report = append(report, coverage)
+	}
+
+	return fieldsCoverageHTMLTemplate.Execute(w, report)
+}
+
+// summary returns a human-readable summary of the fields not exercised by the tests.
+func (c *fieldsCoverage) summary() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	dataStreams := make([]string, 0, len(c.unexercised))
+	for dataStream := range c.unexercised {
+		dataStreams = append(dataStreams, dataStream)
+	}
+	sort.Strings(dataStreams)
+
+	var sb strings.Builder
+	for _, dataStream := range dataStreams {
+		unexercised := c.unexercised[dataStream]
+		if len(unexercised) == 0 {
+			fmt.Fprintf(&sb, "All fields of data stream %q are exercised by pipeline tests\n", dataStream)
+			continue
+		}
+		fmt.Fprintf(&sb, "Fields of data stream %q not exercised by pipeline tests (%d):\n", dataStream, len(unexercised))
+		for _, name := range unexercised {
+			fmt.Fprintf(&sb, "  - %s\n", name)
+		}
+	}
+	return sb.String()
+}
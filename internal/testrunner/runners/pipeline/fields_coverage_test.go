@@ -0,0 +1,59 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package pipeline
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/fields"
+)
+
+func TestFieldsCoverage(t *testing.T) {
+	coverage := newFieldsCoverage()
+	coverage.add("access", []fields.FieldDefinition{{Name: "http.request.method"}, {Name: "url.path"}, {Name: "user.name"}})
+	coverage.add("access", []fields.FieldDefinition{{Name: "url.path"}, {Name: "user.name"}})
+	coverage.add("access", []fields.FieldDefinition{{Name: "http.request.method"}, {Name: "user.name"}})
+	coverage.add("error", nil)
+
+	expected := `Fields of data stream "access" not exercised by pipeline tests (1):
+  - user.name
+All fields of data stream "error" are exercised by pipeline tests
+`
+	assert.Equal(t, expected, coverage.summary())
+}
+
+func TestFieldsCoverageHTML(t *testing.T) {
+	coverage := newFieldsCoverage()
+	coverage.addCoverage("access", []fields.FieldCoverage{
+		{Definition: fields.FieldDefinition{Name: "http.request.method"}, Exercised: true},
+		{Definition: fields.FieldDefinition{Name: "url.path"}},
+		{Definition: fields.FieldDefinition{Name: "user.name"}},
+	})
+	coverage.addCoverage("access", []fields.FieldCoverage{
+		{Definition: fields.FieldDefinition{Name: "http.request.method"}},
+		{Definition: fields.FieldDefinition{Name: "url.path"}, Exercised: true},
+		{Definition: fields.FieldDefinition{Name: "user.name"}},
+	})
+
+	var sb strings.Builder
+	err := coverage.writeHTML(&sb)
+	require.NoError(t, err)
+
+	html := sb.String()
+	assert.Contains(t, html, "<h2>Data stream access</h2>")
+	assert.Contains(t, html, "<p>2 of 3 fields exercised by pipeline tests</p>")
+	assert.Contains(t, html, `<tr class="exercised"><td>http.request.method</td><td>yes</td></tr>`)
+	assert.Contains(t, html, `<tr class="exercised"><td>url.path</td><td>yes</td></tr>`)
+	assert.Contains(t, html, `<tr class="unexercised"><td>user.name</td><td>no</td></tr>`)
+
+	expected := `Fields of data stream "access" not exercised by pipeline tests (1):
+  - user.name
+`
+	assert.Equal(t, expected, coverage.summary())
+}
@@ -0,0 +1,163 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/elasticsearch/ingest"
+	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/logger"
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+// maxRerouteHops is the maximum number of times a document can be rerouted, to avoid loops.
+const maxRerouteHops = 10
+
+// rerouteDestination is a data stream of the package where documents can be rerouted to.
+type rerouteDestination struct {
+	dataStream         string
+	simulateDataStream string
+	pipeline           string
+	validator          *fields.Validator
+}
+
+// findPipeline returns the name of the installed pipeline defined in the file with the given
+// name, without extension.
+func findPipeline(pipelines []ingest.Pipeline, name string) (string, error) {
+	var available []string
+	for _, pipeline := range pipelines {
+		filename := filepath.Base(pipeline.Path)
+		filename = strings.TrimSuffix(filename, filepath.Ext(filename))
+		if filename == name {
+			return pipeline.Name, nil
+		}
+		available = append(available, filename)
+	}
+	return "", fmt.Errorf("pipeline %q not found in data stream (available: %s)", name, strings.Join(available, ", "))
+}
+
+// packageDatasets returns the data streams of the package, indexed by their datasets.
+func packageDatasets(packageRootPath string) (map[string]*packages.DataStreamManifest, error) {
+	manifest, err := packages.ReadPackageManifestFromPackageRoot(packageRootPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read package manifest: %w", err)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(packageRootPath, "data_stream", "*", packages.DataStreamManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed matching data stream manifests: %w", err)
+	}
+
+	datasets := make(map[string]*packages.DataStreamManifest)
+	for _, path := range paths {
+		dsManifest, err := packages.ReadDataStreamManifest(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read data stream manifest: %w", err)
+		}
+		dataset := dsManifest.Dataset
+		if dataset == "" {
+			dataset = manifest.Name + "." + dsManifest.Name
+		}
+		datasets[dataset] = dsManifest
+	}
+	return datasets, nil
+}
+
+// eventDataset returns the value of data_stream.dataset in the event.
+func eventDataset(event json.RawMessage) string {
+	var doc struct {
+		DataStream struct {
+			Dataset string `json:"dataset"`
+		} `json:"data_stream"`
+		Dataset string `json:"data_stream.dataset"`
+	}
+	err := json.Unmarshal(event, &doc)
+	if err != nil {
+		return ""
+	}
+	if doc.Dataset != "" {
+		return doc.Dataset
+	}
+	return doc.DataStream.Dataset
+}
+
+// followReroutes processes the events rerouted to other data streams of the package with the
+// pipelines of the destination data streams, as Elasticsearch does when indexing them. Rerouted
+// events are replaced in the result by the processed ones, and will be validated with the fields
+// of the data stream where they end.
+func (r *tester) followReroutes(ctx context.Context, tc *testCase, result *testResult, validatorOptions []fields.ValidatorOption) error {
+	datasets, err := packageDatasets(r.packageRootPath)
+	if err != nil {
+		return err
+	}
+
+	destinations := make(map[string]*rerouteDestination)
+	result.validators = make([]*fields.Validator, len(result.events))
+	for i := range result.events {
+		visited := []string{r.testFolder.DataStream}
+		for hops := 0; result.events[i] != nil; hops++ {
+			dataset := eventDataset(result.events[i])
+			dsManifest, found := datasets[dataset]
+			if !found || dsManifest.Name == visited[len(visited)-1] {
+				break
+			}
+			if hops >= maxRerouteHops || slices.Contains(visited, dsManifest.Name) {
+				return fmt.Errorf("event %d rerouted in a loop (data streams: %s)", i+1, strings.Join(append(visited, dsManifest.Name), " -> "))
+			}
+			visited = append(visited, dsManifest.Name)
+
+			destination, found := destinations[dsManifest.Name]
+			if !found {
+				destination, err = r.installRerouteDestination(dsManifest, dataset, validatorOptions)
+				if err != nil {
+					return err
+				}
+				destinations[dsManifest.Name] = destination
+			}
+
+			logger.Debugf("Following event %d rerouted to data stream %q", i+1, destination.dataStream)
+			processed, err := ingest.SimulatePipelineDocuments(ctx, r.esAPI, destination.pipeline, []json.RawMessage{result.events[i]}, destination.simulateDataStream, tc.config.ingestTimestamp)
+			if err != nil {
+				return fmt.Errorf("simulating pipeline of data stream %q failed: %w", destination.dataStream, err)
+			}
+			if len(processed) != 1 {
+				return fmt.Errorf("unexpected number of documents processed by pipeline of data stream %q: %d", destination.dataStream, len(processed))
+			}
+
+			result.events[i] = processed[0].Source
+			result.ignoredFields[i] = nil
+			result.validators[i] = destination.validator
+		}
+	}
+	return nil
+}
+
+func (r *tester) installRerouteDestination(dsManifest *packages.DataStreamManifest, dataset string, validatorOptions []fields.ValidatorOption) (*rerouteDestination, error) {
+	dataStreamPath := filepath.Join(r.packageRootPath, "data_stream", dsManifest.Name)
+	entryPipeline, pipelines, err := ingest.InstallDataStreamPipelines(r.esAPI, dataStreamPath)
+	if err != nil {
+		return nil, fmt.Errorf("installing ingest pipelines of data stream %q failed: %w", dsManifest.Name, err)
+	}
+	r.pipelines = append(r.pipelines, pipelines...)
+
+	validatorOptions = append(slices.Clone(validatorOptions), fields.WithExpectedDatasets([]string{dataset}))
+	validator, err := fields.CreateValidatorForDirectory(dataStreamPath, validatorOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("creating fields validator for data stream %q failed: %w", dsManifest.Name, err)
+	}
+
+	return &rerouteDestination{
+		dataStream:         dsManifest.Name,
+		simulateDataStream: dsManifest.Type + "-" + dataset + "-default",
+		pipeline:           entryPipeline,
+		validator:          validator,
+	}, nil
+}
@@ -0,0 +1,47 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package pipeline
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/elasticsearch/ingest"
+)
+
+func TestEventDataset(t *testing.T) {
+	cases := []struct {
+		event    string
+		expected string
+	}{
+		{event: `{"data_stream":{"dataset":"nginx.access","namespace":"default"}}`, expected: "nginx.access"},
+		{event: `{"data_stream.dataset":"nginx.error"}`, expected: "nginx.error"},
+		{event: `{"message":"no dataset"}`, expected: ""},
+		{event: `not json`, expected: ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.event, func(t *testing.T) {
+			assert.Equal(t, c.expected, eventDataset(json.RawMessage(c.event)))
+		})
+	}
+}
+
+func TestFindPipeline(t *testing.T) {
+	pipelines := []ingest.Pipeline{
+		{Path: "data_stream/logs/elasticsearch/ingest_pipeline/default.yml", Name: "default-1234"},
+		{Path: "data_stream/logs/elasticsearch/ingest_pipeline/custom.json", Name: "custom-1234"},
+	}
+
+	name, err := findPipeline(pipelines, "custom")
+	require.NoError(t, err)
+	assert.Equal(t, "custom-1234", name)
+
+	_, err = findPipeline(pipelines, "other")
+	assert.EqualError(t, err, `pipeline "other" not found in data stream (available: default, custom)`)
+}
@@ -6,12 +6,14 @@ package pipeline
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/elastic/elastic-package/internal/elasticsearch"
+	"github.com/elastic/elastic-package/internal/fields"
 	"github.com/elastic/elastic-package/internal/packages"
 	"github.com/elastic/elastic-package/internal/profile"
 	"github.com/elastic/elastic-package/internal/testrunner"
@@ -35,6 +37,13 @@ type runner struct {
 	coverageType     string
 	deferCleanup     time.Duration
 	globalTestConfig testrunner.GlobalRunnerTestConfig
+
+	fieldsCoverage   *fieldsCoverage
+	fieldTimings     *fieldTimings
+	validationBudget fields.ValidationBudget
+
+	// workers is the number of test cases that can run in parallel.
+	workers int
 }
 
 type PipelineTestRunnerOptions struct {
@@ -48,6 +57,10 @@ type PipelineTestRunnerOptions struct {
 	CoverageType       string
 	DeferCleanup       time.Duration
 	GlobalTestConfig   testrunner.GlobalRunnerTestConfig
+	WithFieldsCoverage bool
+	SlowestFields      int // Number of slowest fields to report, zero disables the report.
+	ValidationBudget   fields.ValidationBudget
+	Workers            int // Number of test cases to run in parallel, test cases run sequentially if it is lower than 2.
 }
 
 func NewPipelineTestRunner(options PipelineTestRunnerOptions) *runner {
@@ -62,12 +75,28 @@ func NewPipelineTestRunner(options PipelineTestRunnerOptions) *runner {
 		coverageType:       options.CoverageType,
 		deferCleanup:       options.DeferCleanup,
 		globalTestConfig:   options.GlobalTestConfig,
+		workers:            options.Workers,
+		validationBudget:   options.ValidationBudget,
+	}
+	if options.WithFieldsCoverage {
+		runner.fieldsCoverage = newFieldsCoverage()
+	}
+	if options.SlowestFields > 0 {
+		runner.fieldTimings = newFieldTimings(options.SlowestFields)
 	}
 	return &runner
 }
 
-// Ensures that runner implements testrunner.TestRunner interface
-var _ testrunner.TestRunner = new(runner)
+// Ensures that runner implements testrunner.TestRunner and testrunner.ParallelTestsLimiter interfaces
+var (
+	_ testrunner.TestRunner           = new(runner)
+	_ testrunner.ParallelTestsLimiter = new(runner)
+)
+
+// MaxParallelTests returns the number of test cases that can run in parallel.
+func (r *runner) MaxParallelTests() int {
+	return r.workers
+}
 
 // SetupRunner prepares global resources required by the test runner.
 func (r *runner) SetupRunner(ctx context.Context) error {
@@ -138,6 +167,10 @@ func (r *runner) GetTests(ctx context.Context) ([]testrunner.Tester, error) {
 				API:                r.esAPI,
 				TestCaseFile:       caseFile,
 				GlobalTestConfig:   r.globalTestConfig,
+				FieldsCoverage:     r.fieldsCoverage,
+				FieldTimings:       r.fieldTimings,
+				ValidationBudget:   r.validationBudget,
+				Parallel:           r.workers > 1,
 			})
 			if err != nil {
 				return nil, fmt.Errorf("failed to create pipeline tester: %w", err)
@@ -152,10 +185,52 @@ func (r *runner) Type() testrunner.TestType {
 	return TestType
 }
 
+// FieldsCoverageSummary returns a summary of the fields not exercised by the executed tests.
+// It is only available when the runner is created with fields coverage enabled.
+func (r *runner) FieldsCoverageSummary() string {
+	if r.fieldsCoverage == nil {
+		return ""
+	}
+	return r.fieldsCoverage.summary()
+}
+
+// WriteFieldsCoverageHTML writes an HTML report of the fields exercised by the executed tests in
+// the given path. It is only available when the runner is created with fields coverage enabled.
+func (r *runner) WriteFieldsCoverageHTML(path string) error {
+	if r.fieldsCoverage == nil {
+		return errors.New("fields coverage is not enabled")
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create fields coverage report: %w", err)
+	}
+	defer f.Close()
+
+	err = r.fieldsCoverage.writeHTML(f)
+	if err != nil {
+		return fmt.Errorf("failed to write fields coverage report: %w", err)
+	}
+	return nil
+}
+
+// SlowestFieldsSummary returns a summary of the fields that took longer to validate in the executed
+// tests. It is only available when the runner is created with a number of slowest fields to report.
+func (r *runner) SlowestFieldsSummary() string {
+	if r.fieldTimings == nil {
+		return ""
+	}
+	return r.fieldTimings.summary()
+}
+
 func (r *runner) listTestCaseFiles(folder testrunner.TestFolder) ([]string, error) {
-	fis, err := os.ReadDir(folder.Path)
+	return TestCaseFiles(folder.Path)
+}
+
+// TestCaseFiles returns the names of the files with test cases in the given pipeline test folder.
+func TestCaseFiles(testFolderPath string) ([]string, error) {
+	fis, err := os.ReadDir(testFolderPath)
 	if err != nil {
-		return nil, fmt.Errorf("reading pipeline tests failed (path: %s): %w", folder.Path, err)
+		return nil, fmt.Errorf("reading pipeline tests failed (path: %s): %w", testFolderPath, err)
 	}
 
 	var files []string
@@ -5,10 +5,18 @@
 package pipeline
 
 import (
+	"compress/gzip"
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/elasticsearch/ingest"
+	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/testrunner"
 )
 
 const (
@@ -40,3 +48,226 @@ func TestStripEmptyTestResults(t *testing.T) {
 	require.Equal(t, actual.events[2], json.RawMessage(secondTestResult))
 	require.Equal(t, actual.events[3], json.RawMessage(thirdTestResult))
 }
+
+func TestVerifyIgnoredFields(t *testing.T) {
+	result := &testResult{
+		events: []json.RawMessage{
+			[]byte(firstTestResult),
+			[]byte(secondTestResult),
+			nil,
+		},
+		ignoredFields: [][]string{
+			nil,
+			{"message", "url.original"},
+			nil,
+		},
+	}
+
+	err := verifyIgnoredFields(result, &testConfig{})
+	var failed testrunner.ErrTestCaseFailed
+	require.ErrorAs(t, err, &failed)
+	require.Contains(t, failed.Details, `field "message" would be ignored when indexing event 2`)
+	require.Contains(t, failed.Details, `field "url.original" would be ignored when indexing event 2`)
+
+	config := &testConfig{}
+	config.IgnoredFields.Expected = []string{"message"}
+	err = verifyIgnoredFields(result, config)
+	require.ErrorAs(t, err, &failed)
+	require.NotContains(t, failed.Details, `field "message"`)
+
+	config.IgnoredFields.Expected = []string{"message", "url.original"}
+	err = verifyIgnoredFields(result, config)
+	require.NoError(t, err)
+
+	config.IgnoredFields.Expected = nil
+	config.IgnoredFields.WarnOnly = true
+	err = verifyIgnoredFields(result, config)
+	require.NoError(t, err)
+}
+
+func TestAddIgnoredFieldsFromDefinitions(t *testing.T) {
+	dataStreamDir := t.TempDir()
+	fieldsFile := `
+- name: example.id
+  type: keyword
+  ignore_above: 5
+`
+	require.NoError(t, os.MkdirAll(filepath.Join(dataStreamDir, "fields"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dataStreamDir, "fields", "fields.yml"), []byte(fieldsFile), 0644))
+	validator, err := fields.CreateValidatorForDirectory(dataStreamDir, fields.WithDisabledDependencyManagement())
+	require.NoError(t, err)
+
+	result := &testResult{
+		events: []json.RawMessage{
+			[]byte(`{"example": {"id": "short"}}`),
+			nil,
+			[]byte(`{"example": {"id": "too long"}}`),
+		},
+		ignoredFields: [][]string{nil, nil, {"message"}},
+	}
+	err = addIgnoredFieldsFromDefinitions(result, validator)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{nil, nil, {"message", "example.id"}}, result.ignoredFields)
+}
+
+func TestMergeIndexedDocuments(t *testing.T) {
+	newResult := func() *testResult {
+		return &testResult{
+			events: []json.RawMessage{
+				[]byte(firstTestResult),
+				nil,
+				[]byte(secondTestResult),
+				[]byte(thirdTestResult),
+			},
+			ignoredFields: [][]string{nil, nil, nil, {"message"}},
+		}
+	}
+
+	result := newResult()
+	err := mergeIndexedDocuments(result, []ingest.IndexedDocument{
+		{IgnoredFields: []string{"url.original"}},
+		{},
+		{},
+		{IgnoredFields: []string{"message", "user_agent.original"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"url.original"},
+		nil,
+		nil,
+		{"message", "user_agent.original"},
+	}, result.ignoredFields)
+
+	result = newResult()
+	err = mergeIndexedDocuments(result, []ingest.IndexedDocument{
+		{},
+		{},
+		{Failure: `{"type":"document_parsing_exception"}`},
+		{},
+	})
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `event 3 couldn't be indexed: {"type":"document_parsing_exception"}`)
+
+	err = mergeIndexedDocuments(newResult(), []ingest.IndexedDocument{{}, {}})
+	assert.ErrorContains(t, err, "unexpected number of indexed documents (2), expected 4")
+}
+
+func TestVerifyDroppedEvents(t *testing.T) {
+	result := &testResult{
+		events: []json.RawMessage{
+			[]byte(firstTestResult),
+			nil,
+			[]byte(secondTestResult),
+			nil,
+		},
+	}
+	intPtr := func(n int) *int { return &n }
+
+	cases := []struct {
+		title    string
+		config   droppedEventsConfig
+		expected []string
+	}{
+		{
+			title: "not configured",
+		},
+		{
+			title:  "expected events",
+			config: droppedEventsConfig{Events: []int{2, 4}},
+		},
+		{
+			title:  "expected count",
+			config: droppedEventsConfig{Count: intPtr(2)},
+		},
+		{
+			title:  "unexpected count",
+			config: droppedEventsConfig{Count: intPtr(0)},
+			expected: []string{
+				"expected 0 dropped events, found 2 (dropped events: [2 4])",
+			},
+		},
+		{
+			title:  "unexpected events",
+			config: droppedEventsConfig{Events: []int{1, 2, 5}},
+			expected: []string{
+				"event 1 expected to be dropped, but it was not",
+				"event 5 expected to be dropped, but there are only 4 events",
+				"event 4 was unexpectedly dropped",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			err := verifyDroppedEvents(result, &testConfig{DroppedEvents: c.config})
+			if len(c.expected) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			var failed testrunner.ErrTestCaseFailed
+			require.ErrorAs(t, err, &failed)
+			for _, expected := range c.expected {
+				assert.Contains(t, failed.Details, expected)
+			}
+		})
+	}
+}
+
+func TestVerifyDynamicFields(t *testing.T) {
+	config := &testConfig{
+		DynamicFields: map[string]string{
+			"event.id":       "^[a-f0-9]{8}$",
+			"event.sequence": "^[0-9]+$",
+			"related.hash":   "^[a-f0-9]{8}$",
+		},
+	}
+
+	result := &testResult{
+		events: []json.RawMessage{
+			[]byte(`{"event": {"id": "0a1b2c3d", "sequence": 42, "kind": "event"}, "related": {"hash": ["0a1b2c3d", "4e5f6a7b"]}}`),
+		},
+	}
+	err := verifyDynamicFields(result, config)
+	require.NoError(t, err)
+
+	result = &testResult{
+		events: []json.RawMessage{
+			[]byte(`{"event": {"id": "not-an-id", "sequence": 42}, "related": {"hash": ["0a1b2c3d", "XYZ"]}}`),
+		},
+	}
+	err = verifyDynamicFields(result, config)
+	var failed testrunner.ErrTestCaseFailed
+	require.ErrorAs(t, err, &failed)
+	require.Contains(t, failed.Details, `dynamic field "event.id" doesn't match the pattern (^[a-f0-9]{8}$): not-an-id`)
+	require.Contains(t, failed.Details, `dynamic field "related.hash" doesn't match the pattern (^[a-f0-9]{8}$): XYZ`)
+	require.NotContains(t, failed.Details, "event.sequence")
+}
+
+func TestLoadGzippedTestCaseFile(t *testing.T) {
+	dir := t.TempDir()
+	content := "first line\nsecond line\nthird line\n"
+
+	err := os.WriteFile(filepath.Join(dir, "test-plain.log"), []byte(content), 0644)
+	require.NoError(t, err)
+
+	f, err := os.Create(filepath.Join(dir, "test-gzipped.log.gz"))
+	require.NoError(t, err)
+	w := gzip.NewWriter(f)
+	_, err = w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, f.Close())
+
+	plain, err := loadTestCaseFile(dir, "test-plain.log")
+	require.NoError(t, err)
+	gzipped, err := loadTestCaseFile(dir, "test-gzipped.log.gz")
+	require.NoError(t, err)
+
+	assert.Len(t, gzipped.events, 3)
+	assert.Equal(t, plain.events, gzipped.events)
+
+	err = os.WriteFile(filepath.Join(dir, "test-invalid.log.gz"), []byte(content), 0644)
+	require.NoError(t, err)
+	_, err = loadTestCaseFile(dir, "test-invalid.log.gz")
+	assert.ErrorContains(t, err, "creating gzip reader failed")
+}
@@ -9,9 +9,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/elastic/go-ucfg/yaml"
 
+	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/multierror"
 	"github.com/elastic/elastic-package/internal/testrunner"
 )
 
@@ -28,12 +31,83 @@ type testConfig struct {
 	DynamicFields map[string]string      `config:"dynamic_fields"`
 
 	// NumericKeywordFields holds a list of fields that have keyword
-	// type but can be ingested as numeric type.
-	NumericKeywordFields []string `config:"numeric_keyword_fields"`
+	// type but can be ingested as numeric type, optionally only when
+	// their values match a pattern.
+	NumericKeywordFields []fields.NumericKeywordField `config:"numeric_keyword_fields"`
 
 	// StringNumberFields holds a list of fields that have numeric
 	// types but can be ingested as strings.
 	StringNumberFields []string `config:"string_number_fields"`
+
+	// IgnoredFields configures the verification of fields that would be
+	// ignored when indexing the processed documents.
+	IgnoredFields ignoredFieldsConfig `config:"ignored_fields"`
+
+	// IngestTimestamp is a fixed timestamp in RFC3339 format to use as the
+	// _ingest.timestamp of the simulated documents, so results don't depend
+	// on the time the tests are executed.
+	IngestTimestamp string `config:"ingest_timestamp"`
+
+	// Pipeline is the name of the ingest pipeline of the data stream, without extension,
+	// to use as entry point instead of the default one.
+	Pipeline string `config:"pipeline"`
+
+	// FollowReroute enables processing the documents rerouted to other data streams of the
+	// package with their pipelines, validating them with the fields of the destination.
+	FollowReroute bool `config:"follow_reroute"`
+
+	// IndexDocuments enables indexing the processed documents in a temporary index with the mappings of the
+	// data stream, to detect documents rejected by the mappings and fields ignored at index time,
+	// that are not reported by the simulate API. It requires the package to be installed.
+	IndexDocuments bool `config:"index_documents"`
+
+	// DroppedEvents configures the events that are expected to be dropped by the pipeline.
+	DroppedEvents droppedEventsConfig `config:"dropped_events"`
+
+	// UnproducedFields holds a list of fields defined in the data stream that are not expected
+	// to be produced by the pipeline. It is used by the linter, not by the tests.
+	UnproducedFields []string `config:"unproduced_fields"`
+
+	// ingestTimestamp is the parsed value of IngestTimestamp.
+	ingestTimestamp time.Time
+}
+
+type ignoredFieldsConfig struct {
+	// Expected holds a list of fields that are expected to be ignored.
+	Expected []string `config:"expected"`
+
+	// WarnOnly reports unexpectedly ignored fields as warnings instead of failing the test.
+	WarnOnly bool `config:"warn_only"`
+}
+
+type droppedEventsConfig struct {
+	// Events holds the positions, starting at 1, of the input events that are expected
+	// to be dropped.
+	Events []int `config:"events"`
+
+	// Count is the number of input events that are expected to be dropped, for cases where
+	// it doesn't matter which ones.
+	Count *int `config:"count"`
+}
+
+// enabled returns true if the expected dropped events are configured.
+func (c droppedEventsConfig) enabled() bool {
+	return c.Count != nil || len(c.Events) > 0
+}
+
+func (c droppedEventsConfig) validate() error {
+	if c.Count != nil && *c.Count < 0 {
+		return fmt.Errorf("count cannot be negative (%d)", *c.Count)
+	}
+	for _, n := range c.Events {
+		if n < 1 {
+			return fmt.Errorf("invalid event %d, events are numbered starting at 1", n)
+		}
+	}
+	if c.Count != nil && len(c.Events) > 0 && *c.Count != len(c.Events) {
+		return fmt.Errorf("count (%d) doesn't match the number of events (%d)", *c.Count, len(c.Events))
+	}
+	return nil
 }
 
 type multiline struct {
@@ -52,7 +126,7 @@ func readConfigForTestCase(testCasePath string) (*testConfig, error) {
 	}
 
 	if err == nil {
-		if err := cfg.Unpack(&c); err != nil {
+		if err := testrunner.UnpackConfigStrict(cfg, &c); err != nil {
 			return nil, fmt.Errorf("can't unpack test configuration: %s: %w", commonConfigPath, err)
 		}
 	}
@@ -64,13 +138,47 @@ func readConfigForTestCase(testCasePath string) (*testConfig, error) {
 	}
 
 	if err == nil {
-		if err := cfg.Unpack(&c); err != nil {
+		if err := testrunner.UnpackConfigStrict(cfg, &c); err != nil {
 			return nil, fmt.Errorf("can't unpack test configuration: %s: %w", configPath, err)
 		}
 	}
+
+	if c.IngestTimestamp != "" {
+		c.ingestTimestamp, err = time.Parse(time.RFC3339, c.IngestTimestamp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ingest_timestamp %q, expected RFC3339 format: %w", c.IngestTimestamp, err)
+		}
+	}
+	if err := c.DroppedEvents.validate(); err != nil {
+		return nil, fmt.Errorf("invalid dropped_events: %w", err)
+	}
 	return &c, nil
 }
 
+// ValidateConfigFiles checks that the pipeline test configurations in the given test folder can be loaded.
+func ValidateConfigFiles(testFolderPath string) error {
+	configFiles, err := filepath.Glob(filepath.Join(testFolderPath, "*"+configTestSuffixYAML))
+	if err != nil {
+		return err
+	}
+	var errs multierror.Error
+	for _, configFile := range configFiles {
+		cfg, err := yaml.NewConfigWithFile(configFile)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("can't load test configuration: %s: %w", configFile, err))
+			continue
+		}
+		var c testConfig
+		if err := testrunner.UnpackConfigStrict(cfg, &c); err != nil {
+			errs = append(errs, fmt.Errorf("can't unpack test configuration: %s: %w", configFile, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
 func expectedTestConfigFile(testFile, configTestSuffix string) string {
 	return fmt.Sprintf("%s%s", testFile, configTestSuffix)
 }
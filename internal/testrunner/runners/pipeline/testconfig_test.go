@@ -0,0 +1,160 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/fields"
+)
+
+func TestReadConfigForTestCaseIngestTimestamp(t *testing.T) {
+	cases := []struct {
+		title    string
+		config   string
+		expected time.Time
+		err      string
+	}{
+		{
+			title:  "no ingest timestamp",
+			config: "numeric_keyword_fields: [foo]",
+		},
+		{
+			title:    "valid ingest timestamp",
+			config:   `ingest_timestamp: "2024-03-01T10:15:00Z"`,
+			expected: time.Date(2024, 3, 1, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			title:  "invalid ingest timestamp",
+			config: `ingest_timestamp: "2024-03-01 10:15:00"`,
+			err:    `invalid ingest_timestamp "2024-03-01 10:15:00", expected RFC3339 format`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			dir := t.TempDir()
+			testCasePath := filepath.Join(dir, "test-access.log")
+			err := os.WriteFile(testCasePath+configTestSuffixYAML, []byte(c.config), 0644)
+			require.NoError(t, err)
+
+			config, err := readConfigForTestCase(testCasePath)
+			if c.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), c.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, c.expected.Equal(config.ingestTimestamp), "expected %s, found %s", c.expected, config.ingestTimestamp)
+		})
+	}
+}
+
+func TestReadConfigForTestCaseUnknownSettings(t *testing.T) {
+	dir := t.TempDir()
+	testCasePath := filepath.Join(dir, "test-access.log")
+	config := `
+numericKeywordFields:
+  - network.iana_number
+ignored_fields:
+  expected: [error.message]
+  warnOnly: true
+fields:
+  any.field: value
+`
+	err := os.WriteFile(testCasePath+configTestSuffixYAML, []byte(config), 0644)
+	require.NoError(t, err)
+
+	_, err = readConfigForTestCase(testCasePath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), testCasePath+configTestSuffixYAML)
+	assert.Contains(t, err.Error(), "unknown settings found, they may be misspelled: ignored_fields.warnOnly, numericKeywordFields")
+
+	err = ValidateConfigFiles(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ignored_fields.warnOnly, numericKeywordFields")
+}
+
+func TestReadConfigForTestCaseNumericKeywordFields(t *testing.T) {
+	dir := t.TempDir()
+	testCasePath := filepath.Join(dir, "test-access.log")
+	config := `
+numeric_keyword_fields:
+  - network.iana_number
+  - name: foo.code
+    when: "^[0-9]+$"
+`
+	err := os.WriteFile(testCasePath+configTestSuffixYAML, []byte(config), 0644)
+	require.NoError(t, err)
+
+	c, err := readConfigForTestCase(testCasePath)
+	require.NoError(t, err)
+
+	expected := []fields.NumericKeywordField{
+		{Name: "network.iana_number"},
+		{Name: "foo.code", When: "^[0-9]+$"},
+	}
+	assert.Equal(t, expected, c.NumericKeywordFields)
+}
+
+func TestReadConfigForTestCaseDroppedEvents(t *testing.T) {
+	cases := []struct {
+		title  string
+		config string
+		err    string
+	}{
+		{
+			title:  "events",
+			config: "dropped_events:\n  events: [1, 3]",
+		},
+		{
+			title:  "count",
+			config: "dropped_events:\n  count: 0",
+		},
+		{
+			title:  "events and count",
+			config: "dropped_events:\n  events: [1, 3]\n  count: 2",
+		},
+		{
+			title:  "negative count",
+			config: "dropped_events:\n  count: -1",
+			err:    "invalid dropped_events: count cannot be negative (-1)",
+		},
+		{
+			title:  "invalid event",
+			config: "dropped_events:\n  events: [0]",
+			err:    "invalid dropped_events: invalid event 0, events are numbered starting at 1",
+		},
+		{
+			title:  "count not matching events",
+			config: "dropped_events:\n  events: [1, 3]\n  count: 1",
+			err:    "invalid dropped_events: count (1) doesn't match the number of events (2)",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			dir := t.TempDir()
+			testCasePath := filepath.Join(dir, "test-access.log")
+			err := os.WriteFile(testCasePath+configTestSuffixYAML, []byte(c.config), 0644)
+			require.NoError(t, err)
+
+			config, err := readConfigForTestCase(testCasePath)
+			if c.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), c.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, config.DroppedEvents.enabled())
+		})
+	}
+}
@@ -6,14 +6,17 @@ package pipeline
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -54,6 +57,13 @@ type tester struct {
 	runCompareResults bool
 
 	provider stack.Provider
+
+	fieldsCoverage   *fieldsCoverage
+	fieldTimings     *fieldTimings
+	validationBudget fields.ValidationBudget
+
+	// parallel is set when the test cases can run in parallel.
+	parallel bool
 }
 
 type PipelineTesterOptions struct {
@@ -67,6 +77,10 @@ type PipelineTesterOptions struct {
 	CoverageType       string
 	TestCaseFile       string
 	GlobalTestConfig   testrunner.GlobalRunnerTestConfig
+	FieldsCoverage     *fieldsCoverage
+	FieldTimings       *fieldTimings
+	ValidationBudget   fields.ValidationBudget
+	Parallel           bool
 }
 
 func NewPipelineTester(options PipelineTesterOptions) (*tester, error) {
@@ -81,6 +95,10 @@ func NewPipelineTester(options PipelineTesterOptions) (*tester, error) {
 		withCoverage:       options.WithCoverage,
 		coverageType:       options.CoverageType,
 		globalTestConfig:   options.GlobalTestConfig,
+		fieldsCoverage:     options.FieldsCoverage,
+		fieldTimings:       options.FieldTimings,
+		validationBudget:   options.ValidationBudget,
+		parallel:           options.Parallel,
 	}
 
 	stackConfig, err := stack.LoadConfig(r.profile)
@@ -132,8 +150,9 @@ func (r *tester) String() string {
 
 // Parallel indicates if this tester can run in parallel or not.
 func (r tester) Parallel() bool {
-	// Not supported yet parallel tests even if it is indicated in the global config r.globalTestConfig
-	return false
+	// Parallel tests are only enabled with the number of workers, the global config
+	// r.globalTestConfig is not used.
+	return r.parallel
 }
 
 // Run runs the pipeline tests defined under the given folder
@@ -323,31 +342,74 @@ func (r *tester) runTestCase(ctx context.Context, testCaseFile string, dsPath st
 		return results, nil
 	}
 
+	if tc.config.Pipeline != "" {
+		pipeline, err = findPipeline(r.pipelines, tc.config.Pipeline)
+		if err != nil {
+			results, _ := rc.WithErrorf("selecting entry pipeline failed: %w", err)
+			return results, nil
+		}
+	}
+
 	simulateDataStream := dsType + "-" + r.testFolder.Package + "." + r.testFolder.DataStream + "-default"
-	processedEvents, err := ingest.SimulatePipeline(ctx, r.esAPI, pipeline, tc.events, simulateDataStream)
+	processedDocs, err := ingest.SimulatePipelineDocuments(ctx, r.esAPI, pipeline, tc.events, simulateDataStream, tc.config.ingestTimestamp)
 	if err != nil {
 		results, _ := rc.WithErrorf("simulating pipeline processing failed: %w", err)
 		return results, nil
 	}
 
-	result := &testResult{events: processedEvents}
+	// Ignored fields are added when verifying the results, and when documents are indexed.
+	result := &testResult{ignoredFields: make([][]string, len(processedDocs))}
+	for _, doc := range processedDocs {
+		result.events = append(result.events, doc.Source)
+	}
+
+	if tc.config.IndexDocuments {
+		indexTemplate := dsType + "-" + r.testFolder.Package + "." + r.testFolder.DataStream
+		err = r.indexDocuments(ctx, processedDocs, simulateDataStream, indexTemplate, result)
+		if err != nil {
+			results, _ := rc.WithErrorf("indexing documents failed: %w", err)
+			return results, nil
+		}
+	}
 
-	rc.TimeElapsed = time.Since(startTime)
 	validatorOptions = append(slices.Clone(validatorOptions),
-		fields.WithNumericKeywordFields(tc.config.NumericKeywordFields),
+		fields.WithConditionalNumericKeywordFields(tc.config.NumericKeywordFields),
 		fields.WithStringNumberFields(tc.config.StringNumberFields),
 	)
+	if tc.config.FollowReroute {
+		err = r.followReroutes(ctx, tc, result, validatorOptions)
+		if err != nil {
+			results, _ := rc.WithErrorf("following rerouted documents failed: %w", err)
+			return results, nil
+		}
+	}
+
+	rc.TimeElapsed = time.Since(startTime)
+	if r.fieldsCoverage != nil {
+		validatorOptions = append(validatorOptions, fields.WithEnabledFieldsCoverage())
+	}
+	if r.fieldTimings != nil {
+		validatorOptions = append(validatorOptions, fields.WithEnabledFieldTimings())
+	}
+	validatorOptions = append(validatorOptions, fields.WithValidationBudget(r.validationBudget))
 	fieldsValidator, err := fields.CreateValidatorForDirectory(dsPath, validatorOptions...)
 	if err != nil {
 		return rc.WithErrorf("creating fields validator for data stream failed (path: %s, test case file: %s): %w", dsPath, testCaseFile, err)
 	}
 
 	err = r.verifyResults(testCaseFile, tc.config, result, fieldsValidator)
+	if r.fieldTimings != nil {
+		r.fieldTimings.add(fieldsValidator.FieldTimings())
+	}
 	if err != nil {
 		results, _ := rc.WithErrorf("verifying test result failed: %w", err)
 		return results, nil
 	}
 
+	if r.fieldsCoverage != nil {
+		r.fieldsCoverage.addCoverage(r.testFolder.DataStream, fieldsValidator.FieldsCoverage())
+	}
+
 	if r.withCoverage {
 		options := PipelineTesterOptions{
 			TestFolder:      r.testFolder,
@@ -364,9 +426,56 @@ func (r *tester) runTestCase(ctx context.Context, testCaseFile string, dsPath st
 	return rc.WithSuccess()
 }
 
+// indexDocuments indexes the documents processed by the pipeline in a temporary index created with
+// the mappings of the data stream, and adds the fields ignored at index time to the result. It fails
+// if any of the documents cannot be indexed. Documents rerouted to other data streams are not indexed,
+// as they would be stored with different mappings.
+func (r *tester) indexDocuments(ctx context.Context, processedDocs []ingest.SimulatedDocument, simulateDataStream string, indexTemplate string, result *testResult) error {
+	documents := make([]json.RawMessage, len(processedDocs))
+	for i, doc := range processedDocs {
+		if doc.Source != nil && doc.Index != simulateDataStream {
+			logger.Warnf("event %d was rerouted to another index, fields ignored at index time are not verified", i+1)
+			continue
+		}
+		documents[i] = doc.Source
+	}
+
+	docs, err := ingest.IndexPipelineDocuments(ctx, r.esAPI, documents, indexTemplate)
+	if err != nil {
+		return err
+	}
+
+	return mergeIndexedDocuments(result, docs)
+}
+
+// mergeIndexedDocuments adds the fields ignored when indexing the documents to the result. Indexed
+// documents must be in the same order as the events of the result.
+func mergeIndexedDocuments(result *testResult, docs []ingest.IndexedDocument) error {
+	if len(docs) != len(result.events) || len(docs) != len(result.ignoredFields) {
+		return fmt.Errorf("unexpected number of indexed documents (%d), expected %d", len(docs), len(result.events))
+	}
+
+	var errs multierror.Error
+	for i, doc := range docs {
+		if doc.Failure != "" {
+			errs = append(errs, fmt.Errorf("event %d couldn't be indexed: %s", i+1, doc.Failure))
+			continue
+		}
+		for _, field := range doc.IgnoredFields {
+			if !slices.Contains(result.ignoredFields[i], field) {
+				result.ignoredFields[i] = append(result.ignoredFields[i], field)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
 func loadTestCaseFile(testFolderPath, testCaseFile string) (*testCase, error) {
 	testCasePath := filepath.Join(testFolderPath, testCaseFile)
-	testCaseData, err := os.ReadFile(testCasePath)
+	testCaseData, ext, err := readTestCaseFile(testCasePath)
 	if err != nil {
 		return nil, fmt.Errorf("reading input file failed (testCasePath: %s): %w", testCasePath, err)
 	}
@@ -383,8 +492,6 @@ func loadTestCaseFile(testFolderPath, testCaseFile string) (*testCase, error) {
 		}, nil
 	}
 
-	ext := filepath.Ext(testCaseFile)
-
 	var entries []json.RawMessage
 	switch ext {
 	case ".json":
@@ -392,7 +499,7 @@ func loadTestCaseFile(testFolderPath, testCaseFile string) (*testCase, error) {
 		if err != nil {
 			return nil, fmt.Errorf("reading test case entries for events failed (testCasePath: %s): %w", testCasePath, err)
 		}
-	case ".log":
+	case ".log", ".ndjson":
 		entries, err = readTestCaseEntriesForRawInput(testCaseData, config)
 		if err != nil {
 			return nil, fmt.Errorf("creating test case entries for raw input failed (testCasePath: %s): %w", testCasePath, err)
@@ -408,6 +515,35 @@ func loadTestCaseFile(testFolderPath, testCaseFile string) (*testCase, error) {
 	return tc, nil
 }
 
+// readTestCaseFile reads the content of a test case file, and returns it with the extension of
+// the file. Files with the .gz extension are decompressed, and the extension returned is the one
+// of the decompressed file.
+func readTestCaseFile(testCasePath string) ([]byte, string, error) {
+	f, err := os.Open(testCasePath)
+	if err != nil {
+		return nil, "", err
+	}
+	defer f.Close()
+
+	var reader io.Reader = f
+	ext := filepath.Ext(testCasePath)
+	if ext == ".gz" {
+		gzipReader, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, "", fmt.Errorf("creating gzip reader failed: %w", err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+		ext = filepath.Ext(strings.TrimSuffix(testCasePath, ext))
+	}
+
+	d, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, "", err
+	}
+	return d, ext, nil
+}
+
 func (r *tester) verifyResults(testCaseFile string, config *testConfig, result *testResult, fieldsValidator *fields.Validator) error {
 	testCasePath := filepath.Join(r.testFolder.Path, testCaseFile)
 
@@ -427,9 +563,27 @@ func (r *tester) verifyResults(testCaseFile string, config *testConfig, result *
 		}
 	}
 
+	// Verify dropped events before comparing results, so unexpected drops are reported
+	// as such, and not as differences with the expected results.
+	err = verifyDroppedEvents(result, config)
+	if err != nil {
+		return err
+	}
+
+	// Dynamic fields are excluded when comparing with expected results, verify
+	// them first so failures report the values not matching their patterns.
+	err = verifyDynamicFields(stripEmptyTestResults(result), config)
+	if err != nil {
+		return err
+	}
+
 	// TODO: temporary workaround until other approach for deterministic geoip in serverless can be implemented.
 	if r.runCompareResults {
-		err = compareResults(testCasePath, config, result, *specVersion)
+		var schema []fields.FieldDefinition
+		if fieldsValidator != nil {
+			schema = fieldsValidator.Schema
+		}
+		err = compareResults(testCasePath, config, result, *specVersion, schema)
 		if _, ok := err.(testrunner.ErrTestCaseFailed); ok {
 			return err
 		}
@@ -438,13 +592,18 @@ func (r *tester) verifyResults(testCaseFile string, config *testConfig, result *
 		}
 	}
 
-	result = stripEmptyTestResults(result)
+	err = addIgnoredFieldsFromDefinitions(result, fieldsValidator)
+	if err != nil {
+		return err
+	}
 
-	err = verifyDynamicFields(result, config)
+	err = verifyIgnoredFields(result, config)
 	if err != nil {
 		return err
 	}
 
+	result = stripEmptyTestResults(result)
+
 	err = verifyFieldsInTestResult(result, fieldsValidator)
 	if err != nil {
 		return err
@@ -456,15 +615,124 @@ func (r *tester) verifyResults(testCaseFile string, config *testConfig, result *
 // documents processed by a pipeline which potentially used a "drop" processor (to drop the event at all).
 func stripEmptyTestResults(result *testResult) *testResult {
 	var tr testResult
-	for _, event := range result.events {
+	for i, event := range result.events {
 		if event == nil {
 			continue
 		}
 		tr.events = append(tr.events, event)
+		if len(result.validators) > 0 {
+			tr.validators = append(tr.validators, result.validators[i])
+		}
 	}
 	return &tr
 }
 
+// verifyDroppedEvents checks that the events dropped by the pipeline are the expected ones, if
+// these are configured. Dropped events are the ones without document in the simulate result.
+func verifyDroppedEvents(result *testResult, config *testConfig) error {
+	if config == nil || !config.DroppedEvents.enabled() {
+		return nil
+	}
+
+	var dropped []int
+	for i, event := range result.events {
+		if event == nil {
+			dropped = append(dropped, i+1)
+		}
+	}
+
+	expected := config.DroppedEvents
+	var multiErr multierror.Error
+	if len(expected.Events) > 0 {
+		for _, n := range expected.Events {
+			if n > len(result.events) {
+				multiErr = append(multiErr, fmt.Errorf("event %d expected to be dropped, but there are only %d events", n, len(result.events)))
+				continue
+			}
+			if !slices.Contains(dropped, n) {
+				multiErr = append(multiErr, fmt.Errorf("event %d expected to be dropped, but it was not", n))
+			}
+		}
+		for _, n := range dropped {
+			if !slices.Contains(expected.Events, n) {
+				multiErr = append(multiErr, fmt.Errorf("event %d was unexpectedly dropped", n))
+			}
+		}
+	} else if len(dropped) != *expected.Count {
+		multiErr = append(multiErr, fmt.Errorf("expected %d dropped events, found %d (dropped events: %v)", *expected.Count, len(dropped), dropped))
+	}
+
+	if len(multiErr) > 0 {
+		return testrunner.ErrTestCaseFailed{
+			Reason:  "dropped events don't match the expected ones",
+			Details: multiErr.Error(),
+		}
+	}
+	return nil
+}
+
+// addIgnoredFieldsFromDefinitions adds to the result the fields that would be ignored according to
+// the field definitions of the data streams where the events end, so they are detected even if the
+// documents are not indexed.
+func addIgnoredFieldsFromDefinitions(result *testResult, fieldsValidator *fields.Validator) error {
+	if result.ignoredFields == nil {
+		result.ignoredFields = make([][]string, len(result.events))
+	}
+	for i, event := range result.events {
+		validator := fieldsValidator
+		if len(result.validators) > 0 && result.validators[i] != nil {
+			validator = result.validators[i]
+		}
+		if event == nil || validator == nil {
+			continue
+		}
+
+		ignored, err := validator.IgnoredFields(event)
+		if err != nil {
+			return fmt.Errorf("checking ignored fields of event %d failed: %w", i+1, err)
+		}
+		for _, field := range ignored {
+			if !slices.Contains(result.ignoredFields[i], field) {
+				result.ignoredFields[i] = append(result.ignoredFields[i], field)
+			}
+		}
+	}
+	return nil
+}
+
+// verifyIgnoredFields checks that the processed events don't contain fields that would be ignored
+// on indexing, as happens with values longer than ignore_above, or malformed values with ignore_malformed.
+func verifyIgnoredFields(result *testResult, config *testConfig) error {
+	var expected []string
+	if config != nil {
+		expected = config.IgnoredFields.Expected
+	}
+
+	var multiErr multierror.Error
+	for i, ignoredFields := range result.ignoredFields {
+		for _, field := range ignoredFields {
+			if slices.Contains(expected, field) {
+				continue
+			}
+			multiErr = append(multiErr, fmt.Errorf("field %q would be ignored when indexing event %d", field, i+1))
+		}
+	}
+	if len(multiErr) == 0 {
+		return nil
+	}
+
+	if config != nil && config.IgnoredFields.WarnOnly {
+		for _, err := range multiErr {
+			logger.Warn(err.Error())
+		}
+		return nil
+	}
+	return testrunner.ErrTestCaseFailed{
+		Reason:  "one or more fields would be ignored when indexing documents",
+		Details: multiErr.Error(),
+	}
+}
+
 func verifyDynamicFields(result *testResult, config *testConfig) error {
 	if config == nil || config.DynamicFields == nil {
 		return nil
@@ -484,19 +752,26 @@ func verifyDynamicFields(result *testResult, config *testConfig) error {
 				return fmt.Errorf("can't remove dynamic field: %w", err)
 			}
 
-			valStr, ok := val.(string)
+			// Each value of arrays is matched independently.
+			values, ok := val.([]interface{})
 			if !ok {
-				continue // regular expressions can be verify only string values
-			}
-
-			matched, err := regexp.MatchString(pattern, valStr)
-			if err != nil {
-				return fmt.Errorf("pattern matching for dynamic field failed: %w", err)
+				values = []interface{}{val}
 			}
-
-			if !matched {
-				multiErr = append(multiErr, fmt.Errorf("dynamic field \"%s\" doesn't match the pattern (%s): %s",
-					key, pattern, valStr))
+			for _, value := range values {
+				valStr, ok := dynamicFieldValueToString(value)
+				if !ok {
+					continue // regular expressions can be verify only scalar values
+				}
+
+				matched, err := regexp.MatchString(pattern, valStr)
+				if err != nil {
+					return fmt.Errorf("pattern matching for dynamic field failed: %w", err)
+				}
+
+				if !matched {
+					multiErr = append(multiErr, fmt.Errorf("dynamic field \"%s\" doesn't match the pattern (%s): %s",
+						key, pattern, valStr))
+				}
 			}
 		}
 	}
@@ -510,25 +785,44 @@ func verifyDynamicFields(result *testResult, config *testConfig) error {
 	return nil
 }
 
+func dynamicFieldValueToString(value interface{}) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case json.Number:
+		return value.String(), true
+	case bool:
+		return strconv.FormatBool(value), true
+	default:
+		return "", false
+	}
+}
+
 func verifyFieldsInTestResult(result *testResult, fieldsValidator *fields.Validator) error {
 	var multiErr multierror.Error
-	for _, event := range result.events {
+	for i, event := range result.events {
 		err := checkErrorMessage(event)
 		if err != nil {
 			multiErr = append(multiErr, err)
 			continue // all fields can be wrong, no need validate them
 		}
 
-		errs := fieldsValidator.ValidateDocumentBody(event)
+		validator := fieldsValidator
+		if i < len(result.validators) && result.validators[i] != nil {
+			validator = result.validators[i]
+		}
+		errs := validator.ValidateDocumentBody(event)
 		if errs != nil {
 			multiErr = append(multiErr, errs...)
 		}
 	}
 
 	if len(multiErr) > 0 {
+		multiErr = multiErr.Unique()
 		return testrunner.ErrTestCaseFailed{
 			Reason:  "one or more problems with fields found in documents",
-			Details: multiErr.Unique().Error(),
+			Details: multiErr.Error(),
+			Errors:  multiErr,
 		}
 	}
 	return nil
@@ -8,14 +8,18 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pmezard/go-difflib/difflib"
 
 	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/fields"
 	"github.com/elastic/elastic-package/internal/formatter"
 	"github.com/elastic/elastic-package/internal/testrunner"
 )
@@ -24,6 +28,13 @@ const expectedTestResultSuffix = "-expected.json"
 
 type testResult struct {
 	events []json.RawMessage
+
+	// ignoredFields contains the fields ignored in each one of the events.
+	ignoredFields [][]string
+
+	// validators contains, for events rerouted to other data streams, the validator of
+	// the destination. Events without validator are validated with the tested data stream.
+	validators []*fields.Validator
 }
 
 type testResultDefinition struct {
@@ -45,7 +56,7 @@ func writeTestResult(testCasePath string, result *testResult, specVersion semver
 	return nil
 }
 
-func compareResults(testCasePath string, config *testConfig, result *testResult, specVersion semver.Version) error {
+func compareResults(testCasePath string, config *testConfig, result *testResult, specVersion semver.Version, schema []fields.FieldDefinition) error {
 	resultsWithoutDynamicFields, err := adjustTestResult(result, config)
 	if err != nil {
 		return fmt.Errorf("can't adjust test results: %w", err)
@@ -66,7 +77,7 @@ func compareResults(testCasePath string, config *testConfig, result *testResult,
 		return fmt.Errorf("marshalling expected test results failed: %w", err)
 	}
 
-	report, err := diffJson(expected, actual, specVersion)
+	report, err := diffJson(expected, actual, specVersion, schema)
 	if err != nil {
 		return fmt.Errorf("comparing expected test result: %w", err)
 	}
@@ -103,7 +114,98 @@ func compareJsonNumbers(a, b json.Number) bool {
 	return false
 }
 
-func diffJson(want, got []byte, specVersion semver.Version) (string, error) {
+// compareScaledJsonNumbers compares numbers with the precision given by the scaling factor
+// of a scaled_float field.
+func compareScaledJsonNumbers(a, b json.Number, scalingFactor float64) bool {
+	if compareJsonNumbers(a, b) {
+		return true
+	}
+	floata, err := a.Float64()
+	if err != nil {
+		return false
+	}
+	floatb, err := b.Float64()
+	if err != nil {
+		return false
+	}
+	return math.Round(floata*scalingFactor) == math.Round(floatb*scalingFactor)
+}
+
+// jsonNumbersComparer returns the options to compare numbers in test results. Values of
+// scaled_float fields are compared with the precision of their scaling factor, as this
+// is the precision used to store them.
+func jsonNumbersComparer(schema []fields.FieldDefinition) cmp.Option {
+	factors := scalingFactors(schema, nil)
+	if len(factors) == 0 {
+		return cmp.Comparer(compareJsonNumbers)
+	}
+
+	cache := make(map[string]float64)
+	scalingFactor := func(p cmp.Path) float64 {
+		key := resultFieldKey(p)
+		if key == "" {
+			return 0
+		}
+		factor, found := cache[key]
+		if !found {
+			definition := fields.FindElementDefinition(key, schema)
+			if definition != nil && definition.Type == "scaled_float" {
+				factor = definition.ScalingFactor
+			}
+			cache[key] = factor
+		}
+		return factor
+	}
+
+	options := cmp.Options{
+		cmp.FilterPath(func(p cmp.Path) bool {
+			return scalingFactor(p) == 0
+		}, cmp.Comparer(compareJsonNumbers)),
+	}
+	for _, factor := range factors {
+		options = append(options, cmp.FilterPath(func(p cmp.Path) bool {
+			return scalingFactor(p) == factor
+		}, cmp.Comparer(func(a, b json.Number) bool {
+			return compareScaledJsonNumbers(a, b, factor)
+		})))
+	}
+	return options
+}
+
+// scalingFactors returns the different scaling factors used by the scaled_float fields in the schema.
+func scalingFactors(schema []fields.FieldDefinition, factors []float64) []float64 {
+	for _, definition := range schema {
+		if definition.Type == "scaled_float" && definition.ScalingFactor > 0 && !slices.Contains(factors, definition.ScalingFactor) {
+			factors = append(factors, definition.ScalingFactor)
+		}
+		factors = scalingFactors(definition.Fields, factors)
+		factors = scalingFactors(definition.MultiFields, factors)
+	}
+	return factors
+}
+
+// resultFieldKey returns the key of the field in the events of a test result for the given path.
+// The first key in the path is the list of expected events, so it is not part of the key.
+func resultFieldKey(p cmp.Path) string {
+	var keys []string
+	for _, step := range p {
+		mapIndex, ok := step.(cmp.MapIndex)
+		if !ok {
+			continue
+		}
+		key, ok := mapIndex.Key().Interface().(string)
+		if !ok {
+			return ""
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) < 2 {
+		return ""
+	}
+	return strings.Join(keys[1:], ".")
+}
+
+func diffJson(want, got []byte, specVersion semver.Version, schema []fields.FieldDefinition) (string, error) {
 	var gotVal, wantVal interface{}
 	err := formatter.JSONUnmarshalUsingNumber(want, &wantVal)
 	if err != nil {
@@ -113,7 +215,7 @@ func diffJson(want, got []byte, specVersion semver.Version) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("invalid got value: %w", err)
 	}
-	if cmp.Equal(gotVal, wantVal, cmp.Comparer(compareJsonNumbers)) {
+	if cmp.Equal(gotVal, wantVal, jsonNumbersComparer(schema)) {
 		return "", nil
 	}
 
@@ -159,6 +261,36 @@ func readExpectedTestResult(testCasePath string, config *testConfig) (*testResul
 	return adjusted, nil
 }
 
+// ExpectedDocuments returns the documents in the expected results of the test cases in the given
+// pipeline test folder. Dropped documents are not included.
+func ExpectedDocuments(testFolderPath string) ([]common.MapStr, error) {
+	files, err := filepath.Glob(filepath.Join(testFolderPath, "*"+expectedTestResultSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("failed matching pipeline test results: %w", err)
+	}
+
+	var docs []common.MapStr
+	for _, file := range files {
+		body, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading expected test result failed: %w", err)
+		}
+		var result struct {
+			Expected []common.MapStr `json:"expected"`
+		}
+		err = json.Unmarshal(body, &result)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshalling expected test result failed (path: %s): %w", file, err)
+		}
+		for _, doc := range result.Expected {
+			if doc != nil {
+				docs = append(docs, doc)
+			}
+		}
+	}
+	return docs, nil
+}
+
 func adjustTestResult(result *testResult, config *testConfig) (*testResult, error) {
 	if config == nil || config.DynamicFields == nil {
 		return result, nil
@@ -8,7 +8,11 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/fields"
 )
 
 func TestCompareJsonNumber(t *testing.T) {
@@ -43,3 +47,61 @@ func TestCompareJsonNumber(t *testing.T) {
 		})
 	}
 }
+
+func TestDiffJsonScaledFloat(t *testing.T) {
+	schema := []fields.FieldDefinition{
+		{
+			Name: "host",
+			Type: "group",
+			Fields: []fields.FieldDefinition{
+				{Name: "cpu.usage", Type: "scaled_float", ScalingFactor: 1000},
+				{Name: "load", Type: "float"},
+			},
+		},
+	}
+	specVersion := *semver.MustParse("3.0.0")
+
+	cases := []struct {
+		title string
+		want  string
+		got   string
+		equal bool
+	}{
+		{
+			title: "scaled float with different representation",
+			want:  `{"expected":[{"host":{"cpu":{"usage":0.1234}}}]}`,
+			got:   `{"expected":[{"host":{"cpu":{"usage":0.12340000001}}}]}`,
+			equal: true,
+		},
+		{
+			title: "scaled float with dotted keys",
+			want:  `{"expected":[{"host.cpu.usage":0.1234}]}`,
+			got:   `{"expected":[{"host.cpu.usage":0.1233999}]}`,
+			equal: true,
+		},
+		{
+			title: "scaled float different at scaling factor precision",
+			want:  `{"expected":[{"host":{"cpu":{"usage":0.123}}}]}`,
+			got:   `{"expected":[{"host":{"cpu":{"usage":0.124}}}]}`,
+			equal: false,
+		},
+		{
+			title: "other floats are compared exactly",
+			want:  `{"expected":[{"host":{"load":0.1234}}]}`,
+			got:   `{"expected":[{"host":{"load":0.12340000001}}]}`,
+			equal: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			report, err := diffJson([]byte(c.want), []byte(c.got), specVersion, schema)
+			require.NoError(t, err)
+			if c.equal {
+				assert.Empty(t, report)
+			} else {
+				assert.NotEmpty(t, report)
+			}
+		})
+	}
+}
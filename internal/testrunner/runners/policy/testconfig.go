@@ -5,11 +5,16 @@
 package policy
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/elastic/elastic-package/internal/multierror"
 	"github.com/elastic/elastic-package/internal/testrunner"
 )
 
@@ -30,10 +35,31 @@ func readTestConfig(testPath string) (*testConfig, error) {
 	}
 
 	var config testConfig
-	err = yaml.Unmarshal(d, &config)
-	if err != nil {
+	decoder := yaml.NewDecoder(bytes.NewReader(d))
+	decoder.KnownFields(true)
+	err = decoder.Decode(&config)
+	if err != nil && !errors.Is(err, io.EOF) {
 		return nil, fmt.Errorf("failed to decode config: %w", err)
 	}
 
 	return &config, nil
 }
+
+// ValidateConfigFiles checks that the policy test configurations in the given test folder can be loaded.
+func ValidateConfigFiles(testFolderPath string) error {
+	tests, err := filepath.Glob(filepath.Join(testFolderPath, "test-*.yml"))
+	if err != nil {
+		return err
+	}
+	var errs multierror.Error
+	for _, test := range tests {
+		_, err := readTestConfig(test)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid policy test configuration file: %s: %w", test, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
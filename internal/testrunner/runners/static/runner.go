@@ -17,8 +17,6 @@ import (
 const (
 	// TestType defining asset loading tests
 	TestType testrunner.TestType = "static"
-
-	sampleEventJSON = "sample_event.json"
 )
 
 type runner struct {
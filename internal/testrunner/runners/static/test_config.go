@@ -18,6 +18,23 @@ import (
 
 type testConfig struct {
 	testrunner.SkippableConfig `config:",inline"`
+
+	PIICheck piiCheckConfig `config:"pii_check"`
+
+	// NumericKeywordFields holds a list of fields that have keyword
+	// type but can be ingested as numeric type.
+	NumericKeywordFields []string `config:"numeric_keyword_fields"`
+
+	// StringNumberFields holds a list of fields that have numeric
+	// types but can be ingested as strings.
+	StringNumberFields []string `config:"string_number_fields"`
+}
+
+// piiCheckConfig configures the check for likely real personal data in sample events.
+type piiCheckConfig struct {
+	Enabled        bool     `config:"enabled"`
+	DeniedDomains  []string `config:"denied_domains"`
+	DeniedPatterns []string `config:"denied_patterns"`
 }
 
 func newConfig(staticTestFolderPath string) (*testConfig, error) {
@@ -40,9 +57,15 @@ func newConfig(staticTestFolderPath string) (*testConfig, error) {
 	}
 
 	var c testConfig
-	if err := cfg.Unpack(&c); err != nil {
+	if err := testrunner.UnpackConfigStrict(cfg, &c); err != nil {
 		return nil, fmt.Errorf("unable to unpack static test configuration file: %s: %w", configFilePath, err)
 	}
 
 	return &c, nil
 }
+
+// ValidateConfig checks that the test configuration in the given test folder, if any, can be loaded.
+func ValidateConfig(testFolderPath string) error {
+	_, err := newConfig(testFolderPath)
+	return err
+}
@@ -6,9 +6,7 @@ package static
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 
 	"github.com/elastic/elastic-package/internal/benchrunner/runners/stream"
@@ -96,8 +94,8 @@ func (r tester) run(ctx context.Context) ([]testrunner.TestResult, error) {
 		return result.WithError(fmt.Errorf("failed to read manifest: %w", err))
 	}
 
-	// join together results from verifyStreamConfig and verifySampleEvent
-	return append(r.verifyStreamConfig(ctx, r.packageRootPath), r.verifySampleEvent(pkgManifest)...), nil
+	// join together results from verifyStreamConfig and verifySampleEvents
+	return append(r.verifyStreamConfig(ctx, r.packageRootPath), r.verifySampleEvents(pkgManifest, testConfig)...), nil
 }
 
 func (r tester) verifyStreamConfig(ctx context.Context, packageRootPath string) []testrunner.TestResult {
@@ -129,60 +127,96 @@ func (r tester) verifyStreamConfig(ctx context.Context, packageRootPath string)
 	return results
 }
 
-func (r tester) verifySampleEvent(pkgManifest *packages.PackageManifest) []testrunner.TestResult {
+func (r tester) verifySampleEvents(pkgManifest *packages.PackageManifest, testConfig *testConfig) []testrunner.TestResult {
 	resultComposer := testrunner.NewResultComposer(testrunner.TestResult{
-		Name:       "Verify " + sampleEventJSON,
+		Name:       "Verify sample events",
 		TestType:   TestType,
 		Package:    r.testFolder.Package,
 		DataStream: r.testFolder.DataStream,
 	})
 
-	sampleEventPath, found, err := r.getSampleEventPath()
+	dir := r.packageRootPath
+	if r.testFolder.DataStream != "" {
+		dir = filepath.Join(r.packageRootPath, "data_stream", r.testFolder.DataStream)
+	}
+	paths, err := fields.SampleEventFiles(dir)
 	if err != nil {
 		results, _ := resultComposer.WithError(err)
 		return results
 	}
-	if !found {
+	if len(paths) == 0 {
 		// Nothing to do.
 		return []testrunner.TestResult{}
 	}
 
-	if r.withCoverage {
-		coverage, err := testrunner.GenerateBaseFileCoverageReport(resultComposer.CoveragePackageName(), sampleEventPath, r.coverageType, true)
-		if err != nil {
-			results, _ := resultComposer.WithErrorf("coverage report generation failed: %w", err)
-			return results
-		}
-		resultComposer = resultComposer.WithCoverage(coverage)
-	}
-
 	expectedDatasets, err := r.getExpectedDatasets(pkgManifest)
 	if err != nil {
 		results, _ := resultComposer.WithError(err)
 		return results
 	}
-	fieldsValidator, err := fields.CreateValidatorForDirectory(filepath.Dir(sampleEventPath),
+	validatorOptions := []fields.ValidatorOption{
 		fields.WithSpecVersion(pkgManifest.SpecVersion),
 		fields.WithDefaultNumericConversion(),
 		fields.WithExpectedDatasets(expectedDatasets),
 		fields.WithEnabledImportAllECSSChema(true),
-	)
+	}
+	if testConfig != nil {
+		validatorOptions = append(validatorOptions,
+			fields.WithNumericKeywordFields(testConfig.NumericKeywordFields),
+			fields.WithStringNumberFields(testConfig.StringNumberFields),
+		)
+		if testConfig.PIICheck.Enabled {
+			validatorOptions = append(validatorOptions,
+				fields.WithEnabledPIICheck(testConfig.PIICheck.DeniedDomains, testConfig.PIICheck.DeniedPatterns))
+		}
+	}
+	fieldsValidator, err := fields.CreateValidatorForDirectory(dir, validatorOptions...)
 	if err != nil {
 		results, _ := resultComposer.WithError(fmt.Errorf("creating fields validator for data stream failed: %w", err))
 		return results
 	}
 
-	content, err := os.ReadFile(sampleEventPath)
+	sampleEvents, err := fieldsValidator.ValidateSampleEvents(dir)
 	if err != nil {
-		results, _ := resultComposer.WithError(fmt.Errorf("can't read file: %w", err))
+		results, _ := resultComposer.WithError(err)
 		return results
 	}
 
-	multiErr := fieldsValidator.ValidateDocumentBody(content)
-	if len(multiErr) > 0 {
+	var results []testrunner.TestResult
+	for _, sampleEvent := range sampleEvents {
+		results = append(results, r.sampleEventResult(dir, sampleEvent)...)
+	}
+	return results
+}
+
+// sampleEventResult builds the test result of a validated sample event, named after its
+// path relative to the data stream, so the result of sample_event.json keeps its usual name.
+func (r tester) sampleEventResult(dir string, sampleEvent fields.SampleEventResult) []testrunner.TestResult {
+	name, err := filepath.Rel(dir, sampleEvent.Path)
+	if err != nil {
+		name = sampleEvent.Path
+	}
+	resultComposer := testrunner.NewResultComposer(testrunner.TestResult{
+		Name:       "Verify " + filepath.ToSlash(name),
+		TestType:   TestType,
+		Package:    r.testFolder.Package,
+		DataStream: r.testFolder.DataStream,
+	})
+
+	if r.withCoverage {
+		coverage, err := testrunner.GenerateBaseFileCoverageReport(resultComposer.CoveragePackageName(), sampleEvent.Path, r.coverageType, true)
+		if err != nil {
+			results, _ := resultComposer.WithErrorf("coverage report generation failed: %w", err)
+			return results
+		}
+		resultComposer = resultComposer.WithCoverage(coverage)
+	}
+
+	if len(sampleEvent.Errors) > 0 {
 		results, _ := resultComposer.WithError(testrunner.ErrTestCaseFailed{
-			Reason:  "one or more errors found in document",
-			Details: multiErr.Error(),
+			Reason:  fmt.Sprintf("one or more errors found in %s", relativeSampleEventPath(r.packageRootPath, sampleEvent.Path)),
+			Details: sampleEvent.Errors.Error(),
+			Errors:  sampleEvent.Errors,
 		})
 		return results
 	}
@@ -191,25 +225,13 @@ func (r tester) verifySampleEvent(pkgManifest *packages.PackageManifest) []testr
 	return results
 }
 
-func (r tester) getSampleEventPath() (string, bool, error) {
-	var sampleEventPath string
-	if r.testFolder.DataStream != "" {
-		sampleEventPath = filepath.Join(
-			r.packageRootPath,
-			"data_stream",
-			r.testFolder.DataStream,
-			sampleEventJSON)
-	} else {
-		sampleEventPath = filepath.Join(r.packageRootPath, sampleEventJSON)
-	}
-	_, err := os.Stat(sampleEventPath)
-	if errors.Is(err, os.ErrNotExist) {
-		return "", false, nil
-	}
+// relativeSampleEventPath returns the path of the sample event relative to the package root, to be used in messages.
+func relativeSampleEventPath(packageRootPath, sampleEventPath string) string {
+	rel, err := filepath.Rel(packageRootPath, sampleEventPath)
 	if err != nil {
-		return "", false, fmt.Errorf("stat file failed: %w", err)
+		return sampleEventPath
 	}
-	return sampleEventPath, true, nil
+	return rel
 }
 
 func (r tester) getExpectedDatasets(pkgManifest *packages.PackageManifest) ([]string, error) {
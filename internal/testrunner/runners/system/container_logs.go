@@ -0,0 +1,193 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/elastic/elastic-package/internal/builder"
+	"github.com/elastic/elastic-package/internal/logger"
+	"github.com/elastic/elastic-package/internal/servicedeployer"
+	"github.com/elastic/elastic-package/internal/stack"
+	"github.com/elastic/elastic-package/internal/testrunner"
+)
+
+const (
+	// DefaultContainerLogsTail is the default number of lines dumped from the logs of each
+	// container when a test fails.
+	DefaultContainerLogsTail = 1000
+
+	// maxContainerLogsSize is the maximum size of the logs dumped for each container, only
+	// the last bytes are kept when logs are bigger.
+	maxContainerLogsSize = 5 * 1024 * 1024
+
+	stackElasticAgentService = "elastic-agent"
+)
+
+// containerLogsSource is a container whose logs can be dumped when a test fails.
+type containerLogsSource struct {
+	name string
+	logs func(ctx context.Context, since time.Time) ([]byte, error)
+}
+
+// dumpContainerLogs dumps the logs of the service and agent containers used by the test, so they
+// can be checked when investigating failures. It returns the paths of the files written.
+func (r *tester) dumpContainerLogs(ctx context.Context, testName string, since time.Time) []string {
+	sources := r.containerLogsSources()
+	if len(sources) == 0 {
+		return nil
+	}
+
+	buildDir, err := builder.BuildDirectory()
+	if err != nil {
+		logger.Warnf("can't dump container logs, locating build directory failed: %v", err)
+		return nil
+	}
+	dir := filepath.Join(buildDir, "test-results", "container-logs",
+		fmt.Sprintf("%s-%s-%s-%d", r.testFolder.Package, r.testFolder.DataStream, sanitizeFileName(testName), time.Now().UnixNano()))
+
+	var paths []string
+	for _, source := range sources {
+		content, err := source.logs(ctx, since)
+		if errors.Is(err, servicedeployer.ErrNotSupported) {
+			continue
+		}
+		if err != nil {
+			logger.Warnf("can't get logs of %s container: %v", source.name, err)
+			continue
+		}
+		if len(content) == 0 {
+			continue
+		}
+
+		err = os.MkdirAll(dir, 0755)
+		if err != nil {
+			logger.Warnf("can't create directory for container logs (path: %s): %v", dir, err)
+			return paths
+		}
+		path := filepath.Join(dir, source.name+".log")
+		err = os.WriteFile(path, tailLogs(content, r.containerLogsTail, maxContainerLogsSize), 0644)
+		if err != nil {
+			logger.Warnf("can't write container logs (path: %s): %v", path, err)
+			continue
+		}
+		logger.Debugf("Container logs of %s written to %s", source.name, path)
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func (r *tester) containerLogsSources() []containerLogsSource {
+	var sources []containerLogsSource
+	if r.serviceLogsHandler != nil {
+		sources = append(sources, containerLogsSource{name: "service", logs: r.serviceLogsHandler})
+	}
+	if r.agentLogsHandler != nil {
+		sources = append(sources, containerLogsSource{name: "elastic-agent", logs: r.agentLogsHandler})
+	} else {
+		// Agent running in the Elastic stack.
+		sources = append(sources, containerLogsSource{name: "elastic-agent", logs: r.stackAgentLogs})
+	}
+	return sources
+}
+
+func (r *tester) stackAgentLogs(ctx context.Context, since time.Time) ([]byte, error) {
+	stackConfig, err := stack.LoadConfig(r.profile)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := stack.BuildProvider(stackConfig.Provider, r.profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build stack provider: %w", err)
+	}
+
+	dump, err := provider.Dump(ctx, stack.DumpOptions{
+		Profile:  r.profile,
+		Services: []string{stackElasticAgentService},
+		Since:    since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dump failed: %w", err)
+	}
+	for _, result := range dump {
+		if result.ServiceName == stackElasticAgentService {
+			return result.Logs, nil
+		}
+	}
+	return nil, nil
+}
+
+// tailLogs returns the last lines of the logs, up to the given number of lines and size.
+// All lines are returned if lines is not greater than zero.
+func tailLogs(content []byte, lines int, maxSize int) []byte {
+	content = bytes.TrimRight(content, "\n")
+	if lines > 0 {
+		for i, n := len(content)-1, 0; i >= 0; i-- {
+			if content[i] != '\n' {
+				continue
+			}
+			n++
+			if n == lines {
+				content = content[i+1:]
+				break
+			}
+		}
+	}
+	if len(content) > maxSize {
+		content = content[len(content)-maxSize:]
+		if i := bytes.IndexByte(content, '\n'); i >= 0 {
+			content = content[i+1:]
+		}
+	}
+	return append(content, '\n')
+}
+
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', ' ':
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// withContainerLogs adds references to the dumped container logs to failed results and errors.
+func withContainerLogs(results []testrunner.TestResult, err error, paths []string) ([]testrunner.TestResult, error) {
+	if len(paths) == 0 {
+		return results, err
+	}
+
+	message := "container logs: " + strings.Join(paths, ", ")
+	for i := range results {
+		switch {
+		case results[i].ErrorMsg != "":
+			results[i].ErrorMsg += " (" + message + ")"
+		case results[i].FailureMsg != "":
+			results[i].FailureDetails = strings.TrimSpace(results[i].FailureDetails + "\n" + message)
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("%w (%s)", err, message)
+	}
+	return results, err
+}
+
+func failedResults(results []testrunner.TestResult) bool {
+	for _, result := range results {
+		if result.ErrorMsg != "" || result.FailureMsg != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/elastic-package/internal/testrunner"
+)
+
+func TestTailLogs(t *testing.T) {
+	logs := []byte("line 1\nline 2\nline 3\nline 4\n")
+
+	cases := []struct {
+		title    string
+		lines    int
+		maxSize  int
+		expected string
+	}{
+		{title: "all lines", lines: 0, maxSize: 1024, expected: "line 1\nline 2\nline 3\nline 4\n"},
+		{title: "last lines", lines: 2, maxSize: 1024, expected: "line 3\nline 4\n"},
+		{title: "more lines than available", lines: 10, maxSize: 1024, expected: "line 1\nline 2\nline 3\nline 4\n"},
+		{title: "size cap keeps complete lines", lines: 0, maxSize: 16, expected: "line 3\nline 4\n"},
+		{title: "size cap and lines", lines: 3, maxSize: 10, expected: "line 4\n"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			assert.Equal(t, c.expected, string(tailLogs(logs, c.lines, c.maxSize)))
+		})
+	}
+}
+
+func TestWithContainerLogs(t *testing.T) {
+	paths := []string{"build/test-results/container-logs/test/service.log"}
+
+	t.Run("failure", func(t *testing.T) {
+		results := []testrunner.TestResult{{FailureMsg: "no documents found"}}
+		results, err := withContainerLogs(results, nil, paths)
+		assert.NoError(t, err)
+		assert.Equal(t, "no documents found", results[0].FailureMsg)
+		assert.Equal(t, "container logs: build/test-results/container-logs/test/service.log", results[0].FailureDetails)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		results := []testrunner.TestResult{{ErrorMsg: "could not setup service"}}
+		results, err := withContainerLogs(results, errors.New("could not setup service"), paths)
+		assert.EqualError(t, err, "could not setup service (container logs: build/test-results/container-logs/test/service.log)")
+		assert.Equal(t, "could not setup service (container logs: build/test-results/container-logs/test/service.log)", results[0].ErrorMsg)
+	})
+
+	t.Run("no logs", func(t *testing.T) {
+		results := []testrunner.TestResult{{ErrorMsg: "could not setup service"}}
+		results, err := withContainerLogs(results, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "could not setup service", results[0].ErrorMsg)
+	})
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/elastic/elastic-package/internal/logger"
 	"github.com/elastic/elastic-package/internal/packages"
 	"github.com/elastic/elastic-package/internal/profile"
+	"github.com/elastic/elastic-package/internal/progress"
 	"github.com/elastic/elastic-package/internal/resources"
 	"github.com/elastic/elastic-package/internal/servicedeployer"
 	"github.com/elastic/elastic-package/internal/testrunner"
@@ -37,6 +38,8 @@ type runner struct {
 	globalTestConfig   testrunner.GlobalRunnerTestConfig
 	failOnMissingTests bool
 	checkFailureStore  bool
+	containerLogsTail  int
+	testPolicies       *sharedTestPolicies
 	deferCleanup       time.Duration
 	generateTestResult bool
 	withCoverage       bool
@@ -49,6 +52,8 @@ type runner struct {
 
 	resourcesManager     *resources.Manager
 	serviceStateFilePath string
+
+	progress *progress.Reporter
 }
 
 // Ensures that runner implements testrunner.TestRunner interface
@@ -75,10 +80,18 @@ type SystemTestRunnerOptions struct {
 
 	FailOnMissingTests bool
 	CheckFailureStore  bool
+	ContainerLogsTail  int
 	GenerateTestResult bool
 	DeferCleanup       time.Duration
 	WithCoverage       bool
 	CoverageType       string
+
+	// ReuseAgentPolicy enables reusing the test policies between scenarios that
+	// configure the package data stream with the same variables.
+	ReuseAgentPolicy bool
+
+	// Progress reports the progress of the tests, it can be nil.
+	Progress *progress.Reporter
 }
 
 func NewSystemTestRunner(options SystemTestRunnerOptions) *runner {
@@ -96,11 +109,18 @@ func NewSystemTestRunner(options SystemTestRunnerOptions) *runner {
 		runTearDown:        options.RunTearDown,
 		failOnMissingTests: options.FailOnMissingTests,
 		checkFailureStore:  options.CheckFailureStore,
+		containerLogsTail:  options.ContainerLogsTail,
 		generateTestResult: options.GenerateTestResult,
 		deferCleanup:       options.DeferCleanup,
 		globalTestConfig:   options.GlobalTestConfig,
 		withCoverage:       options.WithCoverage,
 		coverageType:       options.CoverageType,
+		progress:           options.Progress,
+	}
+
+	// Policies are not reused when running by stages, as they are stored in the service state.
+	if options.ReuseAgentPolicy && !r.runSetup && !r.runTearDown && !r.runTestsOnly {
+		r.testPolicies = newSharedTestPolicies()
 	}
 
 	r.resourcesManager = resources.NewManager()
@@ -124,7 +144,12 @@ func (r *runner) SetupRunner(ctx context.Context) error {
 		// Install it unless we are running the tear down only.
 		installedPackage: !r.runTearDown,
 	}
+	done := func() {}
+	if resourcesOptions.installedPackage {
+		done = r.progress.Wait("Installing package")
+	}
 	_, err := r.resourcesManager.ApplyCtx(ctx, r.resources(resourcesOptions))
+	done()
 	if err != nil {
 		return fmt.Errorf("can't install the package: %w", err)
 	}
@@ -163,6 +188,13 @@ func (r *runner) setupFailureStore(ctx context.Context) error {
 // TearDownRunner cleans up any global test runner resources. It must be called
 // after the test runner has finished executing all its tests.
 func (r *runner) TearDownRunner(ctx context.Context) error {
+	if r.testPolicies != nil {
+		// Policies must be deleted before uninstalling the package, as they use it.
+		if err := r.testPolicies.deleteAll(ctx, r.kibanaClient); err != nil {
+			return err
+		}
+	}
+
 	logger.Debug("Uninstalling package...")
 	resourcesOptions := resourcesOptions{
 		// Keep it installed only if we were running setup, or tests only.
@@ -272,6 +304,13 @@ func (r *runner) GetTests(ctx context.Context) ([]testrunner.Tester, error) {
 			if err != nil {
 				return nil, fmt.Errorf("failed to retrieve config files from %s: %w", t.Path, err)
 			}
+
+			for _, config := range cfgFiles {
+				err := checkConfigDurations(filepath.Join(t.Path, config))
+				if err != nil {
+					return nil, err
+				}
+			}
 		}
 
 		for _, variant := range variants {
@@ -295,6 +334,9 @@ func (r *runner) GetTests(ctx context.Context) ([]testrunner.Tester, error) {
 					WithCoverage:       r.withCoverage,
 					CoverageType:       r.coverageType,
 					CheckFailureStore:  r.checkFailureStore,
+					ContainerLogsTail:  r.containerLogsTail,
+					TestPolicies:       r.testPolicies,
+					Progress:           r.progress,
 				})
 				if err != nil {
 					return nil, fmt.Errorf(
@@ -32,6 +32,7 @@ type ServiceState struct {
 	ServiceRunID     string        `json:"service_info_run_id"`
 	AgentRunID       string        `json:"agent_info_run_id"`
 	ServiceOutputDir string        `json:"service_output_dir"`
+	CreatedSpace     bool          `json:"created_space,omitempty"`
 }
 
 // stateFolderPath returns the folder where the state data is stored
@@ -61,6 +62,7 @@ type scenarioStateOpts struct {
 	agent         kibana.Agent
 	agentInfo     agentdeployer.AgentInfo
 	svcInfo       servicedeployer.ServiceInfo
+	createdSpace  bool
 }
 
 func writeScenarioState(opts scenarioStateOpts, target string) error {
@@ -74,6 +76,7 @@ func writeScenarioState(opts scenarioStateOpts, target string) error {
 		ServiceRunID:     opts.svcInfo.Test.RunID,
 		AgentRunID:       opts.agentInfo.Test.RunID,
 		ServiceOutputDir: opts.svcInfo.OutputDir,
+		CreatedSpace:     opts.createdSpace,
 	}
 	dataBytes, err := json.Marshal(data)
 	if err != nil {
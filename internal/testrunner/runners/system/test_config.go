@@ -20,12 +20,19 @@ import (
 
 	"github.com/elastic/elastic-package/internal/agentdeployer"
 	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/multierror"
 	"github.com/elastic/elastic-package/internal/servicedeployer"
 	"github.com/elastic/elastic-package/internal/testrunner"
 )
 
 var systemTestConfigFilePattern = regexp.MustCompile(`^test-([a-z0-9_.-]+)-config.yml$`)
 
+// kibanaSpaceIDPattern matches the identifiers allowed for Kibana spaces.
+var kibanaSpaceIDPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// durationConfigKeys are the settings of the system test configuration whose values are durations.
+var durationConfigKeys = []string{"wait_for_data_timeout", "timeout"}
+
 type testConfig struct {
 	testrunner.SkippableConfig `config:",inline"`
 
@@ -35,8 +42,13 @@ type testConfig struct {
 	ServiceNotifySignal string        `config:"service_notify_signal"` // Signal to send when the agent policy is applied.
 	IgnoreServiceError  bool          `config:"ignore_service_error"`
 	WaitForDataTimeout  time.Duration `config:"wait_for_data_timeout"`
+	Timeout             time.Duration `config:"timeout"` // Overrides the deadlines to wait for agents and data, including wait_for_data_timeout.
 	SkipIgnoredFields   []string      `config:"skip_ignored_fields"`
 
+	// SpaceID is the Kibana space where the assets of the package are installed and where the
+	// test policies are created. The space is created if it doesn't exist.
+	SpaceID string `config:"space_id"`
+
 	Vars       common.MapStr `config:"vars"`
 	DataStream struct {
 		Vars common.MapStr `config:"vars"`
@@ -45,6 +57,11 @@ type testConfig struct {
 	Assert struct {
 		// Expected number of hits for a given test
 		HitCount int `config:"hit_count"`
+
+		// Minimum and maximum number of documents expected in the data stream
+		// after waiting for data.
+		MinCount int `config:"min_count"`
+		MaxCount int `config:"max_count"`
 	} `config:"assert"`
 
 	// NumericKeywordFields holds a list of fields that have keyword
@@ -64,6 +81,27 @@ type testConfig struct {
 	} `config:"agent"`
 }
 
+// waitForDataTimeout returns the time to wait for data in the data stream. If set, timeout
+// overrides wait_for_data_timeout.
+func (t testConfig) waitForDataTimeout() time.Duration {
+	switch {
+	case t.Timeout > 0:
+		return t.Timeout
+	case t.WaitForDataTimeout > 0:
+		return t.WaitForDataTimeout
+	default:
+		return waitForDataDefaultTimeout
+	}
+}
+
+// enrollmentTimeout returns the time to wait for agents to be enrolled.
+func (t testConfig) enrollmentTimeout() time.Duration {
+	if t.Timeout > 0 {
+		return t.Timeout
+	}
+	return enrollmentDefaultTimeout
+}
+
 func (t testConfig) Name() string {
 	name := filepath.Base(t.Path)
 	if matches := systemTestConfigFilePattern.FindStringSubmatch(name); len(matches) > 1 {
@@ -101,9 +139,23 @@ func newConfig(configFilePath string, svcInfo servicedeployer.ServiceInfo, servi
 	if err != nil {
 		return nil, fmt.Errorf("unable to load system test configuration file: %s: %w", configFilePath, err)
 	}
-	if err := cfg.Unpack(&c); err != nil {
+	if err := validateDurations(cfg); err != nil {
+		return nil, fmt.Errorf("invalid system test configuration file: %s: %w", configFilePath, err)
+	}
+	if err := testrunner.UnpackConfigStrict(cfg, &c); err != nil {
 		return nil, fmt.Errorf("unable to unpack system test configuration file: %s: %w", configFilePath, err)
 	}
+	if c.Assert.MinCount < 0 || c.Assert.MaxCount < 0 {
+		return nil, fmt.Errorf("invalid assert configuration in %s: min_count and max_count cannot be negative", configFilePath)
+	}
+	if c.Assert.MaxCount > 0 && c.Assert.MinCount > c.Assert.MaxCount {
+		return nil, fmt.Errorf("invalid assert configuration in %s: min_count (%d) cannot be greater than max_count (%d)", configFilePath, c.Assert.MinCount, c.Assert.MaxCount)
+	}
+
+	if c.SpaceID != "" && !kibanaSpaceIDPattern.MatchString(c.SpaceID) {
+		return nil, fmt.Errorf("invalid space_id %q in %s: only lowercase letters, numbers, underscores and hyphens are allowed", c.SpaceID, configFilePath)
+	}
+
 	// Save path
 	c.Path = configFilePath
 	c.ServiceVariantName = serviceVariantName
@@ -124,6 +176,72 @@ func newConfig(configFilePath string, svcInfo servicedeployer.ServiceInfo, servi
 	return &c, nil
 }
 
+// checkConfigDurations validates the durations of a system test configuration file before
+// any service is deployed, so malformed values are reported without waiting for the test to start.
+// Placeholders are rendered with empty values, as the service information is not known yet.
+func checkConfigDurations(configFilePath string) error {
+	data, err := os.ReadFile(configFilePath)
+	if err != nil {
+		return fmt.Errorf("could not load system test configuration file: %s: %w", configFilePath, err)
+	}
+	data, err = applyServiceInfo(data, servicedeployer.ServiceInfo{})
+	if err != nil {
+		return fmt.Errorf("could not apply context to test configuration file: %s: %w", configFilePath, err)
+	}
+	cfg, err := yaml.NewConfig(data, ucfg.PathSep("."))
+	if err != nil {
+		return fmt.Errorf("unable to load system test configuration file: %s: %w", configFilePath, err)
+	}
+	if err := validateDurations(cfg); err != nil {
+		return fmt.Errorf("invalid system test configuration file: %s: %w", configFilePath, err)
+	}
+	return nil
+}
+
+// validateDurations checks that the duration settings of the configuration can be parsed.
+// Numeric values are accepted, as they are interpreted as seconds.
+func validateDurations(cfg *ucfg.Config) error {
+	var settings map[string]interface{}
+	if err := cfg.Unpack(&settings); err != nil {
+		return err
+	}
+	for _, key := range durationConfigKeys {
+		value, ok := settings[key].(string)
+		if !ok {
+			continue
+		}
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("invalid duration %q in %s: %w", value, key, err)
+		}
+	}
+	return nil
+}
+
+// ValidateConfigFiles checks that the system test configurations in the given test folder can be
+// loaded. Placeholders are rendered with empty values, as the service information is not known.
+func ValidateConfigFiles(testFolderPath string) error {
+	configFiles, err := listConfigFiles(testFolderPath)
+	if err != nil {
+		return err
+	}
+	var errs multierror.Error
+	for _, configFile := range configFiles {
+		_, err := newConfig(filepath.Join(testFolderPath, configFile), servicedeployer.ServiceInfo{}, "")
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ConfigFiles returns the names of the system test configuration files in the given test folder.
+func ConfigFiles(testFolderPath string) ([]string, error) {
+	return listConfigFiles(testFolderPath)
+}
+
 func listConfigFiles(systemTestFolderPath string) (files []string, err error) {
 	fHandle, err := os.Open(systemTestFolderPath)
 	if err != nil {
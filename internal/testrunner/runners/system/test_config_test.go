@@ -0,0 +1,53 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestConfigTimeouts(t *testing.T) {
+	cases := []struct {
+		title              string
+		config             testConfig
+		waitForDataTimeout time.Duration
+		enrollmentTimeout  time.Duration
+	}{
+		{
+			title:              "defaults",
+			config:             testConfig{},
+			waitForDataTimeout: waitForDataDefaultTimeout,
+			enrollmentTimeout:  enrollmentDefaultTimeout,
+		},
+		{
+			title:              "wait_for_data_timeout",
+			config:             testConfig{WaitForDataTimeout: 20 * time.Minute},
+			waitForDataTimeout: 20 * time.Minute,
+			enrollmentTimeout:  enrollmentDefaultTimeout,
+		},
+		{
+			title:              "timeout",
+			config:             testConfig{Timeout: 2 * time.Minute},
+			waitForDataTimeout: 2 * time.Minute,
+			enrollmentTimeout:  2 * time.Minute,
+		},
+		{
+			title:              "timeout overrides wait_for_data_timeout",
+			config:             testConfig{Timeout: 2 * time.Minute, WaitForDataTimeout: 20 * time.Minute},
+			waitForDataTimeout: 2 * time.Minute,
+			enrollmentTimeout:  2 * time.Minute,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			assert.Equal(t, c.waitForDataTimeout, c.config.waitForDataTimeout())
+			assert.Equal(t, c.enrollmentTimeout, c.config.enrollmentTimeout())
+		})
+	}
+}
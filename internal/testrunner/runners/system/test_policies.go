@@ -0,0 +1,103 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/elastic/elastic-package/internal/kibana"
+	"github.com/elastic/elastic-package/internal/logger"
+)
+
+// sharedTestPolicy is a test policy, with its package data stream already added, that can be
+// reused by all the scenarios that would configure the same package data stream.
+type sharedTestPolicy struct {
+	fingerprint string
+	policy      *kibana.Policy
+	dataStream  kibana.PackageDataStream
+}
+
+// sharedTestPolicies keeps the test policies created by the testers of a runner. A policy is
+// used by a single scenario at a time, so scenarios running in parallel get different policies.
+type sharedTestPolicies struct {
+	mutex    sync.Mutex
+	policies []*sharedTestPolicy
+	free     map[string][]*sharedTestPolicy
+}
+
+func newSharedTestPolicies() *sharedTestPolicies {
+	return &sharedTestPolicies{
+		free: make(map[string][]*sharedTestPolicy),
+	}
+}
+
+// acquire returns a policy with the given fingerprint that is not in use by other scenario,
+// or nil if there is none.
+func (p *sharedTestPolicies) acquire(fingerprint string) *sharedTestPolicy {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	free := p.free[fingerprint]
+	if len(free) == 0 {
+		return nil
+	}
+	policy := free[len(free)-1]
+	p.free[fingerprint] = free[:len(free)-1]
+	return policy
+}
+
+// add registers a new policy, that is considered in use until it is released.
+func (p *sharedTestPolicies) add(policy *sharedTestPolicy) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.policies = append(p.policies, policy)
+}
+
+// release makes the policy available for other scenarios.
+func (p *sharedTestPolicies) release(policy *sharedTestPolicy) {
+	if policy == nil {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.free[policy.fingerprint] = append(p.free[policy.fingerprint], policy)
+}
+
+// deleteAll deletes all the registered policies.
+func (p *sharedTestPolicies) deleteAll(ctx context.Context, kibanaClient *kibana.Client) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var errs []error
+	for _, policy := range p.policies {
+		logger.Debugf("deleting shared test policy %q...", policy.policy.Name)
+		if err := kibanaClient.DeletePolicy(ctx, policy.policy.ID); err != nil {
+			errs = append(errs, fmt.Errorf("error cleaning up test policy %q: %w", policy.policy.Name, err))
+		}
+	}
+	p.policies = nil
+	p.free = make(map[string][]*sharedTestPolicy)
+	return errors.Join(errs...)
+}
+
+// testPolicyFingerprint calculates a fingerprint of the configuration of a package data stream,
+// ignoring the policy it is added to, so it can be used to find policies with the same configuration.
+func testPolicyFingerprint(ds kibana.PackageDataStream) (string, error) {
+	ds.Name = ""
+	ds.Namespace = ""
+	ds.PolicyID = ""
+	d, err := json.Marshal(ds)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode package data stream: %w", err)
+	}
+	sum := sha256.Sum256(d)
+	return hex.EncodeToString(sum[:]), nil
+}
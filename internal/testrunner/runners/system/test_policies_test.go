@@ -0,0 +1,72 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/kibana"
+)
+
+func TestTestPolicyFingerprint(t *testing.T) {
+	dataStream := func(policyID, namespace, dataset string) kibana.PackageDataStream {
+		return kibana.PackageDataStream{
+			Name:      "nginx-access-" + namespace,
+			Namespace: namespace,
+			PolicyID:  policyID,
+			Enabled:   true,
+			Inputs: []kibana.Input{
+				{
+					PolicyTemplate: "nginx",
+					Type:           "logfile",
+					Enabled:        true,
+					Streams: []kibana.Stream{
+						{
+							ID:         "logfile-nginx.access",
+							Enabled:    true,
+							DataStream: kibana.DataStream{Type: "logs", Dataset: dataset},
+							Vars: kibana.Vars{
+								"tags": kibana.Var{Type: "text"},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	first, err := testPolicyFingerprint(dataStream("policy-1", "ep1", "nginx.access"))
+	require.NoError(t, err)
+	second, err := testPolicyFingerprint(dataStream("policy-2", "ep2", "nginx.access"))
+	require.NoError(t, err)
+	other, err := testPolicyFingerprint(dataStream("policy-1", "ep1", "nginx.error"))
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second, "policy and namespace shouldn't affect the fingerprint")
+	assert.NotEqual(t, first, other)
+}
+
+func TestSharedTestPolicies(t *testing.T) {
+	policies := newSharedTestPolicies()
+	assert.Nil(t, policies.acquire("a"))
+
+	policyA := &sharedTestPolicy{fingerprint: "a", policy: &kibana.Policy{ID: "1"}}
+	policies.add(policyA)
+
+	// Policies in use are not available for other scenarios.
+	assert.Nil(t, policies.acquire("a"))
+
+	policies.release(policyA)
+	assert.Nil(t, policies.acquire("b"))
+	assert.Same(t, policyA, policies.acquire("a"))
+	assert.Nil(t, policies.acquire("a"))
+
+	// Releasing a nil policy, as when the scenario failed before getting one, does nothing.
+	policies.release(nil)
+	assert.Nil(t, policies.acquire(""))
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/elastic/elastic-package/internal/multierror"
 	"github.com/elastic/elastic-package/internal/packages"
 	"github.com/elastic/elastic-package/internal/profile"
+	"github.com/elastic/elastic-package/internal/progress"
 	"github.com/elastic/elastic-package/internal/resources"
 	"github.com/elastic/elastic-package/internal/servicedeployer"
 	"github.com/elastic/elastic-package/internal/stack"
@@ -88,6 +89,7 @@ const (
 	ServiceLogsAgentDir = "/tmp/service_logs"
 
 	waitForDataDefaultTimeout = 10 * time.Minute
+	enrollmentDefaultTimeout  = 5 * time.Minute
 )
 
 type logsRegexp struct {
@@ -157,6 +159,13 @@ type tester struct {
 	withCoverage       bool
 	coverageType       string
 	checkFailureStore  bool
+	containerLogsTail  int
+
+	// testPolicies, when set, keeps test policies that are reused by scenarios
+	// with the same configuration of the package data stream.
+	testPolicies *sharedTestPolicies
+
+	progress *progress.Reporter
 
 	serviceStateFilePath string
 
@@ -170,6 +179,12 @@ type tester struct {
 	resetAgentLogLevelHandler func(context.Context) error
 	shutdownServiceHandler    func(context.Context) error
 	shutdownAgentHandler      func(context.Context) error
+	resetKibanaSpaceHandler   func(context.Context) error
+
+	// Handlers to get the logs of the containers deployed for the test, used to dump them
+	// when the test fails.
+	serviceLogsHandler func(context.Context, time.Time) ([]byte, error)
+	agentLogsHandler   func(context.Context, time.Time) ([]byte, error)
 }
 
 type SystemTesterOptions struct {
@@ -191,6 +206,17 @@ type SystemTesterOptions struct {
 	CoverageType      string
 	CheckFailureStore bool
 
+	// ContainerLogsTail is the number of lines dumped from the logs of each container when
+	// the test fails, all lines are dumped when it is zero.
+	ContainerLogsTail int
+
+	// TestPolicies, when set, is used to reuse the test policies between scenarios that
+	// configure the package data stream with the same variables.
+	TestPolicies *sharedTestPolicies
+
+	// Progress reports the progress of the test, it can be nil.
+	Progress *progress.Reporter
+
 	RunSetup     bool
 	RunTearDown  bool
 	RunTestsOnly bool
@@ -215,6 +241,9 @@ func NewSystemTester(options SystemTesterOptions) (*tester, error) {
 		withCoverage:               options.WithCoverage,
 		coverageType:               options.CoverageType,
 		checkFailureStore:          options.CheckFailureStore,
+		containerLogsTail:          options.ContainerLogsTail,
+		testPolicies:               options.TestPolicies,
+		progress:                   options.Progress,
 		runIndependentElasticAgent: true,
 	}
 	r.resourcesManager = resources.NewManager()
@@ -234,7 +263,7 @@ func NewSystemTester(options SystemTesterOptions) (*tester, error) {
 		return nil, fmt.Errorf("locating data stream root failed: %w", err)
 	}
 
-	if r.esAPI == nil {
+	if r.esAPI == nil || r.esClient == nil {
 		return nil, errors.New("missing Elasticsearch client")
 	}
 	if r.kibanaClient == nil {
@@ -525,6 +554,14 @@ func (r *tester) tearDownTest(ctx context.Context) error {
 		r.shutdownAgentHandler = nil
 	}
 
+	// Run after all the other handlers, as they may need to use the Kibana space.
+	if r.resetKibanaSpaceHandler != nil {
+		if err := r.resetKibanaSpaceHandler(cleanupCtx); err != nil {
+			return err
+		}
+		r.resetKibanaSpaceHandler = nil
+	}
+
 	return nil
 }
 
@@ -602,7 +639,13 @@ func (r *tester) runTestPerVariant(ctx context.Context, result *testrunner.Resul
 	}
 	logger.Debugf("Using config: %q", testConfig.Name())
 
+	startTime := time.Now()
 	partial, err := r.runTest(ctx, testConfig, svcInfo)
+	if err != nil || failedResults(partial) {
+		// Logs are dumped before tearing down, while containers are still available.
+		paths := r.dumpContainerLogs(context.WithoutCancel(ctx), testConfig.Name(), startTime)
+		partial, err = withContainerLogs(partial, err, paths)
+	}
 
 	tdErr := r.tearDownTest(ctx)
 	if err != nil {
@@ -671,6 +714,10 @@ type hits struct {
 	Fields        []common.MapStr `json:"fields"`
 	IgnoredFields []string
 	DegradedDocs  []common.MapStr
+
+	// Total is the number of documents found in the data stream, that can be
+	// greater than the number of retrieved documents.
+	Total int
 }
 
 func (h hits) getDocs(syntheticsEnabled bool) []common.MapStr {
@@ -752,7 +799,7 @@ func (r *tester) getDocs(ctx context.Context, dataStream string) (*hits, error)
 		logger.Debugf("found %d hits in %s data stream", numHits, dataStream)
 	}
 
-	var hits hits
+	hits := hits{Total: numHits}
 	for _, hit := range results.Hits.Hits {
 		hits.Source = append(hits.Source, hit.Source)
 		hits.Fields = append(hits.Fields, hit.Fields)
@@ -835,6 +882,7 @@ type scenarioTest struct {
 	kibanaDataStream   kibana.PackageDataStream
 	syntheticEnabled   bool
 	docs               []common.MapStr
+	hitCount           int
 	failureStore       []failureStoreDocument
 	ignoredFields      []string
 	degradedDocs       []common.MapStr
@@ -914,6 +962,14 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, svcInf
 
 	serviceOptions.DeployIndependentAgent = r.runIndependentElasticAgent
 
+	var createdSpace bool
+	if config.SpaceID != "" {
+		createdSpace, err = r.useKibanaSpace(ctx, config.SpaceID, serviceStateData.CreatedSpace)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	policyTemplateName := config.PolicyTemplate
 	if policyTemplateName == "" {
 		policyTemplateName, err = findPolicyTemplateForInput(*r.pkgManifest, *r.dataStreamManifest, config.Input)
@@ -967,34 +1023,29 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, svcInf
 		return nil
 	}
 
+	// Shared test policies are selected once the configuration of the scenario is known.
+	var sharedPolicy *sharedTestPolicy
 	if r.runTearDown {
 		// required to assign the policy stored in the service state file
 		// so data stream related to this Agent Policy can be obtained (and deleted)
 		// in the cleanTestScenarioHandler handler
 		policyToTest = policyCurrent
-	} else {
+	} else if r.testPolicies == nil {
 		// Create a specific Agent Policy just for testing this test.
 		// This allows us to ensure that the Agent Policy used for testing is
 		// assigned to the agent with all the required changes (e.g. Package DataStream)
-		logger.Debug("creating test policy...")
-		policy := kibana.Policy{
-			Name:        fmt.Sprintf("ep-test-system-%s-%s-%s-%s-%s", r.testFolder.Package, r.testFolder.DataStream, r.serviceVariant, r.configFileName, testTime),
-			Description: fmt.Sprintf("test policy created by elastic-package test system for data stream %s/%s", r.testFolder.Package, r.testFolder.DataStream),
-			Namespace:   common.CreateTestRunID(),
-		}
-		// Assign the data_output_id to the agent policy to configure the output to logstash. The value is inferred from stack/_static/kibana.yml.tmpl
-		if r.profile.Config("stack.logstash_enabled", "false") == "true" {
-			policy.DataOutputID = "fleet-logstash-output"
-		}
-		policyToTest, err = r.kibanaClient.CreatePolicy(ctx, policy)
+		policyToTest, err = r.createTestPolicy(ctx, testTime)
 		if err != nil {
-			return nil, fmt.Errorf("could not create test policy: %w", err)
+			return nil, err
 		}
 	}
 
 	r.deleteTestPolicyHandler = func(ctx context.Context) error {
 		logger.Debug("deleting test policies...")
-		if err := r.kibanaClient.DeletePolicy(ctx, policyToTest.ID); err != nil {
+		if r.testPolicies != nil {
+			// Shared test policies are deleted when tearing down the runner.
+			r.testPolicies.release(sharedPolicy)
+		} else if err := r.kibanaClient.DeletePolicy(ctx, policyToTest.ID); err != nil {
 			return fmt.Errorf("error cleaning up test policy: %w", err)
 		}
 		if r.runTestsOnly {
@@ -1038,13 +1089,23 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, svcInf
 	// the agent logs from that time onwards to avoid possible previous errors present in logs
 	scenario.startTestTime = time.Now()
 
-	logger.Debug("adding package data stream to test policy...")
-	ds := createPackageDatastream(*policyToTest, *r.pkgManifest, policyTemplate, *r.dataStreamManifest, *config, policyToTest.Namespace)
-	if r.runTearDown {
-		logger.Debug("Skip adding data stream config to policy")
+	var ds kibana.PackageDataStream
+	if r.testPolicies != nil {
+		sharedPolicy, err = r.acquireSharedTestPolicy(ctx, config, policyTemplate, testTime)
+		if err != nil {
+			return nil, err
+		}
+		policyToTest = sharedPolicy.policy
+		ds = sharedPolicy.dataStream
 	} else {
-		if err := r.kibanaClient.AddPackageDataStreamToPolicy(ctx, ds); err != nil {
-			return nil, fmt.Errorf("could not add data stream config to policy: %w", err)
+		logger.Debug("adding package data stream to test policy...")
+		ds = createPackageDatastream(*policyToTest, *r.pkgManifest, policyTemplate, *r.dataStreamManifest, *config, policyToTest.Namespace)
+		if r.runTearDown {
+			logger.Debug("Skip adding data stream config to policy")
+		} else {
+			if err := r.kibanaClient.AddPackageDataStreamToPolicy(ctx, ds); err != nil {
+				return nil, fmt.Errorf("could not add data stream config to policy: %w", err)
+			}
 		}
 	}
 	scenario.kibanaDataStream = ds
@@ -1067,6 +1128,15 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, svcInf
 		ds.Namespace,
 	)
 
+	if sharedPolicy != nil {
+		// The data stream is shared with previous scenarios using the same policy, ensure
+		// that it doesn't contain documents ingested by them.
+		logger.Debugf("Deleting data stream %s used by previous scenarios", scenario.dataStream)
+		if err := r.deleteDataStream(ctx, scenario.dataStream); err != nil {
+			return nil, fmt.Errorf("failed to delete data stream %s: %w", scenario.dataStream, err)
+		}
+	}
+
 	r.cleanTestScenarioHandler = func(ctx context.Context) error {
 		logger.Debugf("Deleting data stream for testing %s", scenario.dataStream)
 		err := r.deleteDataStream(ctx, scenario.dataStream)
@@ -1080,9 +1150,11 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, svcInf
 	var origPolicy kibana.Policy
 	// While there could be created Elastic Agents within `setupService()` (custom agents and k8s agents),
 	// this "checkEnrolledAgents" call to must be located after creating the service.
-	agents, err := checkEnrolledAgents(ctx, r.kibanaClient, agentInfo, svcInfo, r.runIndependentElasticAgent)
+	done := r.progress.Wait("Waiting for agents to enroll")
+	agents, err := checkEnrolledAgents(ctx, r.kibanaClient, agentInfo, svcInfo, r.runIndependentElasticAgent, config.enrollmentTimeout())
+	done()
 	if err != nil {
-		return nil, fmt.Errorf("can't check enrolled agents: %w", err)
+		return nil, fmt.Errorf("can't check enrolled agents (scenario: %s): %w", config.Name(), err)
 	}
 	agent := agents[0]
 	logger.Debugf("Selected enrolled agent %q", agent.ID)
@@ -1178,15 +1250,14 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, svcInf
 	}
 
 	// Use custom timeout if the service can't collect data immediately.
-	waitForDataTimeout := waitForDataDefaultTimeout
-	if config.WaitForDataTimeout > 0 {
-		waitForDataTimeout = config.WaitForDataTimeout
-	}
+	waitForDataTimeout := config.waitForDataTimeout()
 
 	// (TODO in future) Optionally exercise service to generate load.
 	logger.Debugf("checking for expected data in data stream (%s)...", waitForDataTimeout)
 	var hits *hits
 	oldHits := 0
+	docCount := 0
+	done = r.progress.Wait("Waiting for documents in data stream %s", scenario.dataStream)
 	passed, waitErr := wait.UntilTrue(ctx, func(ctx context.Context) (bool, error) {
 		var err error
 		hits, err = r.getDocs(ctx, scenario.dataStream)
@@ -1220,8 +1291,17 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, svcInf
 			return ret, nil
 		}
 
+		if config.Assert.MinCount > 0 {
+			docCount, err = r.esClient.CountDocuments(ctx, scenario.dataStream)
+			if err != nil {
+				return false, err
+			}
+			return docCount >= config.Assert.MinCount, nil
+		}
+
 		return hits.size() > 0, nil
 	}, 1*time.Second, waitForDataTimeout)
+	done()
 
 	if service != nil && config.Service != "" && !config.IgnoreServiceError {
 		exited, code, err := service.ExitCode(ctx, config.Service)
@@ -1238,7 +1318,17 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, svcInf
 	}
 
 	if !passed {
-		return nil, testrunner.ErrTestCaseFailed{Reason: fmt.Sprintf("could not find hits in %s data stream", scenario.dataStream)}
+		diagnostics := r.waitForDataDiagnostics(ctx, agent.ID, scenario.dataStream, hits)
+		if config.Assert.MinCount > 0 && docCount > 0 {
+			return nil, testrunner.ErrTestCaseFailed{
+				Reason:  fmt.Sprintf("observed %d hits in %s data stream after waiting for %s, expected at least %d (scenario: %s)", docCount, scenario.dataStream, waitForDataTimeout, config.Assert.MinCount, config.Name()),
+				Details: diagnostics,
+			}
+		}
+		return nil, testrunner.ErrTestCaseFailed{
+			Reason:  fmt.Sprintf("could not find hits in %s data stream after waiting for %s (scenario: %s)", scenario.dataStream, waitForDataTimeout, config.Name()),
+			Details: diagnostics,
+		}
 	}
 
 	logger.Debugf("Check whether or not synthetic source mode is enabled (data stream %s)...", scenario.dataStream)
@@ -1249,6 +1339,13 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, svcInf
 	logger.Debugf("Data stream %s has synthetic source mode enabled: %t", scenario.dataStream, scenario.syntheticEnabled)
 
 	scenario.docs = hits.getDocs(scenario.syntheticEnabled)
+	if config.Assert.MinCount > 0 || config.Assert.MaxCount > 0 {
+		// Count after the data stream is refreshed, search hits may not include all the documents.
+		scenario.hitCount, err = r.esClient.CountDocuments(ctx, scenario.dataStream)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count documents in data stream %s: %w", scenario.dataStream, err)
+		}
+	}
 	scenario.ignoredFields = hits.IgnoredFields
 	scenario.degradedDocs = hits.DegradedDocs
 	if r.checkFailureStore {
@@ -1269,6 +1366,7 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, svcInf
 			agent:         origAgent,
 			agentInfo:     agentInfo,
 			svcInfo:       svcInfo,
+			createdSpace:  createdSpace,
 		}
 		err = writeScenarioState(opts, r.serviceStateFilePath)
 		if err != nil {
@@ -1279,6 +1377,111 @@ func (r *tester) prepareScenario(ctx context.Context, config *testConfig, svcInf
 	return &scenario, nil
 }
 
+// useKibanaSpace configures the tester to send the requests to Kibana in the context of the given
+// space, creating it if it doesn't exist, and installs there the Kibana assets of the package.
+// createdInSetup is true if the space was created in a previous setup stage. It returns true if the
+// space has been created for the test. The original client is restored, and the space is deleted if
+// it was created for the test, when tearing down the test.
+func (r *tester) useKibanaSpace(ctx context.Context, spaceID string, createdInSetup bool) (bool, error) {
+	created := createdInSetup
+	if !r.runTearDown {
+		ensured, err := r.kibanaClient.EnsureSpace(ctx, spaceID)
+		if err != nil {
+			return false, fmt.Errorf("failed to prepare Kibana space %q: %w", spaceID, err)
+		}
+		created = created || ensured
+	}
+
+	defaultClient := r.kibanaClient
+	spaceClient := r.kibanaClient.WithSpace(spaceID)
+	r.kibanaClient = spaceClient
+	// Shared test policies are created and deleted in the default space.
+	r.testPolicies = nil
+
+	r.resetKibanaSpaceHandler = func(ctx context.Context) error {
+		r.kibanaClient = defaultClient
+		if r.runSetup || r.runTestsOnly {
+			// The space is kept till the tear down stage.
+			return nil
+		}
+		logger.Debugf("removing Kibana assets of package from space %q...", spaceID)
+		if err := spaceClient.RemovePackageKibanaAssets(ctx, r.pkgManifest.Name, r.pkgManifest.Version); err != nil {
+			return fmt.Errorf("error removing Kibana assets from space %q: %w", spaceID, err)
+		}
+		if !created {
+			return nil
+		}
+		logger.Debugf("deleting Kibana space %q...", spaceID)
+		if err := defaultClient.DeleteSpace(ctx, spaceID); err != nil {
+			return fmt.Errorf("error deleting Kibana space %q: %w", spaceID, err)
+		}
+		return nil
+	}
+
+	if r.runTearDown {
+		// Assets were installed in the setup stage, they only need to be removed.
+		return created, nil
+	}
+
+	logger.Debugf("installing Kibana assets of package in space %q...", spaceID)
+	err := spaceClient.InstallPackageKibanaAssets(ctx, r.pkgManifest.Name, r.pkgManifest.Version)
+	if err != nil {
+		return created, fmt.Errorf("failed to install Kibana assets in space %q: %w", spaceID, err)
+	}
+	return created, nil
+}
+
+// createTestPolicy creates an empty Agent Policy to add the package data stream under test.
+func (r *tester) createTestPolicy(ctx context.Context, testTime string) (*kibana.Policy, error) {
+	logger.Debug("creating test policy...")
+	policy := kibana.Policy{
+		Name:        fmt.Sprintf("ep-test-system-%s-%s-%s-%s-%s", r.testFolder.Package, r.testFolder.DataStream, r.serviceVariant, r.configFileName, testTime),
+		Description: fmt.Sprintf("test policy created by elastic-package test system for data stream %s/%s", r.testFolder.Package, r.testFolder.DataStream),
+		Namespace:   common.CreateTestRunID(),
+	}
+	// Assign the data_output_id to the agent policy to configure the output to logstash. The value is inferred from stack/_static/kibana.yml.tmpl
+	if r.profile.Config("stack.logstash_enabled", "false") == "true" {
+		policy.DataOutputID = "fleet-logstash-output"
+	}
+	policyToTest, err := r.kibanaClient.CreatePolicy(ctx, policy)
+	if err != nil {
+		return nil, fmt.Errorf("could not create test policy: %w", err)
+	}
+	return policyToTest, nil
+}
+
+// acquireSharedTestPolicy returns a test policy with the same configuration of the package
+// data stream that this scenario needs. The policy is created if there is no policy available.
+func (r *tester) acquireSharedTestPolicy(ctx context.Context, config *testConfig, policyTemplate packages.PolicyTemplate, testTime string) (*sharedTestPolicy, error) {
+	fingerprint, err := testPolicyFingerprint(createPackageDatastream(kibana.Policy{}, *r.pkgManifest, policyTemplate, *r.dataStreamManifest, *config, ""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate fingerprint of test policy: %w", err)
+	}
+
+	if shared := r.testPolicies.acquire(fingerprint); shared != nil {
+		logger.Debugf("reusing test policy %q...", shared.policy.Name)
+		return shared, nil
+	}
+
+	policy, err := r.createTestPolicy(ctx, testTime)
+	if err != nil {
+		return nil, err
+	}
+	shared := &sharedTestPolicy{
+		fingerprint: fingerprint,
+		policy:      policy,
+		dataStream:  createPackageDatastream(*policy, *r.pkgManifest, policyTemplate, *r.dataStreamManifest, *config, policy.Namespace),
+	}
+	// Register the policy before adding the data stream, so it is deleted even if this fails.
+	r.testPolicies.add(shared)
+
+	logger.Debug("adding package data stream to test policy...")
+	if err := r.kibanaClient.AddPackageDataStreamToPolicy(ctx, shared.dataStream); err != nil {
+		return nil, fmt.Errorf("could not add data stream config to policy: %w", err)
+	}
+	return shared, nil
+}
+
 func (r *tester) setupService(ctx context.Context, config *testConfig, serviceOptions servicedeployer.FactoryOptions, svcInfo servicedeployer.ServiceInfo, agentInfo agentdeployer.AgentInfo, agentDeployed agentdeployer.DeployedAgent, policy *kibana.Policy, state ServiceState) (servicedeployer.DeployedService, servicedeployer.ServiceInfo, error) {
 	logger.Debug("setting up service...")
 	if r.runTearDown || r.runTestsOnly {
@@ -1315,6 +1518,7 @@ func (r *tester) setupService(ctx context.Context, config *testConfig, serviceOp
 		return nil, svcInfo, fmt.Errorf("could not setup service: %w", err)
 	}
 
+	r.serviceLogsHandler = service.Logs
 	r.shutdownServiceHandler = func(ctx context.Context) error {
 		if r.runTestsOnly {
 			return nil
@@ -1361,6 +1565,7 @@ func (r *tester) setupAgent(ctx context.Context, config *testConfig, state Servi
 	if err != nil {
 		return nil, agentInfo, fmt.Errorf("could not setup agent: %w", err)
 	}
+	r.agentLogsHandler = agentDeployed.Logs
 	r.shutdownAgentHandler = func(ctx context.Context) error {
 		if r.runTestsOnly {
 			return nil
@@ -1454,6 +1659,7 @@ func (r *tester) validateTestScenario(ctx context.Context, result *testrunner.Re
 		return result.WithError(testrunner.ErrTestCaseFailed{
 			Reason:  fmt.Sprintf("one or more errors found in documents stored in %s data stream", scenario.dataStream),
 			Details: errs.Error(),
+			Errors:  errs,
 		})
 	}
 
@@ -1486,6 +1692,11 @@ func (r *tester) validateTestScenario(ctx context.Context, result *testrunner.Re
 		result.FailureMsg = message
 	}
 
+	// Check the number of documents in the data stream, if limits have been specified
+	if assertionPass, message := assertCountRange(config.Assert.MinCount, config.Assert.MaxCount, scenario.hitCount); !assertionPass {
+		result.FailureMsg = message
+	}
+
 	// Check transforms if present
 	if err := r.checkTransforms(ctx, config, r.pkgManifest, scenario.kibanaDataStream, scenario.dataStream, scenario.syntheticEnabled); err != nil {
 		results, _ := result.WithError(err)
@@ -1560,7 +1771,44 @@ func dumpScenarioDocs(docs any) error {
 	return nil
 }
 
-func checkEnrolledAgents(ctx context.Context, client *kibana.Client, agentInfo agentdeployer.AgentInfo, svcInfo servicedeployer.ServiceInfo, runIndependentElasticAgent bool) ([]kibana.Agent, error) {
+// waitForDataDiagnostics describes how far ingestion got when the wait for data times out: the
+// number of documents found in the data stream, and the status and errors reported to Fleet
+// by the agent.
+func (r *tester) waitForDataDiagnostics(ctx context.Context, agentID string, dataStream string, hits *hits) string {
+	var sb strings.Builder
+	total := 0
+	if hits != nil {
+		total = hits.Total
+	}
+	fmt.Fprintf(&sb, "documents found in data stream %s: %d\n", dataStream, total)
+
+	agent, err := r.kibanaClient.GetAgent(ctx, agentID)
+	if err != nil {
+		fmt.Fprintf(&sb, "could not get the status of agent %s: %v\n", agentID, err)
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, "agent %s (host: %s) status: %s", agent.ID, agent.LocalMetadata.Host.Name, agent.Status)
+	if agent.LastCheckinStatus != "" {
+		fmt.Fprintf(&sb, ", last check-in status: %s", agent.LastCheckinStatus)
+	}
+	if agent.LastCheckinMessage != "" {
+		fmt.Fprintf(&sb, ", last check-in message: %q", agent.LastCheckinMessage)
+	}
+	sb.WriteString("\n")
+
+	errs := agent.Errors()
+	if len(errs) == 0 {
+		sb.WriteString("no errors reported by the agent\n")
+		return sb.String()
+	}
+	sb.WriteString("errors reported by the agent:\n")
+	for _, e := range errs {
+		fmt.Fprintf(&sb, "  - %s\n", e)
+	}
+	return sb.String()
+}
+
+func checkEnrolledAgents(ctx context.Context, client *kibana.Client, agentInfo agentdeployer.AgentInfo, svcInfo servicedeployer.ServiceInfo, runIndependentElasticAgent bool, timeout time.Duration) ([]kibana.Agent, error) {
 	var agents []kibana.Agent
 
 	enrolled, err := wait.UntilTrue(ctx, func(ctx context.Context) (bool, error) {
@@ -1579,12 +1827,12 @@ func checkEnrolledAgents(ctx context.Context, client *kibana.Client, agentInfo a
 			return false, nil // selected agents are unavailable yet
 		}
 		return true, nil
-	}, 1*time.Second, 5*time.Minute)
+	}, 1*time.Second, timeout)
 	if err != nil {
 		return nil, fmt.Errorf("agent enrollment failed: %w", err)
 	}
 	if !enrolled {
-		return nil, errors.New("no agent enrolled in time")
+		return nil, fmt.Errorf("no agent enrolled after waiting for %s", timeout)
 	}
 	return agents, nil
 }
@@ -1900,6 +2148,7 @@ func (r *tester) checkTransforms(ctx context.Context, config *testConfig, pkgMan
 			return testrunner.ErrTestCaseFailed{
 				Reason:  fmt.Sprintf("errors found in documents of preview for transform %s for data stream %s", transformId, dataStream),
 				Details: errs.Error(),
+				Errors:  errs,
 			}
 		}
 	}
@@ -2127,6 +2376,19 @@ func assertHitCount(expected int, docs []common.MapStr) (pass bool, message stri
 	return true, ""
 }
 
+// assertCountRange checks that the observed number of documents is within the given limits,
+// zero values mean that the limit is not set.
+func assertCountRange(minCount, maxCount int, observed int) (pass bool, message string) {
+	logger.Debugf("assert count range expected [%d, %d], observed %d", minCount, maxCount, observed)
+	if minCount > 0 && observed < minCount {
+		return false, fmt.Sprintf("observed hit count %d is lower than the expected minimum count %d", observed, minCount)
+	}
+	if maxCount > 0 && observed > maxCount {
+		return false, fmt.Sprintf("observed hit count %d is greater than the expected maximum count %d", observed, maxCount)
+	}
+	return true, ""
+}
+
 func (r *tester) generateTestResultFile(docs []common.MapStr, specVersion semver.Version) error {
 	if !r.generateTestResult {
 		return nil
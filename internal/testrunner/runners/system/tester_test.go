@@ -19,6 +19,7 @@ import (
 
 	estest "github.com/elastic/elastic-package/internal/elasticsearch/test"
 	"github.com/elastic/elastic-package/internal/packages"
+	"github.com/elastic/elastic-package/internal/servicedeployer"
 	"github.com/elastic/elastic-package/internal/stack"
 	"github.com/elastic/elastic-package/internal/testrunner"
 )
@@ -442,3 +443,48 @@ func TestIsSyntheticSourceModeEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestAssertCountRange(t *testing.T) {
+	cases := []struct {
+		title    string
+		minCount int
+		maxCount int
+		observed int
+		message  string
+	}{
+		{title: "no limits", observed: 3},
+		{title: "within limits", minCount: 2, maxCount: 5, observed: 5},
+		{title: "only minimum", minCount: 10, observed: 10},
+		{title: "below minimum", minCount: 10, observed: 3, message: "observed hit count 3 is lower than the expected minimum count 10"},
+		{title: "above maximum", maxCount: 5, observed: 7, message: "observed hit count 7 is greater than the expected maximum count 5"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			pass, message := assertCountRange(c.minCount, c.maxCount, c.observed)
+			assert.Equal(t, c.message == "", pass)
+			assert.Equal(t, c.message, message)
+		})
+	}
+}
+
+func TestCheckConfigDurations(t *testing.T) {
+	err := checkConfigDurations(filepath.Join("testdata", "durations", "test-valid-config.yml"))
+	require.NoError(t, err)
+
+	err = checkConfigDurations(filepath.Join("testdata", "durations", "test-invalid-config.yml"))
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `invalid duration "10 minutes" in wait_for_data_timeout`)
+
+	_, err = newConfig(filepath.Join("testdata", "durations", "test-invalid-config.yml"), servicedeployer.ServiceInfo{}, "")
+	assert.ErrorContains(t, err, `invalid duration "10 minutes" in wait_for_data_timeout`)
+}
+
+func TestConfigSpaceID(t *testing.T) {
+	config, err := newConfig(filepath.Join("testdata", "spaces", "test-valid-config.yml"), servicedeployer.ServiceInfo{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "custom_space-1", config.SpaceID)
+
+	_, err = newConfig(filepath.Join("testdata", "spaces", "test-invalid-config.yml"), servicedeployer.ServiceInfo{}, "")
+	assert.ErrorContains(t, err, `invalid space_id "Custom Space"`)
+}
@@ -0,0 +1,59 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package runners
+
+import (
+	"fmt"
+
+	"github.com/elastic/elastic-package/internal/multierror"
+	"github.com/elastic/elastic-package/internal/testrunner"
+	"github.com/elastic/elastic-package/internal/testrunner/runners/asset"
+	"github.com/elastic/elastic-package/internal/testrunner/runners/pipeline"
+	"github.com/elastic/elastic-package/internal/testrunner/runners/policy"
+	"github.com/elastic/elastic-package/internal/testrunner/runners/static"
+	"github.com/elastic/elastic-package/internal/testrunner/runners/system"
+)
+
+// configValidators validate the configuration files of the test folders of each test type.
+var configValidators = []struct {
+	testType testrunner.TestType
+	validate func(testFolderPath string) error
+}{
+	{asset.TestType, asset.ValidateConfig},
+	{pipeline.TestType, pipeline.ValidateConfigFiles},
+	{policy.TestType, policy.ValidateConfigFiles},
+	{static.TestType, static.ValidateConfig},
+	{system.TestType, system.ValidateConfigFiles},
+}
+
+// ValidateTestConfigs checks that all the test configuration files of the package can be loaded,
+// including the global test configuration. Unknown settings are reported as errors, as they are
+// usually misspelled settings that would be ignored otherwise.
+func ValidateTestConfigs(packageRootPath string) error {
+	var errs multierror.Error
+	if _, err := testrunner.ReadGlobalTestConfig(packageRootPath); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, validator := range configValidators {
+		folders, err := testrunner.FindTestFolders(packageRootPath, nil, validator.testType)
+		if err != nil {
+			return fmt.Errorf("unable to determine %s test folder paths: %w", validator.testType, err)
+		}
+		for _, folder := range folders {
+			err := validator.validate(folder.Path)
+			if merr, ok := err.(multierror.Error); ok {
+				errs = append(errs, merr...)
+			} else if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
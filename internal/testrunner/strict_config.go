@@ -0,0 +1,90 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package testrunner
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/elastic/go-ucfg"
+)
+
+// UnpackConfigStrict unpacks the configuration into the given struct, failing if the configuration
+// contains settings that are not defined in the struct. Unknown settings are usually misspelled
+// settings that would be silently ignored otherwise.
+func UnpackConfigStrict(cfg *ucfg.Config, to any) error {
+	var settings map[string]any
+	if err := cfg.Unpack(&settings); err != nil {
+		return err
+	}
+	unknown := unknownSettings("", settings, reflect.TypeOf(to))
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown settings found, they may be misspelled: %s", strings.Join(unknown, ", "))
+	}
+	return cfg.Unpack(to)
+}
+
+// unknownSettings returns the names of the settings that are not defined in the given type.
+// Only structs are checked, other types can hold any settings.
+func unknownSettings(prefix string, settings map[string]any, t reflect.Type) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	known := make(map[string]reflect.Type)
+	collectConfigFields(t, known)
+
+	var unknown []string
+	for key, value := range settings {
+		name := key
+		if prefix != "" {
+			name = prefix + "." + key
+		}
+		fieldType, found := known[key]
+		if !found {
+			unknown = append(unknown, name)
+			continue
+		}
+		if nested, ok := value.(map[string]any); ok {
+			unknown = append(unknown, unknownSettings(name, nested, fieldType)...)
+		}
+	}
+	return unknown
+}
+
+// collectConfigFields collects the settings that can be unpacked in the given struct type, by name,
+// following the conventions of the `config` tags used by ucfg.
+func collectConfigFields(t reflect.Type, known map[string]reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("config"), ",")
+		if strings.Contains(options, "ignore") {
+			continue
+		}
+		if strings.Contains(options, "inline") {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				collectConfigFields(fieldType, known)
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		known[name] = field.Type
+	}
+}
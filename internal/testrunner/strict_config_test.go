@@ -0,0 +1,80 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package testrunner
+
+import (
+	"testing"
+
+	"github.com/elastic/go-ucfg"
+	"github.com/elastic/go-ucfg/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpackConfigStrict(t *testing.T) {
+	type nestedConfig struct {
+		Enabled bool `config:"enabled"`
+	}
+	type testConfig struct {
+		SkippableConfig `config:",inline"`
+
+		Name     string         `config:"name"`
+		Nested   nestedConfig   `config:"nested"`
+		Vars     map[string]any `config:"vars"`
+		Ignored  string         `config:",ignore"`
+		Untagged string
+	}
+
+	cases := []struct {
+		title  string
+		config string
+		err    string
+	}{
+		{
+			title: "known settings",
+			config: `
+name: foo
+nested.enabled: true
+vars:
+  any: value
+skip:
+  reason: testing
+  link: https://github.com/elastic/elastic-package/issues/1
+untagged: bar
+`,
+		},
+		{
+			title: "unknown settings",
+			config: `
+nmae: foo
+nested:
+  enable: true
+vars:
+  any: value
+`,
+			err: "unknown settings found, they may be misspelled: nested.enable, nmae",
+		},
+		{
+			title:  "ignored settings",
+			config: `ignored: foo`,
+			err:    "unknown settings found, they may be misspelled: ignored",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			cfg, err := yaml.NewConfig([]byte(c.config), ucfg.PathSep("."))
+			require.NoError(t, err)
+
+			var config testConfig
+			err = UnpackConfigStrict(cfg, &config)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -91,6 +91,13 @@ type TestRunner interface {
 	TearDownRunner(context.Context) error
 }
 
+// ParallelTestsLimiter is the interface test runners that limit the number of their tests that
+// can run in parallel must implement.
+type ParallelTestsLimiter interface {
+	// MaxParallelTests returns the maximum number of tests to run in parallel, zero to use the default.
+	MaxParallelTests() int
+}
+
 // TestResult contains a single test's results
 type TestResult struct {
 	// Name of test result. Optional.
@@ -116,6 +123,9 @@ type TestResult struct {
 	// If test case failed, longer description of the failure.
 	FailureDetails string
 
+	// If test case failed, individual errors that caused the failure, if available.
+	FailureErrors []error
+
 	// If there was an error while running the test case, description
 	// of the error. An error is when the test cannot complete execution due
 	// to an unexpected runtime error in the test execution.
@@ -173,6 +183,7 @@ func (rc *ResultComposer) WithError(err error) ([]TestResult, error) {
 	if errors.As(err, &tcf) {
 		rc.FailureMsg += tcf.Error()
 		rc.FailureDetails += tcf.Details
+		rc.FailureErrors = append(rc.FailureErrors, tcf.Errors...)
 		return []TestResult{rc.TestResult}, nil
 	}
 
@@ -328,6 +339,14 @@ func RunSuite(ctx context.Context, runner TestRunner) ([]TestResult, error) {
 		return nil, nil
 	}
 
+	maxRoutines, err := maxNumberRoutines()
+	if err != nil {
+		return nil, err
+	}
+	if limiter, ok := runner.(ParallelTestsLimiter); ok && limiter.MaxParallelTests() > 0 {
+		maxRoutines = limiter.MaxParallelTests()
+	}
+
 	err = runner.SetupRunner(ctx)
 	if err != nil {
 		cleanupCtx := context.WithoutCancel(ctx)
@@ -350,7 +369,7 @@ func RunSuite(ctx context.Context, runner TestRunner) ([]TestResult, error) {
 	var allResults, results []TestResult
 	var parallelErr, sequentialErr error
 
-	results, parallelErr = runSuiteParallel(ctx, parallelTesters)
+	results, parallelErr = runSuiteParallel(ctx, parallelTesters, maxRoutines)
 	allResults = append(allResults, results...)
 
 	results, sequentialErr = runSuite(ctx, sequentialTesters)
@@ -404,13 +423,13 @@ func runSuite(ctx context.Context, testers []Tester) ([]TestResult, error) {
 }
 
 // runSuiteParallel method delegates execution of tests to the runners generated through the factory function.
-func runSuiteParallel(ctx context.Context, testers []Tester) ([]TestResult, error) {
+// Results are returned in the same order as the testers, independently of the order they finish.
+func runSuiteParallel(ctx context.Context, testers []Tester, maxRoutines int) ([]TestResult, error) {
 	if len(testers) == 0 {
 		return nil, nil
 	}
-	maxRoutines, err := maxNumberRoutines()
-	if err != nil {
-		return nil, err
+	if maxRoutines < 1 {
+		maxRoutines = 1
 	}
 
 	var wg sync.WaitGroup
@@ -418,15 +437,14 @@ func runSuiteParallel(ctx context.Context, testers []Tester) ([]TestResult, erro
 		results []TestResult
 		err     error
 	}
-	chResults := make(chan routineResult, len(testers))
+	routineResults := make([]routineResult, len(testers))
 
 	logger.Debugf("Running tests in parallel. Maximum routines to run in parallel: %d", maxRoutines)
 	// Use channel as a semaphore to limit the number of test executions in parallel
 	sem := make(chan int, maxRoutines)
 
-	for _, tester := range testers {
+	for i, tester := range testers {
 		wg.Add(1)
-		tester := tester
 		sem <- 1
 		go func() {
 			defer wg.Done()
@@ -435,22 +453,21 @@ func runSuiteParallel(ctx context.Context, testers []Tester) ([]TestResult, erro
 			}()
 			if err := ctx.Err(); err != nil {
 				logger.Errorf("context error: %s", context.Cause(ctx))
-				chResults <- routineResult{nil, err}
+				routineResults[i] = routineResult{nil, err}
 				return
 			}
 			r, err := run(ctx, tester)
-			chResults <- routineResult{r, err}
+			routineResults[i] = routineResult{r, err}
 		}()
 	}
 
 	wg.Wait()
-	close(chResults)
 	close(sem)
 
 	var results []TestResult
 	var multiErr error
 	testType := testers[0].Type()
-	for testResults := range chResults {
+	for _, testResults := range routineResults {
 		if testResults.err != nil {
 			multiErr = errors.Join(multiErr, testResults.err)
 		}
@@ -0,0 +1,65 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package testrunner
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTester struct {
+	name    string
+	delay   time.Duration
+	running *atomic.Int32
+	maxSeen *atomic.Int32
+}
+
+func (t *fakeTester) Type() TestType { return "fake" }
+func (t *fakeTester) String() string { return t.name }
+func (t *fakeTester) Parallel() bool { return true }
+
+func (t *fakeTester) Run(ctx context.Context) ([]TestResult, error) {
+	running := t.running.Add(1)
+	defer t.running.Add(-1)
+	for {
+		seen := t.maxSeen.Load()
+		if running <= seen || t.maxSeen.CompareAndSwap(seen, running) {
+			break
+		}
+	}
+	time.Sleep(t.delay)
+	return []TestResult{{Name: t.name}}, nil
+}
+
+func (t *fakeTester) TearDown(ctx context.Context) error { return nil }
+
+func TestRunSuiteParallelKeepsOrder(t *testing.T) {
+	var running, maxSeen atomic.Int32
+	var testers []Tester
+	for i := 0; i < 6; i++ {
+		testers = append(testers, &fakeTester{
+			name:    fmt.Sprintf("test-%d", i),
+			delay:   time.Duration(6-i) * 10 * time.Millisecond,
+			running: &running,
+			maxSeen: &maxSeen,
+		})
+	}
+
+	results, err := runSuiteParallel(context.Background(), testers, 3)
+	require.NoError(t, err)
+
+	var names []string
+	for _, result := range results {
+		names = append(names, result.Name)
+	}
+	assert.Equal(t, []string{"test-0", "test-1", "test-2", "test-3", "test-4", "test-5"}, names)
+	assert.LessOrEqual(t, maxSeen.Load(), int32(3))
+}
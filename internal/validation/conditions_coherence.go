@@ -0,0 +1,99 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Masterminds/semver/v3"
+	"gopkg.in/yaml.v3"
+
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+// Minimum versions of Kibana supporting features that can be used in policy templates.
+var (
+	semver8_10_0 = semver.MustParse("8.10.0")
+	semver8_15_0 = semver.MustParse("8.15.0")
+)
+
+type policyTemplateVar struct {
+	Name   string `yaml:"name"`
+	Secret bool   `yaml:"secret"`
+}
+
+type gatedManifest struct {
+	Conditions struct {
+		Kibana struct {
+			Version string `yaml:"version"`
+		} `yaml:"kibana"`
+	} `yaml:"conditions"`
+	PolicyTemplates []struct {
+		Name            string `yaml:"name"`
+		DeploymentModes struct {
+			Agentless struct {
+				Enabled bool `yaml:"enabled"`
+			} `yaml:"agentless"`
+		} `yaml:"deployment_modes"`
+		Vars   []policyTemplateVar `yaml:"vars"`
+		Inputs []struct {
+			Type string              `yaml:"type"`
+			Vars []policyTemplateVar `yaml:"vars"`
+		} `yaml:"inputs"`
+	} `yaml:"policy_templates"`
+}
+
+// checkConditionsCoherence checks that the features used by the policy templates are available
+// in some of the versions of Kibana allowed by the conditions of the package. Otherwise these
+// policy templates cannot be used.
+func checkConditionsCoherence(packageRoot string, issues *Issues) error {
+	manifestPath := filepath.Join(packageRoot, packages.PackageManifestFile)
+	d, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return fmt.Errorf("failed to read package manifest: %w", err)
+	}
+	var manifest gatedManifest
+	err = yaml.Unmarshal(d, &manifest)
+	if err != nil {
+		return fmt.Errorf("failed to parse package manifest: %w", err)
+	}
+	if manifest.Conditions.Kibana.Version == "" {
+		return nil
+	}
+	constraint, err := semver.NewConstraint(manifest.Conditions.Kibana.Version)
+	if err != nil {
+		// Invalid constraints are reported by the spec validation.
+		return nil
+	}
+
+	report := func(policyTemplate, feature string, required *semver.Version) {
+		if allowsVersionsBetween(constraint, required, nil) {
+			return
+		}
+		issues.addWarningf("policy template %q uses %s, that requires Kibana %s or later, but conditions.kibana.version %q excludes these versions",
+			policyTemplate, feature, required, manifest.Conditions.Kibana.Version)
+	}
+
+	for _, policyTemplate := range manifest.PolicyTemplates {
+		if policyTemplate.DeploymentModes.Agentless.Enabled {
+			report(policyTemplate.Name, "the agentless deployment mode", semver8_15_0)
+		}
+		for _, v := range policyTemplate.Vars {
+			if v.Secret {
+				report(policyTemplate.Name, fmt.Sprintf("secret variable %q", v.Name), semver8_10_0)
+			}
+		}
+		for _, input := range policyTemplate.Inputs {
+			for _, v := range input.Vars {
+				if v.Secret {
+					report(policyTemplate.Name, fmt.Sprintf("secret variable %q in input %q", v.Name, input.Type), semver8_10_0)
+				}
+			}
+		}
+	}
+	return nil
+}
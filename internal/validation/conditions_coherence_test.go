@@ -0,0 +1,21 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckConditionsCoherence(t *testing.T) {
+	var issues Issues
+	err := checkConditionsCoherence("testdata/conditions_coherence", &issues)
+	require.NoError(t, err)
+	assert.Empty(t, issues.Errors)
+	require.Len(t, issues.Warnings, 1)
+	assert.EqualError(t, issues.Warnings[0], `policy template "api" uses the agentless deployment mode, that requires Kibana 8.15.0 or later, but conditions.kibana.version "~8.12.0 || ~8.13.0" excludes these versions`)
+}
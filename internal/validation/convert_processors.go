@@ -0,0 +1,136 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/logger"
+)
+
+var numericFieldTypes = []string{"long", "integer", "short", "byte", "unsigned_long", "double", "float", "half_float", "scaled_float"}
+
+// convertTargetFieldTypes contains the field types that can store the values produced
+// by convert processors, for each one of the types they convert to.
+var convertTargetFieldTypes = map[string][]string{
+	"integer": numericFieldTypes,
+	"long":    numericFieldTypes,
+	"float":   numericFieldTypes,
+	"double":  numericFieldTypes,
+	"string":  {"keyword", "constant_keyword", "wildcard", "text", "match_only_text"},
+	"boolean": {"boolean"},
+	"ip":      {"ip", "keyword"},
+}
+
+type pipelineProcessors struct {
+	Processors []map[string]any `yaml:"processors"`
+	OnFailure  []map[string]any `yaml:"on_failure"`
+}
+
+// checkConvertProcessors checks that the types used in convert processors of ingest pipelines
+// are coherent with the types of the fields where the converted values are stored.
+func checkConvertProcessors(packageRoot string, issues *Issues) error {
+	manifests, err := dataStreamManifests(packageRoot)
+	if err != nil {
+		return err
+	}
+
+	for _, manifest := range manifests {
+		dataStreamRoot := filepath.Join(packageRoot, "data_stream", manifest.Name)
+		pipelineFiles, err := filepath.Glob(filepath.Join(dataStreamRoot, "elasticsearch", "ingest_pipeline", "*"))
+		if err != nil {
+			return fmt.Errorf("failed matching ingest pipelines: %w", err)
+		}
+		if len(pipelineFiles) == 0 {
+			continue
+		}
+
+		definitions, err := fields.LoadFieldsFromDataStream(dataStreamRoot)
+		if err != nil {
+			return fmt.Errorf("failed to load fields of data stream %q: %w", manifest.Name, err)
+		}
+
+		for _, pipelineFile := range pipelineFiles {
+			switch filepath.Ext(pipelineFile) {
+			case ".yml", ".yaml", ".json":
+			default:
+				continue
+			}
+
+			d, err := os.ReadFile(pipelineFile)
+			if err != nil {
+				return fmt.Errorf("failed to read ingest pipeline: %w", err)
+			}
+			var pipeline pipelineProcessors
+			err = yaml.Unmarshal(d, &pipeline)
+			if err != nil {
+				// Pipelines can contain templates that are only rendered on installation.
+				logger.Debugf("Skipping convert processors check for %s: %v", pipelineFile, err)
+				continue
+			}
+
+			path := relativePath(packageRoot, pipelineFile)
+			processors := append(pipeline.Processors, pipeline.OnFailure...)
+			checkConvertProcessorsInList(path, processors, definitions, issues)
+		}
+	}
+	return nil
+}
+
+func checkConvertProcessorsInList(pipelinePath string, processors []map[string]any, definitions []fields.FieldDefinition, issues *Issues) {
+	for _, processor := range processors {
+		for processorType, config := range processor {
+			c, ok := config.(map[string]any)
+			if !ok {
+				continue
+			}
+			if onFailure, ok := c["on_failure"].([]any); ok {
+				checkConvertProcessorsInList(pipelinePath, processorsFromList(onFailure), definitions, issues)
+			}
+			if processorType != "convert" {
+				continue
+			}
+
+			field, _ := c["target_field"].(string)
+			if field == "" {
+				field, _ = c["field"].(string)
+			}
+			convertType, _ := c["type"].(string)
+			if field == "" || strings.Contains(field, "{{") {
+				continue
+			}
+			expectedTypes, found := convertTargetFieldTypes[convertType]
+			if !found {
+				// "auto" and unknown types cannot be checked.
+				continue
+			}
+
+			definition := fields.FindElementDefinition(field, definitions)
+			if definition == nil || definition.Type == "" || definition.External != "" {
+				continue
+			}
+			if !slices.Contains(expectedTypes, definition.Type) {
+				issues.addWarningf("convert processor in %s converts field %q to %q, but it is defined with type %q", pipelinePath, field, convertType, definition.Type)
+			}
+		}
+	}
+}
+
+func processorsFromList(list []any) []map[string]any {
+	var processors []map[string]any
+	for _, elem := range list {
+		if processor, ok := elem.(map[string]any); ok {
+			processors = append(processors, processor)
+		}
+	}
+	return processors
+}
@@ -0,0 +1,22 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckConvertProcessors(t *testing.T) {
+	var issues Issues
+	err := checkConvertProcessors("testdata/convert_processors", &issues)
+	require.NoError(t, err)
+	assert.Empty(t, issues.Errors)
+	require.Len(t, issues.Warnings, 2)
+	assert.EqualError(t, issues.Warnings[0], `convert processor in data_stream/logs/elasticsearch/ingest_pipeline/default.yml converts field "http.response.status_code" to "long", but it is defined with type "keyword"`)
+	assert.EqualError(t, issues.Warnings[1], `convert processor in data_stream/logs/elasticsearch/ingest_pipeline/default.yml converts field "http.request.secure" to "string", but it is defined with type "boolean"`)
+}
@@ -0,0 +1,42 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"strings"
+)
+
+// checkDuplicateDataStreamTitles checks that each data stream in the package has a different
+// title, so they can be distinguished in the Fleet UI.
+func checkDuplicateDataStreamTitles(packageRoot string, issues *Issues) error {
+	manifests, err := dataStreamManifests(packageRoot)
+	if err != nil {
+		return err
+	}
+
+	// Titles are compared ignoring case and surrounding spaces, keeping the first
+	// occurrence of each one to be used in messages.
+	var titles []string
+	dataStreamsByTitle := make(map[string][]string)
+	for _, manifest := range manifests {
+		title := strings.TrimSpace(manifest.Title)
+		key := strings.ToLower(title)
+		if key == "" {
+			continue
+		}
+		if _, found := dataStreamsByTitle[key]; !found {
+			titles = append(titles, title)
+		}
+		dataStreamsByTitle[key] = append(dataStreamsByTitle[key], manifest.Name)
+	}
+
+	for _, title := range titles {
+		dataStreams := dataStreamsByTitle[strings.ToLower(title)]
+		if len(dataStreams) > 1 {
+			issues.addWarningf("data streams %s have the same title %q", strings.Join(dataStreams, ", "), title)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDuplicateDataStreamTitles(t *testing.T) {
+	var issues Issues
+	err := checkDuplicateDataStreamTitles("testdata/duplicate_data_stream_titles", &issues)
+	require.NoError(t, err)
+	assert.Empty(t, issues.Errors)
+	require.Len(t, issues.Warnings, 1)
+	assert.EqualError(t, issues.Warnings[0], `data streams access, error have the same title "Nginx logs"`)
+}
@@ -0,0 +1,138 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Masterminds/semver/v3"
+	"gopkg.in/yaml.v3"
+
+	"github.com/elastic/elastic-package/internal/logger"
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+// deprecatedProcessorOption is an option of an ingest processor deprecated since some version
+// of the stack.
+type deprecatedProcessorOption struct {
+	processor   string
+	option      string
+	since       *semver.Version
+	replacement string
+}
+
+// deprecatedProcessorOptions is the list of deprecated options of ingest processors. It is not
+// exhaustive, it is a starting point to add other options as they are deprecated by Elasticsearch.
+var deprecatedProcessorOptions = []deprecatedProcessorOption{
+	{
+		processor:   "user_agent",
+		option:      "ecs",
+		since:       semver.MustParse("7.0.0"),
+		replacement: "remove it, ECS format is always used",
+	},
+	{
+		processor:   "inference",
+		option:      "field_mappings",
+		since:       semver.MustParse("7.11.0"),
+		replacement: `use "field_map" instead`,
+	},
+}
+
+// checkDeprecatedProcessorOptions checks that the ingest pipelines of the data streams don't use
+// processor options that are deprecated in all the versions supported by the package, according
+// to its conditions. Options deprecated only in some of the supported versions are not reported,
+// as their replacements may not be available in the older ones.
+func checkDeprecatedProcessorOptions(packageRoot string, issues *Issues) error {
+	manifestPath := filepath.Join(packageRoot, packages.PackageManifestFile)
+	d, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return fmt.Errorf("failed to read package manifest: %w", err)
+	}
+	var manifest gatedManifest
+	err = yaml.Unmarshal(d, &manifest)
+	if err != nil {
+		return fmt.Errorf("failed to parse package manifest: %w", err)
+	}
+	if manifest.Conditions.Kibana.Version == "" {
+		return nil
+	}
+	constraint, err := semver.NewConstraint(manifest.Conditions.Kibana.Version)
+	if err != nil {
+		// Invalid constraints are reported by the spec validation.
+		return nil
+	}
+
+	var deprecated []deprecatedProcessorOption
+	for _, option := range deprecatedProcessorOptions {
+		if !allowsVersionsBetween(constraint, nil, option.since) {
+			deprecated = append(deprecated, option)
+		}
+	}
+	if len(deprecated) == 0 {
+		return nil
+	}
+
+	kibanaVersion := manifest.Conditions.Kibana.Version
+	dsManifests, err := dataStreamManifests(packageRoot)
+	if err != nil {
+		return err
+	}
+	for _, dsManifest := range dsManifests {
+		pipelineFiles, err := filepath.Glob(filepath.Join(packageRoot, "data_stream", dsManifest.Name, "elasticsearch", "ingest_pipeline", "*"))
+		if err != nil {
+			return fmt.Errorf("failed matching ingest pipelines: %w", err)
+		}
+		for _, pipelineFile := range pipelineFiles {
+			switch filepath.Ext(pipelineFile) {
+			case ".yml", ".yaml", ".json":
+			default:
+				continue
+			}
+
+			d, err := os.ReadFile(pipelineFile)
+			if err != nil {
+				return fmt.Errorf("failed to read ingest pipeline: %w", err)
+			}
+			var pipeline pipelineProcessors
+			err = yaml.Unmarshal(d, &pipeline)
+			if err != nil {
+				// Pipelines can contain templates that are only rendered on installation.
+				logger.Debugf("Skipping deprecated processor options check for %s: %v", pipelineFile, err)
+				continue
+			}
+
+			path := relativePath(packageRoot, pipelineFile)
+			processors := append(pipeline.Processors, pipeline.OnFailure...)
+			checkDeprecatedProcessorOptionsInList(path, processors, deprecated, kibanaVersion, issues)
+		}
+	}
+	return nil
+}
+
+func checkDeprecatedProcessorOptionsInList(pipelinePath string, processors []map[string]any, deprecated []deprecatedProcessorOption, constraint string, issues *Issues) {
+	for _, processor := range processors {
+		for processorType, config := range processor {
+			c, ok := config.(map[string]any)
+			if !ok {
+				continue
+			}
+			if onFailure, ok := c["on_failure"].([]any); ok {
+				checkDeprecatedProcessorOptionsInList(pipelinePath, processorsFromList(onFailure), deprecated, constraint, issues)
+			}
+			for _, option := range deprecated {
+				if option.processor != processorType {
+					continue
+				}
+				if _, found := c[option.option]; !found {
+					continue
+				}
+				issues.addWarningf("%s processor in %s uses option %q, deprecated since %s, and conditions.kibana.version %q only allows later versions (%s)",
+					processorType, pipelinePath, option.option, option.since, constraint, option.replacement)
+			}
+		}
+	}
+}
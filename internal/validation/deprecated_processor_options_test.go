@@ -0,0 +1,22 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDeprecatedProcessorOptions(t *testing.T) {
+	var issues Issues
+	err := checkDeprecatedProcessorOptions("testdata/deprecated_processor_options", &issues)
+	require.NoError(t, err)
+	assert.Empty(t, issues.Errors)
+	require.Len(t, issues.Warnings, 2)
+	assert.EqualError(t, issues.Warnings[0], `user_agent processor in data_stream/logs/elasticsearch/ingest_pipeline/default.yml uses option "ecs", deprecated since 7.0.0, and conditions.kibana.version "^7.11.0 || ^8.0.0" only allows later versions (remove it, ECS format is always used)`)
+	assert.EqualError(t, issues.Warnings[1], `inference processor in data_stream/logs/elasticsearch/ingest_pipeline/default.yml uses option "field_mappings", deprecated since 7.11.0, and conditions.kibana.version "^7.11.0 || ^8.0.0" only allows later versions (use "field_map" instead)`)
+}
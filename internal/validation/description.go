@@ -0,0 +1,60 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+// Limits of the length of package descriptions accepted by the package registry.
+const (
+	minDescriptionLength = 3
+	maxDescriptionLength = 300
+)
+
+// checkPackageDescription checks that the description of the package is accepted by the registry.
+// Descriptions out of the length limits are reported as warnings, so existing packages with them
+// can still be linted.
+func checkPackageDescription(packageRoot string, issues *Issues) error {
+	manifest, err := packages.ReadPackageManifestFromPackageRoot(packageRoot)
+	if err != nil {
+		return fmt.Errorf("failed to read package manifest: %w", err)
+	}
+
+	err = validateDescription(manifest.Description)
+	if err != nil {
+		issues.addErrorf("invalid package description: %w", err)
+		return nil
+	}
+	err = validateDescriptionLength(manifest.Description)
+	if err != nil {
+		issues.addWarningf("invalid package description: %w", err)
+	}
+	return nil
+}
+
+func validateDescription(description string) error {
+	// Descriptions can be defined with YAML block scalars, ignore surrounding whitespaces.
+	if strings.TrimSpace(description) == "" {
+		return errors.New("description cannot be empty")
+	}
+	return nil
+}
+
+func validateDescriptionLength(description string) error {
+	length := utf8.RuneCountInString(strings.TrimSpace(description))
+	if length < minDescriptionLength {
+		return fmt.Errorf("description is too short (%d characters, minimum is %d)", length, minDescriptionLength)
+	}
+	if length > maxDescriptionLength {
+		return fmt.Errorf("description is too long (%d characters, maximum is %d)", length, maxDescriptionLength)
+	}
+	return nil
+}
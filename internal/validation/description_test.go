@@ -0,0 +1,92 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDescription(t *testing.T) {
+	cases := []struct {
+		title       string
+		description string
+		expected    string
+	}{
+		{
+			title:       "valid",
+			description: "Collect logs from Nginx with Elastic Agent.",
+		},
+		{
+			title:       "empty",
+			description: "",
+			expected:    "description cannot be empty",
+		},
+		{
+			title:       "only whitespaces",
+			description: "  \n",
+			expected:    "description cannot be empty",
+		},
+		{
+			title:       "block scalar",
+			description: "Collect logs from Nginx\nwith Elastic Agent.\n",
+		},
+		{
+			title:       "too short",
+			description: "Ng",
+			expected:    "description is too short (2 characters, minimum is 3)",
+		},
+		{
+			title:       "too long",
+			description: strings.Repeat("a", maxDescriptionLength+1),
+			expected:    "description is too long (301 characters, maximum is 300)",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			err := validateDescription(c.description)
+			if err == nil {
+				err = validateDescriptionLength(c.description)
+			}
+			if c.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, c.expected)
+		})
+	}
+}
+
+func TestCheckPackageDescription(t *testing.T) {
+	check := func(t *testing.T, description string) Issues {
+		packageRoot := t.TempDir()
+		manifest := "name: example\ntitle: Example\nversion: 1.0.0\ntype: integration\ndescription: \"" + description + "\"\n"
+		require.NoError(t, os.WriteFile(filepath.Join(packageRoot, "manifest.yml"), []byte(manifest), 0644))
+
+		var issues Issues
+		require.NoError(t, checkPackageDescription(packageRoot, &issues))
+		return issues
+	}
+
+	t.Run("too short", func(t *testing.T) {
+		issues := check(t, "Ng")
+		assert.Empty(t, issues.Errors)
+		require.Len(t, issues.Warnings, 1)
+		assert.EqualError(t, issues.Warnings[0], "invalid package description: description is too short (2 characters, minimum is 3)")
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		issues := check(t, "")
+		assert.Empty(t, issues.Warnings)
+		require.Len(t, issues.Errors, 1)
+		assert.EqualError(t, issues.Errors[0], "invalid package description: description cannot be empty")
+	})
+}
@@ -0,0 +1,79 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/multierror"
+)
+
+// CheckDimensionTypeChanges compares the dimension fields of the data streams of the package with
+// the ones in a base version of the same package, and returns errors for the dimension fields
+// whose type has changed, as this breaks upgrades of time series data streams.
+func CheckDimensionTypeChanges(basePackageRoot, packageRoot string) (multierror.Error, error) {
+	manifests, err := dataStreamManifests(packageRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs multierror.Error
+	for _, manifest := range manifests {
+		baseDataStreamRoot := filepath.Join(basePackageRoot, "data_stream", manifest.Name)
+		_, err := os.Stat(baseDataStreamRoot)
+		if errors.Is(err, os.ErrNotExist) {
+			// New data stream.
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to check data stream %q in base package: %w", manifest.Name, err)
+		}
+
+		baseDefinitions, err := fields.LoadFieldsFromDataStream(baseDataStreamRoot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load fields of data stream %q in base package: %w", manifest.Name, err)
+		}
+		definitions, err := fields.LoadFieldsFromDataStream(filepath.Join(packageRoot, "data_stream", manifest.Name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load fields of data stream %q: %w", manifest.Name, err)
+		}
+
+		baseDimensions := make(map[string]string)
+		collectDimensionTypes("", baseDefinitions, baseDimensions)
+
+		keys := make([]string, 0, len(baseDimensions))
+		for key := range baseDimensions {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			definition := fields.FindElementDefinition(key, definitions)
+			if definition == nil {
+				continue
+			}
+			if baseType := baseDimensions[key]; definition.Type != baseType {
+				errs = append(errs, fmt.Errorf("type of dimension field %q in data stream %q changed from %q to %q", key, manifest.Name, baseType, definition.Type))
+			}
+		}
+	}
+	return errs, nil
+}
+
+func collectDimensionTypes(root string, definitions []fields.FieldDefinition, dimensions map[string]string) {
+	for _, definition := range definitions {
+		key := strings.TrimLeft(root+"."+definition.Name, ".")
+		if definition.Dimension {
+			dimensions[key] = definition.Type
+		}
+		collectDimensionTypes(key, definition.Fields, dimensions)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDimensionTypeChanges(t *testing.T) {
+	errs, err := CheckDimensionTypeChanges("testdata/dimension_type_change/base", "testdata/dimension_type_change/current")
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], `type of dimension field "host.port" in data stream "metrics" changed from "keyword" to "long"`)
+
+	errs, err = CheckDimensionTypeChanges("testdata/dimension_type_change/base", "testdata/dimension_type_change/base")
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+}
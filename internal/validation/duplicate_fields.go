@@ -0,0 +1,157 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/logger"
+	"github.com/elastic/elastic-package/internal/packages/buildmanifest"
+)
+
+// externalFieldTypeResolver returns the type of a field in an external schema.
+type externalFieldTypeResolver func(schema, name string) (string, error)
+
+// checkDuplicateFieldDefinitions checks that fields are not defined more than once in the fields
+// files of each data stream. Local definitions of external fields are only considered intentional
+// overrides when their type is meaningfully different to the type in the external schema.
+func checkDuplicateFieldDefinitions(packageRoot string, issues *Issues) error {
+	return checkDuplicateFieldDefinitionsWithResolver(packageRoot, issues, newExternalFieldTypeResolver(packageRoot))
+}
+
+func checkDuplicateFieldDefinitionsWithResolver(packageRoot string, issues *Issues, resolveType externalFieldTypeResolver) error {
+	manifests, err := dataStreamManifests(packageRoot)
+	if err != nil {
+		return err
+	}
+
+	for _, manifest := range manifests {
+		dataStreamRoot := filepath.Join(packageRoot, "data_stream", manifest.Name)
+		definitions, err := fields.LoadFieldsFromDataStream(dataStreamRoot)
+		if err != nil {
+			return fmt.Errorf("failed to load fields of data stream %q: %w", manifest.Name, err)
+		}
+
+		leaves := make(map[string][]fields.FieldDefinition)
+		collectLeafDefinitions("", definitions, leaves)
+
+		names := make([]string, 0, len(leaves))
+		for name, definitions := range leaves {
+			if len(definitions) > 1 {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			definitions := leaves[name]
+			if isExternalTypeOverride(name, definitions, resolveType) {
+				continue
+			}
+
+			var locations []string
+			for _, definition := range definitions {
+				location := "unknown location"
+				if definition.Source != nil {
+					location = definition.Source.String()
+				}
+				if definition.External != "" {
+					location += " (external: " + definition.External + ")"
+				}
+				locations = append(locations, location)
+			}
+			issues.addWarningf("field %q is defined more than once in data stream %q, at %s", name, manifest.Name, strings.Join(locations, ", "))
+		}
+	}
+	return nil
+}
+
+// collectLeafDefinitions collects the definitions of the fields that are not groups, by full name.
+func collectLeafDefinitions(root string, definitions []fields.FieldDefinition, leaves map[string][]fields.FieldDefinition) {
+	for _, definition := range definitions {
+		key := strings.TrimLeft(root+"."+definition.Name, ".")
+		if len(definition.Fields) > 0 {
+			collectLeafDefinitions(key, definition.Fields, leaves)
+			continue
+		}
+		if definition.Type == "group" {
+			continue
+		}
+		leaves[key] = append(leaves[key], definition)
+	}
+}
+
+// isExternalTypeOverride returns true if the definitions are an external field and a local
+// definition with a type that is meaningfully different to the external one.
+func isExternalTypeOverride(name string, definitions []fields.FieldDefinition, resolveType externalFieldTypeResolver) bool {
+	if len(definitions) != 2 {
+		return false
+	}
+	external, local := definitions[0], definitions[1]
+	if external.External == "" {
+		external, local = local, external
+	}
+	if external.External == "" || local.External != "" || local.Type == "" {
+		return false
+	}
+
+	externalType, err := resolveType(external.External, name)
+	if err != nil {
+		logger.Debugf("Failed to resolve type of external field %q: %v", name, err)
+		return false
+	}
+	return isMeaningfulTypeChange(externalType, local.Type)
+}
+
+// isMeaningfulTypeChange returns true if the types are different, and the new type is not
+// a refinement that could be set in the external definition itself.
+func isMeaningfulTypeChange(fromType, toType string) bool {
+	switch {
+	case fromType == toType:
+		return false
+	case fromType == "keyword" && toType == "constant_keyword":
+		return false
+	default:
+		return true
+	}
+}
+
+// newExternalFieldTypeResolver returns a resolver for the external fields of the package, that
+// only loads the external schemas when needed.
+func newExternalFieldTypeResolver(packageRoot string) externalFieldTypeResolver {
+	var dm *fields.DependencyManager
+	var dmErr error
+	loaded := false
+	return func(schema, name string) (string, error) {
+		if !loaded {
+			loaded = true
+			dm, dmErr = loadFieldDependencyManager(packageRoot)
+		}
+		if dmErr != nil {
+			return "", dmErr
+		}
+		definition, err := dm.ImportField(schema, name)
+		if err != nil {
+			return "", err
+		}
+		return definition.Type, nil
+	}
+}
+
+func loadFieldDependencyManager(packageRoot string) (*fields.DependencyManager, error) {
+	buildManifest, found, err := buildmanifest.ReadBuildManifest(packageRoot)
+	if err != nil {
+		return nil, fmt.Errorf("can't read build manifest: %w", err)
+	}
+	if !found {
+		return nil, errors.New("package doesn't have a build manifest with dependencies")
+	}
+	return fields.CreateFieldDependencyManager(buildManifest.Dependencies)
+}
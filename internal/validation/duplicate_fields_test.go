@@ -0,0 +1,41 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDuplicateFieldDefinitions(t *testing.T) {
+	ecsTypes := map[string]string{
+		"host.name": "keyword",
+		"source.ip": "ip",
+	}
+	resolveType := func(schema, name string) (string, error) {
+		if fieldType, found := ecsTypes[name]; schema == "ecs" && found {
+			return fieldType, nil
+		}
+		return "", fmt.Errorf("field %q not found in schema %q", name, schema)
+	}
+
+	var issues Issues
+	err := checkDuplicateFieldDefinitionsWithResolver("testdata/duplicate_fields", &issues, resolveType)
+	require.NoError(t, err)
+	assert.Empty(t, issues.Errors)
+
+	var warnings []string
+	for _, warning := range issues.Warnings {
+		warnings = append(warnings, warning.Error())
+	}
+	expected := []string{
+		`field "example.message" is defined more than once in data stream "logs", at fields/extra.yml:1, fields/fields.yml:10`,
+		`field "host.name" is defined more than once in data stream "logs", at fields/ecs.yml:1 (external: ecs), fields/fields.yml:1`,
+	}
+	assert.Equal(t, expected, warnings)
+}
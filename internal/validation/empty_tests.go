@@ -0,0 +1,67 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// testTypes are the types of tests supported by the test runners.
+var testTypes = []string{"asset", "pipeline", "policy", "static", "system"}
+
+// checkEmptyTestDirectories checks that the data streams with a _dev/test directory
+// contain some test for any of the supported test types.
+func checkEmptyTestDirectories(packageRoot string, issues *Issues) error {
+	manifests, err := dataStreamManifests(packageRoot)
+	if err != nil {
+		return err
+	}
+
+	for _, manifest := range manifests {
+		testRoot := filepath.Join(packageRoot, "data_stream", manifest.Name, "_dev", "test")
+		info, err := os.Stat(testRoot)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check test directory of data stream %q: %w", manifest.Name, err)
+		}
+		if !info.IsDir() {
+			continue
+		}
+
+		found, err := containsTests(testRoot)
+		if err != nil {
+			return fmt.Errorf("failed to look for tests in data stream %q: %w", manifest.Name, err)
+		}
+		if !found {
+			issues.addWarningf("data stream %q has a test directory, but it doesn't contain tests for any of the supported test types (%s)", manifest.Name, strings.Join(testTypes, ", "))
+		}
+	}
+	return nil
+}
+
+// containsTests returns true if any of the directories of the supported test types contain files.
+func containsTests(testRoot string) (bool, error) {
+	for _, testType := range testTypes {
+		entries, err := os.ReadDir(filepath.Join(testRoot, testType))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		for _, entry := range entries {
+			if entry.Type().IsRegular() && !strings.HasPrefix(entry.Name(), ".") {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
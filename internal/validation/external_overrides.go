@@ -0,0 +1,73 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/fields"
+)
+
+// checkExternalFieldOverrides checks that fields imported from external schemas don't override
+// locally settings that describe the values of the field, as they could contradict the schema.
+// Other settings, like descriptions or allowed type refinements, can be overridden.
+func checkExternalFieldOverrides(packageRoot string, issues *Issues) error {
+	manifests, err := dataStreamManifests(packageRoot)
+	if err != nil {
+		return err
+	}
+
+	for _, manifest := range manifests {
+		dataStreamRoot := filepath.Join(packageRoot, "data_stream", manifest.Name)
+		definitions, err := fields.LoadFieldsFromDataStream(dataStreamRoot)
+		if err != nil {
+			return fmt.Errorf("failed to load fields of data stream %q: %w", manifest.Name, err)
+		}
+		checkExternalFieldDefinitionsOverrides(manifest.Name, "", definitions, issues)
+	}
+	return nil
+}
+
+func checkExternalFieldDefinitionsOverrides(dataStream, root string, definitions []fields.FieldDefinition, issues *Issues) {
+	for _, definition := range definitions {
+		name := strings.TrimLeft(root+"."+definition.Name, ".")
+		if definition.External == "" {
+			checkExternalFieldDefinitionsOverrides(dataStream, name, definition.Fields, issues)
+			continue
+		}
+
+		overrides := disallowedExternalOverrides(definition)
+		if len(overrides) == 0 {
+			continue
+		}
+		location := "unknown location"
+		if definition.Source != nil {
+			location = definition.Source.String()
+		}
+		issues.addErrorf("field %q of data stream %q imported from %q at %s overrides %s, these settings cannot be changed in external fields",
+			name, dataStream, definition.External, location, strings.Join(overrides, ", "))
+	}
+}
+
+// disallowedExternalOverrides returns the settings of the definition that cannot be set locally
+// in external fields.
+func disallowedExternalOverrides(definition fields.FieldDefinition) []string {
+	var overrides []string
+	if len(definition.AllowedValues) > 0 {
+		overrides = append(overrides, "allowed_values")
+	}
+	if len(definition.ExpectedValues) > 0 {
+		overrides = append(overrides, "expected_values")
+	}
+	if len(definition.Normalize) > 0 {
+		overrides = append(overrides, "normalize")
+	}
+	if definition.Pattern != "" {
+		overrides = append(overrides, "pattern")
+	}
+	return overrides
+}
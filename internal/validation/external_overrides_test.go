@@ -0,0 +1,29 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckExternalFieldOverrides(t *testing.T) {
+	var issues Issues
+	err := checkExternalFieldOverrides("testdata/external_overrides", &issues)
+	require.NoError(t, err)
+	assert.Empty(t, issues.Warnings)
+
+	var errors []string
+	for _, err := range issues.Errors {
+		errors = append(errors, err.Error())
+	}
+	expected := []string{
+		`field "event.category" of data stream "logs" imported from "ecs" at fields/ecs.yml:1 overrides allowed_values, normalize, these settings cannot be changed in external fields`,
+		`field "user.name" of data stream "logs" imported from "ecs" at fields/ecs.yml:14 overrides pattern, these settings cannot be changed in external fields`,
+	}
+	assert.Equal(t, expected, errors)
+}
@@ -0,0 +1,91 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ErrPackageNotFoundInRef is returned when the package doesn't exist in the requested git reference.
+var ErrPackageNotFoundInRef = errors.New("package not found in git reference")
+
+// ExtractPackageFromRef writes the files of the package, as they are in the given git reference
+// of the repository containing the package, in the destination directory.
+func ExtractPackageFromRef(packageRoot, ref, destination string) error {
+	repo, err := git.PlainOpenWithOptions(packageRoot, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return fmt.Errorf("failed to open git repository of package: %w", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get working tree of git repository: %w", err)
+	}
+	packagePath, err := pathInRepository(wt.Filesystem.Root(), packageRoot)
+	if err != nil {
+		return err
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return fmt.Errorf("failed to resolve git reference %q: %w", ref, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return fmt.Errorf("failed to get commit for git reference %q: %w", ref, err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return fmt.Errorf("failed to get tree for git reference %q: %w", ref, err)
+	}
+	if packagePath != "." {
+		tree, err = tree.Tree(filepath.ToSlash(packagePath))
+		if errors.Is(err, object.ErrDirectoryNotFound) {
+			return ErrPackageNotFoundInRef
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find package in git reference %q: %w", ref, err)
+		}
+	}
+
+	return tree.Files().ForEach(func(f *object.File) error {
+		contents, err := f.Contents()
+		if err != nil {
+			return fmt.Errorf("failed to read %s in git reference %q: %w", f.Name, ref, err)
+		}
+		path := filepath.Join(destination, filepath.FromSlash(f.Name))
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, []byte(contents), 0644)
+	})
+}
+
+func pathInRepository(repositoryRoot, path string) (string, error) {
+	repositoryRoot, err := filepath.EvalSymlinks(repositoryRoot)
+	if err != nil {
+		return "", err
+	}
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	path, err = filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(repositoryRoot, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to find package path in git repository: %w", err)
+	}
+	return rel, nil
+}
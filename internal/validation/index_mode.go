@@ -0,0 +1,58 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/elastic/elastic-package/internal/fields"
+)
+
+const indexModeTimeSeries = "time_series"
+
+// checkIndexModeCoherence checks that data streams using the time_series index mode
+// declare TSDB dimensions and metric types, and that dimensions are not declared in
+// data streams that don't use this index mode.
+func checkIndexModeCoherence(packageRoot string, issues *Issues) error {
+	manifests, err := dataStreamManifests(packageRoot)
+	if err != nil {
+		return err
+	}
+
+	for _, manifest := range manifests {
+		definitions, err := fields.LoadFieldsFromDataStream(filepath.Join(packageRoot, "data_stream", manifest.Name))
+		if err != nil {
+			return fmt.Errorf("failed to load fields of data stream %q: %w", manifest.Name, err)
+		}
+
+		dimensions, metrics := countTimeSeriesFields(definitions)
+		timeSeries := manifest.Elasticsearch != nil && manifest.Elasticsearch.IndexMode == indexModeTimeSeries
+		switch {
+		case timeSeries && dimensions == 0:
+			issues.addErrorf("data stream %q uses index_mode %q, but it doesn't declare any dimension field", manifest.Name, indexModeTimeSeries)
+		case timeSeries && metrics == 0:
+			issues.addWarningf("data stream %q uses index_mode %q, but it doesn't declare any field with metric_type", manifest.Name, indexModeTimeSeries)
+		case !timeSeries && dimensions > 0:
+			issues.addWarningf("data stream %q declares %d dimension fields, but it doesn't use index_mode %q", manifest.Name, dimensions, indexModeTimeSeries)
+		}
+	}
+	return nil
+}
+
+func countTimeSeriesFields(definitions []fields.FieldDefinition) (dimensions int, metrics int) {
+	for _, definition := range definitions {
+		if definition.Dimension {
+			dimensions++
+		}
+		if definition.MetricType != "" {
+			metrics++
+		}
+		d, m := countTimeSeriesFields(definition.Fields)
+		dimensions += d
+		metrics += m
+	}
+	return dimensions, metrics
+}
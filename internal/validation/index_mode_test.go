@@ -0,0 +1,30 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckIndexModeCoherence(t *testing.T) {
+	t.Run("time series without dimensions", func(t *testing.T) {
+		var issues Issues
+		err := checkIndexModeCoherence("testdata/time_series_without_dimensions", &issues)
+		require.NoError(t, err)
+		require.Len(t, issues.Errors, 1)
+		assert.Contains(t, issues.Errors[0].Error(), `data stream "metrics" uses index_mode "time_series", but it doesn't declare any dimension field`)
+	})
+
+	t.Run("time series with dimensions", func(t *testing.T) {
+		var issues Issues
+		err := checkIndexModeCoherence("testdata/time_series_with_dimensions", &issues)
+		require.NoError(t, err)
+		assert.Empty(t, issues.Errors)
+		assert.Empty(t, issues.Warnings)
+	})
+}
@@ -0,0 +1,46 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/fields"
+)
+
+// checkIndexPrefixes checks that the index_prefixes settings of the fields are within the
+// bounds allowed by Elasticsearch, so they don't fail when the package is installed.
+func checkIndexPrefixes(packageRoot string, issues *Issues) error {
+	manifests, err := dataStreamManifests(packageRoot)
+	if err != nil {
+		return err
+	}
+
+	for _, manifest := range manifests {
+		definitions, err := fields.LoadFieldsFromDataStream(filepath.Join(packageRoot, "data_stream", manifest.Name))
+		if err != nil {
+			return fmt.Errorf("failed to load fields of data stream %q: %w", manifest.Name, err)
+		}
+
+		checkIndexPrefixesInDefinitions(manifest.Name, "", definitions, issues)
+	}
+	return nil
+}
+
+func checkIndexPrefixesInDefinitions(dataStream, root string, definitions []fields.FieldDefinition, issues *Issues) {
+	for _, definition := range definitions {
+		key := strings.TrimLeft(root+"."+definition.Name, ".")
+		if definition.IndexPrefixes != nil {
+			err := definition.IndexPrefixes.Validate()
+			if err != nil {
+				issues.addErrorf("field %q in data stream %q has invalid index_prefixes: %w", key, dataStream, err)
+			}
+		}
+		checkIndexPrefixesInDefinitions(dataStream, key, definition.Fields, issues)
+		checkIndexPrefixesInDefinitions(dataStream, key, definition.MultiFields, issues)
+	}
+}
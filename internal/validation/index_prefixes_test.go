@@ -0,0 +1,23 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckIndexPrefixes(t *testing.T) {
+	var issues Issues
+	err := checkIndexPrefixes("testdata/index_prefixes", &issues)
+	require.NoError(t, err)
+	assert.Empty(t, issues.Warnings)
+	require.Len(t, issues.Errors, 3)
+	assert.EqualError(t, issues.Errors[0], `field "log.body" in data stream "logs" has invalid index_prefixes: max_chars [25] must be less than 20`)
+	assert.EqualError(t, issues.Errors[1], `field "log.title.text" in data stream "logs" has invalid index_prefixes: min_chars [6] must be less than or equal to max_chars [5]`)
+	assert.EqualError(t, issues.Errors[2], `field "log.subject" in data stream "logs" has invalid index_prefixes: min_chars [0] must be greater than zero`)
+}
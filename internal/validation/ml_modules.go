@@ -0,0 +1,111 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+// mlModuleIndexPatternPlaceholder is replaced by Kibana with the default index pattern of the module.
+const mlModuleIndexPatternPlaceholder = "INDEX_PATTERN_NAME"
+
+type mlModule struct {
+	Attributes struct {
+		DefaultIndexPattern string `json:"defaultIndexPattern"`
+		Datafeeds           []struct {
+			ID     string `json:"id"`
+			Config struct {
+				Indices []string `json:"indices"`
+			} `json:"config"`
+		} `json:"datafeeds"`
+	} `json:"attributes"`
+}
+
+// checkMLModuleIndexPatterns checks that the index patterns used by the ML modules of the
+// package match the indexes of some of its data streams. Modules can legitimately query data
+// of other packages, so patterns that don't match are reported as warnings.
+func checkMLModuleIndexPatterns(packageRoot string, issues *Issues) error {
+	modulePaths, err := filepath.Glob(filepath.Join(packageRoot, "kibana", "ml_module", "*.json"))
+	if err != nil {
+		return fmt.Errorf("failed matching ML modules: %w", err)
+	}
+	if len(modulePaths) == 0 {
+		return nil
+	}
+
+	manifest, err := packages.ReadPackageManifestFromPackageRoot(packageRoot)
+	if err != nil {
+		return fmt.Errorf("failed to read package manifest: %w", err)
+	}
+	dataStreams, err := dataStreamManifests(packageRoot)
+	if err != nil {
+		return err
+	}
+	if len(dataStreams) == 0 {
+		// Nothing to compare with.
+		return nil
+	}
+
+	var indexes []string
+	for _, dataStream := range dataStreams {
+		dataset := dataStream.Dataset
+		if dataset == "" {
+			dataset = manifest.Name + "." + dataStream.Name
+		}
+		indexes = append(indexes, fmt.Sprintf("%s-%s-default", dataStream.Type, dataset))
+	}
+
+	for _, modulePath := range modulePaths {
+		d, err := os.ReadFile(modulePath)
+		if err != nil {
+			return fmt.Errorf("failed to read ML module: %w", err)
+		}
+		var module mlModule
+		err = json.Unmarshal(d, &module)
+		if err != nil {
+			issues.addErrorf("failed to parse ML module %s: %v", relativePath(packageRoot, modulePath), err)
+			continue
+		}
+
+		patterns := []string{module.Attributes.DefaultIndexPattern}
+		for _, datafeed := range module.Attributes.Datafeeds {
+			patterns = append(patterns, datafeed.Config.Indices...)
+		}
+
+		reported := make(map[string]bool)
+		for _, pattern := range patterns {
+			if pattern == "" || pattern == mlModuleIndexPatternPlaceholder || reported[pattern] {
+				continue
+			}
+			if !indexPatternMatchesAny(pattern, indexes) {
+				issues.addWarningf("ML module %s references index pattern %q, that doesn't match any data stream of the package", relativePath(packageRoot, modulePath), pattern)
+				reported[pattern] = true
+			}
+		}
+	}
+	return nil
+}
+
+// indexPatternMatchesAny returns true if some of the comma-separated expressions in the
+// index pattern match any of the given indexes.
+func indexPatternMatchesAny(pattern string, indexes []string) bool {
+	for _, expression := range strings.Split(pattern, ",") {
+		expression = strings.TrimSpace(expression)
+		for _, index := range indexes {
+			// Index names cannot contain slashes, so path matching can be used for wildcards.
+			if matched, err := path.Match(expression, index); err == nil && matched {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckMLModuleIndexPatterns(t *testing.T) {
+	var issues Issues
+	err := checkMLModuleIndexPatterns("testdata/ml_module_index_patterns", &issues)
+	require.NoError(t, err)
+	assert.Empty(t, issues.Errors)
+	require.Len(t, issues.Warnings, 1)
+	assert.EqualError(t, issues.Warnings[0], `ML module kibana/ml_module/ml_module_index_patterns-Logs-ml.json references index pattern "logs-ml_module_index_patterns.error-*", that doesn't match any data stream of the package`)
+}
+
+func TestIndexPatternMatchesAny(t *testing.T) {
+	indexes := []string{"logs-nginx.access-default", "metrics-nginx.stubstatus-default"}
+
+	assert.True(t, indexPatternMatchesAny("logs-*", indexes))
+	assert.True(t, indexPatternMatchesAny("logs-nginx.error-*, metrics-nginx.*", indexes))
+	assert.False(t, indexPatternMatchesAny("logs-nginx.error-*", indexes))
+	assert.False(t, indexPatternMatchesAny("traces-*", indexes))
+}
@@ -0,0 +1,97 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/logger"
+)
+
+// testConfigPatterns are the patterns of the test configuration files that can declare
+// exceptions to fields validation, relative to the data stream directory.
+var testConfigPatterns = []string{
+	filepath.Join("_dev", "test", "pipeline", "*-config.yml"),
+	filepath.Join("_dev", "test", "system", "test-*-config.yml"),
+	filepath.Join("_dev", "test", "static", "config.yml"),
+}
+
+type fieldsExceptionsConfig struct {
+	NumericKeywordFields []fields.NumericKeywordField `yaml:"numeric_keyword_fields"`
+}
+
+// checkStaleNumericKeywordFields checks that the fields configured as numeric keyword
+// fields in test configurations are defined in the data stream.
+func checkStaleNumericKeywordFields(packageRoot string, issues *Issues) error {
+	manifests, err := dataStreamManifests(packageRoot)
+	if err != nil {
+		return err
+	}
+
+	for _, manifest := range manifests {
+		dataStreamRoot := filepath.Join(packageRoot, "data_stream", manifest.Name)
+		configFiles, err := testConfigFiles(dataStreamRoot)
+		if err != nil {
+			return err
+		}
+		if len(configFiles) == 0 {
+			continue
+		}
+
+		definitions, err := fields.LoadFieldsFromDataStream(dataStreamRoot)
+		if err != nil {
+			return fmt.Errorf("failed to load fields of data stream %q: %w", manifest.Name, err)
+		}
+
+		for _, configFile := range configFiles {
+			d, err := os.ReadFile(configFile)
+			if err != nil {
+				return fmt.Errorf("failed to read test configuration: %w", err)
+			}
+
+			var config fieldsExceptionsConfig
+			err = yaml.Unmarshal(d, &config)
+			if err != nil {
+				// Test configurations can contain templates that are only rendered on runtime.
+				logger.Debugf("Skipping numeric keyword fields check for %s: %v", configFile, err)
+				continue
+			}
+
+			for _, field := range config.NumericKeywordFields {
+				if fields.FindElementDefinition(field.Name, definitions) == nil {
+					issues.addWarningf("numeric keyword field %q configured in %s doesn't match any field defined in data stream %q", field.Name, relativePath(packageRoot, configFile), manifest.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// testConfigFiles returns the test configuration files of the data stream in the given path.
+func testConfigFiles(dataStreamRoot string) ([]string, error) {
+	var files []string
+	for _, pattern := range testConfigPatterns {
+		matches, err := filepath.Glob(filepath.Join(dataStreamRoot, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("failed matching test configuration files: %w", err)
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// relativePath returns the path of the given file relative to the package root, to be used in messages.
+func relativePath(packageRoot, path string) string {
+	rel, err := filepath.Rel(packageRoot, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
@@ -0,0 +1,21 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckStaleNumericKeywordFields(t *testing.T) {
+	var issues Issues
+	err := checkStaleNumericKeywordFields("testdata/stale_numeric_keyword_fields", &issues)
+	require.NoError(t, err)
+	assert.Empty(t, issues.Errors)
+	require.Len(t, issues.Warnings, 1)
+	assert.Contains(t, issues.Warnings[0].Error(), `numeric keyword field "service.status" configured in data_stream/logs/_dev/test/pipeline/test-common-config.yml doesn't match any field defined in data stream "logs"`)
+}
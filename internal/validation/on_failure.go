@@ -0,0 +1,168 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/elastic/elastic-package/internal/logger"
+)
+
+// pipelineErrorEventKind is the value of event.kind for documents that failed in the ingest pipeline.
+const pipelineErrorEventKind = "pipeline_error"
+
+// onFailureMessageField is the ingest metadata field with the message of the failure, available
+// in on_failure processors.
+const onFailureMessageField = "_ingest.on_failure_message"
+
+// failureStoreOptions contains the settings of a data stream manifest that enable the failure
+// store, so failed documents are stored there without needing on_failure processors.
+type failureStoreOptions struct {
+	Elasticsearch struct {
+		IndexTemplate struct {
+			DataStreamOptions struct {
+				FailureStore struct {
+					Enabled bool `yaml:"enabled"`
+				} `yaml:"failure_store"`
+			} `yaml:"data_stream_options"`
+		} `yaml:"index_template"`
+	} `yaml:"elasticsearch"`
+}
+
+// checkOnFailureHandling checks that the entry ingest pipelines of the data streams handle
+// failures, by failing the document so it is stored in the failure store, rerouting it, or
+// tagging it as a failure. Data streams with the failure store enabled are not checked.
+func checkOnFailureHandling(packageRoot string, issues *Issues) error {
+	manifests, err := dataStreamManifests(packageRoot)
+	if err != nil {
+		return err
+	}
+
+	for _, manifest := range manifests {
+		dataStreamDir := filepath.Join(packageRoot, "data_stream", manifest.Name)
+		enabled, err := failureStoreEnabled(dataStreamDir)
+		if err != nil {
+			return err
+		}
+		if enabled {
+			continue
+		}
+
+		pipelineDir := filepath.Join(dataStreamDir, "elasticsearch", "ingest_pipeline")
+		pipelinePath, found, err := findPipelineFile(pipelineDir, manifest.GetPipelineNameOrDefault())
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+
+		d, err := os.ReadFile(pipelinePath)
+		if err != nil {
+			return fmt.Errorf("failed to read ingest pipeline: %w", err)
+		}
+		var pipeline pipelineProcessors
+		err = yaml.Unmarshal(d, &pipeline)
+		if err != nil {
+			// Pipelines can contain templates that are only rendered on installation.
+			logger.Debugf("Skipping on_failure check for %s: %v", pipelinePath, err)
+			continue
+		}
+
+		path := relativePath(packageRoot, pipelinePath)
+		switch {
+		case len(pipeline.OnFailure) == 0:
+			issues.addWarningf("ingest pipeline %s doesn't define on_failure processors, failures won't be handled", path)
+		case !handlesFailures(pipeline.OnFailure):
+			issues.addWarningf("on_failure processors of ingest pipeline %s don't store failed documents in the failure store, reroute them, or tag them as failures (with event.kind: %s, error.message from %s or a failure tag)", path, pipelineErrorEventKind, onFailureMessageField)
+		}
+	}
+	return nil
+}
+
+// findPipelineFile looks for the file of the pipeline with the given name, in any of the supported formats.
+func findPipelineFile(pipelineDir, name string) (string, bool, error) {
+	for _, ext := range []string{".yml", ".yaml", ".json"} {
+		path := filepath.Join(pipelineDir, name+ext)
+		_, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", false, fmt.Errorf("failed to look for ingest pipeline: %w", err)
+		}
+		return path, true, nil
+	}
+	return "", false, nil
+}
+
+// failureStoreEnabled returns true if the manifest of the data stream in the given directory
+// enables the failure store.
+func failureStoreEnabled(dataStreamDir string) (bool, error) {
+	d, err := os.ReadFile(filepath.Join(dataStreamDir, "manifest.yml"))
+	if err != nil {
+		return false, fmt.Errorf("failed to read data stream manifest: %w", err)
+	}
+	var options failureStoreOptions
+	err = yaml.Unmarshal(d, &options)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse data stream manifest: %w", err)
+	}
+	return options.Elasticsearch.IndexTemplate.DataStreamOptions.FailureStore.Enabled, nil
+}
+
+// handlesFailures returns true if some of the processors fails the document, so it is stored in
+// the failure store when enabled, reroutes it, or tags it as a failure.
+func handlesFailures(processors []map[string]any) bool {
+	for _, processor := range processors {
+		for processorType, config := range processor {
+			c, _ := config.(map[string]any)
+			switch processorType {
+			case "fail", "reroute":
+				return true
+			case "set", "append":
+				field, _ := c["field"].(string)
+				switch field {
+				case "event.kind":
+					if value, _ := c["value"].(string); value == pipelineErrorEventKind {
+						return true
+					}
+				case "error.message":
+					if value, _ := c["value"].(string); strings.Contains(value, onFailureMessageField) {
+						return true
+					}
+				case "tags":
+					if isFailureTag(c["value"]) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isFailureTag returns true if the value, or some of the values if it is a list, is a tag that
+// identifies failed documents, as "pipeline_error" or "_grokparsefailure".
+func isFailureTag(value any) bool {
+	switch value := value.(type) {
+	case string:
+		tag := strings.ToLower(value)
+		return strings.Contains(tag, "error") || strings.Contains(tag, "fail")
+	case []any:
+		for _, v := range value {
+			if isFailureTag(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckOnFailureHandling(t *testing.T) {
+	var issues Issues
+	err := checkOnFailureHandling("testdata/on_failure_handling", &issues)
+	require.NoError(t, err)
+	assert.Empty(t, issues.Errors)
+	require.Len(t, issues.Warnings, 3)
+	assert.EqualError(t, issues.Warnings[0], "on_failure processors of ingest pipeline data_stream/generic_tags/elasticsearch/ingest_pipeline/default.yml don't store failed documents in the failure store, reroute them, or tag them as failures (with event.kind: pipeline_error, error.message from _ingest.on_failure_message or a failure tag)")
+	assert.EqualError(t, issues.Warnings[1], "ingest pipeline data_stream/missing/elasticsearch/ingest_pipeline/default.json doesn't define on_failure processors, failures won't be handled")
+	assert.EqualError(t, issues.Warnings[2], "on_failure processors of ingest pipeline data_stream/unhandled/elasticsearch/ingest_pipeline/default.yml don't store failed documents in the failure store, reroute them, or tag them as failures (with event.kind: pipeline_error, error.message from _ingest.on_failure_message or a failure tag)")
+}
+
+func TestValidateSemanticsFromPath_OptionalChecks(t *testing.T) {
+	missingWarning := "ingest pipeline data_stream/missing/elasticsearch/ingest_pipeline/default.json doesn't define on_failure processors, failures won't be handled"
+
+	issues, err := ValidateSemanticsFromPath("testdata/on_failure_handling")
+	require.NoError(t, err)
+	assert.NotContains(t, warningMessages(issues), missingWarning)
+
+	issues, err = ValidateSemanticsFromPath("testdata/on_failure_handling", "on_failure_handling")
+	require.NoError(t, err)
+	assert.Contains(t, warningMessages(issues), missingWarning)
+
+	_, err = ValidateSemanticsFromPath("testdata/on_failure_handling", "unknown")
+	assert.EqualError(t, err, `unknown check "unknown" (available: on_failure_handling)`)
+}
+
+func warningMessages(issues *Issues) []string {
+	var messages []string
+	for _, warning := range issues.Warnings {
+		messages = append(messages, warning.Error())
+	}
+	return messages
+}
@@ -0,0 +1,143 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"gopkg.in/yaml.v3"
+
+	"github.com/elastic/elastic-package/internal/multierror"
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+var semver3_0_0 = semver.MustParse("3.0.0")
+
+// removedManifestKey is a manifest key that is not allowed anymore since some version of the spec.
+type removedManifestKey struct {
+	// path is the path of the key in the manifest, keys can contain dots.
+	path      []string
+	removedIn *semver.Version
+	guidance  string
+}
+
+var removedPackageManifestKeys = []removedManifestKey{
+	{
+		path:      []string{"release"},
+		removedIn: semver3_0_0,
+		guidance:  "use a prerelease version, such as 1.0.0-beta1, for packages that are not generally available",
+	},
+	{
+		path:      []string{"license"},
+		removedIn: semver3_0_0,
+		guidance:  "use conditions.elastic.subscription instead",
+	},
+	{
+		path:      []string{"conditions", "kibana.version"},
+		removedIn: semver3_0_0,
+		guidance:  "use conditions.kibana.version instead, dotted keys are not allowed",
+	},
+	{
+		path:      []string{"conditions", "elastic.subscription"},
+		removedIn: semver3_0_0,
+		guidance:  "use conditions.elastic.subscription instead, dotted keys are not allowed",
+	},
+}
+
+var removedDataStreamManifestKeys = []removedManifestKey{
+	{
+		path:      []string{"release"},
+		removedIn: semver3_0_0,
+		guidance:  "remove it, the maturity of data streams is determined by the version of the package",
+	},
+}
+
+// CheckRemovedManifestKeys looks for keys in the manifests of the package that are not allowed
+// anymore in its spec version, and returns errors with guidance about how to replace them.
+func CheckRemovedManifestKeys(packageRoot string) (multierror.Error, error) {
+	manifest, err := packages.ReadPackageManifestFromPackageRoot(packageRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read package manifest: %w", err)
+	}
+	specVersion, err := semver.NewVersion(manifest.SpecVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse format version %q: %w", manifest.SpecVersion, err)
+	}
+
+	errs, err := findRemovedManifestKeys(packageRoot, packages.PackageManifestFile, specVersion, removedPackageManifestKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	dataStreamManifests, err := filepath.Glob(filepath.Join(packageRoot, "data_stream", "*", packages.DataStreamManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed matching data stream manifests: %w", err)
+	}
+	for _, path := range dataStreamManifests {
+		dsErrs, err := findRemovedManifestKeys(packageRoot, relativePath(packageRoot, path), specVersion, removedDataStreamManifestKeys)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, dsErrs...)
+	}
+	return errs, nil
+}
+
+func findRemovedManifestKeys(packageRoot, manifestPath string, specVersion *semver.Version, removedKeys []removedManifestKey) (multierror.Error, error) {
+	d, err := os.ReadFile(filepath.Join(packageRoot, manifestPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	var manifest map[string]any
+	err = yaml.Unmarshal(d, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest (path: %s): %w", manifestPath, err)
+	}
+
+	var errs multierror.Error
+	for _, removed := range removedKeys {
+		if specVersion.LessThan(removed.removedIn) {
+			continue
+		}
+		if !hasManifestKey(manifest, removed.path) {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("%s: key %s was removed in format version %s and cannot be used with format version %s, %s",
+			manifestPath, manifestKeyString(removed.path), removed.removedIn, specVersion, removed.guidance))
+	}
+	return errs, nil
+}
+
+func hasManifestKey(manifest map[string]any, path []string) bool {
+	var current any = manifest
+	for _, key := range path {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return false
+		}
+		current, ok = m[key]
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// manifestKeyString returns the representation of a manifest key path, quoting the keys that contain dots.
+func manifestKeyString(path []string) string {
+	keys := make([]string, len(path))
+	for i, key := range path {
+		if strings.Contains(key, ".") {
+			key = strconv.Quote(key)
+		}
+		keys[i] = key
+	}
+	return strings.Join(keys, ".")
+}
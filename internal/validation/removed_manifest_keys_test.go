@@ -0,0 +1,30 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRemovedManifestKeys(t *testing.T) {
+	t.Run("removed keys", func(t *testing.T) {
+		errs, err := CheckRemovedManifestKeys("testdata/removed_manifest_keys")
+		require.NoError(t, err)
+		require.Len(t, errs, 4)
+		assert.EqualError(t, errs[0], `manifest.yml: key release was removed in format version 3.0.0 and cannot be used with format version 3.0.0, use a prerelease version, such as 1.0.0-beta1, for packages that are not generally available`)
+		assert.EqualError(t, errs[1], `manifest.yml: key license was removed in format version 3.0.0 and cannot be used with format version 3.0.0, use conditions.elastic.subscription instead`)
+		assert.EqualError(t, errs[2], `manifest.yml: key conditions."kibana.version" was removed in format version 3.0.0 and cannot be used with format version 3.0.0, use conditions.kibana.version instead, dotted keys are not allowed`)
+		assert.EqualError(t, errs[3], `data_stream/logs/manifest.yml: key release was removed in format version 3.0.0 and cannot be used with format version 3.0.0, remove it, the maturity of data streams is determined by the version of the package`)
+	})
+
+	t.Run("no removed keys", func(t *testing.T) {
+		errs, err := CheckRemovedManifestKeys("testdata/time_series_with_dimensions")
+		require.NoError(t, err)
+		assert.Empty(t, errs)
+	})
+}
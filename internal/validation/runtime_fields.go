@@ -0,0 +1,62 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/multierror"
+)
+
+// CheckRuntimeFields checks the given runtime fields against the fields defined in the package,
+// using a validator created with the given options, and against the values found in the sample
+// events of the package and its data streams. The returned error is only used when the package
+// cannot be checked, conflicts are returned in the list of errors.
+func CheckRuntimeFields(packageRootPath string, runtimeFields []fields.RuntimeField, opts ...fields.ValidatorOption) (multierror.Error, error) {
+	dataStreamDirs, err := filepath.Glob(filepath.Join(packageRootPath, "data_stream", "*"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look for data streams: %w", err)
+	}
+	var validator *fields.Validator
+	if len(dataStreamDirs) > 0 {
+		validator, err = fields.CreateValidatorForDataStreams(dataStreamDirs, opts...)
+	} else {
+		validator, err = fields.CreateValidatorForDirectory(packageRootPath, opts...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating fields validator failed: %w", err)
+	}
+
+	errs := validator.ValidateRuntimeFields(runtimeFields)
+
+	var sampleEvents []string
+	for _, dir := range append([]string{packageRootPath}, dataStreamDirs...) {
+		paths, err := fields.SampleEventFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		sampleEvents = append(sampleEvents, paths...)
+	}
+	for _, path := range sampleEvents {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("can't read file: %w", err)
+		}
+		var doc common.MapStr
+		err = json.Unmarshal(content, &doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sample event %q: %w", path, err)
+		}
+		for _, err := range fields.ValidateRuntimeFieldsInDocument(runtimeFields, doc) {
+			errs = append(errs, fmt.Errorf("sample event %s: %w", path, err))
+		}
+	}
+	return errs, nil
+}
@@ -0,0 +1,70 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/fields"
+)
+
+const runtimeFieldsTestFields = `
+- name: http.status
+  type: long
+- name: event.slow
+  type: boolean
+`
+
+func TestCheckRuntimeFields(t *testing.T) {
+	runtimeFields := []fields.RuntimeField{
+		{Name: "http.status", Type: "keyword", Path: "index_pattern.json"},
+		{Name: "event.slow", Type: "boolean", Path: "index_pattern.json"},
+	}
+
+	t.Run("data streams", func(t *testing.T) {
+		packageRoot := t.TempDir()
+		dataStreamDir := filepath.Join(packageRoot, "data_stream", "example")
+		writeTestFile(t, filepath.Join(dataStreamDir, "fields", "fields.yml"), runtimeFieldsTestFields)
+		writeTestFile(t, filepath.Join(dataStreamDir, "sample_event.json"), `{"event": {"slow": true}}`)
+		writeTestFile(t, filepath.Join(dataStreamDir, "_dev", "sample_events", "slow.json"), `{"event": {"slow": "yes"}}`)
+
+		errs, err := CheckRuntimeFields(packageRoot, runtimeFields, fields.WithDisabledDependencyManagement())
+		require.NoError(t, err)
+		require.Len(t, errs, 2)
+		assert.Contains(t, errs[0].Error(), `runtime field "http.status" of type keyword`)
+		assert.Contains(t, errs[1].Error(), "sample event "+filepath.Join(dataStreamDir, "_dev", "sample_events", "slow.json"))
+		assert.Contains(t, errs[1].Error(), `value yes of field "event.slow" conflicts with runtime field of type boolean`)
+	})
+
+	t.Run("package without data streams", func(t *testing.T) {
+		packageRoot := t.TempDir()
+		writeTestFile(t, filepath.Join(packageRoot, "fields", "fields.yml"), runtimeFieldsTestFields)
+		writeTestFile(t, filepath.Join(packageRoot, "sample_event.json"), `{"event": {"slow": false}}`)
+
+		errs, err := CheckRuntimeFields(packageRoot, runtimeFields[1:], fields.WithDisabledDependencyManagement())
+		require.NoError(t, err)
+		assert.Empty(t, errs)
+	})
+
+	t.Run("invalid sample event", func(t *testing.T) {
+		packageRoot := t.TempDir()
+		writeTestFile(t, filepath.Join(packageRoot, "fields", "fields.yml"), runtimeFieldsTestFields)
+		writeTestFile(t, filepath.Join(packageRoot, "sample_event.json"), `{"event": `)
+
+		_, err := CheckRuntimeFields(packageRoot, runtimeFields, fields.WithDisabledDependencyManagement())
+		assert.ErrorContains(t, err, "failed to parse sample event")
+	})
+}
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
@@ -0,0 +1,60 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// generatedIDPattern matches the UUIDs that Kibana generates as IDs of new saved objects. IDs
+// that include a UUID with a prefix, as the <package>-<uuid> IDs used by many packages, are stable.
+var generatedIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// stableIDAssetTypes are the folders of the Kibana assets exported from Kibana, that can
+// have stable IDs. Other assets, as security rules, use UUIDs by convention.
+var stableIDAssetTypes = []string{
+	"dashboard",
+	"index_pattern",
+	"lens",
+	"map",
+	"search",
+	"tag",
+	"visualization",
+}
+
+// checkSavedObjectIDs checks that the Kibana saved objects of the package have stable IDs,
+// instead of the random ones generated by Kibana, so they don't change when exported again.
+func checkSavedObjectIDs(packageRoot string, issues *Issues) error {
+	for _, assetType := range stableIDAssetTypes {
+		paths, err := filepath.Glob(filepath.Join(packageRoot, "kibana", assetType, "*.json"))
+		if err != nil {
+			return fmt.Errorf("failed matching Kibana assets: %w", err)
+		}
+
+		for _, path := range paths {
+			d, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read Kibana asset: %w", err)
+			}
+			var savedObject struct {
+				ID string `json:"id"`
+			}
+			err = json.Unmarshal(d, &savedObject)
+			if err != nil {
+				// Invalid assets are reported by the spec validation.
+				continue
+			}
+
+			if generatedIDPattern.MatchString(savedObject.ID) {
+				issues.addWarningf("saved object %s has ID %q that looks auto-generated, use a stable descriptive ID to avoid changes when exporting it again", relativePath(packageRoot, path), savedObject.ID)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSavedObjectIDs(t *testing.T) {
+	var issues Issues
+	err := checkSavedObjectIDs("testdata/saved_object_ids", &issues)
+	require.NoError(t, err)
+	assert.Empty(t, issues.Errors)
+	// IDs prefixed with the package name, as <package>-<uuid>, are accepted.
+	require.Len(t, issues.Warnings, 1)
+	assert.EqualError(t, issues.Warnings[0], `saved object kibana/visualization/5e8d8c1a-9f3b-4c2e-a7d6-1b2c3d4e5f60.json has ID "5e8d8c1a-9f3b-4c2e-a7d6-1b2c3d4e5f60" that looks auto-generated, use a stable descriptive ID to avoid changes when exporting it again`)
+}
@@ -0,0 +1,153 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/elastic/elastic-package/internal/logger"
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+// secretVarsConfigPattern is the pattern of the test configuration files that can set values for
+// variables of tests run against services, relative to the package or data stream directory. Policy
+// tests don't connect to services, so they are not checked.
+var secretVarsConfigPattern = filepath.Join("_dev", "test", "system", "test-*-config.yml")
+
+type varsConfig struct {
+	Vars       map[string]any `yaml:"vars"`
+	DataStream struct {
+		Vars map[string]any `yaml:"vars"`
+	} `yaml:"data_stream"`
+}
+
+// checkSecretVarsInTestConfigs checks that variables declared as secret don't have literal
+// values in the test configurations committed with the package. Tests of services deployed
+// with Docker Compose run against local services, so their credentials are not checked.
+func checkSecretVarsInTestConfigs(packageRoot string, issues *Issues) error {
+	manifest, err := packages.ReadPackageManifestFromPackageRoot(packageRoot)
+	if err != nil {
+		return fmt.Errorf("failed to read package manifest: %w", err)
+	}
+	dataStreams, err := dataStreamManifests(packageRoot)
+	if err != nil {
+		return err
+	}
+
+	secrets := secretVarNames(manifest, dataStreams)
+	if len(secrets) == 0 {
+		return nil
+	}
+
+	roots := []string{packageRoot}
+	for _, dataStream := range dataStreams {
+		roots = append(roots, filepath.Join(packageRoot, "data_stream", dataStream.Name))
+	}
+	for _, root := range roots {
+		if deploysLocalService(packageRoot, root) {
+			continue
+		}
+		configFiles, err := filepath.Glob(filepath.Join(root, secretVarsConfigPattern))
+		if err != nil {
+			return fmt.Errorf("failed matching test configuration files: %w", err)
+		}
+		for _, configFile := range configFiles {
+			d, err := os.ReadFile(configFile)
+			if err != nil {
+				return fmt.Errorf("failed to read test configuration: %w", err)
+			}
+
+			var config varsConfig
+			err = yaml.Unmarshal(d, &config)
+			if err != nil {
+				// Test configurations can contain templates that are only rendered on runtime.
+				logger.Debugf("Skipping secret variables check for %s: %v", configFile, err)
+				continue
+			}
+
+			for _, name := range leakedSecretVars(secrets, config.Vars, config.DataStream.Vars) {
+				issues.addErrorf("secret variable %q has a literal value in %s, use a variable or a value provided by the service instead", name, relativePath(packageRoot, configFile))
+			}
+		}
+	}
+	return nil
+}
+
+// deploysLocalService returns true if the tests of the given package or data stream directory run
+// against a service deployed with Docker Compose. The deployment of a data stream takes precedence
+// over the one of the package.
+func deploysLocalService(packageRoot, root string) bool {
+	for _, dir := range []string{root, packageRoot} {
+		deployDir := filepath.Join(dir, "_dev", "deploy")
+		if _, err := os.Stat(deployDir); err != nil {
+			continue
+		}
+		_, err := os.Stat(filepath.Join(deployDir, "docker"))
+		return err == nil
+	}
+	return false
+}
+
+// secretVarNames returns the names of the variables declared as secret in the package.
+func secretVarNames(manifest *packages.PackageManifest, dataStreams []*packages.DataStreamManifest) []string {
+	var secrets []string
+	addSecrets := func(vars []packages.Variable) {
+		for _, v := range vars {
+			if v.Secret && !slices.Contains(secrets, v.Name) {
+				secrets = append(secrets, v.Name)
+			}
+		}
+	}
+
+	addSecrets(manifest.Vars)
+	for _, policyTemplate := range manifest.PolicyTemplates {
+		addSecrets(policyTemplate.Vars)
+		for _, input := range policyTemplate.Inputs {
+			addSecrets(input.Vars)
+		}
+	}
+	for _, dataStream := range dataStreams {
+		for _, stream := range dataStream.Streams {
+			addSecrets(stream.Vars)
+		}
+	}
+	return secrets
+}
+
+// leakedSecretVars returns the sorted names of the secret variables with literal values.
+func leakedSecretVars(secrets []string, varsMaps ...map[string]any) []string {
+	var leaked []string
+	for _, vars := range varsMaps {
+		for name, value := range vars {
+			if slices.Contains(secrets, name) && isLiteralValue(value) && !slices.Contains(leaked, name) {
+				leaked = append(leaked, name)
+			}
+		}
+	}
+	sort.Strings(leaked)
+	return leaked
+}
+
+// isLiteralValue returns true if the value is a non-empty string that is not obtained from a
+// template or an environment variable, or a list containing any of them. Other values, as
+// numbers or booleans, cannot contain secrets.
+func isLiteralValue(value any) bool {
+	switch value := value.(type) {
+	case string:
+		value = strings.TrimSpace(value)
+		return value != "" && !strings.Contains(value, "{{") && !strings.HasPrefix(value, "${")
+	case []any:
+		return slices.ContainsFunc(value, isLiteralValue)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSecretVarsInTestConfigs(t *testing.T) {
+	var issues Issues
+	err := checkSecretVarsInTestConfigs("testdata/secret_vars", &issues)
+	require.NoError(t, err)
+	assert.Empty(t, issues.Warnings)
+	require.Len(t, issues.Errors, 1)
+	assert.EqualError(t, issues.Errors[0], `secret variable "client_secret" has a literal value in data_stream/api/_dev/test/system/test-default-config.yml, use a variable or a value provided by the service instead`)
+}
+
+func TestIsLiteralValue(t *testing.T) {
+	cases := []struct {
+		value    any
+		expected bool
+	}{
+		{value: "s3cr3t-v4lu3", expected: true},
+		{value: []any{"{{TOKEN}}", "s3cr3t-v4lu3"}, expected: true},
+		{value: "test", expected: true},
+		{value: "xxxxxxxx", expected: true},
+		{value: nil, expected: false},
+		{value: "", expected: false},
+		{value: "  ", expected: false},
+		{value: "{{API_KEY}}", expected: false},
+		{value: "${SECRET_API_KEY}", expected: false},
+		{value: []any{"{{TOKEN}}", ""}, expected: false},
+		{value: 1234, expected: false},
+		{value: true, expected: false},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, isLiteralValue(c.value), "value: %v", c.value)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/elastic/elastic-package/internal/multierror"
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+// Issues contains the problems found by the semantic checks that elastic-package
+// runs on top of the package spec validation.
+type Issues struct {
+	Errors   multierror.Error
+	Warnings multierror.Error
+}
+
+func (i *Issues) addErrorf(format string, a ...any) {
+	i.Errors = append(i.Errors, fmt.Errorf(format, a...))
+}
+
+func (i *Issues) addWarningf(format string, a ...any) {
+	i.Warnings = append(i.Warnings, fmt.Errorf(format, a...))
+}
+
+// semanticCheck is a check on the contents of the package in the given root path. Problems
+// found in the package are added to issues, the returned error is only used when the check
+// cannot be completed.
+type semanticCheck func(packageRoot string, issues *Issues) error
+
+var semanticChecks = []semanticCheck{
+	checkIndexModeCoherence,
+	checkStaleNumericKeywordFields,
+	checkPackageDescription,
+	checkIndexPrefixes,
+	checkEmptyTestDirectories,
+	checkConvertProcessors,
+	checkDuplicateDataStreamTitles,
+	checkMLModuleIndexPatterns,
+	checkSecretVarsInTestConfigs,
+	checkUnproducedFields,
+	checkUnusedPipelineTestFixtures,
+	checkSavedObjectIDs,
+	checkDuplicateFieldDefinitions,
+	checkConditionsCoherence,
+	checkExternalFieldOverrides,
+	checkDeprecatedProcessorOptions,
+	checkTrailingWhitespace,
+}
+
+// optionalSemanticChecks are the semantic checks that are only run when explicitly enabled.
+var optionalSemanticChecks = map[string]semanticCheck{
+	"on_failure_handling": checkOnFailureHandling,
+}
+
+// OptionalSemanticChecks returns the names of the semantic checks that can be enabled.
+func OptionalSemanticChecks() []string {
+	names := make([]string, 0, len(optionalSemanticChecks))
+	for name := range optionalSemanticChecks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateSemanticsFromPath runs the semantic checks on the package in the given path, including
+// the optional checks with the given names.
+func ValidateSemanticsFromPath(packageRoot string, enabledChecks ...string) (*Issues, error) {
+	checks := slices.Clone(semanticChecks)
+	for _, name := range enabledChecks {
+		check, found := optionalSemanticChecks[name]
+		if !found {
+			return nil, fmt.Errorf("unknown check %q (available: %s)", name, strings.Join(OptionalSemanticChecks(), ", "))
+		}
+		checks = append(checks, check)
+	}
+
+	var issues Issues
+	for _, check := range checks {
+		err := check(packageRoot, &issues)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &issues, nil
+}
+
+// dataStreamManifests returns the manifests of all the data streams in the package.
+func dataStreamManifests(packageRoot string) ([]*packages.DataStreamManifest, error) {
+	paths, err := filepath.Glob(filepath.Join(packageRoot, "data_stream", "*", packages.DataStreamManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed matching data stream manifests: %w", err)
+	}
+
+	var manifests []*packages.DataStreamManifest
+	for _, path := range paths {
+		manifest, err := packages.ReadDataStreamManifest(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read data stream manifest: %w", err)
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests, nil
+}
@@ -0,0 +1,45 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"github.com/Masterminds/semver/v3"
+)
+
+// oldestStackMajor is the oldest major version of the stack considered when a range of versions
+// has no lower bound.
+const oldestStackMajor = 6
+
+// allowsVersionsBetween returns true if the constraint is satisfied by some version equal or
+// greater than from, and lower than to. A nil from or to leaves that side of the range open.
+// Constraints are checked against the first and a late patch of the minors of each major in
+// the range, up to two majors after from when there is no upper bound.
+func allowsVersionsBetween(constraint *semver.Constraints, from, to *semver.Version) bool {
+	firstMajor := uint64(oldestStackMajor)
+	if from != nil {
+		firstMajor = from.Major()
+	}
+	lastMajor := firstMajor + 2
+	if to != nil {
+		lastMajor = to.Major()
+	}
+	for major := firstMajor; major <= lastMajor; major++ {
+		for minor := uint64(0); minor <= 30; minor++ {
+			for _, patch := range []uint64{0, 99} {
+				v := semver.New(major, minor, patch, "", "")
+				if from != nil && v.LessThan(from) {
+					continue
+				}
+				if to != nil && !v.LessThan(to) {
+					continue
+				}
+				if constraint.Check(v) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowsVersionsBetween(t *testing.T) {
+	cases := []struct {
+		title      string
+		constraint string
+		from       *semver.Version
+		to         *semver.Version
+		expected   bool
+	}{
+		{"before 7.11.0", "^7.11.0", nil, semver.MustParse("7.11.0"), false},
+		{"before 7.11.0", "^8.0.0", nil, semver.MustParse("7.11.0"), false},
+		{"before 7.11.0", "^7.11.0 || ^8.0.0", nil, semver.MustParse("7.11.0"), false},
+		{"before 7.11.0", "^7.10.0", nil, semver.MustParse("7.11.0"), true},
+		{"before 7.11.0", "^7.17.0 || ^8.0.0", nil, semver.MustParse("7.11.0"), false},
+		{"before 7.11.0", "~7.10.2 || ^8.0.0", nil, semver.MustParse("7.11.0"), true},
+		{"before 7.11.0", ">=7.0.0", nil, semver.MustParse("7.11.0"), true},
+		{"from 8.15.0", "^8.15.0", semver8_15_0, nil, true},
+		{"from 8.15.0", "^8.0.0", semver8_15_0, nil, true},
+		{"from 8.15.0", "^8.14.0 || ^9.0.0", semver8_15_0, nil, true},
+		{"from 8.15.0", ">=8.16.0", semver8_15_0, nil, true},
+		{"from 8.15.0", "~8.14.0", semver8_15_0, nil, false},
+		{"from 8.15.0", "^7.17.0", semver8_15_0, nil, false},
+		{"from 8.10.0 before 8.15.0", "^8.12.0", semver8_10_0, semver8_15_0, true},
+		{"from 8.10.0 before 8.15.0", "^8.15.0", semver8_10_0, semver8_15_0, false},
+		{"from 8.10.0 before 8.15.0", "~8.9.0", semver8_10_0, semver8_15_0, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title+"/"+c.constraint, func(t *testing.T) {
+			constraint, err := semver.NewConstraint(c.constraint)
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, allowsVersionsBetween(constraint, c.from, c.to))
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+var trailingWhitespacePattern = regexp.MustCompile(`(?m)[ \t]+$`)
+
+// checkTrailingWhitespace checks that the YAML files of the package don't have lines with
+// trailing whitespace.
+func checkTrailingWhitespace(packageRoot string, issues *Issues) error {
+	paths, err := yamlFilesWithTrailingWhitespace(packageRoot)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		issues.addWarningf("file %s has lines with trailing whitespace, remove it or run 'elastic-package lint --fix'", relativePath(packageRoot, path))
+	}
+	return nil
+}
+
+// FixTrailingWhitespace removes the trailing whitespace from the lines of the YAML files of
+// the package. Files are only rewritten if their contents don't change, what could happen with
+// whitespace in block scalars. It returns the paths of the rewritten files, relative to the
+// package root.
+func FixTrailingWhitespace(packageRoot string) ([]string, error) {
+	paths, err := yamlFilesWithTrailingWhitespace(packageRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixed []string
+	for _, path := range paths {
+		d, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		trimmed := trailingWhitespacePattern.ReplaceAll(d, nil)
+		same, err := sameYAMLContents(d, trimmed)
+		if err != nil || !same {
+			continue
+		}
+		err = os.WriteFile(path, trimmed, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write file: %w", err)
+		}
+		fixed = append(fixed, relativePath(packageRoot, path))
+	}
+	return fixed, nil
+}
+
+func yamlFilesWithTrailingWhitespace(packageRoot string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(packageRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		ext := filepath.Ext(path)
+		if ext != ".yml" && ext != ".yaml" {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if trailingWhitespacePattern.Match(content) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look for YAML files: %w", err)
+	}
+	return paths, nil
+}
+
+// sameYAMLContents checks if both YAML contents decode to the same documents.
+func sameYAMLContents(a, b []byte) (bool, error) {
+	docsA, err := decodeYAMLDocuments(a)
+	if err != nil {
+		return false, err
+	}
+	docsB, err := decodeYAMLDocuments(b)
+	if err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(docsA, docsB), nil
+}
+
+func decodeYAMLDocuments(d []byte) ([]any, error) {
+	var docs []any
+	decoder := yaml.NewDecoder(bytes.NewReader(d))
+	for {
+		var doc any
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrailingWhitespace(t *testing.T) {
+	// Files are created here, so editors don't remove the trailing whitespace of testdata files.
+	packageRoot := t.TempDir()
+	files := map[string]string{
+		"manifest.yml":                          "name: example \nversion: 0.0.1\t\ntitle: Example\n",
+		"changelog.yml":                         "- version: \"0.0.1\"\n",
+		"data_stream/logs/fields/fields.yml":    "- name: message \n  type: text\n",
+		"data_stream/logs/fields/block.yml":     "- name: message\n  description: |\n    Some text \n    in a block\n",
+		"data_stream/logs/agent/stream/log.hbs": "paths: \n",
+	}
+	for name, content := range files {
+		path := filepath.Join(packageRoot, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	var issues Issues
+	err := checkTrailingWhitespace(packageRoot, &issues)
+	require.NoError(t, err)
+	assert.Empty(t, issues.Errors)
+	require.Len(t, issues.Warnings, 3)
+	assert.ErrorContains(t, issues.Warnings[0], "file data_stream/logs/fields/block.yml has lines with trailing whitespace")
+
+	fixed, err := FixTrailingWhitespace(packageRoot)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"data_stream/logs/fields/fields.yml", "manifest.yml"}, fixed)
+
+	d, err := os.ReadFile(filepath.Join(packageRoot, "manifest.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, "name: example\nversion: 0.0.1\ntitle: Example\n", string(d))
+
+	// Whitespace in block scalars is part of the value, it is not fixed.
+	d, err = os.ReadFile(filepath.Join(packageRoot, "data_stream/logs/fields/block.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, files["data_stream/logs/fields/block.yml"], string(d))
+
+	fixed, err = FixTrailingWhitespace(packageRoot)
+	require.NoError(t, err)
+	assert.Empty(t, fixed)
+}
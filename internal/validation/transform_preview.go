@@ -0,0 +1,79 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/multierror"
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+// TransformPreviewer previews the documents generated by a transform with the given definition.
+type TransformPreviewer interface {
+	PreviewTransform(ctx context.Context, definition map[string]any) ([]common.MapStr, error)
+}
+
+// TransformPreview is the result of previewing a transform.
+type TransformPreview struct {
+	// Transform is the name of the transform.
+	Transform string
+
+	// Documents is the number of documents generated in the preview.
+	Documents int
+
+	// Err contains the problems found in the preview or in the generated documents.
+	Err error
+}
+
+// PreviewTransforms previews the given transforms, and validates the documents they generate with
+// the fields defined for each transform, using a validator created with the given options. Errors
+// in the preview of a transform are reported in its result, the returned error is only used when
+// the transforms cannot be checked.
+func PreviewTransforms(ctx context.Context, previewer TransformPreviewer, transforms []packages.Transform, opts ...fields.ValidatorOption) ([]TransformPreview, error) {
+	var previews []TransformPreview
+	for _, transform := range transforms {
+		definition, err := packages.ReadTransformDefinition(transform.Path)
+		if err != nil {
+			return nil, err
+		}
+		// Metadata is only used by Fleet, and the destination, that can reference ingest
+		// pipelines installed by Fleet, is not needed to preview the transform.
+		delete(definition, "_meta")
+		delete(definition, "dest")
+
+		preview := TransformPreview{Transform: transform.Name}
+		docs, err := previewer.PreviewTransform(ctx, definition)
+		if err != nil {
+			preview.Err = err
+			previews = append(previews, preview)
+			continue
+		}
+		preview.Documents = len(docs)
+		if len(docs) == 0 {
+			previews = append(previews, preview)
+			continue
+		}
+
+		transformRootPath := filepath.Dir(transform.Path)
+		validator, err := fields.CreateValidatorForDirectory(transformRootPath, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("creating fields validator for transform failed (path: %s): %w", transformRootPath, err)
+		}
+		var errs multierror.Error
+		for _, doc := range docs {
+			errs = append(errs, validator.ValidateDocumentMap(doc)...)
+		}
+		if len(errs) > 0 {
+			preview.Err = fmt.Errorf("errors found in documents of preview:\n%w", errs.Unique())
+		}
+		previews = append(previews, preview)
+	}
+	return previews, nil
+}
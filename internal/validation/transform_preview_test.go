@@ -0,0 +1,85 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/packages"
+)
+
+type fakeTransformPreviewer map[string][]common.MapStr
+
+func (p fakeTransformPreviewer) PreviewTransform(ctx context.Context, definition map[string]any) ([]common.MapStr, error) {
+	if _, found := definition["dest"]; found {
+		return nil, errors.New("unexpected destination in definition")
+	}
+	description, _ := definition["description"].(string)
+	docs, found := p[description]
+	if !found {
+		return nil, errors.New("source index not found")
+	}
+	return docs, nil
+}
+
+func TestPreviewTransforms(t *testing.T) {
+	transformDir := filepath.Join(t.TempDir(), "elasticsearch", "transform", "latest")
+	require.NoError(t, os.MkdirAll(filepath.Join(transformDir, "fields"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(transformDir, "fields", "fields.yml"), []byte(`
+- name: host.name
+  type: keyword
+- name: event.count
+  type: long
+`), 0644))
+
+	writeTransform := func(description string) string {
+		path := filepath.Join(transformDir, description+".yml")
+		content := "description: " + description + "\nsource:\n  index: [logs-*]\ndest:\n  index: example\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	transforms := []packages.Transform{
+		{Name: "valid", Path: writeTransform("valid")},
+		{Name: "invalid", Path: writeTransform("invalid")},
+		{Name: "empty", Path: writeTransform("empty")},
+		{Name: "failing", Path: writeTransform("failing")},
+	}
+
+	// Previewed documents are decoded from JSON, so their objects are maps.
+	previewer := fakeTransformPreviewer{
+		"valid": {
+			{"host": map[string]any{"name": "a"}, "event": map[string]any{"count": 2.0}},
+			{"host": map[string]any{"name": "b"}, "event": map[string]any{"count": 3.0}},
+		},
+		"invalid": {
+			{"host": map[string]any{"name": "a"}, "event": map[string]any{"count": "many"}},
+		},
+		"empty": {},
+	}
+
+	previews, err := PreviewTransforms(context.Background(), previewer, transforms, fields.WithDisabledDependencyManagement())
+	require.NoError(t, err)
+	require.Len(t, previews, 4)
+
+	assert.Equal(t, TransformPreview{Transform: "valid", Documents: 2}, previews[0])
+
+	assert.Equal(t, "invalid", previews[1].Transform)
+	assert.Equal(t, 1, previews[1].Documents)
+	assert.ErrorContains(t, previews[1].Err, `field "event.count"`)
+
+	assert.Equal(t, TransformPreview{Transform: "empty"}, previews[2])
+
+	assert.Equal(t, "failing", previews[3].Transform)
+	assert.EqualError(t, previews[3].Err, "source index not found")
+}
@@ -0,0 +1,137 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/elastic/elastic-package/internal/common"
+	"github.com/elastic/elastic-package/internal/fields"
+	"github.com/elastic/elastic-package/internal/logger"
+)
+
+// pipelineTestCommonConfig is the configuration file shared by all the pipeline tests of a data stream.
+const pipelineTestCommonConfig = "test-common-config.yml"
+
+// checkUnproducedFields checks that the fields defined in the data streams with pipeline tests
+// are present in some of the expected documents of these tests. Fields that are external, are
+// constant keywords, or are listed in unproduced_fields in the common pipeline tests configuration
+// are not reported.
+func checkUnproducedFields(packageRoot string, issues *Issues) error {
+	manifests, err := dataStreamManifests(packageRoot)
+	if err != nil {
+		return err
+	}
+
+	for _, manifest := range manifests {
+		dataStreamRoot := filepath.Join(packageRoot, "data_stream", manifest.Name)
+		testDir := filepath.Join(dataStreamRoot, "_dev", "test", "pipeline")
+		expectedFiles, err := filepath.Glob(filepath.Join(testDir, "*-expected.json"))
+		if err != nil {
+			return fmt.Errorf("failed matching pipeline test results: %w", err)
+		}
+		if len(expectedFiles) == 0 {
+			continue
+		}
+
+		// External fields are not resolved, they are not expected to be produced by the pipelines.
+		validator, err := fields.CreateValidatorForDirectory(dataStreamRoot,
+			fields.WithDisabledDependencyManagement(),
+			fields.WithEnabledFieldsCoverage(),
+		)
+		if err != nil {
+			logger.Debugf("Skipping unproduced fields check for data stream %s: %v", manifest.Name, err)
+			continue
+		}
+
+		for _, expectedFile := range expectedFiles {
+			docs, err := readExpectedDocuments(expectedFile)
+			if err != nil {
+				return err
+			}
+			for _, doc := range docs {
+				// Only the coverage is relevant here, the documents are validated by the tests.
+				_ = validator.ValidateDocumentMap(doc)
+			}
+		}
+
+		ignored, err := readUnproducedFieldsConfig(filepath.Join(testDir, pipelineTestCommonConfig))
+		if err != nil {
+			return err
+		}
+
+		var unproduced []string
+		for _, definition := range validator.CoverageReport() {
+			if definition.External != "" || definition.Type == "constant_keyword" || matchesAnyField(definition.Name, ignored) {
+				continue
+			}
+			unproduced = append(unproduced, definition.Name)
+		}
+		if len(unproduced) > 0 {
+			issues.addWarningf("fields of data stream %q are defined but not found in any pipeline test result, remove them or list them in unproduced_fields in %s: %s",
+				manifest.Name, pipelineTestCommonConfig, strings.Join(unproduced, ", "))
+		}
+	}
+	return nil
+}
+
+// readExpectedDocuments reads the documents in a file with the expected results of a pipeline test.
+func readExpectedDocuments(path string) ([]common.MapStr, error) {
+	d, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pipeline test results: %w", err)
+	}
+
+	var results struct {
+		Expected []common.MapStr `json:"expected"`
+	}
+	err = json.Unmarshal(d, &results)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode pipeline test results (path: %s): %w", path, err)
+	}
+
+	// Dropped documents are null in the expected results.
+	return slices.DeleteFunc(results.Expected, func(doc common.MapStr) bool { return doc == nil }), nil
+}
+
+// readUnproducedFieldsConfig reads the fields that are intentionally not produced by the ingest
+// pipeline, from the common configuration of the pipeline tests.
+func readUnproducedFieldsConfig(path string) ([]string, error) {
+	d, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pipeline tests configuration: %w", err)
+	}
+
+	var config struct {
+		UnproducedFields []string `yaml:"unproduced_fields"`
+	}
+	err = yaml.Unmarshal(d, &config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode pipeline tests configuration (path: %s): %w", path, err)
+	}
+	return config.UnproducedFields, nil
+}
+
+// matchesAnyField returns true if the field name matches any of the given names or patterns.
+func matchesAnyField(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	pipelineTestExpectedSuffix = "-expected.json"
+	pipelineTestConfigSuffix   = "-config.yml"
+)
+
+// checkUnusedPipelineTestFixtures checks that the expected results and configuration files in the
+// pipeline tests of the data streams belong to some test case. The pipeline test runner considers
+// test cases all the files in the directory, except expected results and configurations, and looks
+// for the files of a test case by adding the -expected.json and -config.yml suffixes to its name.
+func checkUnusedPipelineTestFixtures(packageRoot string, issues *Issues) error {
+	manifests, err := dataStreamManifests(packageRoot)
+	if err != nil {
+		return err
+	}
+
+	for _, manifest := range manifests {
+		testDir := filepath.Join(packageRoot, "data_stream", manifest.Name, "_dev", "test", "pipeline")
+		unused, err := unusedPipelineTestFixtures(testDir)
+		if err != nil {
+			return fmt.Errorf("failed to look for unused pipeline test files in data stream %q: %w", manifest.Name, err)
+		}
+		if len(unused) > 0 {
+			issues.addWarningf("pipeline test files of data stream %q don't belong to any test case, remove them or rename them to match their test case: %s",
+				manifest.Name, strings.Join(unused, ", "))
+		}
+	}
+	return nil
+}
+
+// unusedPipelineTestFixtures returns the names of the expected results and configuration files in
+// the pipeline tests directory whose test case file doesn't exist.
+func unusedPipelineTestFixtures(testDir string) ([]string, error) {
+	entries, err := os.ReadDir(testDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var testCases, fixtures []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		switch {
+		case name == pipelineTestCommonConfig:
+			// Shared by all the test cases.
+		case strings.HasSuffix(name, pipelineTestExpectedSuffix), strings.HasSuffix(name, pipelineTestConfigSuffix):
+			fixtures = append(fixtures, name)
+		default:
+			testCases = append(testCases, name)
+		}
+	}
+
+	var unused []string
+	for _, fixture := range fixtures {
+		testCase := strings.TrimSuffix(fixture, pipelineTestExpectedSuffix)
+		testCase = strings.TrimSuffix(testCase, pipelineTestConfigSuffix)
+		if !slices.Contains(testCases, testCase) {
+			unused = append(unused, fixture)
+		}
+	}
+	return unused, nil
+}
@@ -0,0 +1,21 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckUnusedPipelineTestFixtures(t *testing.T) {
+	var issues Issues
+	err := checkUnusedPipelineTestFixtures("testdata/unused_pipeline_fixtures", &issues)
+	require.NoError(t, err)
+	assert.Empty(t, issues.Errors)
+	require.Len(t, issues.Warnings, 1)
+	assert.EqualError(t, issues.Warnings[0], `pipeline test files of data stream "logs" don't belong to any test case, remove them or rename them to match their test case: test-removed.log-expected.json, test-renamed.log-config.yml`)
+}
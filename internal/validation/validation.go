@@ -10,7 +10,9 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"strings"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/elastic/package-spec/v3/code/go/pkg/specerrors"
 	"github.com/elastic/package-spec/v3/code/go/pkg/validator"
 )
@@ -61,6 +63,57 @@ func ValidateAndFilterFromZip(packagePath string) (error, error) {
 	return result.Processed, result.Removed
 }
 
+// missingChangelogEntryError is the error reported by the package spec when the version in
+// the manifest doesn't match the top changelog entry.
+const missingChangelogEntryError = "current manifest version doesn't have changelog entry"
+
+// prereleaseChangelogTags are the prerelease tags of the versions that can be built without
+// a changelog entry, when explicitly allowed.
+var prereleaseChangelogTags = []string{"SNAPSHOT", "next"}
+
+// IsUnreleasedPrerelease returns true if the version is a prerelease that is not intended to
+// be published, as "1.2.0-SNAPSHOT" or "1.2.0-next".
+func IsUnreleasedPrerelease(version string) bool {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+	tag, _, _ := strings.Cut(v.Prerelease(), ".")
+	for _, prereleaseTag := range prereleaseChangelogTags {
+		if tag == prereleaseTag {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterPrereleaseChangelogErrors removes from the validation errors the ones about the manifest
+// version not having a changelog entry. It returns the remaining errors and the removed ones.
+func FilterPrereleaseChangelogErrors(allErrors error) (error, error) {
+	var errs specerrors.ValidationErrors
+	if !errors.As(allErrors, &errs) {
+		return allErrors, nil
+	}
+
+	var processed, removed specerrors.ValidationErrors
+	for _, err := range errs {
+		if strings.Contains(err.Error(), missingChangelogEntryError) {
+			removed = append(removed, err)
+			continue
+		}
+		processed = append(processed, err)
+	}
+
+	var processedErr, removedErr error
+	if len(processed) > 0 {
+		processedErr = processed
+	}
+	if len(removed) > 0 {
+		removedErr = removed
+	}
+	return processedErr, removedErr
+}
+
 func fsFromPackageZip(fsys fs.FS) (fs.FS, error) {
 	dirs, err := fs.ReadDir(fsys, ".")
 	if err != nil {
@@ -0,0 +1,56 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/elastic/package-spec/v3/code/go/pkg/specerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsUnreleasedPrerelease(t *testing.T) {
+	cases := map[string]bool{
+		"1.2.0":          false,
+		"1.2.0-beta1":    false,
+		"1.2.0-preview1": false,
+		"1.2.0-SNAPSHOT": true,
+		"1.2.0-next":     true,
+		"1.2.0-next.1":   true,
+		"1.2.0-nextgen":  false,
+		"invalid":        false,
+	}
+
+	for version, expected := range cases {
+		t.Run(version, func(t *testing.T) {
+			assert.Equal(t, expected, IsUnreleasedPrerelease(version))
+		})
+	}
+}
+
+func TestFilterPrereleaseChangelogErrors(t *testing.T) {
+	errs := specerrors.ValidationErrors{
+		specerrors.NewStructuredErrorf("file \"changelog.yml\" is invalid: current manifest version doesn't have changelog entry"),
+		specerrors.NewStructuredErrorf("file \"manifest.yml\" is invalid: field title: Invalid type. Expected: string, given: integer"),
+	}
+
+	processed, removed := FilterPrereleaseChangelogErrors(errs)
+	require.Error(t, processed)
+	require.Error(t, removed)
+	assert.NotContains(t, processed.Error(), missingChangelogEntryError)
+	assert.Contains(t, processed.Error(), "Invalid type")
+	assert.Contains(t, removed.Error(), missingChangelogEntryError)
+
+	processed, removed = FilterPrereleaseChangelogErrors(errs[:1])
+	assert.NoError(t, processed)
+	assert.Error(t, removed)
+
+	other := errors.New("not a validation error")
+	processed, removed = FilterPrereleaseChangelogErrors(other)
+	assert.Equal(t, other, processed)
+	assert.NoError(t, removed)
+}
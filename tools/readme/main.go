@@ -55,6 +55,9 @@ func generateCommandsDoc(cmdTmpl *template.Template, subCommandTemplate *templat
 			log.Fatalf("Writing documentation for command '%s' failed: %v", cmd.Name(), err)
 		}
 		for _, subCommand := range cmd.Commands() {
+			if subCommand.Hidden {
+				continue
+			}
 			log.Printf("Generating command doc for %s %s...\n", cmd.Name(), subCommand.Name())
 			description := subCommand.Long
 			if description == "" {